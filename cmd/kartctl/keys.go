@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/google/uuid"
+
+	"github.com/xenking/oolio-kart-challenge/internal/domain/auth"
+	"github.com/xenking/oolio-kart-challenge/internal/repository"
+)
+
+const keysUsage = `usage: kartctl keys <command> [flags] [args]
+
+commands:
+  create  -name NAME [-scopes a,b] [-id ID] [-expires 720h] [-plan PLAN]
+                                              create a key and print its secret once
+  list                                        list all keys
+  show    ID                                  show one key
+  revoke  ID                                  disable a key
+  rotate  ID                                  replace a key's secret and print it once
+  scopes  ID a,b                              replace a key's scopes ("" for none)
+  plan    ID PLAN                             set a key's rate limit plan ("" for default)
+
+common flags:
+  -database-url  PostgreSQL URL (or KART_DATABASE_URL / DATABASE_URL)
+  -api-key-pepper HMAC pepper, must match the API server (or KART_API_KEY_PEPPER)
+  -api-key-peppers versioned peppers as v:secret,... (or KART_API_KEY_PEPPERS);
+                 overrides -api-key-pepper, the newest hashes new secrets
+  -o             output format: table or json (default table)
+`
+
+var errUsage = errors.New("invalid usage")
+
+// keysCmd holds the flags shared by all keys subcommands.
+type keysCmd struct {
+	databaseURL string
+	pepper      string
+	peppers     string
+	output      string
+}
+
+func (c *keysCmd) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("kartctl keys "+name, flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, keysUsage) }
+	fs.StringVar(&c.databaseURL, "database-url", "", "PostgreSQL connection URL")
+	fs.StringVar(&c.pepper, "api-key-pepper", "", "HMAC pepper for API key hashing")
+	fs.StringVar(&c.peppers, "api-key-peppers", "", "versioned HMAC peppers as version:secret,...")
+	fs.StringVar(&c.output, "o", "table", "output format: table or json")
+	return fs
+}
+
+func runKeys(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, keysUsage)
+		return errUsage
+	}
+
+	var (
+		c       keysCmd
+		name    string
+		id      string
+		scopes  string
+		plan    string
+		expires time.Duration
+	)
+	fs := c.flags(args[0])
+	nargs := 1
+	switch args[0] {
+	case "create":
+		fs.StringVar(&name, "name", "", "human-readable key name")
+		fs.StringVar(&scopes, "scopes", "", "comma-separated scopes")
+		fs.StringVar(&id, "id", "", "key ID (default: random UUID)")
+		fs.DurationVar(&expires, "expires", 0, "lifetime of the key (default: never expires)")
+		fs.StringVar(&plan, "plan", "", "rate limit plan (default: the server's default limits)")
+		nargs = 0
+	case "list":
+		nargs = 0
+	case "scopes", "plan":
+		nargs = 2
+	case "show", "revoke", "rotate":
+	default:
+		fmt.Fprint(os.Stderr, keysUsage)
+		return fmt.Errorf("unknown keys command %q", args[0])
+	}
+
+	pos, err := parseInterspersed(fs, args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(pos) != nargs {
+		fs.Usage()
+		return errUsage
+	}
+	if c.output != "table" && c.output != "json" {
+		return fmt.Errorf("unknown output format %q", c.output)
+	}
+
+	mgr, closeDB, err := c.manager(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	out := os.Stdout
+	switch args[0] {
+	case "create":
+		if name == "" {
+			return errors.New("-name is required")
+		}
+		req := auth.CreateKeyRequest{
+			ID:            id,
+			Name:          name,
+			Scopes:        splitList(scopes),
+			RateLimitPlan: strings.TrimSpace(plan),
+		}
+		if req.ID == "" {
+			req.ID = uuid.New().String()
+		}
+		if expires > 0 {
+			at := time.Now().Add(expires).Truncate(time.Second)
+			req.ExpiresAt = &at
+		}
+		key, secret, err := mgr.Create(ctx, req)
+		if err != nil {
+			return err
+		}
+		return c.printSecret(out, key, secret)
+	case "list":
+		keys, err := mgr.List(ctx)
+		if err != nil {
+			return err
+		}
+		return c.printKeys(out, keys)
+	case "show":
+		key, err := mgr.Get(ctx, pos[0])
+		if err != nil {
+			return err
+		}
+		return c.printKeys(out, []auth.Key{*key})
+	case "revoke":
+		key, err := mgr.Revoke(ctx, pos[0])
+		if err != nil {
+			return err
+		}
+		return c.printKeys(out, []auth.Key{*key})
+	case "rotate":
+		key, secret, err := mgr.Rotate(ctx, pos[0])
+		if err != nil {
+			return err
+		}
+		return c.printSecret(out, key, secret)
+	case "scopes":
+		key, err := mgr.SetScopes(ctx, pos[0], splitList(pos[1]))
+		if err != nil {
+			return err
+		}
+		return c.printKeys(out, []auth.Key{*key})
+	case "plan":
+		key, err := mgr.SetRateLimitPlan(ctx, pos[0], strings.TrimSpace(pos[1]))
+		if err != nil {
+			return err
+		}
+		return c.printKeys(out, []auth.Key{*key})
+	}
+	return nil
+}
+
+// manager connects to the database and returns a key manager with a
+// function that closes the connection.
+func (c *keysCmd) manager(ctx context.Context) (*auth.Manager, func(), error) {
+	dbURL := firstNonEmpty(c.databaseURL, os.Getenv("KART_DATABASE_URL"), os.Getenv("DATABASE_URL"))
+	if dbURL == "" {
+		return nil, nil, errors.New("database URL is required: set -database-url or KART_DATABASE_URL")
+	}
+	peppers, err := auth.ParsePeppers(
+		splitList(firstNonEmpty(c.peppers, os.Getenv("KART_API_KEY_PEPPERS"))),
+		firstNonEmpty(c.pepper, os.Getenv("KART_API_KEY_PEPPER")),
+	)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "parse api key peppers")
+	}
+
+	pool, err := repository.NewPool(ctx, dbURL)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "connect to database")
+	}
+	mgr := auth.NewManager(repository.NewAPIKeyRepository(pool), peppers)
+	return mgr, pool.Close, nil
+}
+
+// printKeys writes keys as a table or a JSON array. A single key is written
+// as a JSON object.
+func (c *keysCmd) printKeys(w io.Writer, keys []auth.Key) error {
+	if c.output == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if len(keys) == 1 {
+			return enc.Encode(keys[0])
+		}
+		if keys == nil {
+			keys = []auth.Key{}
+		}
+		return enc.Encode(keys)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tPREFIX\tNAME\tSCOPES\tPLAN\tACTIVE\tCREATED\tEXPIRES\tLAST USED\tPEPPER")
+	for _, k := range keys {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%t\t%s\t%s\t%s\tv%d\n",
+			k.ID, k.Prefix, k.Name, strings.Join(k.Scopes, ","), firstNonEmpty(k.RateLimitPlan, "default"), k.Active,
+			k.CreatedAt.Format(time.RFC3339), formatTime(k.ExpiresAt, "never"), formatTime(k.LastUsedAt, "-"),
+			k.PepperVersion)
+	}
+	return tw.Flush()
+}
+
+func formatTime(t *time.Time, zero string) string {
+	if t == nil {
+		return zero
+	}
+	return t.Format(time.RFC3339)
+}
+
+// printSecret writes a key together with its freshly generated secret.
+func (c *keysCmd) printSecret(w io.Writer, key *auth.Key, secret string) error {
+	if c.output == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			auth.Key
+			Secret string `json:"secret"`
+		}{*key, secret})
+	}
+
+	if err := c.printKeys(w, []auth.Key{*key}); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nSecret: %s\n", secret)
+	fmt.Fprintln(os.Stderr, "Store the secret now; it cannot be shown again.")
+	return nil
+}
+
+// parseInterspersed parses flags that may appear before or after positional
+// arguments and returns the positional ones.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return pos, nil
+		}
+		pos = append(pos, args[0])
+		args = args[1:]
+	}
+}
+
+func splitList(s string) []string {
+	scopes := []string{}
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			scopes = append(scopes, part)
+		}
+	}
+	return scopes
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+// Command kartctl administers a kart deployment from the command line.
+//
+//	kartctl keys <create|list|show|revoke|rotate|scopes> [flags] [args]
+//
+// The database URL and pepper are read from flags or the same environment
+// variables the API server uses.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+const usage = `usage: kartctl <command> [flags] [args]
+
+commands:
+  keys    manage API keys (run "kartctl keys" for details)
+`
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "kartctl:", err)
+		cancel()
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return errUsage
+	}
+	switch args[0] {
+	case "keys":
+		return runKeys(ctx, args[1:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
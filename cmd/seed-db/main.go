@@ -2,19 +2,18 @@ package main
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"flag"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 
 	"github.com/go-faster/errors"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/shopspring/decimal"
 
+	"github.com/xenking/oolio-kart-challenge/internal/domain/auth"
 	"github.com/xenking/oolio-kart-challenge/internal/repository"
 )
 
@@ -33,16 +32,18 @@ type productJSON struct {
 
 func main() {
 	var (
-		databaseURL  string
-		productsFile string
-		apiKey       string
-		apiKeyPepper string
+		databaseURL   string
+		productsFile  string
+		apiKey        string
+		apiKeyPepper  string
+		apiKeyPeppers string
 	)
 
 	flag.StringVar(&databaseURL, "database-url", "", "PostgreSQL connection URL (or DATABASE_URL env)")
 	flag.StringVar(&productsFile, "products-file", "db/seed/products.json", "path to products JSON file")
 	flag.StringVar(&apiKey, "api-key", "", "API key to seed (or KART_SEED_API_KEY env)")
 	flag.StringVar(&apiKeyPepper, "api-key-pepper", "", "HMAC pepper for API key hashing (or KART_API_KEY_PEPPER env)")
+	flag.StringVar(&apiKeyPeppers, "api-key-peppers", "", "versioned HMAC peppers as version:secret,... (or KART_API_KEY_PEPPERS env)")
 	flag.Parse()
 
 	if databaseURL == "" {
@@ -62,11 +63,23 @@ func main() {
 	if apiKeyPepper == "" {
 		apiKeyPepper = os.Getenv("KART_API_KEY_PEPPER")
 	}
+	if apiKeyPeppers == "" {
+		apiKeyPeppers = os.Getenv("KART_API_KEY_PEPPERS")
+	}
+	var pepperEntries []string
+	if apiKeyPeppers != "" {
+		pepperEntries = strings.Split(apiKeyPeppers, ",")
+	}
+	peppers, err := auth.ParsePeppers(pepperEntries, apiKeyPepper)
+	if err != nil {
+		slog.Error("invalid API key peppers", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	if err := run(ctx, databaseURL, productsFile, apiKey, apiKeyPepper); err != nil {
+	if err := run(ctx, databaseURL, productsFile, apiKey, peppers.Current()); err != nil {
 		slog.Error("seed failed", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
@@ -74,7 +87,7 @@ func main() {
 	slog.Info("seed completed successfully")
 }
 
-func run(ctx context.Context, databaseURL, productsFile, apiKey, pepper string) error {
+func run(ctx context.Context, databaseURL, productsFile, apiKey string, pepper auth.Pepper) error {
 	slog.Info("connecting to database")
 
 	pool, err := repository.NewPool(ctx, databaseURL)
@@ -188,21 +201,23 @@ func seedCoupons(ctx context.Context, pool *pgxpool.Pool) error {
 	return nil
 }
 
-const upsertAPIKeySQL = `INSERT INTO api_keys (id, key_hash, name, scopes, active)
-	VALUES ($1, $2, $3, $4, $5)
+const upsertAPIKeySQL = `INSERT INTO api_keys (id, key_hash, prefix, name, scopes, active, pepper_version, signing_key)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	ON CONFLICT (id) DO UPDATE SET
-		key_hash = EXCLUDED.key_hash, name = EXCLUDED.name,
-		scopes = EXCLUDED.scopes, active = EXCLUDED.active`
+		key_hash = EXCLUDED.key_hash, prefix = EXCLUDED.prefix, name = EXCLUDED.name,
+		scopes = EXCLUDED.scopes, active = EXCLUDED.active, pepper_version = EXCLUDED.pepper_version,
+		signing_key = EXCLUDED.signing_key`
 
-func seedAPIKey(ctx context.Context, pool *pgxpool.Pool, apiKey, pepper string) error {
+func seedAPIKey(ctx context.Context, pool *pgxpool.Pool, apiKey string, pepper auth.Pepper) error {
 	slog.Info("seeding default API key")
 
-	mac := hmac.New(sha256.New, []byte(pepper))
-	mac.Write([]byte(apiKey))
-	keyHash := hex.EncodeToString(mac.Sum(nil))
-
+	stored, err := pepper.Protect("default", apiKey)
+	if err != nil {
+		return errors.Wrap(err, "protect default API key")
+	}
 	if _, err := pool.Exec(ctx, upsertAPIKeySQL,
-		"default", keyHash, "Default test key", []string{"create_order"}, true,
+		"default", stored.Hash, auth.KeyPrefix(apiKey), "Default test key",
+		auth.KnownScopes, true, stored.PepperVersion, stored.SigningKey,
 	); err != nil {
 		return errors.Wrap(err, "upsert default API key")
 	}
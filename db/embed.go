@@ -1,9 +1,10 @@
 // Package db provides embedded database schema and migration files.
 package db
 
-import _ "embed"
+import "embed"
 
-// Schema contains the DDL statements for all application tables.
+// Migrations contains the DDL files applied at startup, in lexical order.
+// Every file runs on each start, so statements must be idempotent.
 //
-//go:embed migrations/001_schema.sql
-var Schema string
+//go:embed migrations/*.sql
+var Migrations embed.FS
@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS idx_orders_created_at ON orders(created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_orders_coupon_code ON orders(UPPER(coupon_code), created_at DESC)
+    WHERE coupon_code <> '';
@@ -0,0 +1,39 @@
+-- Line items carry a snapshot of name, category, unit price, subtotal and
+-- allocated discount. Orders placed before snapshots existed are backfilled
+-- best-effort from the current catalog: prices may have drifted since, and
+-- the discount is split pro rata by line subtotal and rounded per line.
+-- Only rows whose items lack unit_price are touched, so this is idempotent.
+UPDATE orders o
+SET items = s.items
+FROM (
+    SELECT
+        ord.id,
+        jsonb_agg(
+            e.item || jsonb_build_object(
+                'name', COALESCE(p.name, ''),
+                'category', COALESCE(p.category, ''),
+                'unit_price', COALESCE(p.price, 0)::text,
+                'subtotal', (COALESCE(p.price, 0) * (e.item->>'quantity')::int)::text,
+                'discount', (CASE
+                    WHEN t.subtotal > 0 THEN ROUND(
+                        LEAST(ord.discounts, t.subtotal)
+                            * COALESCE(p.price, 0) * (e.item->>'quantity')::int
+                            / t.subtotal, 2)
+                    ELSE 0
+                END)::text
+            ) ORDER BY e.pos
+        ) AS items
+    FROM orders ord
+    CROSS JOIN LATERAL jsonb_array_elements(ord.items) WITH ORDINALITY AS e(item, pos)
+    LEFT JOIN products p ON p.id = e.item->>'product_id'
+    CROSS JOIN LATERAL (
+        SELECT SUM(COALESCE(p2.price, 0) * (x.item->>'quantity')::int) AS subtotal
+        FROM jsonb_array_elements(ord.items) AS x(item)
+        LEFT JOIN products p2 ON p2.id = x.item->>'product_id'
+    ) t
+    WHERE jsonb_typeof(ord.items) = 'array'
+      AND jsonb_array_length(ord.items) > 0
+      AND NOT (ord.items->0 ? 'unit_price')
+    GROUP BY ord.id
+) s
+WHERE o.id = s.id;
@@ -0,0 +1,21 @@
+-- Order lifecycle. Every order starts as 'placed'; allowed transitions are
+-- enforced by order.Status in the domain layer. Each change is appended to
+-- order_status_transitions with the acting API key and an optional reason.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'placed';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS status_updated_at TIMESTAMPTZ;
+UPDATE orders SET status_updated_at = created_at WHERE status_updated_at IS NULL;
+ALTER TABLE orders ALTER COLUMN status_updated_at SET DEFAULT NOW();
+ALTER TABLE orders ALTER COLUMN status_updated_at SET NOT NULL;
+
+CREATE TABLE IF NOT EXISTS order_status_transitions (
+    id          BIGSERIAL PRIMARY KEY,
+    order_id    TEXT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL DEFAULT '',
+    to_status   TEXT NOT NULL,
+    actor       TEXT NOT NULL DEFAULT '',
+    reason      TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_transitions_order_id
+    ON order_status_transitions (order_id, id);
@@ -0,0 +1,14 @@
+-- Server-side carts. Items hold only product IDs and quantities; prices are
+-- read from the catalog whenever a cart is priced. Carts are owned by the API
+-- key that created them and deleted once expires_at passes without a change.
+CREATE TABLE IF NOT EXISTS carts (
+    id          TEXT PRIMARY KEY,
+    owner       TEXT NOT NULL,
+    items       JSONB NOT NULL DEFAULT '[]',
+    coupon_code TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_carts_expires_at ON carts (expires_at);
@@ -0,0 +1,6 @@
+-- Scopes are now enforced per operation. Keys seeded before that carry the
+-- old create_order scope; give them the customer scopes it stood for.
+-- Status changes need orders:admin, which must be granted explicitly.
+UPDATE api_keys
+SET scopes = array_remove(scopes, 'create_order') || ARRAY['orders:read', 'orders:write']
+WHERE 'create_order' = ANY(scopes);
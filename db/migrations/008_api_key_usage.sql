@@ -0,0 +1,6 @@
+-- API key expiry, last use and a non-secret prefix for identifying keys in
+-- logs and listings. last_used_at is written in batches by the server, so it
+-- may lag real use by up to one flush interval.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS prefix TEXT NOT NULL DEFAULT '';
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMPTZ;
@@ -0,0 +1,4 @@
+-- Version of the pepper each key hash was made with. Existing hashes were
+-- all made with the single pepper used so far, which is version 1. Keys
+-- move to the current pepper when they next authenticate.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS pepper_version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,20 @@
+-- Announce API key changes on the api_keys_changed channel so servers can
+-- drop cached lookups. last_used_at is left out: it changes constantly and
+-- does not affect authentication.
+CREATE OR REPLACE FUNCTION notify_api_key_change() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM pg_notify('api_keys_changed', OLD.id);
+    ELSE
+        PERFORM pg_notify('api_keys_changed', NEW.id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS api_keys_changed ON api_keys;
+CREATE TRIGGER api_keys_changed
+    AFTER INSERT OR DELETE
+    OR UPDATE OF key_hash, prefix, name, scopes, active, expires_at, pepper_version
+    ON api_keys
+    FOR EACH ROW EXECUTE FUNCTION notify_api_key_change();
@@ -0,0 +1,14 @@
+-- Signed requests. signing_key holds the key's request-signing key sealed
+-- with the pepper of pepper_version; keys created before this migration
+-- have none until they are rotated or rehashed. request_nonces remembers
+-- each key's nonces until the request's timestamp is too old to accept.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS signing_key BYTEA;
+
+CREATE TABLE IF NOT EXISTS request_nonces (
+    key_id     TEXT NOT NULL,
+    nonce      TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (key_id, nonce)
+);
+
+CREATE INDEX IF NOT EXISTS idx_request_nonces_expires_at ON request_nonces (expires_at);
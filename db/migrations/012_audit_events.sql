@@ -0,0 +1,17 @@
+-- Audit log of authenticated state-changing requests. One row per request,
+-- written after the response with the acting key, the OpenAPI operation,
+-- the request ID, the entity acted on and how the request ended.
+CREATE TABLE IF NOT EXISTS audit_events (
+    id          BIGSERIAL PRIMARY KEY,
+    actor       TEXT NOT NULL,
+    operation   TEXT NOT NULL,
+    request_id  TEXT NOT NULL DEFAULT '',
+    target_type TEXT NOT NULL DEFAULT '',
+    target_id   TEXT NOT NULL DEFAULT '',
+    outcome     TEXT NOT NULL,
+    status_code INTEGER NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events (actor, created_at DESC, id DESC);
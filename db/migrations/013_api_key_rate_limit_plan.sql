@@ -0,0 +1,11 @@
+-- Per-key rate limit plans. The plan name refers to a policy in the server
+-- configuration; '' means the default limits. The change trigger from 010
+-- is recreated with the new column so cached keys pick up plan changes.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS rate_limit_plan TEXT NOT NULL DEFAULT '';
+
+DROP TRIGGER IF EXISTS api_keys_changed ON api_keys;
+CREATE TRIGGER api_keys_changed
+    AFTER INSERT OR DELETE
+    OR UPDATE OF key_hash, prefix, name, scopes, active, expires_at, pepper_version, rate_limit_plan
+    ON api_keys
+    FOR EACH ROW EXECUTE FUNCTION notify_api_key_change();
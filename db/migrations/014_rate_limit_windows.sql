@@ -0,0 +1,13 @@
+-- Shared rate limit counters. Each row counts one key's requests in one
+-- fixed window; the limiter weighs it with the previous window's row to get
+-- a sliding count. Rows are pruned once they can no longer be the previous
+-- window of anything.
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_windows (
+    key          TEXT NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL,
+    hits         INTEGER NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (key, window_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_windows_expires_at ON rate_limit_windows (expires_at);
@@ -0,0 +1,3 @@
+-- The client address of audited requests, as resolved through the trusted
+-- proxies. Events recorded before this migration have ''.
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS client_ip TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- Coupon codes are matched case-insensitively everywhere, so they must also
+-- be unique that way before administrators can create them through the API.
+-- The index also serves the UPPER(code) lookups of order placement.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_coupons_code_upper ON coupons (UPPER(code));
@@ -0,0 +1,17 @@
+-- Per-customer coupon limits. Every redemption is recorded with the key that
+-- placed the order, so a coupon can be limited to a number of uses per
+-- customer (0 = no limit). The order row is inserted after the coupon is
+-- redeemed in the same transaction, so the foreign key is checked at commit.
+ALTER TABLE coupons ADD COLUMN IF NOT EXISTS max_uses_per_customer INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    id          BIGSERIAL PRIMARY KEY,
+    coupon_code TEXT NOT NULL,
+    customer    TEXT NOT NULL,
+    order_id    TEXT NOT NULL UNIQUE
+                REFERENCES orders (id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_code_customer
+    ON coupon_redemptions (coupon_code, customer);
@@ -0,0 +1,32 @@
+-- Coupon stacking. Coupons opt in to being combined on one order, and
+-- priority orders coupons of the same kind within a stack. Existing coupons
+-- are not stackable, so they keep being used alone.
+ALTER TABLE coupons ADD COLUMN IF NOT EXISTS stackable BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE coupons ADD COLUMN IF NOT EXISTS priority INTEGER NOT NULL DEFAULT 0;
+
+-- What each coupon contributed to an order, in the order they were applied.
+-- orders.coupon_code keeps the first code as typed.
+CREATE TABLE IF NOT EXISTS order_coupons (
+    order_id    TEXT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    position    INTEGER NOT NULL,
+    code        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    discount    NUMERIC(10,2) NOT NULL,
+    PRIMARY KEY (order_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_coupons_code ON order_coupons (UPPER(code));
+
+-- Orders placed before stacking have at most one coupon, which accounts for
+-- the whole discount. Descriptions are today's, not the ones at purchase.
+INSERT INTO order_coupons (order_id, position, code, description, discount)
+SELECT o.id, 1, COALESCE(c.code, o.coupon_code), COALESCE(c.description, ''), o.discounts
+FROM orders o
+LEFT JOIN coupons c ON UPPER(c.code) = UPPER(o.coupon_code)
+WHERE o.coupon_code <> ''
+ON CONFLICT DO NOTHING;
+
+-- A stacked order redeems several coupons, each once.
+ALTER TABLE coupon_redemptions DROP CONSTRAINT IF EXISTS coupon_redemptions_order_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_coupon_redemptions_order_code
+    ON coupon_redemptions (order_id, coupon_code);
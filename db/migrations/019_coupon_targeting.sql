@@ -0,0 +1,7 @@
+-- Coupon targeting. A coupon can be limited to some products or categories
+-- (product IDs and products.category values), or leave some out. Empty lists
+-- put no limit, so existing coupons keep discounting the whole cart.
+ALTER TABLE coupons ADD COLUMN IF NOT EXISTS include_products TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE coupons ADD COLUMN IF NOT EXISTS include_categories TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE coupons ADD COLUMN IF NOT EXISTS exclude_products TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE coupons ADD COLUMN IF NOT EXISTS exclude_categories TEXT[] NOT NULL DEFAULT '{}';
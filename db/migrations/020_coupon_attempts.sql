@@ -0,0 +1,14 @@
+-- Shared coupon guard state. Each row holds one subject's distinct invalid
+-- codes since its count last started over, and how long it is blocked.
+-- repeated tells whether the latest code was already among them, so the
+-- guard learns it from the same statement that records the code. Losing
+-- the table in a crash only forgives some callers early, so it is unlogged.
+CREATE UNLOGGED TABLE IF NOT EXISTS coupon_attempts (
+    subject       TEXT PRIMARY KEY,
+    codes         TEXT[] NOT NULL,
+    repeated      BOOLEAN NOT NULL DEFAULT FALSE,
+    last_at       TIMESTAMPTZ NOT NULL,
+    blocked_until TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_attempts_last_at ON coupon_attempts (last_at);
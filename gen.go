@@ -1,3 +1,4 @@
 package kart
 
 //go:generate go run github.com/ogen-go/ogen/cmd/ogen --clean --package oas --target gen/oas api/openapi.yaml
+//go:generate go run github.com/ogen-go/ogen/cmd/ogen --clean --package oasv2 --target gen/oasv2 api/openapi-v2.yaml
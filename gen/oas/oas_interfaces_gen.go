@@ -1,10 +1,82 @@
 // Code generated by ogen, DO NOT EDIT.
 package oas
 
+type AttachCartCouponRes interface {
+	attachCartCouponRes()
+}
+
+type CancelOrderRes interface {
+	cancelOrderRes()
+}
+
+type CheckoutCartRes interface {
+	checkoutCartRes()
+}
+
+type CreateCartRes interface {
+	createCartRes()
+}
+
+type CreateCouponRes interface {
+	createCouponRes()
+}
+
+type DeactivateCouponRes interface {
+	deactivateCouponRes()
+}
+
+type DetachCartCouponRes interface {
+	detachCartCouponRes()
+}
+
+type GetCartRes interface {
+	getCartRes()
+}
+
+type GetCouponRes interface {
+	getCouponRes()
+}
+
+type GetOrderRes interface {
+	getOrderRes()
+}
+
 type GetProductRes interface {
 	getProductRes()
 }
 
+type ListAuditEventsRes interface {
+	listAuditEventsRes()
+}
+
+type ListCouponsRes interface {
+	listCouponsRes()
+}
+
+type ListOrdersRes interface {
+	listOrdersRes()
+}
+
 type PlaceOrderRes interface {
 	placeOrderRes()
 }
+
+type QuoteOrderRes interface {
+	quoteOrderRes()
+}
+
+type RemoveCartItemRes interface {
+	removeCartItemRes()
+}
+
+type SetCartItemRes interface {
+	setCartItemRes()
+}
+
+type UpdateCouponRes interface {
+	updateCouponRes()
+}
+
+type UpdateOrderStatusRes interface {
+	updateOrderStatusRes()
+}
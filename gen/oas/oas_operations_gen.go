@@ -6,7 +6,25 @@ package oas
 type OperationName = string
 
 const (
-	GetProductOperation   OperationName = "GetProduct"
-	ListProductsOperation OperationName = "ListProducts"
-	PlaceOrderOperation   OperationName = "PlaceOrder"
+	AttachCartCouponOperation  OperationName = "AttachCartCoupon"
+	CancelOrderOperation       OperationName = "CancelOrder"
+	CheckoutCartOperation      OperationName = "CheckoutCart"
+	CreateCartOperation        OperationName = "CreateCart"
+	CreateCouponOperation      OperationName = "CreateCoupon"
+	DeactivateCouponOperation  OperationName = "DeactivateCoupon"
+	DetachCartCouponOperation  OperationName = "DetachCartCoupon"
+	GetCartOperation           OperationName = "GetCart"
+	GetCouponOperation         OperationName = "GetCoupon"
+	GetOrderOperation          OperationName = "GetOrder"
+	GetProductOperation        OperationName = "GetProduct"
+	ListAuditEventsOperation   OperationName = "ListAuditEvents"
+	ListCouponsOperation       OperationName = "ListCoupons"
+	ListOrdersOperation        OperationName = "ListOrders"
+	ListProductsOperation      OperationName = "ListProducts"
+	PlaceOrderOperation        OperationName = "PlaceOrder"
+	QuoteOrderOperation        OperationName = "QuoteOrder"
+	RemoveCartItemOperation    OperationName = "RemoveCartItem"
+	SetCartItemOperation       OperationName = "SetCartItem"
+	UpdateCouponOperation      OperationName = "UpdateCoupon"
+	UpdateOrderStatusOperation OperationName = "UpdateOrderStatus"
 )
@@ -2,6 +2,12 @@
 
 package oas
 
+import (
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
 type APIKey struct {
 	APIKey string
 	Roles  []string
@@ -27,6 +33,1385 @@ func (s *APIKey) SetRoles(val []string) {
 	s.Roles = val
 }
 
+// Ref: #/components/schemas/AppliedCoupon
+type AppliedCoupon struct {
+	Code        string    `json:"code"`
+	Description OptString `json:"description"`
+	// This coupon's part of the order discount.
+	Discount float64 `json:"discount"`
+}
+
+// GetCode returns the value of Code.
+func (s *AppliedCoupon) GetCode() string {
+	return s.Code
+}
+
+// GetDescription returns the value of Description.
+func (s *AppliedCoupon) GetDescription() OptString {
+	return s.Description
+}
+
+// GetDiscount returns the value of Discount.
+func (s *AppliedCoupon) GetDiscount() float64 {
+	return s.Discount
+}
+
+// SetCode sets the value of Code.
+func (s *AppliedCoupon) SetCode(val string) {
+	s.Code = val
+}
+
+// SetDescription sets the value of Description.
+func (s *AppliedCoupon) SetDescription(val OptString) {
+	s.Description = val
+}
+
+// SetDiscount sets the value of Discount.
+func (s *AppliedCoupon) SetDiscount(val float64) {
+	s.Discount = val
+}
+
+type AttachCartCouponForbidden Error
+
+func (*AttachCartCouponForbidden) attachCartCouponRes() {}
+
+type AttachCartCouponNotFound Error
+
+func (*AttachCartCouponNotFound) attachCartCouponRes() {}
+
+type AttachCartCouponUnauthorized Error
+
+func (*AttachCartCouponUnauthorized) attachCartCouponRes() {}
+
+type AttachCartCouponUnprocessableEntity Error
+
+func (*AttachCartCouponUnprocessableEntity) attachCartCouponRes() {}
+
+// Ref: #/components/schemas/AuditEvent
+type AuditEvent struct {
+	ID int64 `json:"id"`
+	// API key ID, or `jwt:<sub>` for bearer tokens.
+	Actor string `json:"actor"`
+	// OpenAPI operation ID.
+	Operation string `json:"operation"`
+	// X-Request-ID of the audited request.
+	RequestId OptString `json:"requestId"`
+	// Client address, resolved through trusted proxies.
+	ClientIp   OptString               `json:"clientIp"`
+	TargetType OptAuditEventTargetType `json:"targetType"`
+	// ID of the entity acted on; absent when none was known.
+	TargetId OptString         `json:"targetId"`
+	Outcome  AuditEventOutcome `json:"outcome"`
+	// HTTP status of the response.
+	StatusCode int       `json:"statusCode"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// GetID returns the value of ID.
+func (s *AuditEvent) GetID() int64 {
+	return s.ID
+}
+
+// GetActor returns the value of Actor.
+func (s *AuditEvent) GetActor() string {
+	return s.Actor
+}
+
+// GetOperation returns the value of Operation.
+func (s *AuditEvent) GetOperation() string {
+	return s.Operation
+}
+
+// GetRequestId returns the value of RequestId.
+func (s *AuditEvent) GetRequestId() OptString {
+	return s.RequestId
+}
+
+// GetClientIp returns the value of ClientIp.
+func (s *AuditEvent) GetClientIp() OptString {
+	return s.ClientIp
+}
+
+// GetTargetType returns the value of TargetType.
+func (s *AuditEvent) GetTargetType() OptAuditEventTargetType {
+	return s.TargetType
+}
+
+// GetTargetId returns the value of TargetId.
+func (s *AuditEvent) GetTargetId() OptString {
+	return s.TargetId
+}
+
+// GetOutcome returns the value of Outcome.
+func (s *AuditEvent) GetOutcome() AuditEventOutcome {
+	return s.Outcome
+}
+
+// GetStatusCode returns the value of StatusCode.
+func (s *AuditEvent) GetStatusCode() int {
+	return s.StatusCode
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *AuditEvent) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
+// SetID sets the value of ID.
+func (s *AuditEvent) SetID(val int64) {
+	s.ID = val
+}
+
+// SetActor sets the value of Actor.
+func (s *AuditEvent) SetActor(val string) {
+	s.Actor = val
+}
+
+// SetOperation sets the value of Operation.
+func (s *AuditEvent) SetOperation(val string) {
+	s.Operation = val
+}
+
+// SetRequestId sets the value of RequestId.
+func (s *AuditEvent) SetRequestId(val OptString) {
+	s.RequestId = val
+}
+
+// SetClientIp sets the value of ClientIp.
+func (s *AuditEvent) SetClientIp(val OptString) {
+	s.ClientIp = val
+}
+
+// SetTargetType sets the value of TargetType.
+func (s *AuditEvent) SetTargetType(val OptAuditEventTargetType) {
+	s.TargetType = val
+}
+
+// SetTargetId sets the value of TargetId.
+func (s *AuditEvent) SetTargetId(val OptString) {
+	s.TargetId = val
+}
+
+// SetOutcome sets the value of Outcome.
+func (s *AuditEvent) SetOutcome(val AuditEventOutcome) {
+	s.Outcome = val
+}
+
+// SetStatusCode sets the value of StatusCode.
+func (s *AuditEvent) SetStatusCode(val int) {
+	s.StatusCode = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *AuditEvent) SetCreatedAt(val time.Time) {
+	s.CreatedAt = val
+}
+
+// Ref: #/components/schemas/AuditEventList
+type AuditEventList struct {
+	Events []AuditEvent `json:"events"`
+	// Cursor for the next page; absent on the last page.
+	NextCursor OptString `json:"nextCursor"`
+}
+
+// GetEvents returns the value of Events.
+func (s *AuditEventList) GetEvents() []AuditEvent {
+	return s.Events
+}
+
+// GetNextCursor returns the value of NextCursor.
+func (s *AuditEventList) GetNextCursor() OptString {
+	return s.NextCursor
+}
+
+// SetEvents sets the value of Events.
+func (s *AuditEventList) SetEvents(val []AuditEvent) {
+	s.Events = val
+}
+
+// SetNextCursor sets the value of NextCursor.
+func (s *AuditEventList) SetNextCursor(val OptString) {
+	s.NextCursor = val
+}
+
+func (*AuditEventList) listAuditEventsRes() {}
+
+type AuditEventOutcome string
+
+const (
+	AuditEventOutcomeSuccess  AuditEventOutcome = "success"
+	AuditEventOutcomeDenied   AuditEventOutcome = "denied"
+	AuditEventOutcomeRejected AuditEventOutcome = "rejected"
+	AuditEventOutcomeFailed   AuditEventOutcome = "failed"
+)
+
+// AllValues returns all AuditEventOutcome values.
+func (AuditEventOutcome) AllValues() []AuditEventOutcome {
+	return []AuditEventOutcome{
+		AuditEventOutcomeSuccess,
+		AuditEventOutcomeDenied,
+		AuditEventOutcomeRejected,
+		AuditEventOutcomeFailed,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s AuditEventOutcome) MarshalText() ([]byte, error) {
+	switch s {
+	case AuditEventOutcomeSuccess:
+		return []byte(s), nil
+	case AuditEventOutcomeDenied:
+		return []byte(s), nil
+	case AuditEventOutcomeRejected:
+		return []byte(s), nil
+	case AuditEventOutcomeFailed:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *AuditEventOutcome) UnmarshalText(data []byte) error {
+	switch AuditEventOutcome(data) {
+	case AuditEventOutcomeSuccess:
+		*s = AuditEventOutcomeSuccess
+		return nil
+	case AuditEventOutcomeDenied:
+		*s = AuditEventOutcomeDenied
+		return nil
+	case AuditEventOutcomeRejected:
+		*s = AuditEventOutcomeRejected
+		return nil
+	case AuditEventOutcomeFailed:
+		*s = AuditEventOutcomeFailed
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+type AuditEventTargetType string
+
+const (
+	AuditEventTargetTypeOrder  AuditEventTargetType = "order"
+	AuditEventTargetTypeCart   AuditEventTargetType = "cart"
+	AuditEventTargetTypeCoupon AuditEventTargetType = "coupon"
+)
+
+// AllValues returns all AuditEventTargetType values.
+func (AuditEventTargetType) AllValues() []AuditEventTargetType {
+	return []AuditEventTargetType{
+		AuditEventTargetTypeOrder,
+		AuditEventTargetTypeCart,
+		AuditEventTargetTypeCoupon,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s AuditEventTargetType) MarshalText() ([]byte, error) {
+	switch s {
+	case AuditEventTargetTypeOrder:
+		return []byte(s), nil
+	case AuditEventTargetTypeCart:
+		return []byte(s), nil
+	case AuditEventTargetTypeCoupon:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *AuditEventTargetType) UnmarshalText(data []byte) error {
+	switch AuditEventTargetType(data) {
+	case AuditEventTargetTypeOrder:
+		*s = AuditEventTargetTypeOrder
+		return nil
+	case AuditEventTargetTypeCart:
+		*s = AuditEventTargetTypeCart
+		return nil
+	case AuditEventTargetTypeCoupon:
+		*s = AuditEventTargetTypeCoupon
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+type BearerAuth struct {
+	Token string
+	Roles []string
+}
+
+// GetToken returns the value of Token.
+func (s *BearerAuth) GetToken() string {
+	return s.Token
+}
+
+// GetRoles returns the value of Roles.
+func (s *BearerAuth) GetRoles() []string {
+	return s.Roles
+}
+
+// SetToken sets the value of Token.
+func (s *BearerAuth) SetToken(val string) {
+	s.Token = val
+}
+
+// SetRoles sets the value of Roles.
+func (s *BearerAuth) SetRoles(val []string) {
+	s.Roles = val
+}
+
+type CancelOrderConflict Error
+
+func (*CancelOrderConflict) cancelOrderRes() {}
+
+type CancelOrderForbidden Error
+
+func (*CancelOrderForbidden) cancelOrderRes() {}
+
+type CancelOrderNotFound Error
+
+func (*CancelOrderNotFound) cancelOrderRes() {}
+
+type CancelOrderUnauthorized Error
+
+func (*CancelOrderUnauthorized) cancelOrderRes() {}
+
+// Ref: #/components/schemas/Cart
+type Cart struct {
+	ID string `json:"id"`
+	// Cart lines priced against the current catalog.
+	Items    []OrderItem `json:"items"`
+	Products []Product   `json:"products"`
+	// Sum of line subtotals before discount.
+	Subtotal float64 `json:"subtotal"`
+	// Discount the coupon would give at checkout; 0 when none applies.
+	Discount float64 `json:"discount"`
+	// Amount that would be charged at checkout.
+	Total float64 `json:"total"`
+	// Coupon code attached to the cart.
+	CouponCode OptString `json:"couponCode"`
+	// Human-readable description of the applied coupon.
+	CouponDescription OptString `json:"couponDescription"`
+	// Why the attached coupon does not apply; absent when it does.
+	CouponError OptString   `json:"couponError"`
+	CreatedAt   OptDateTime `json:"createdAt"`
+	UpdatedAt   OptDateTime `json:"updatedAt"`
+	// The cart is deleted if left unchanged until this time.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// GetID returns the value of ID.
+func (s *Cart) GetID() string {
+	return s.ID
+}
+
+// GetItems returns the value of Items.
+func (s *Cart) GetItems() []OrderItem {
+	return s.Items
+}
+
+// GetProducts returns the value of Products.
+func (s *Cart) GetProducts() []Product {
+	return s.Products
+}
+
+// GetSubtotal returns the value of Subtotal.
+func (s *Cart) GetSubtotal() float64 {
+	return s.Subtotal
+}
+
+// GetDiscount returns the value of Discount.
+func (s *Cart) GetDiscount() float64 {
+	return s.Discount
+}
+
+// GetTotal returns the value of Total.
+func (s *Cart) GetTotal() float64 {
+	return s.Total
+}
+
+// GetCouponCode returns the value of CouponCode.
+func (s *Cart) GetCouponCode() OptString {
+	return s.CouponCode
+}
+
+// GetCouponDescription returns the value of CouponDescription.
+func (s *Cart) GetCouponDescription() OptString {
+	return s.CouponDescription
+}
+
+// GetCouponError returns the value of CouponError.
+func (s *Cart) GetCouponError() OptString {
+	return s.CouponError
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *Cart) GetCreatedAt() OptDateTime {
+	return s.CreatedAt
+}
+
+// GetUpdatedAt returns the value of UpdatedAt.
+func (s *Cart) GetUpdatedAt() OptDateTime {
+	return s.UpdatedAt
+}
+
+// GetExpiresAt returns the value of ExpiresAt.
+func (s *Cart) GetExpiresAt() time.Time {
+	return s.ExpiresAt
+}
+
+// SetID sets the value of ID.
+func (s *Cart) SetID(val string) {
+	s.ID = val
+}
+
+// SetItems sets the value of Items.
+func (s *Cart) SetItems(val []OrderItem) {
+	s.Items = val
+}
+
+// SetProducts sets the value of Products.
+func (s *Cart) SetProducts(val []Product) {
+	s.Products = val
+}
+
+// SetSubtotal sets the value of Subtotal.
+func (s *Cart) SetSubtotal(val float64) {
+	s.Subtotal = val
+}
+
+// SetDiscount sets the value of Discount.
+func (s *Cart) SetDiscount(val float64) {
+	s.Discount = val
+}
+
+// SetTotal sets the value of Total.
+func (s *Cart) SetTotal(val float64) {
+	s.Total = val
+}
+
+// SetCouponCode sets the value of CouponCode.
+func (s *Cart) SetCouponCode(val OptString) {
+	s.CouponCode = val
+}
+
+// SetCouponDescription sets the value of CouponDescription.
+func (s *Cart) SetCouponDescription(val OptString) {
+	s.CouponDescription = val
+}
+
+// SetCouponError sets the value of CouponError.
+func (s *Cart) SetCouponError(val OptString) {
+	s.CouponError = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *Cart) SetCreatedAt(val OptDateTime) {
+	s.CreatedAt = val
+}
+
+// SetUpdatedAt sets the value of UpdatedAt.
+func (s *Cart) SetUpdatedAt(val OptDateTime) {
+	s.UpdatedAt = val
+}
+
+// SetExpiresAt sets the value of ExpiresAt.
+func (s *Cart) SetExpiresAt(val time.Time) {
+	s.ExpiresAt = val
+}
+
+func (*Cart) attachCartCouponRes() {}
+func (*Cart) createCartRes()       {}
+func (*Cart) detachCartCouponRes() {}
+func (*Cart) getCartRes()          {}
+func (*Cart) removeCartItemRes()   {}
+func (*Cart) setCartItemRes()      {}
+
+// Ref: #/components/schemas/CartCoupon
+type CartCoupon struct {
+	// Promo code to apply at checkout.
+	Code string `json:"code"`
+}
+
+// GetCode returns the value of Code.
+func (s *CartCoupon) GetCode() string {
+	return s.Code
+}
+
+// SetCode sets the value of Code.
+func (s *CartCoupon) SetCode(val string) {
+	s.Code = val
+}
+
+// Ref: #/components/schemas/CartItemUpdate
+type CartItemUpdate struct {
+	// New item count.
+	Quantity int `json:"quantity"`
+}
+
+// GetQuantity returns the value of Quantity.
+func (s *CartItemUpdate) GetQuantity() int {
+	return s.Quantity
+}
+
+// SetQuantity sets the value of Quantity.
+func (s *CartItemUpdate) SetQuantity(val int) {
+	s.Quantity = val
+}
+
+type CheckoutCartBadRequest Error
+
+func (*CheckoutCartBadRequest) checkoutCartRes() {}
+
+type CheckoutCartForbidden Error
+
+func (*CheckoutCartForbidden) checkoutCartRes() {}
+
+type CheckoutCartNotFound Error
+
+func (*CheckoutCartNotFound) checkoutCartRes() {}
+
+type CheckoutCartUnauthorized Error
+
+func (*CheckoutCartUnauthorized) checkoutCartRes() {}
+
+type CheckoutCartUnprocessableEntity Error
+
+func (*CheckoutCartUnprocessableEntity) checkoutCartRes() {}
+
+// Merged schema.
+// Ref: #/components/schemas/Coupon
+type Coupon struct {
+	Code         string       `json:"code"`
+	Active       bool         `json:"active"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	Usage        CouponUsage  `json:"usage"`
+	DiscountType DiscountType `json:"discountType"`
+	// Percentage (at most 100) or amount off; 0 for `free_lowest`.
+	Value float64 `json:"value"`
+	// Targeted items the cart must hold; 0 for no minimum.
+	MinItems OptInt `json:"minItems"`
+	// Shown to customers when the coupon applies.
+	Description OptString `json:"description"`
+	// Start of the validity window; absent for no start.
+	ValidFrom OptDateTime `json:"validFrom"`
+	// End of the validity window; absent for no end.
+	ValidUntil OptDateTime `json:"validUntil"`
+	// How many orders may use the coupon; 0 for unlimited.
+	MaxUses OptInt `json:"maxUses"`
+	// How many orders one API key may use the coupon on; 0 for unlimited.
+	MaxUsesPerCustomer OptInt `json:"maxUsesPerCustomer"`
+	// Whether the coupon can be combined with other stackable coupons on one order.
+	Stackable OptBool `json:"stackable"`
+	// Order among stacked coupons of the same kind; higher applies first.
+	Priority OptInt `json:"priority"`
+	// Product IDs the coupon applies to. When this or `includeCategories` is set, other items are not
+	// discounted.
+	IncludeProducts []string `json:"includeProducts"`
+	// Product categories the coupon applies to, matched ignoring case.
+	IncludeCategories []string `json:"includeCategories"`
+	// Product IDs the coupon never applies to, even if included.
+	ExcludeProducts []string `json:"excludeProducts"`
+	// Product categories the coupon never applies to, even if included.
+	ExcludeCategories []string `json:"excludeCategories"`
+	// Largest discount per order; 0 for no cap. When stacked, it also caps the combined discount.
+	MaxDiscount OptFloat64 `json:"maxDiscount"`
+}
+
+// GetCode returns the value of Code.
+func (s *Coupon) GetCode() string {
+	return s.Code
+}
+
+// GetActive returns the value of Active.
+func (s *Coupon) GetActive() bool {
+	return s.Active
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *Coupon) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
+// GetUsage returns the value of Usage.
+func (s *Coupon) GetUsage() CouponUsage {
+	return s.Usage
+}
+
+// GetDiscountType returns the value of DiscountType.
+func (s *Coupon) GetDiscountType() DiscountType {
+	return s.DiscountType
+}
+
+// GetValue returns the value of Value.
+func (s *Coupon) GetValue() float64 {
+	return s.Value
+}
+
+// GetMinItems returns the value of MinItems.
+func (s *Coupon) GetMinItems() OptInt {
+	return s.MinItems
+}
+
+// GetDescription returns the value of Description.
+func (s *Coupon) GetDescription() OptString {
+	return s.Description
+}
+
+// GetValidFrom returns the value of ValidFrom.
+func (s *Coupon) GetValidFrom() OptDateTime {
+	return s.ValidFrom
+}
+
+// GetValidUntil returns the value of ValidUntil.
+func (s *Coupon) GetValidUntil() OptDateTime {
+	return s.ValidUntil
+}
+
+// GetMaxUses returns the value of MaxUses.
+func (s *Coupon) GetMaxUses() OptInt {
+	return s.MaxUses
+}
+
+// GetMaxUsesPerCustomer returns the value of MaxUsesPerCustomer.
+func (s *Coupon) GetMaxUsesPerCustomer() OptInt {
+	return s.MaxUsesPerCustomer
+}
+
+// GetStackable returns the value of Stackable.
+func (s *Coupon) GetStackable() OptBool {
+	return s.Stackable
+}
+
+// GetPriority returns the value of Priority.
+func (s *Coupon) GetPriority() OptInt {
+	return s.Priority
+}
+
+// GetIncludeProducts returns the value of IncludeProducts.
+func (s *Coupon) GetIncludeProducts() []string {
+	return s.IncludeProducts
+}
+
+// GetIncludeCategories returns the value of IncludeCategories.
+func (s *Coupon) GetIncludeCategories() []string {
+	return s.IncludeCategories
+}
+
+// GetExcludeProducts returns the value of ExcludeProducts.
+func (s *Coupon) GetExcludeProducts() []string {
+	return s.ExcludeProducts
+}
+
+// GetExcludeCategories returns the value of ExcludeCategories.
+func (s *Coupon) GetExcludeCategories() []string {
+	return s.ExcludeCategories
+}
+
+// GetMaxDiscount returns the value of MaxDiscount.
+func (s *Coupon) GetMaxDiscount() OptFloat64 {
+	return s.MaxDiscount
+}
+
+// SetCode sets the value of Code.
+func (s *Coupon) SetCode(val string) {
+	s.Code = val
+}
+
+// SetActive sets the value of Active.
+func (s *Coupon) SetActive(val bool) {
+	s.Active = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *Coupon) SetCreatedAt(val time.Time) {
+	s.CreatedAt = val
+}
+
+// SetUsage sets the value of Usage.
+func (s *Coupon) SetUsage(val CouponUsage) {
+	s.Usage = val
+}
+
+// SetDiscountType sets the value of DiscountType.
+func (s *Coupon) SetDiscountType(val DiscountType) {
+	s.DiscountType = val
+}
+
+// SetValue sets the value of Value.
+func (s *Coupon) SetValue(val float64) {
+	s.Value = val
+}
+
+// SetMinItems sets the value of MinItems.
+func (s *Coupon) SetMinItems(val OptInt) {
+	s.MinItems = val
+}
+
+// SetDescription sets the value of Description.
+func (s *Coupon) SetDescription(val OptString) {
+	s.Description = val
+}
+
+// SetValidFrom sets the value of ValidFrom.
+func (s *Coupon) SetValidFrom(val OptDateTime) {
+	s.ValidFrom = val
+}
+
+// SetValidUntil sets the value of ValidUntil.
+func (s *Coupon) SetValidUntil(val OptDateTime) {
+	s.ValidUntil = val
+}
+
+// SetMaxUses sets the value of MaxUses.
+func (s *Coupon) SetMaxUses(val OptInt) {
+	s.MaxUses = val
+}
+
+// SetMaxUsesPerCustomer sets the value of MaxUsesPerCustomer.
+func (s *Coupon) SetMaxUsesPerCustomer(val OptInt) {
+	s.MaxUsesPerCustomer = val
+}
+
+// SetStackable sets the value of Stackable.
+func (s *Coupon) SetStackable(val OptBool) {
+	s.Stackable = val
+}
+
+// SetPriority sets the value of Priority.
+func (s *Coupon) SetPriority(val OptInt) {
+	s.Priority = val
+}
+
+// SetIncludeProducts sets the value of IncludeProducts.
+func (s *Coupon) SetIncludeProducts(val []string) {
+	s.IncludeProducts = val
+}
+
+// SetIncludeCategories sets the value of IncludeCategories.
+func (s *Coupon) SetIncludeCategories(val []string) {
+	s.IncludeCategories = val
+}
+
+// SetExcludeProducts sets the value of ExcludeProducts.
+func (s *Coupon) SetExcludeProducts(val []string) {
+	s.ExcludeProducts = val
+}
+
+// SetExcludeCategories sets the value of ExcludeCategories.
+func (s *Coupon) SetExcludeCategories(val []string) {
+	s.ExcludeCategories = val
+}
+
+// SetMaxDiscount sets the value of MaxDiscount.
+func (s *Coupon) SetMaxDiscount(val OptFloat64) {
+	s.MaxDiscount = val
+}
+
+func (*Coupon) createCouponRes()     {}
+func (*Coupon) deactivateCouponRes() {}
+func (*Coupon) getCouponRes()        {}
+func (*Coupon) updateCouponRes()     {}
+
+// CouponAttemptsExceededHeaders wraps Error with response headers.
+type CouponAttemptsExceededHeaders struct {
+	RetryAfter int
+	Response   Error
+}
+
+// GetRetryAfter returns the value of RetryAfter.
+func (s *CouponAttemptsExceededHeaders) GetRetryAfter() int {
+	return s.RetryAfter
+}
+
+// GetResponse returns the value of Response.
+func (s *CouponAttemptsExceededHeaders) GetResponse() Error {
+	return s.Response
+}
+
+// SetRetryAfter sets the value of RetryAfter.
+func (s *CouponAttemptsExceededHeaders) SetRetryAfter(val int) {
+	s.RetryAfter = val
+}
+
+// SetResponse sets the value of Response.
+func (s *CouponAttemptsExceededHeaders) SetResponse(val Error) {
+	s.Response = val
+}
+
+func (*CouponAttemptsExceededHeaders) attachCartCouponRes() {}
+func (*CouponAttemptsExceededHeaders) placeOrderRes()       {}
+func (*CouponAttemptsExceededHeaders) quoteOrderRes()       {}
+
+// Merged schema.
+// Ref: #/components/schemas/CouponCreate
+type CouponCreate struct {
+	// Letters and digits, up to 32.
+	Code         string       `json:"code"`
+	DiscountType DiscountType `json:"discountType"`
+	// Percentage (at most 100) or amount off; 0 for `free_lowest`.
+	Value float64 `json:"value"`
+	// Targeted items the cart must hold; 0 for no minimum.
+	MinItems OptInt `json:"minItems"`
+	// Shown to customers when the coupon applies.
+	Description OptString `json:"description"`
+	// Start of the validity window; absent for no start.
+	ValidFrom OptDateTime `json:"validFrom"`
+	// End of the validity window; absent for no end.
+	ValidUntil OptDateTime `json:"validUntil"`
+	// How many orders may use the coupon; 0 for unlimited.
+	MaxUses OptInt `json:"maxUses"`
+	// How many orders one API key may use the coupon on; 0 for unlimited.
+	MaxUsesPerCustomer OptInt `json:"maxUsesPerCustomer"`
+	// Whether the coupon can be combined with other stackable coupons on one order.
+	Stackable OptBool `json:"stackable"`
+	// Order among stacked coupons of the same kind; higher applies first.
+	Priority OptInt `json:"priority"`
+	// Product IDs the coupon applies to. When this or `includeCategories` is set, other items are not
+	// discounted.
+	IncludeProducts []string `json:"includeProducts"`
+	// Product categories the coupon applies to, matched ignoring case.
+	IncludeCategories []string `json:"includeCategories"`
+	// Product IDs the coupon never applies to, even if included.
+	ExcludeProducts []string `json:"excludeProducts"`
+	// Product categories the coupon never applies to, even if included.
+	ExcludeCategories []string `json:"excludeCategories"`
+	// Largest discount per order; 0 for no cap. When stacked, it also caps the combined discount.
+	MaxDiscount OptFloat64 `json:"maxDiscount"`
+}
+
+// GetCode returns the value of Code.
+func (s *CouponCreate) GetCode() string {
+	return s.Code
+}
+
+// GetDiscountType returns the value of DiscountType.
+func (s *CouponCreate) GetDiscountType() DiscountType {
+	return s.DiscountType
+}
+
+// GetValue returns the value of Value.
+func (s *CouponCreate) GetValue() float64 {
+	return s.Value
+}
+
+// GetMinItems returns the value of MinItems.
+func (s *CouponCreate) GetMinItems() OptInt {
+	return s.MinItems
+}
+
+// GetDescription returns the value of Description.
+func (s *CouponCreate) GetDescription() OptString {
+	return s.Description
+}
+
+// GetValidFrom returns the value of ValidFrom.
+func (s *CouponCreate) GetValidFrom() OptDateTime {
+	return s.ValidFrom
+}
+
+// GetValidUntil returns the value of ValidUntil.
+func (s *CouponCreate) GetValidUntil() OptDateTime {
+	return s.ValidUntil
+}
+
+// GetMaxUses returns the value of MaxUses.
+func (s *CouponCreate) GetMaxUses() OptInt {
+	return s.MaxUses
+}
+
+// GetMaxUsesPerCustomer returns the value of MaxUsesPerCustomer.
+func (s *CouponCreate) GetMaxUsesPerCustomer() OptInt {
+	return s.MaxUsesPerCustomer
+}
+
+// GetStackable returns the value of Stackable.
+func (s *CouponCreate) GetStackable() OptBool {
+	return s.Stackable
+}
+
+// GetPriority returns the value of Priority.
+func (s *CouponCreate) GetPriority() OptInt {
+	return s.Priority
+}
+
+// GetIncludeProducts returns the value of IncludeProducts.
+func (s *CouponCreate) GetIncludeProducts() []string {
+	return s.IncludeProducts
+}
+
+// GetIncludeCategories returns the value of IncludeCategories.
+func (s *CouponCreate) GetIncludeCategories() []string {
+	return s.IncludeCategories
+}
+
+// GetExcludeProducts returns the value of ExcludeProducts.
+func (s *CouponCreate) GetExcludeProducts() []string {
+	return s.ExcludeProducts
+}
+
+// GetExcludeCategories returns the value of ExcludeCategories.
+func (s *CouponCreate) GetExcludeCategories() []string {
+	return s.ExcludeCategories
+}
+
+// GetMaxDiscount returns the value of MaxDiscount.
+func (s *CouponCreate) GetMaxDiscount() OptFloat64 {
+	return s.MaxDiscount
+}
+
+// SetCode sets the value of Code.
+func (s *CouponCreate) SetCode(val string) {
+	s.Code = val
+}
+
+// SetDiscountType sets the value of DiscountType.
+func (s *CouponCreate) SetDiscountType(val DiscountType) {
+	s.DiscountType = val
+}
+
+// SetValue sets the value of Value.
+func (s *CouponCreate) SetValue(val float64) {
+	s.Value = val
+}
+
+// SetMinItems sets the value of MinItems.
+func (s *CouponCreate) SetMinItems(val OptInt) {
+	s.MinItems = val
+}
+
+// SetDescription sets the value of Description.
+func (s *CouponCreate) SetDescription(val OptString) {
+	s.Description = val
+}
+
+// SetValidFrom sets the value of ValidFrom.
+func (s *CouponCreate) SetValidFrom(val OptDateTime) {
+	s.ValidFrom = val
+}
+
+// SetValidUntil sets the value of ValidUntil.
+func (s *CouponCreate) SetValidUntil(val OptDateTime) {
+	s.ValidUntil = val
+}
+
+// SetMaxUses sets the value of MaxUses.
+func (s *CouponCreate) SetMaxUses(val OptInt) {
+	s.MaxUses = val
+}
+
+// SetMaxUsesPerCustomer sets the value of MaxUsesPerCustomer.
+func (s *CouponCreate) SetMaxUsesPerCustomer(val OptInt) {
+	s.MaxUsesPerCustomer = val
+}
+
+// SetStackable sets the value of Stackable.
+func (s *CouponCreate) SetStackable(val OptBool) {
+	s.Stackable = val
+}
+
+// SetPriority sets the value of Priority.
+func (s *CouponCreate) SetPriority(val OptInt) {
+	s.Priority = val
+}
+
+// SetIncludeProducts sets the value of IncludeProducts.
+func (s *CouponCreate) SetIncludeProducts(val []string) {
+	s.IncludeProducts = val
+}
+
+// SetIncludeCategories sets the value of IncludeCategories.
+func (s *CouponCreate) SetIncludeCategories(val []string) {
+	s.IncludeCategories = val
+}
+
+// SetExcludeProducts sets the value of ExcludeProducts.
+func (s *CouponCreate) SetExcludeProducts(val []string) {
+	s.ExcludeProducts = val
+}
+
+// SetExcludeCategories sets the value of ExcludeCategories.
+func (s *CouponCreate) SetExcludeCategories(val []string) {
+	s.ExcludeCategories = val
+}
+
+// SetMaxDiscount sets the value of MaxDiscount.
+func (s *CouponCreate) SetMaxDiscount(val OptFloat64) {
+	s.MaxDiscount = val
+}
+
+// Ref: #/components/schemas/CouponList
+type CouponList struct {
+	Coupons []Coupon `json:"coupons"`
+	// Cursor for the next page; absent on the last page.
+	NextCursor OptString `json:"nextCursor"`
+}
+
+// GetCoupons returns the value of Coupons.
+func (s *CouponList) GetCoupons() []Coupon {
+	return s.Coupons
+}
+
+// GetNextCursor returns the value of NextCursor.
+func (s *CouponList) GetNextCursor() OptString {
+	return s.NextCursor
+}
+
+// SetCoupons sets the value of Coupons.
+func (s *CouponList) SetCoupons(val []Coupon) {
+	s.Coupons = val
+}
+
+// SetNextCursor sets the value of NextCursor.
+func (s *CouponList) SetNextCursor(val OptString) {
+	s.NextCursor = val
+}
+
+func (*CouponList) listCouponsRes() {}
+
+// Ref: #/components/schemas/CouponRule
+type CouponRule struct {
+	DiscountType DiscountType `json:"discountType"`
+	// Percentage (at most 100) or amount off; 0 for `free_lowest`.
+	Value float64 `json:"value"`
+	// Targeted items the cart must hold; 0 for no minimum.
+	MinItems OptInt `json:"minItems"`
+	// Shown to customers when the coupon applies.
+	Description OptString `json:"description"`
+	// Start of the validity window; absent for no start.
+	ValidFrom OptDateTime `json:"validFrom"`
+	// End of the validity window; absent for no end.
+	ValidUntil OptDateTime `json:"validUntil"`
+	// How many orders may use the coupon; 0 for unlimited.
+	MaxUses OptInt `json:"maxUses"`
+	// How many orders one API key may use the coupon on; 0 for unlimited.
+	MaxUsesPerCustomer OptInt `json:"maxUsesPerCustomer"`
+	// Whether the coupon can be combined with other stackable coupons on one order.
+	Stackable OptBool `json:"stackable"`
+	// Order among stacked coupons of the same kind; higher applies first.
+	Priority OptInt `json:"priority"`
+	// Product IDs the coupon applies to. When this or `includeCategories` is set, other items are not
+	// discounted.
+	IncludeProducts []string `json:"includeProducts"`
+	// Product categories the coupon applies to, matched ignoring case.
+	IncludeCategories []string `json:"includeCategories"`
+	// Product IDs the coupon never applies to, even if included.
+	ExcludeProducts []string `json:"excludeProducts"`
+	// Product categories the coupon never applies to, even if included.
+	ExcludeCategories []string `json:"excludeCategories"`
+	// Largest discount per order; 0 for no cap. When stacked, it also caps the combined discount.
+	MaxDiscount OptFloat64 `json:"maxDiscount"`
+}
+
+// GetDiscountType returns the value of DiscountType.
+func (s *CouponRule) GetDiscountType() DiscountType {
+	return s.DiscountType
+}
+
+// GetValue returns the value of Value.
+func (s *CouponRule) GetValue() float64 {
+	return s.Value
+}
+
+// GetMinItems returns the value of MinItems.
+func (s *CouponRule) GetMinItems() OptInt {
+	return s.MinItems
+}
+
+// GetDescription returns the value of Description.
+func (s *CouponRule) GetDescription() OptString {
+	return s.Description
+}
+
+// GetValidFrom returns the value of ValidFrom.
+func (s *CouponRule) GetValidFrom() OptDateTime {
+	return s.ValidFrom
+}
+
+// GetValidUntil returns the value of ValidUntil.
+func (s *CouponRule) GetValidUntil() OptDateTime {
+	return s.ValidUntil
+}
+
+// GetMaxUses returns the value of MaxUses.
+func (s *CouponRule) GetMaxUses() OptInt {
+	return s.MaxUses
+}
+
+// GetMaxUsesPerCustomer returns the value of MaxUsesPerCustomer.
+func (s *CouponRule) GetMaxUsesPerCustomer() OptInt {
+	return s.MaxUsesPerCustomer
+}
+
+// GetStackable returns the value of Stackable.
+func (s *CouponRule) GetStackable() OptBool {
+	return s.Stackable
+}
+
+// GetPriority returns the value of Priority.
+func (s *CouponRule) GetPriority() OptInt {
+	return s.Priority
+}
+
+// GetIncludeProducts returns the value of IncludeProducts.
+func (s *CouponRule) GetIncludeProducts() []string {
+	return s.IncludeProducts
+}
+
+// GetIncludeCategories returns the value of IncludeCategories.
+func (s *CouponRule) GetIncludeCategories() []string {
+	return s.IncludeCategories
+}
+
+// GetExcludeProducts returns the value of ExcludeProducts.
+func (s *CouponRule) GetExcludeProducts() []string {
+	return s.ExcludeProducts
+}
+
+// GetExcludeCategories returns the value of ExcludeCategories.
+func (s *CouponRule) GetExcludeCategories() []string {
+	return s.ExcludeCategories
+}
+
+// GetMaxDiscount returns the value of MaxDiscount.
+func (s *CouponRule) GetMaxDiscount() OptFloat64 {
+	return s.MaxDiscount
+}
+
+// SetDiscountType sets the value of DiscountType.
+func (s *CouponRule) SetDiscountType(val DiscountType) {
+	s.DiscountType = val
+}
+
+// SetValue sets the value of Value.
+func (s *CouponRule) SetValue(val float64) {
+	s.Value = val
+}
+
+// SetMinItems sets the value of MinItems.
+func (s *CouponRule) SetMinItems(val OptInt) {
+	s.MinItems = val
+}
+
+// SetDescription sets the value of Description.
+func (s *CouponRule) SetDescription(val OptString) {
+	s.Description = val
+}
+
+// SetValidFrom sets the value of ValidFrom.
+func (s *CouponRule) SetValidFrom(val OptDateTime) {
+	s.ValidFrom = val
+}
+
+// SetValidUntil sets the value of ValidUntil.
+func (s *CouponRule) SetValidUntil(val OptDateTime) {
+	s.ValidUntil = val
+}
+
+// SetMaxUses sets the value of MaxUses.
+func (s *CouponRule) SetMaxUses(val OptInt) {
+	s.MaxUses = val
+}
+
+// SetMaxUsesPerCustomer sets the value of MaxUsesPerCustomer.
+func (s *CouponRule) SetMaxUsesPerCustomer(val OptInt) {
+	s.MaxUsesPerCustomer = val
+}
+
+// SetStackable sets the value of Stackable.
+func (s *CouponRule) SetStackable(val OptBool) {
+	s.Stackable = val
+}
+
+// SetPriority sets the value of Priority.
+func (s *CouponRule) SetPriority(val OptInt) {
+	s.Priority = val
+}
+
+// SetIncludeProducts sets the value of IncludeProducts.
+func (s *CouponRule) SetIncludeProducts(val []string) {
+	s.IncludeProducts = val
+}
+
+// SetIncludeCategories sets the value of IncludeCategories.
+func (s *CouponRule) SetIncludeCategories(val []string) {
+	s.IncludeCategories = val
+}
+
+// SetExcludeProducts sets the value of ExcludeProducts.
+func (s *CouponRule) SetExcludeProducts(val []string) {
+	s.ExcludeProducts = val
+}
+
+// SetExcludeCategories sets the value of ExcludeCategories.
+func (s *CouponRule) SetExcludeCategories(val []string) {
+	s.ExcludeCategories = val
+}
+
+// SetMaxDiscount sets the value of MaxDiscount.
+func (s *CouponRule) SetMaxDiscount(val OptFloat64) {
+	s.MaxDiscount = val
+}
+
+// Ref: #/components/schemas/CouponUsage
+type CouponUsage struct {
+	// Uses counted against `maxUses`.
+	Uses int `json:"uses"`
+	// Uses left; absent when unlimited.
+	Remaining OptInt `json:"remaining"`
+	// Orders placed with the coupon that were not cancelled or rejected.
+	Orders int `json:"orders"`
+	// Discount granted on those orders.
+	DiscountTotal float64 `json:"discountTotal"`
+}
+
+// GetUses returns the value of Uses.
+func (s *CouponUsage) GetUses() int {
+	return s.Uses
+}
+
+// GetRemaining returns the value of Remaining.
+func (s *CouponUsage) GetRemaining() OptInt {
+	return s.Remaining
+}
+
+// GetOrders returns the value of Orders.
+func (s *CouponUsage) GetOrders() int {
+	return s.Orders
+}
+
+// GetDiscountTotal returns the value of DiscountTotal.
+func (s *CouponUsage) GetDiscountTotal() float64 {
+	return s.DiscountTotal
+}
+
+// SetUses sets the value of Uses.
+func (s *CouponUsage) SetUses(val int) {
+	s.Uses = val
+}
+
+// SetRemaining sets the value of Remaining.
+func (s *CouponUsage) SetRemaining(val OptInt) {
+	s.Remaining = val
+}
+
+// SetOrders sets the value of Orders.
+func (s *CouponUsage) SetOrders(val int) {
+	s.Orders = val
+}
+
+// SetDiscountTotal sets the value of DiscountTotal.
+func (s *CouponUsage) SetDiscountTotal(val float64) {
+	s.DiscountTotal = val
+}
+
+type CreateCartForbidden Error
+
+func (*CreateCartForbidden) createCartRes() {}
+
+type CreateCartUnauthorized Error
+
+func (*CreateCartUnauthorized) createCartRes() {}
+
+type CreateCouponConflict Error
+
+func (*CreateCouponConflict) createCouponRes() {}
+
+type CreateCouponForbidden Error
+
+func (*CreateCouponForbidden) createCouponRes() {}
+
+type CreateCouponUnauthorized Error
+
+func (*CreateCouponUnauthorized) createCouponRes() {}
+
+type CreateCouponUnprocessableEntity Error
+
+func (*CreateCouponUnprocessableEntity) createCouponRes() {}
+
+type DeactivateCouponForbidden Error
+
+func (*DeactivateCouponForbidden) deactivateCouponRes() {}
+
+type DeactivateCouponNotFound Error
+
+func (*DeactivateCouponNotFound) deactivateCouponRes() {}
+
+type DeactivateCouponUnauthorized Error
+
+func (*DeactivateCouponUnauthorized) deactivateCouponRes() {}
+
+type DetachCartCouponForbidden Error
+
+func (*DetachCartCouponForbidden) detachCartCouponRes() {}
+
+type DetachCartCouponNotFound Error
+
+func (*DetachCartCouponNotFound) detachCartCouponRes() {}
+
+type DetachCartCouponUnauthorized Error
+
+func (*DetachCartCouponUnauthorized) detachCartCouponRes() {}
+
+type DetachCartCouponUnprocessableEntity Error
+
+func (*DetachCartCouponUnprocessableEntity) detachCartCouponRes() {}
+
+// `percentage` takes `value` percent off the subtotal, `fixed` takes
+// `value` off, and `free_lowest` makes the cheapest item free.
+// Ref: #/components/schemas/DiscountType
+type DiscountType string
+
+const (
+	DiscountTypePercentage DiscountType = "percentage"
+	DiscountTypeFixed      DiscountType = "fixed"
+	DiscountTypeFreeLowest DiscountType = "free_lowest"
+)
+
+// AllValues returns all DiscountType values.
+func (DiscountType) AllValues() []DiscountType {
+	return []DiscountType{
+		DiscountTypePercentage,
+		DiscountTypeFixed,
+		DiscountTypeFreeLowest,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s DiscountType) MarshalText() ([]byte, error) {
+	switch s {
+	case DiscountTypePercentage:
+		return []byte(s), nil
+	case DiscountTypeFixed:
+		return []byte(s), nil
+	case DiscountTypeFreeLowest:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *DiscountType) UnmarshalText(data []byte) error {
+	switch DiscountType(data) {
+	case DiscountTypePercentage:
+		*s = DiscountTypePercentage
+		return nil
+	case DiscountTypeFixed:
+		*s = DiscountTypeFixed
+		return nil
+	case DiscountTypeFreeLowest:
+		*s = DiscountTypeFreeLowest
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
 // Ref: #/components/schemas/Error
 type Error struct {
 	Code    int32  `json:"code"`
@@ -53,6 +1438,46 @@ func (s *Error) SetMessage(val string) {
 	s.Message = val
 }
 
+type GetCartForbidden Error
+
+func (*GetCartForbidden) getCartRes() {}
+
+type GetCartNotFound Error
+
+func (*GetCartNotFound) getCartRes() {}
+
+type GetCartUnauthorized Error
+
+func (*GetCartUnauthorized) getCartRes() {}
+
+type GetCartUnprocessableEntity Error
+
+func (*GetCartUnprocessableEntity) getCartRes() {}
+
+type GetCouponForbidden Error
+
+func (*GetCouponForbidden) getCouponRes() {}
+
+type GetCouponNotFound Error
+
+func (*GetCouponNotFound) getCouponRes() {}
+
+type GetCouponUnauthorized Error
+
+func (*GetCouponUnauthorized) getCouponRes() {}
+
+type GetOrderForbidden Error
+
+func (*GetOrderForbidden) getOrderRes() {}
+
+type GetOrderNotFound Error
+
+func (*GetOrderNotFound) getOrderRes() {}
+
+type GetOrderUnauthorized Error
+
+func (*GetOrderUnauthorized) getOrderRes() {}
+
 type GetProductBadRequest Error
 
 func (*GetProductBadRequest) getProductRes() {}
@@ -61,6 +1486,226 @@ type GetProductNotFound Error
 
 func (*GetProductNotFound) getProductRes() {}
 
+type ListAuditEventsBadRequest Error
+
+func (*ListAuditEventsBadRequest) listAuditEventsRes() {}
+
+type ListAuditEventsForbidden Error
+
+func (*ListAuditEventsForbidden) listAuditEventsRes() {}
+
+type ListAuditEventsUnauthorized Error
+
+func (*ListAuditEventsUnauthorized) listAuditEventsRes() {}
+
+type ListCouponsBadRequest Error
+
+func (*ListCouponsBadRequest) listCouponsRes() {}
+
+type ListCouponsForbidden Error
+
+func (*ListCouponsForbidden) listCouponsRes() {}
+
+type ListCouponsUnauthorized Error
+
+func (*ListCouponsUnauthorized) listCouponsRes() {}
+
+type ListOrdersBadRequest Error
+
+func (*ListOrdersBadRequest) listOrdersRes() {}
+
+type ListOrdersForbidden Error
+
+func (*ListOrdersForbidden) listOrdersRes() {}
+
+type ListOrdersUnauthorized Error
+
+func (*ListOrdersUnauthorized) listOrdersRes() {}
+
+// NewOptAuditEventTargetType returns new OptAuditEventTargetType with value set to v.
+func NewOptAuditEventTargetType(v AuditEventTargetType) OptAuditEventTargetType {
+	return OptAuditEventTargetType{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptAuditEventTargetType is optional AuditEventTargetType.
+type OptAuditEventTargetType struct {
+	Value AuditEventTargetType
+	Set   bool
+}
+
+// IsSet returns true if OptAuditEventTargetType was set.
+func (o OptAuditEventTargetType) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptAuditEventTargetType) Reset() {
+	var v AuditEventTargetType
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptAuditEventTargetType) SetTo(v AuditEventTargetType) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptAuditEventTargetType) Get() (v AuditEventTargetType, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptAuditEventTargetType) Or(d AuditEventTargetType) AuditEventTargetType {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptBool returns new OptBool with value set to v.
+func NewOptBool(v bool) OptBool {
+	return OptBool{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptBool is optional bool.
+type OptBool struct {
+	Value bool
+	Set   bool
+}
+
+// IsSet returns true if OptBool was set.
+func (o OptBool) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptBool) Reset() {
+	var v bool
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptBool) SetTo(v bool) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptBool) Get() (v bool, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptBool) Or(d bool) bool {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptDateTime returns new OptDateTime with value set to v.
+func NewOptDateTime(v time.Time) OptDateTime {
+	return OptDateTime{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptDateTime is optional time.Time.
+type OptDateTime struct {
+	Value time.Time
+	Set   bool
+}
+
+// IsSet returns true if OptDateTime was set.
+func (o OptDateTime) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptDateTime) Reset() {
+	var v time.Time
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptDateTime) SetTo(v time.Time) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptDateTime) Get() (v time.Time, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptDateTime) Or(d time.Time) time.Time {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptDiscountType returns new OptDiscountType with value set to v.
+func NewOptDiscountType(v DiscountType) OptDiscountType {
+	return OptDiscountType{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptDiscountType is optional DiscountType.
+type OptDiscountType struct {
+	Value DiscountType
+	Set   bool
+}
+
+// IsSet returns true if OptDiscountType was set.
+func (o OptDiscountType) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptDiscountType) Reset() {
+	var v DiscountType
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptDiscountType) SetTo(v DiscountType) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptDiscountType) Get() (v DiscountType, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptDiscountType) Or(d DiscountType) DiscountType {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
 // NewOptFloat32 returns new OptFloat32 with value set to v.
 func NewOptFloat32(v float32) OptFloat32 {
 	return OptFloat32{
@@ -125,20 +1770,112 @@ type OptFloat64 struct {
 func (o OptFloat64) IsSet() bool { return o.Set }
 
 // Reset unsets value.
-func (o *OptFloat64) Reset() {
-	var v float64
+func (o *OptFloat64) Reset() {
+	var v float64
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptFloat64) SetTo(v float64) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptFloat64) Get() (v float64, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptFloat64) Or(d float64) float64 {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptInt returns new OptInt with value set to v.
+func NewOptInt(v int) OptInt {
+	return OptInt{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptInt is optional int.
+type OptInt struct {
+	Value int
+	Set   bool
+}
+
+// IsSet returns true if OptInt was set.
+func (o OptInt) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptInt) Reset() {
+	var v int
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptInt) SetTo(v int) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptInt) Get() (v int, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptInt) Or(d int) int {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptOrderCancel returns new OptOrderCancel with value set to v.
+func NewOptOrderCancel(v OrderCancel) OptOrderCancel {
+	return OptOrderCancel{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptOrderCancel is optional OrderCancel.
+type OptOrderCancel struct {
+	Value OrderCancel
+	Set   bool
+}
+
+// IsSet returns true if OptOrderCancel was set.
+func (o OptOrderCancel) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptOrderCancel) Reset() {
+	var v OrderCancel
 	o.Value = v
 	o.Set = false
 }
 
 // SetTo sets value to v.
-func (o *OptFloat64) SetTo(v float64) {
+func (o *OptOrderCancel) SetTo(v OrderCancel) {
 	o.Set = true
 	o.Value = v
 }
 
 // Get returns value and boolean that denotes whether value was set.
-func (o OptFloat64) Get() (v float64, ok bool) {
+func (o OptOrderCancel) Get() (v OrderCancel, ok bool) {
 	if !o.Set {
 		return v, false
 	}
@@ -146,45 +1883,45 @@ func (o OptFloat64) Get() (v float64, ok bool) {
 }
 
 // Or returns value if set, or given parameter if does not.
-func (o OptFloat64) Or(d float64) float64 {
+func (o OptOrderCancel) Or(d OrderCancel) OrderCancel {
 	if v, ok := o.Get(); ok {
 		return v
 	}
 	return d
 }
 
-// NewOptInt returns new OptInt with value set to v.
-func NewOptInt(v int) OptInt {
-	return OptInt{
+// NewOptOrderStatus returns new OptOrderStatus with value set to v.
+func NewOptOrderStatus(v OrderStatus) OptOrderStatus {
+	return OptOrderStatus{
 		Value: v,
 		Set:   true,
 	}
 }
 
-// OptInt is optional int.
-type OptInt struct {
-	Value int
+// OptOrderStatus is optional OrderStatus.
+type OptOrderStatus struct {
+	Value OrderStatus
 	Set   bool
 }
 
-// IsSet returns true if OptInt was set.
-func (o OptInt) IsSet() bool { return o.Set }
+// IsSet returns true if OptOrderStatus was set.
+func (o OptOrderStatus) IsSet() bool { return o.Set }
 
 // Reset unsets value.
-func (o *OptInt) Reset() {
-	var v int
+func (o *OptOrderStatus) Reset() {
+	var v OrderStatus
 	o.Value = v
 	o.Set = false
 }
 
 // SetTo sets value to v.
-func (o *OptInt) SetTo(v int) {
+func (o *OptOrderStatus) SetTo(v OrderStatus) {
 	o.Set = true
 	o.Value = v
 }
 
 // Get returns value and boolean that denotes whether value was set.
-func (o OptInt) Get() (v int, ok bool) {
+func (o OptOrderStatus) Get() (v OrderStatus, ok bool) {
 	if !o.Set {
 		return v, false
 	}
@@ -192,7 +1929,7 @@ func (o OptInt) Get() (v int, ok bool) {
 }
 
 // Or returns value if set, or given parameter if does not.
-func (o OptInt) Or(d int) int {
+func (o OptOrderStatus) Or(d OrderStatus) OrderStatus {
 	if v, ok := o.Get(); ok {
 		return v
 	}
@@ -293,11 +2030,22 @@ func (o OptString) Or(d string) string {
 
 // Ref: #/components/schemas/Order
 type Order struct {
-	ID        OptString   `json:"id"`
-	Total     OptFloat64  `json:"total"`
-	Discounts OptFloat64  `json:"discounts"`
-	Items     []OrderItem `json:"items"`
-	Products  []Product   `json:"products"`
+	ID        OptString  `json:"id"`
+	Total     OptFloat64 `json:"total"`
+	Discounts OptFloat64 `json:"discounts"`
+	// First coupon code the order was placed with, as typed; `coupons` lists all of them.
+	CouponCode OptString `json:"couponCode"`
+	// What each coupon contributed to `discounts`, in the order they were applied.
+	Coupons []AppliedCoupon `json:"coupons"`
+	// Time the order was placed.
+	CreatedAt OptDateTime    `json:"createdAt"`
+	Status    OptOrderStatus `json:"status"`
+	// Time of the last status change.
+	StatusUpdatedAt OptDateTime `json:"statusUpdatedAt"`
+	// Status changes, oldest first. Only returned for single-order lookups.
+	StatusHistory []OrderStatusTransition `json:"statusHistory"`
+	Items         []OrderItem             `json:"items"`
+	Products      []Product               `json:"products"`
 }
 
 // GetID returns the value of ID.
@@ -315,6 +2063,36 @@ func (s *Order) GetDiscounts() OptFloat64 {
 	return s.Discounts
 }
 
+// GetCouponCode returns the value of CouponCode.
+func (s *Order) GetCouponCode() OptString {
+	return s.CouponCode
+}
+
+// GetCoupons returns the value of Coupons.
+func (s *Order) GetCoupons() []AppliedCoupon {
+	return s.Coupons
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *Order) GetCreatedAt() OptDateTime {
+	return s.CreatedAt
+}
+
+// GetStatus returns the value of Status.
+func (s *Order) GetStatus() OptOrderStatus {
+	return s.Status
+}
+
+// GetStatusUpdatedAt returns the value of StatusUpdatedAt.
+func (s *Order) GetStatusUpdatedAt() OptDateTime {
+	return s.StatusUpdatedAt
+}
+
+// GetStatusHistory returns the value of StatusHistory.
+func (s *Order) GetStatusHistory() []OrderStatusTransition {
+	return s.StatusHistory
+}
+
 // GetItems returns the value of Items.
 func (s *Order) GetItems() []OrderItem {
 	return s.Items
@@ -340,6 +2118,36 @@ func (s *Order) SetDiscounts(val OptFloat64) {
 	s.Discounts = val
 }
 
+// SetCouponCode sets the value of CouponCode.
+func (s *Order) SetCouponCode(val OptString) {
+	s.CouponCode = val
+}
+
+// SetCoupons sets the value of Coupons.
+func (s *Order) SetCoupons(val []AppliedCoupon) {
+	s.Coupons = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *Order) SetCreatedAt(val OptDateTime) {
+	s.CreatedAt = val
+}
+
+// SetStatus sets the value of Status.
+func (s *Order) SetStatus(val OptOrderStatus) {
+	s.Status = val
+}
+
+// SetStatusUpdatedAt sets the value of StatusUpdatedAt.
+func (s *Order) SetStatusUpdatedAt(val OptDateTime) {
+	s.StatusUpdatedAt = val
+}
+
+// SetStatusHistory sets the value of StatusHistory.
+func (s *Order) SetStatusHistory(val []OrderStatusTransition) {
+	s.StatusHistory = val
+}
+
 // SetItems sets the value of Items.
 func (s *Order) SetItems(val []OrderItem) {
 	s.Items = val
@@ -350,7 +2158,27 @@ func (s *Order) SetProducts(val []Product) {
 	s.Products = val
 }
 
-func (*Order) placeOrderRes() {}
+func (*Order) cancelOrderRes()       {}
+func (*Order) checkoutCartRes()      {}
+func (*Order) getOrderRes()          {}
+func (*Order) placeOrderRes()        {}
+func (*Order) updateOrderStatusRes() {}
+
+// Ref: #/components/schemas/OrderCancel
+type OrderCancel struct {
+	// Why the order is being cancelled; stored in the status history.
+	Reason OptString `json:"reason"`
+}
+
+// GetReason returns the value of Reason.
+func (s *OrderCancel) GetReason() OptString {
+	return s.Reason
+}
+
+// SetReason sets the value of Reason.
+func (s *OrderCancel) SetReason(val OptString) {
+	s.Reason = val
+}
 
 // Ref: #/components/schemas/OrderItem
 type OrderItem struct {
@@ -358,6 +2186,16 @@ type OrderItem struct {
 	ProductId OptString `json:"productId"`
 	// Item count.
 	Quantity OptInt `json:"quantity"`
+	// Product name at the time of purchase.
+	Name OptString `json:"name"`
+	// Product category at the time of purchase.
+	Category OptString `json:"category"`
+	// Unit price at the time of purchase.
+	UnitPrice OptFloat64 `json:"unitPrice"`
+	// Unit price multiplied by quantity, before discounts.
+	Subtotal OptFloat64 `json:"subtotal"`
+	// This line's share of the order discount.
+	Discount OptFloat64 `json:"discount"`
 }
 
 // GetProductId returns the value of ProductId.
@@ -370,6 +2208,31 @@ func (s *OrderItem) GetQuantity() OptInt {
 	return s.Quantity
 }
 
+// GetName returns the value of Name.
+func (s *OrderItem) GetName() OptString {
+	return s.Name
+}
+
+// GetCategory returns the value of Category.
+func (s *OrderItem) GetCategory() OptString {
+	return s.Category
+}
+
+// GetUnitPrice returns the value of UnitPrice.
+func (s *OrderItem) GetUnitPrice() OptFloat64 {
+	return s.UnitPrice
+}
+
+// GetSubtotal returns the value of Subtotal.
+func (s *OrderItem) GetSubtotal() OptFloat64 {
+	return s.Subtotal
+}
+
+// GetDiscount returns the value of Discount.
+func (s *OrderItem) GetDiscount() OptFloat64 {
+	return s.Discount
+}
+
 // SetProductId sets the value of ProductId.
 func (s *OrderItem) SetProductId(val OptString) {
 	s.ProductId = val
@@ -380,12 +2243,69 @@ func (s *OrderItem) SetQuantity(val OptInt) {
 	s.Quantity = val
 }
 
+// SetName sets the value of Name.
+func (s *OrderItem) SetName(val OptString) {
+	s.Name = val
+}
+
+// SetCategory sets the value of Category.
+func (s *OrderItem) SetCategory(val OptString) {
+	s.Category = val
+}
+
+// SetUnitPrice sets the value of UnitPrice.
+func (s *OrderItem) SetUnitPrice(val OptFloat64) {
+	s.UnitPrice = val
+}
+
+// SetSubtotal sets the value of Subtotal.
+func (s *OrderItem) SetSubtotal(val OptFloat64) {
+	s.Subtotal = val
+}
+
+// SetDiscount sets the value of Discount.
+func (s *OrderItem) SetDiscount(val OptFloat64) {
+	s.Discount = val
+}
+
+// Ref: #/components/schemas/OrderList
+type OrderList struct {
+	Orders []Order `json:"orders"`
+	// Cursor for the next page; absent on the last page.
+	NextCursor OptString `json:"nextCursor"`
+}
+
+// GetOrders returns the value of Orders.
+func (s *OrderList) GetOrders() []Order {
+	return s.Orders
+}
+
+// GetNextCursor returns the value of NextCursor.
+func (s *OrderList) GetNextCursor() OptString {
+	return s.NextCursor
+}
+
+// SetOrders sets the value of Orders.
+func (s *OrderList) SetOrders(val []Order) {
+	s.Orders = val
+}
+
+// SetNextCursor sets the value of NextCursor.
+func (s *OrderList) SetNextCursor(val OptString) {
+	s.NextCursor = val
+}
+
+func (*OrderList) listOrdersRes() {}
+
 // Place a new order.
 // Ref: #/components/schemas/OrderReq
 type OrderReq struct {
 	// Optional promo code applied to the order.
-	CouponCode OptString           `json:"couponCode"`
-	Items      []OrderReqItemsItem `json:"items"`
+	CouponCode OptString `json:"couponCode"`
+	// Further promo codes to combine with `couponCode`. Every coupon in a combination must be stackable.
+	// Item discounts apply first, then percentages, then fixed amounts.
+	CouponCodes []string            `json:"couponCodes"`
+	Items       []OrderReqItemsItem `json:"items"`
 }
 
 // GetCouponCode returns the value of CouponCode.
@@ -393,6 +2313,11 @@ func (s *OrderReq) GetCouponCode() OptString {
 	return s.CouponCode
 }
 
+// GetCouponCodes returns the value of CouponCodes.
+func (s *OrderReq) GetCouponCodes() []string {
+	return s.CouponCodes
+}
+
 // GetItems returns the value of Items.
 func (s *OrderReq) GetItems() []OrderReqItemsItem {
 	return s.Items
@@ -403,6 +2328,11 @@ func (s *OrderReq) SetCouponCode(val OptString) {
 	s.CouponCode = val
 }
 
+// SetCouponCodes sets the value of CouponCodes.
+func (s *OrderReq) SetCouponCodes(val []string) {
+	s.CouponCodes = val
+}
+
 // SetItems sets the value of Items.
 func (s *OrderReq) SetItems(val []OrderReqItemsItem) {
 	s.Items = val
@@ -435,10 +2365,183 @@ func (s *OrderReqItemsItem) SetQuantity(val int) {
 	s.Quantity = val
 }
 
+// Order lifecycle state. Allowed transitions:
+// placed → accepted | rejected | cancelled,
+// accepted → preparing | cancelled,
+// preparing → ready | cancelled,
+// ready → completed.
+// Ref: #/components/schemas/OrderStatus
+type OrderStatus string
+
+const (
+	OrderStatusPlaced    OrderStatus = "placed"
+	OrderStatusAccepted  OrderStatus = "accepted"
+	OrderStatusPreparing OrderStatus = "preparing"
+	OrderStatusReady     OrderStatus = "ready"
+	OrderStatusCompleted OrderStatus = "completed"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusRejected  OrderStatus = "rejected"
+)
+
+// AllValues returns all OrderStatus values.
+func (OrderStatus) AllValues() []OrderStatus {
+	return []OrderStatus{
+		OrderStatusPlaced,
+		OrderStatusAccepted,
+		OrderStatusPreparing,
+		OrderStatusReady,
+		OrderStatusCompleted,
+		OrderStatusCancelled,
+		OrderStatusRejected,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s OrderStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case OrderStatusPlaced:
+		return []byte(s), nil
+	case OrderStatusAccepted:
+		return []byte(s), nil
+	case OrderStatusPreparing:
+		return []byte(s), nil
+	case OrderStatusReady:
+		return []byte(s), nil
+	case OrderStatusCompleted:
+		return []byte(s), nil
+	case OrderStatusCancelled:
+		return []byte(s), nil
+	case OrderStatusRejected:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *OrderStatus) UnmarshalText(data []byte) error {
+	switch OrderStatus(data) {
+	case OrderStatusPlaced:
+		*s = OrderStatusPlaced
+		return nil
+	case OrderStatusAccepted:
+		*s = OrderStatusAccepted
+		return nil
+	case OrderStatusPreparing:
+		*s = OrderStatusPreparing
+		return nil
+	case OrderStatusReady:
+		*s = OrderStatusReady
+		return nil
+	case OrderStatusCompleted:
+		*s = OrderStatusCompleted
+		return nil
+	case OrderStatusCancelled:
+		*s = OrderStatusCancelled
+		return nil
+	case OrderStatusRejected:
+		*s = OrderStatusRejected
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Ref: #/components/schemas/OrderStatusTransition
+type OrderStatusTransition struct {
+	From OptOrderStatus `json:"from"`
+	To   OrderStatus    `json:"to"`
+	// ID of the API key that made the change.
+	Actor  OptString `json:"actor"`
+	Reason OptString `json:"reason"`
+	At     time.Time `json:"at"`
+}
+
+// GetFrom returns the value of From.
+func (s *OrderStatusTransition) GetFrom() OptOrderStatus {
+	return s.From
+}
+
+// GetTo returns the value of To.
+func (s *OrderStatusTransition) GetTo() OrderStatus {
+	return s.To
+}
+
+// GetActor returns the value of Actor.
+func (s *OrderStatusTransition) GetActor() OptString {
+	return s.Actor
+}
+
+// GetReason returns the value of Reason.
+func (s *OrderStatusTransition) GetReason() OptString {
+	return s.Reason
+}
+
+// GetAt returns the value of At.
+func (s *OrderStatusTransition) GetAt() time.Time {
+	return s.At
+}
+
+// SetFrom sets the value of From.
+func (s *OrderStatusTransition) SetFrom(val OptOrderStatus) {
+	s.From = val
+}
+
+// SetTo sets the value of To.
+func (s *OrderStatusTransition) SetTo(val OrderStatus) {
+	s.To = val
+}
+
+// SetActor sets the value of Actor.
+func (s *OrderStatusTransition) SetActor(val OptString) {
+	s.Actor = val
+}
+
+// SetReason sets the value of Reason.
+func (s *OrderStatusTransition) SetReason(val OptString) {
+	s.Reason = val
+}
+
+// SetAt sets the value of At.
+func (s *OrderStatusTransition) SetAt(val time.Time) {
+	s.At = val
+}
+
+// Ref: #/components/schemas/OrderStatusUpdate
+type OrderStatusUpdate struct {
+	Status OrderStatus `json:"status"`
+	// Optional note stored with the transition.
+	Reason OptString `json:"reason"`
+}
+
+// GetStatus returns the value of Status.
+func (s *OrderStatusUpdate) GetStatus() OrderStatus {
+	return s.Status
+}
+
+// GetReason returns the value of Reason.
+func (s *OrderStatusUpdate) GetReason() OptString {
+	return s.Reason
+}
+
+// SetStatus sets the value of Status.
+func (s *OrderStatusUpdate) SetStatus(val OrderStatus) {
+	s.Status = val
+}
+
+// SetReason sets the value of Reason.
+func (s *OrderStatusUpdate) SetReason(val OptString) {
+	s.Reason = val
+}
+
 type PlaceOrderBadRequest Error
 
 func (*PlaceOrderBadRequest) placeOrderRes() {}
 
+type PlaceOrderConflict Error
+
+func (*PlaceOrderConflict) placeOrderRes() {}
+
 type PlaceOrderForbidden Error
 
 func (*PlaceOrderForbidden) placeOrderRes() {}
@@ -560,3 +2663,224 @@ func (s *ProductImage) SetTablet(val OptString) {
 func (s *ProductImage) SetDesktop(val OptString) {
 	s.Desktop = val
 }
+
+// Ref: #/components/schemas/Quote
+type Quote struct {
+	// Sum of line subtotals before discount.
+	Subtotal float64 `json:"subtotal"`
+	// Discount the coupons would give; 0 when none applied.
+	Discount float64 `json:"discount"`
+	// Amount that would be charged.
+	Total float64 `json:"total"`
+	// First coupon code that was evaluated.
+	CouponCode OptString `json:"couponCode"`
+	// Human-readable description of the applied coupons.
+	CouponDescription OptString `json:"couponDescription"`
+	// What each coupon would contribute to `discount`, in the order they apply.
+	Coupons []AppliedCoupon `json:"coupons"`
+	// Why the coupons were not applied; absent when they were.
+	CouponError OptString   `json:"couponError"`
+	Items       []OrderItem `json:"items"`
+	Products    []Product   `json:"products"`
+}
+
+// GetSubtotal returns the value of Subtotal.
+func (s *Quote) GetSubtotal() float64 {
+	return s.Subtotal
+}
+
+// GetDiscount returns the value of Discount.
+func (s *Quote) GetDiscount() float64 {
+	return s.Discount
+}
+
+// GetTotal returns the value of Total.
+func (s *Quote) GetTotal() float64 {
+	return s.Total
+}
+
+// GetCouponCode returns the value of CouponCode.
+func (s *Quote) GetCouponCode() OptString {
+	return s.CouponCode
+}
+
+// GetCouponDescription returns the value of CouponDescription.
+func (s *Quote) GetCouponDescription() OptString {
+	return s.CouponDescription
+}
+
+// GetCoupons returns the value of Coupons.
+func (s *Quote) GetCoupons() []AppliedCoupon {
+	return s.Coupons
+}
+
+// GetCouponError returns the value of CouponError.
+func (s *Quote) GetCouponError() OptString {
+	return s.CouponError
+}
+
+// GetItems returns the value of Items.
+func (s *Quote) GetItems() []OrderItem {
+	return s.Items
+}
+
+// GetProducts returns the value of Products.
+func (s *Quote) GetProducts() []Product {
+	return s.Products
+}
+
+// SetSubtotal sets the value of Subtotal.
+func (s *Quote) SetSubtotal(val float64) {
+	s.Subtotal = val
+}
+
+// SetDiscount sets the value of Discount.
+func (s *Quote) SetDiscount(val float64) {
+	s.Discount = val
+}
+
+// SetTotal sets the value of Total.
+func (s *Quote) SetTotal(val float64) {
+	s.Total = val
+}
+
+// SetCouponCode sets the value of CouponCode.
+func (s *Quote) SetCouponCode(val OptString) {
+	s.CouponCode = val
+}
+
+// SetCouponDescription sets the value of CouponDescription.
+func (s *Quote) SetCouponDescription(val OptString) {
+	s.CouponDescription = val
+}
+
+// SetCoupons sets the value of Coupons.
+func (s *Quote) SetCoupons(val []AppliedCoupon) {
+	s.Coupons = val
+}
+
+// SetCouponError sets the value of CouponError.
+func (s *Quote) SetCouponError(val OptString) {
+	s.CouponError = val
+}
+
+// SetItems sets the value of Items.
+func (s *Quote) SetItems(val []OrderItem) {
+	s.Items = val
+}
+
+// SetProducts sets the value of Products.
+func (s *Quote) SetProducts(val []Product) {
+	s.Products = val
+}
+
+func (*Quote) quoteOrderRes() {}
+
+type QuoteOrderBadRequest Error
+
+func (*QuoteOrderBadRequest) quoteOrderRes() {}
+
+type QuoteOrderForbidden Error
+
+func (*QuoteOrderForbidden) quoteOrderRes() {}
+
+type QuoteOrderUnauthorized Error
+
+func (*QuoteOrderUnauthorized) quoteOrderRes() {}
+
+type QuoteOrderUnprocessableEntity Error
+
+func (*QuoteOrderUnprocessableEntity) quoteOrderRes() {}
+
+type RemoveCartItemForbidden Error
+
+func (*RemoveCartItemForbidden) removeCartItemRes() {}
+
+type RemoveCartItemNotFound Error
+
+func (*RemoveCartItemNotFound) removeCartItemRes() {}
+
+type RemoveCartItemUnauthorized Error
+
+func (*RemoveCartItemUnauthorized) removeCartItemRes() {}
+
+type RemoveCartItemUnprocessableEntity Error
+
+func (*RemoveCartItemUnprocessableEntity) removeCartItemRes() {}
+
+type SetCartItemForbidden Error
+
+func (*SetCartItemForbidden) setCartItemRes() {}
+
+type SetCartItemNotFound Error
+
+func (*SetCartItemNotFound) setCartItemRes() {}
+
+type SetCartItemUnauthorized Error
+
+func (*SetCartItemUnauthorized) setCartItemRes() {}
+
+type SetCartItemUnprocessableEntity Error
+
+func (*SetCartItemUnprocessableEntity) setCartItemRes() {}
+
+type SignedRequest struct {
+	APIKey string
+	Roles  []string
+}
+
+// GetAPIKey returns the value of APIKey.
+func (s *SignedRequest) GetAPIKey() string {
+	return s.APIKey
+}
+
+// GetRoles returns the value of Roles.
+func (s *SignedRequest) GetRoles() []string {
+	return s.Roles
+}
+
+// SetAPIKey sets the value of APIKey.
+func (s *SignedRequest) SetAPIKey(val string) {
+	s.APIKey = val
+}
+
+// SetRoles sets the value of Roles.
+func (s *SignedRequest) SetRoles(val []string) {
+	s.Roles = val
+}
+
+type UpdateCouponForbidden Error
+
+func (*UpdateCouponForbidden) updateCouponRes() {}
+
+type UpdateCouponNotFound Error
+
+func (*UpdateCouponNotFound) updateCouponRes() {}
+
+type UpdateCouponUnauthorized Error
+
+func (*UpdateCouponUnauthorized) updateCouponRes() {}
+
+type UpdateCouponUnprocessableEntity Error
+
+func (*UpdateCouponUnprocessableEntity) updateCouponRes() {}
+
+type UpdateOrderStatusBadRequest Error
+
+func (*UpdateOrderStatusBadRequest) updateOrderStatusRes() {}
+
+type UpdateOrderStatusConflict Error
+
+func (*UpdateOrderStatusConflict) updateOrderStatusRes() {}
+
+type UpdateOrderStatusForbidden Error
+
+func (*UpdateOrderStatusForbidden) updateOrderStatusRes() {}
+
+type UpdateOrderStatusNotFound Error
+
+func (*UpdateOrderStatusNotFound) updateOrderStatusRes() {}
+
+type UpdateOrderStatusUnauthorized Error
+
+func (*UpdateOrderStatusUnauthorized) updateOrderStatusRes() {}
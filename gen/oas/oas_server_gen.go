@@ -8,12 +8,105 @@ import (
 
 // Handler handles operations described by OpenAPI v3 specification.
 type Handler interface {
+	// AttachCartCoupon implements attachCartCoupon operation.
+	//
+	// Sets the coupon code applied to the cart, replacing any previous one.
+	// The coupon is only redeemed at checkout; a code that cannot be applied
+	// is reported in `couponError`.
+	//
+	// PUT /cart/{cartId}/coupon
+	AttachCartCoupon(ctx context.Context, req *CartCoupon, params AttachCartCouponParams) (AttachCartCouponRes, error)
+	// CancelOrder implements cancelOrder operation.
+	//
+	// Cancels an order that has not reached `ready`, within the configured
+	// cancellation window after placement. Any coupon use the order consumed
+	// is given back. Cancelling an order twice returns 409.
+	//
+	// POST /order/{orderId}/cancel
+	CancelOrder(ctx context.Context, req OptOrderCancel, params CancelOrderParams) (CancelOrderRes, error)
+	// CheckoutCart implements checkoutCart operation.
+	//
+	// Places an order for the cart's contents and coupon, then deletes the
+	// cart. Checking out the same cart twice returns 404.
+	//
+	// POST /cart/{cartId}/checkout
+	CheckoutCart(ctx context.Context, params CheckoutCartParams) (CheckoutCartRes, error)
+	// CreateCart implements createCart operation.
+	//
+	// Starts an empty cart owned by the calling API key. Carts expire after
+	// a period of inactivity; every change extends the expiry.
+	//
+	// POST /cart
+	CreateCart(ctx context.Context) (CreateCartRes, error)
+	// CreateCoupon implements createCoupon operation.
+	//
+	// Creates an active coupon. The code is stored upper-cased and must not
+	// match an existing code, ignoring case.
+	//
+	// POST /coupon
+	CreateCoupon(ctx context.Context, req *CouponCreate) (CreateCouponRes, error)
+	// DeactivateCoupon implements deactivateCoupon operation.
+	//
+	// Stops a coupon from being applied to new orders, quotes and carts.
+	// The coupon and its history are kept. Deactivating an inactive coupon
+	// succeeds.
+	//
+	// POST /coupon/{code}/deactivate
+	DeactivateCoupon(ctx context.Context, params DeactivateCouponParams) (DeactivateCouponRes, error)
+	// DetachCartCoupon implements detachCartCoupon operation.
+	//
+	// Removes the coupon code from the cart.
+	//
+	// DELETE /cart/{cartId}/coupon
+	DetachCartCoupon(ctx context.Context, params DetachCartCouponParams) (DetachCartCouponRes, error)
+	// GetCart implements getCart operation.
+	//
+	// Returns a cart priced against the current catalog and coupon rules.
+	//
+	// GET /cart/{cartId}
+	GetCart(ctx context.Context, params GetCartParams) (GetCartRes, error)
+	// GetCoupon implements getCoupon operation.
+	//
+	// Returns a coupon, active or not, with how often it was used.
+	//
+	// GET /coupon/{code}
+	GetCoupon(ctx context.Context, params GetCouponParams) (GetCouponRes, error)
+	// GetOrder implements getOrder operation.
+	//
+	// Returns a single placed order with its products.
+	//
+	// GET /order/{orderId}
+	GetOrder(ctx context.Context, params GetOrderParams) (GetOrderRes, error)
 	// GetProduct implements getProduct operation.
 	//
 	// Returns a single product.
 	//
 	// GET /product/{productId}
 	GetProduct(ctx context.Context, params GetProductParams) (GetProductRes, error)
+	// ListAuditEvents implements listAuditEvents operation.
+	//
+	// Returns authenticated state-changing requests, newest first: who made
+	// them, which operation, on which entity, and how they ended. Results
+	// are paginated with an opaque cursor; pass `nextCursor` from the
+	// previous page to continue.
+	//
+	// GET /audit
+	ListAuditEvents(ctx context.Context, params ListAuditEventsParams) (ListAuditEventsRes, error)
+	// ListCoupons implements listCoupons operation.
+	//
+	// Returns coupons ordered by code, with how often each was used.
+	// Results are paginated with an opaque cursor; pass `nextCursor` from
+	// the previous page to continue.
+	//
+	// GET /coupon
+	ListCoupons(ctx context.Context, params ListCouponsParams) (ListCouponsRes, error)
+	// ListOrders implements listOrders operation.
+	//
+	// Returns placed orders, newest first. Results are paginated with an
+	// opaque cursor; pass `nextCursor` from the previous page to continue.
+	//
+	// GET /order
+	ListOrders(ctx context.Context, params ListOrdersParams) (ListOrdersRes, error)
 	// ListProducts implements listProducts operation.
 	//
 	// Get all products available for order.
@@ -25,7 +118,42 @@ type Handler interface {
 	// Place a new order in the store.
 	//
 	// POST /order
-	PlaceOrder(ctx context.Context, req *OrderReq) (PlaceOrderRes, error)
+	PlaceOrder(ctx context.Context, req *OrderReq, params PlaceOrderParams) (PlaceOrderRes, error)
+	// QuoteOrder implements quoteOrder operation.
+	//
+	// Prices the items and optional coupons exactly as placing the order
+	// would, without creating an order or using up the coupons. Coupons
+	// that cannot be applied are reported in `couponError` and left out of
+	// the totals.
+	//
+	// POST /order/quote
+	QuoteOrder(ctx context.Context, req *OrderReq) (QuoteOrderRes, error)
+	// RemoveCartItem implements removeCartItem operation.
+	//
+	// Removes a product from the cart. Removing a product that is not in the cart is a no-op.
+	//
+	// DELETE /cart/{cartId}/items/{productId}
+	RemoveCartItem(ctx context.Context, params RemoveCartItemParams) (RemoveCartItemRes, error)
+	// SetCartItem implements setCartItem operation.
+	//
+	// Adds a product to the cart or changes its quantity.
+	//
+	// PUT /cart/{cartId}/items/{productId}
+	SetCartItem(ctx context.Context, req *CartItemUpdate, params SetCartItemParams) (SetCartItemRes, error)
+	// UpdateCoupon implements updateCoupon operation.
+	//
+	// Replaces the rule of a coupon. Its uses so far and whether it is
+	// active are kept. Orders already placed keep the discount they got.
+	//
+	// PUT /coupon/{code}
+	UpdateCoupon(ctx context.Context, req *CouponRule, params UpdateCouponParams) (UpdateCouponRes, error)
+	// UpdateOrderStatus implements updateOrderStatus operation.
+	//
+	// Moves an order to a new lifecycle status. Transitions not allowed by
+	// the state machine are rejected with 409.
+	//
+	// PATCH /order/{orderId}/status
+	UpdateOrderStatus(ctx context.Context, req *OrderStatusUpdate, params UpdateOrderStatusParams) (UpdateOrderStatusRes, error)
 }
 
 // Server implements http server based on OpenAPI v3 specification and
@@ -13,6 +13,105 @@ type UnimplementedHandler struct{}
 
 var _ Handler = UnimplementedHandler{}
 
+// AttachCartCoupon implements attachCartCoupon operation.
+//
+// Sets the coupon code applied to the cart, replacing any previous one.
+// The coupon is only redeemed at checkout; a code that cannot be applied
+// is reported in `couponError`.
+//
+// PUT /cart/{cartId}/coupon
+func (UnimplementedHandler) AttachCartCoupon(ctx context.Context, req *CartCoupon, params AttachCartCouponParams) (r AttachCartCouponRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// CancelOrder implements cancelOrder operation.
+//
+// Cancels an order that has not reached `ready`, within the configured
+// cancellation window after placement. Any coupon use the order consumed
+// is given back. Cancelling an order twice returns 409.
+//
+// POST /order/{orderId}/cancel
+func (UnimplementedHandler) CancelOrder(ctx context.Context, req OptOrderCancel, params CancelOrderParams) (r CancelOrderRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// CheckoutCart implements checkoutCart operation.
+//
+// Places an order for the cart's contents and coupon, then deletes the
+// cart. Checking out the same cart twice returns 404.
+//
+// POST /cart/{cartId}/checkout
+func (UnimplementedHandler) CheckoutCart(ctx context.Context, params CheckoutCartParams) (r CheckoutCartRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// CreateCart implements createCart operation.
+//
+// Starts an empty cart owned by the calling API key. Carts expire after
+// a period of inactivity; every change extends the expiry.
+//
+// POST /cart
+func (UnimplementedHandler) CreateCart(ctx context.Context) (r CreateCartRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// CreateCoupon implements createCoupon operation.
+//
+// Creates an active coupon. The code is stored upper-cased and must not
+// match an existing code, ignoring case.
+//
+// POST /coupon
+func (UnimplementedHandler) CreateCoupon(ctx context.Context, req *CouponCreate) (r CreateCouponRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// DeactivateCoupon implements deactivateCoupon operation.
+//
+// Stops a coupon from being applied to new orders, quotes and carts.
+// The coupon and its history are kept. Deactivating an inactive coupon
+// succeeds.
+//
+// POST /coupon/{code}/deactivate
+func (UnimplementedHandler) DeactivateCoupon(ctx context.Context, params DeactivateCouponParams) (r DeactivateCouponRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// DetachCartCoupon implements detachCartCoupon operation.
+//
+// Removes the coupon code from the cart.
+//
+// DELETE /cart/{cartId}/coupon
+func (UnimplementedHandler) DetachCartCoupon(ctx context.Context, params DetachCartCouponParams) (r DetachCartCouponRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// GetCart implements getCart operation.
+//
+// Returns a cart priced against the current catalog and coupon rules.
+//
+// GET /cart/{cartId}
+func (UnimplementedHandler) GetCart(ctx context.Context, params GetCartParams) (r GetCartRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// GetCoupon implements getCoupon operation.
+//
+// Returns a coupon, active or not, with how often it was used.
+//
+// GET /coupon/{code}
+func (UnimplementedHandler) GetCoupon(ctx context.Context, params GetCouponParams) (r GetCouponRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// GetOrder implements getOrder operation.
+//
+// Returns a single placed order with its products.
+//
+// GET /order/{orderId}
+func (UnimplementedHandler) GetOrder(ctx context.Context, params GetOrderParams) (r GetOrderRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // GetProduct implements getProduct operation.
 //
 // Returns a single product.
@@ -22,6 +121,39 @@ func (UnimplementedHandler) GetProduct(ctx context.Context, params GetProductPar
 	return r, ht.ErrNotImplemented
 }
 
+// ListAuditEvents implements listAuditEvents operation.
+//
+// Returns authenticated state-changing requests, newest first: who made
+// them, which operation, on which entity, and how they ended. Results
+// are paginated with an opaque cursor; pass `nextCursor` from the
+// previous page to continue.
+//
+// GET /audit
+func (UnimplementedHandler) ListAuditEvents(ctx context.Context, params ListAuditEventsParams) (r ListAuditEventsRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// ListCoupons implements listCoupons operation.
+//
+// Returns coupons ordered by code, with how often each was used.
+// Results are paginated with an opaque cursor; pass `nextCursor` from
+// the previous page to continue.
+//
+// GET /coupon
+func (UnimplementedHandler) ListCoupons(ctx context.Context, params ListCouponsParams) (r ListCouponsRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// ListOrders implements listOrders operation.
+//
+// Returns placed orders, newest first. Results are paginated with an
+// opaque cursor; pass `nextCursor` from the previous page to continue.
+//
+// GET /order
+func (UnimplementedHandler) ListOrders(ctx context.Context, params ListOrdersParams) (r ListOrdersRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // ListProducts implements listProducts operation.
 //
 // Get all products available for order.
@@ -36,6 +168,56 @@ func (UnimplementedHandler) ListProducts(ctx context.Context) (r []Product, _ er
 // Place a new order in the store.
 //
 // POST /order
-func (UnimplementedHandler) PlaceOrder(ctx context.Context, req *OrderReq) (r PlaceOrderRes, _ error) {
+func (UnimplementedHandler) PlaceOrder(ctx context.Context, req *OrderReq, params PlaceOrderParams) (r PlaceOrderRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// QuoteOrder implements quoteOrder operation.
+//
+// Prices the items and optional coupons exactly as placing the order
+// would, without creating an order or using up the coupons. Coupons
+// that cannot be applied are reported in `couponError` and left out of
+// the totals.
+//
+// POST /order/quote
+func (UnimplementedHandler) QuoteOrder(ctx context.Context, req *OrderReq) (r QuoteOrderRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// RemoveCartItem implements removeCartItem operation.
+//
+// Removes a product from the cart. Removing a product that is not in the cart is a no-op.
+//
+// DELETE /cart/{cartId}/items/{productId}
+func (UnimplementedHandler) RemoveCartItem(ctx context.Context, params RemoveCartItemParams) (r RemoveCartItemRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// SetCartItem implements setCartItem operation.
+//
+// Adds a product to the cart or changes its quantity.
+//
+// PUT /cart/{cartId}/items/{productId}
+func (UnimplementedHandler) SetCartItem(ctx context.Context, req *CartItemUpdate, params SetCartItemParams) (r SetCartItemRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// UpdateCoupon implements updateCoupon operation.
+//
+// Replaces the rule of a coupon. Its uses so far and whether it is
+// active are kept. Orders already placed keep the discount they got.
+//
+// PUT /coupon/{code}
+func (UnimplementedHandler) UpdateCoupon(ctx context.Context, req *CouponRule, params UpdateCouponParams) (r UpdateCouponRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// UpdateOrderStatus implements updateOrderStatus operation.
+//
+// Moves an order to a new lifecycle status. Transitions not allowed by
+// the state machine are rejected with 409.
+//
+// PATCH /order/{orderId}/status
+func (UnimplementedHandler) UpdateOrderStatus(ctx context.Context, req *OrderStatusUpdate, params UpdateOrderStatusParams) (r UpdateOrderStatusRes, _ error) {
 	return r, ht.ErrNotImplemented
 }
@@ -0,0 +1,58 @@
+// Code generated by ogen, DO NOT EDIT.
+package oasv2
+
+type AttachCartCouponRes interface {
+	attachCartCouponRes()
+}
+
+type CancelOrderRes interface {
+	cancelOrderRes()
+}
+
+type CheckoutCartRes interface {
+	checkoutCartRes()
+}
+
+type CreateCartRes interface {
+	createCartRes()
+}
+
+type DetachCartCouponRes interface {
+	detachCartCouponRes()
+}
+
+type GetCartRes interface {
+	getCartRes()
+}
+
+type GetOrderRes interface {
+	getOrderRes()
+}
+
+type GetProductRes interface {
+	getProductRes()
+}
+
+type ListOrdersRes interface {
+	listOrdersRes()
+}
+
+type PlaceOrderRes interface {
+	placeOrderRes()
+}
+
+type QuoteOrderRes interface {
+	quoteOrderRes()
+}
+
+type RemoveCartItemRes interface {
+	removeCartItemRes()
+}
+
+type SetCartItemRes interface {
+	setCartItemRes()
+}
+
+type UpdateOrderStatusRes interface {
+	updateOrderStatusRes()
+}
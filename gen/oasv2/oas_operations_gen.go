@@ -0,0 +1,24 @@
+// Code generated by ogen, DO NOT EDIT.
+
+package oasv2
+
+// OperationName is the ogen operation name
+type OperationName = string
+
+const (
+	AttachCartCouponOperation  OperationName = "AttachCartCoupon"
+	CancelOrderOperation       OperationName = "CancelOrder"
+	CheckoutCartOperation      OperationName = "CheckoutCart"
+	CreateCartOperation        OperationName = "CreateCart"
+	DetachCartCouponOperation  OperationName = "DetachCartCoupon"
+	GetCartOperation           OperationName = "GetCart"
+	GetOrderOperation          OperationName = "GetOrder"
+	GetProductOperation        OperationName = "GetProduct"
+	ListOrdersOperation        OperationName = "ListOrders"
+	ListProductsOperation      OperationName = "ListProducts"
+	PlaceOrderOperation        OperationName = "PlaceOrder"
+	QuoteOrderOperation        OperationName = "QuoteOrder"
+	RemoveCartItemOperation    OperationName = "RemoveCartItem"
+	SetCartItemOperation       OperationName = "SetCartItem"
+	UpdateOrderStatusOperation OperationName = "UpdateOrderStatus"
+)
@@ -0,0 +1,1642 @@
+// Code generated by ogen, DO NOT EDIT.
+
+package oasv2
+
+import (
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+type APIKey struct {
+	APIKey string
+	Roles  []string
+}
+
+// GetAPIKey returns the value of APIKey.
+func (s *APIKey) GetAPIKey() string {
+	return s.APIKey
+}
+
+// GetRoles returns the value of Roles.
+func (s *APIKey) GetRoles() []string {
+	return s.Roles
+}
+
+// SetAPIKey sets the value of APIKey.
+func (s *APIKey) SetAPIKey(val string) {
+	s.APIKey = val
+}
+
+// SetRoles sets the value of Roles.
+func (s *APIKey) SetRoles(val []string) {
+	s.Roles = val
+}
+
+// Ref: #/components/schemas/AppliedCoupon
+type AppliedCoupon struct {
+	Code        string    `json:"code"`
+	Description OptString `json:"description"`
+	Discount    Money     `json:"discount"`
+}
+
+// GetCode returns the value of Code.
+func (s *AppliedCoupon) GetCode() string {
+	return s.Code
+}
+
+// GetDescription returns the value of Description.
+func (s *AppliedCoupon) GetDescription() OptString {
+	return s.Description
+}
+
+// GetDiscount returns the value of Discount.
+func (s *AppliedCoupon) GetDiscount() Money {
+	return s.Discount
+}
+
+// SetCode sets the value of Code.
+func (s *AppliedCoupon) SetCode(val string) {
+	s.Code = val
+}
+
+// SetDescription sets the value of Description.
+func (s *AppliedCoupon) SetDescription(val OptString) {
+	s.Description = val
+}
+
+// SetDiscount sets the value of Discount.
+func (s *AppliedCoupon) SetDiscount(val Money) {
+	s.Discount = val
+}
+
+type AttachCartCouponForbidden Error
+
+func (*AttachCartCouponForbidden) attachCartCouponRes() {}
+
+type AttachCartCouponNotFound Error
+
+func (*AttachCartCouponNotFound) attachCartCouponRes() {}
+
+type AttachCartCouponUnauthorized Error
+
+func (*AttachCartCouponUnauthorized) attachCartCouponRes() {}
+
+type AttachCartCouponUnprocessableEntity Error
+
+func (*AttachCartCouponUnprocessableEntity) attachCartCouponRes() {}
+
+type BearerAuth struct {
+	Token string
+	Roles []string
+}
+
+// GetToken returns the value of Token.
+func (s *BearerAuth) GetToken() string {
+	return s.Token
+}
+
+// GetRoles returns the value of Roles.
+func (s *BearerAuth) GetRoles() []string {
+	return s.Roles
+}
+
+// SetToken sets the value of Token.
+func (s *BearerAuth) SetToken(val string) {
+	s.Token = val
+}
+
+// SetRoles sets the value of Roles.
+func (s *BearerAuth) SetRoles(val []string) {
+	s.Roles = val
+}
+
+type CancelOrderConflict Error
+
+func (*CancelOrderConflict) cancelOrderRes() {}
+
+type CancelOrderForbidden Error
+
+func (*CancelOrderForbidden) cancelOrderRes() {}
+
+type CancelOrderNotFound Error
+
+func (*CancelOrderNotFound) cancelOrderRes() {}
+
+type CancelOrderUnauthorized Error
+
+func (*CancelOrderUnauthorized) cancelOrderRes() {}
+
+// Ref: #/components/schemas/Cart
+type Cart struct {
+	ID string `json:"id"`
+	// Cart lines priced against the current catalog.
+	Items    []OrderItem `json:"items"`
+	Products []Product   `json:"products"`
+	Subtotal Money       `json:"subtotal"`
+	Discount Money       `json:"discount"`
+	Total    Money       `json:"total"`
+	// Coupon code attached to the cart.
+	CouponCode OptString `json:"couponCode"`
+	// Human-readable description of the applied coupon.
+	CouponDescription OptString `json:"couponDescription"`
+	// Why the attached coupon does not apply; absent when it does.
+	CouponError OptString   `json:"couponError"`
+	CreatedAt   OptDateTime `json:"createdAt"`
+	UpdatedAt   OptDateTime `json:"updatedAt"`
+	// The cart is deleted if left unchanged until this time.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// GetID returns the value of ID.
+func (s *Cart) GetID() string {
+	return s.ID
+}
+
+// GetItems returns the value of Items.
+func (s *Cart) GetItems() []OrderItem {
+	return s.Items
+}
+
+// GetProducts returns the value of Products.
+func (s *Cart) GetProducts() []Product {
+	return s.Products
+}
+
+// GetSubtotal returns the value of Subtotal.
+func (s *Cart) GetSubtotal() Money {
+	return s.Subtotal
+}
+
+// GetDiscount returns the value of Discount.
+func (s *Cart) GetDiscount() Money {
+	return s.Discount
+}
+
+// GetTotal returns the value of Total.
+func (s *Cart) GetTotal() Money {
+	return s.Total
+}
+
+// GetCouponCode returns the value of CouponCode.
+func (s *Cart) GetCouponCode() OptString {
+	return s.CouponCode
+}
+
+// GetCouponDescription returns the value of CouponDescription.
+func (s *Cart) GetCouponDescription() OptString {
+	return s.CouponDescription
+}
+
+// GetCouponError returns the value of CouponError.
+func (s *Cart) GetCouponError() OptString {
+	return s.CouponError
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *Cart) GetCreatedAt() OptDateTime {
+	return s.CreatedAt
+}
+
+// GetUpdatedAt returns the value of UpdatedAt.
+func (s *Cart) GetUpdatedAt() OptDateTime {
+	return s.UpdatedAt
+}
+
+// GetExpiresAt returns the value of ExpiresAt.
+func (s *Cart) GetExpiresAt() time.Time {
+	return s.ExpiresAt
+}
+
+// SetID sets the value of ID.
+func (s *Cart) SetID(val string) {
+	s.ID = val
+}
+
+// SetItems sets the value of Items.
+func (s *Cart) SetItems(val []OrderItem) {
+	s.Items = val
+}
+
+// SetProducts sets the value of Products.
+func (s *Cart) SetProducts(val []Product) {
+	s.Products = val
+}
+
+// SetSubtotal sets the value of Subtotal.
+func (s *Cart) SetSubtotal(val Money) {
+	s.Subtotal = val
+}
+
+// SetDiscount sets the value of Discount.
+func (s *Cart) SetDiscount(val Money) {
+	s.Discount = val
+}
+
+// SetTotal sets the value of Total.
+func (s *Cart) SetTotal(val Money) {
+	s.Total = val
+}
+
+// SetCouponCode sets the value of CouponCode.
+func (s *Cart) SetCouponCode(val OptString) {
+	s.CouponCode = val
+}
+
+// SetCouponDescription sets the value of CouponDescription.
+func (s *Cart) SetCouponDescription(val OptString) {
+	s.CouponDescription = val
+}
+
+// SetCouponError sets the value of CouponError.
+func (s *Cart) SetCouponError(val OptString) {
+	s.CouponError = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *Cart) SetCreatedAt(val OptDateTime) {
+	s.CreatedAt = val
+}
+
+// SetUpdatedAt sets the value of UpdatedAt.
+func (s *Cart) SetUpdatedAt(val OptDateTime) {
+	s.UpdatedAt = val
+}
+
+// SetExpiresAt sets the value of ExpiresAt.
+func (s *Cart) SetExpiresAt(val time.Time) {
+	s.ExpiresAt = val
+}
+
+func (*Cart) attachCartCouponRes() {}
+func (*Cart) createCartRes()       {}
+func (*Cart) detachCartCouponRes() {}
+func (*Cart) getCartRes()          {}
+func (*Cart) removeCartItemRes()   {}
+func (*Cart) setCartItemRes()      {}
+
+// Ref: #/components/schemas/CartCoupon
+type CartCoupon struct {
+	// Promo code to apply at checkout.
+	Code string `json:"code"`
+}
+
+// GetCode returns the value of Code.
+func (s *CartCoupon) GetCode() string {
+	return s.Code
+}
+
+// SetCode sets the value of Code.
+func (s *CartCoupon) SetCode(val string) {
+	s.Code = val
+}
+
+// Ref: #/components/schemas/CartItemUpdate
+type CartItemUpdate struct {
+	// New item count.
+	Quantity int `json:"quantity"`
+}
+
+// GetQuantity returns the value of Quantity.
+func (s *CartItemUpdate) GetQuantity() int {
+	return s.Quantity
+}
+
+// SetQuantity sets the value of Quantity.
+func (s *CartItemUpdate) SetQuantity(val int) {
+	s.Quantity = val
+}
+
+type CheckoutCartBadRequest Error
+
+func (*CheckoutCartBadRequest) checkoutCartRes() {}
+
+type CheckoutCartForbidden Error
+
+func (*CheckoutCartForbidden) checkoutCartRes() {}
+
+type CheckoutCartNotFound Error
+
+func (*CheckoutCartNotFound) checkoutCartRes() {}
+
+type CheckoutCartUnauthorized Error
+
+func (*CheckoutCartUnauthorized) checkoutCartRes() {}
+
+type CheckoutCartUnprocessableEntity Error
+
+func (*CheckoutCartUnprocessableEntity) checkoutCartRes() {}
+
+// CouponAttemptsExceededHeaders wraps Error with response headers.
+type CouponAttemptsExceededHeaders struct {
+	RetryAfter int
+	Response   Error
+}
+
+// GetRetryAfter returns the value of RetryAfter.
+func (s *CouponAttemptsExceededHeaders) GetRetryAfter() int {
+	return s.RetryAfter
+}
+
+// GetResponse returns the value of Response.
+func (s *CouponAttemptsExceededHeaders) GetResponse() Error {
+	return s.Response
+}
+
+// SetRetryAfter sets the value of RetryAfter.
+func (s *CouponAttemptsExceededHeaders) SetRetryAfter(val int) {
+	s.RetryAfter = val
+}
+
+// SetResponse sets the value of Response.
+func (s *CouponAttemptsExceededHeaders) SetResponse(val Error) {
+	s.Response = val
+}
+
+func (*CouponAttemptsExceededHeaders) attachCartCouponRes() {}
+func (*CouponAttemptsExceededHeaders) placeOrderRes()       {}
+func (*CouponAttemptsExceededHeaders) quoteOrderRes()       {}
+
+type CreateCartForbidden Error
+
+func (*CreateCartForbidden) createCartRes() {}
+
+type CreateCartUnauthorized Error
+
+func (*CreateCartUnauthorized) createCartRes() {}
+
+type DetachCartCouponForbidden Error
+
+func (*DetachCartCouponForbidden) detachCartCouponRes() {}
+
+type DetachCartCouponNotFound Error
+
+func (*DetachCartCouponNotFound) detachCartCouponRes() {}
+
+type DetachCartCouponUnauthorized Error
+
+func (*DetachCartCouponUnauthorized) detachCartCouponRes() {}
+
+type DetachCartCouponUnprocessableEntity Error
+
+func (*DetachCartCouponUnprocessableEntity) detachCartCouponRes() {}
+
+// Ref: #/components/schemas/Error
+type Error struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+}
+
+// GetCode returns the value of Code.
+func (s *Error) GetCode() int32 {
+	return s.Code
+}
+
+// GetMessage returns the value of Message.
+func (s *Error) GetMessage() string {
+	return s.Message
+}
+
+// SetCode sets the value of Code.
+func (s *Error) SetCode(val int32) {
+	s.Code = val
+}
+
+// SetMessage sets the value of Message.
+func (s *Error) SetMessage(val string) {
+	s.Message = val
+}
+
+type GetCartForbidden Error
+
+func (*GetCartForbidden) getCartRes() {}
+
+type GetCartNotFound Error
+
+func (*GetCartNotFound) getCartRes() {}
+
+type GetCartUnauthorized Error
+
+func (*GetCartUnauthorized) getCartRes() {}
+
+type GetCartUnprocessableEntity Error
+
+func (*GetCartUnprocessableEntity) getCartRes() {}
+
+type GetOrderForbidden Error
+
+func (*GetOrderForbidden) getOrderRes() {}
+
+type GetOrderNotFound Error
+
+func (*GetOrderNotFound) getOrderRes() {}
+
+type GetOrderUnauthorized Error
+
+func (*GetOrderUnauthorized) getOrderRes() {}
+
+type GetProductBadRequest Error
+
+func (*GetProductBadRequest) getProductRes() {}
+
+type GetProductNotFound Error
+
+func (*GetProductNotFound) getProductRes() {}
+
+type ListOrdersBadRequest Error
+
+func (*ListOrdersBadRequest) listOrdersRes() {}
+
+type ListOrdersForbidden Error
+
+func (*ListOrdersForbidden) listOrdersRes() {}
+
+type ListOrdersUnauthorized Error
+
+func (*ListOrdersUnauthorized) listOrdersRes() {}
+
+// An exact monetary amount. `amount` is a decimal string with two
+// fraction digits, never a binary float, so it can be parsed into a
+// decimal type without rounding error.
+// Ref: #/components/schemas/Money
+type Money struct {
+	Amount string `json:"amount"`
+	// ISO 4217 currency code.
+	Currency string `json:"currency"`
+}
+
+// GetAmount returns the value of Amount.
+func (s *Money) GetAmount() string {
+	return s.Amount
+}
+
+// GetCurrency returns the value of Currency.
+func (s *Money) GetCurrency() string {
+	return s.Currency
+}
+
+// SetAmount sets the value of Amount.
+func (s *Money) SetAmount(val string) {
+	s.Amount = val
+}
+
+// SetCurrency sets the value of Currency.
+func (s *Money) SetCurrency(val string) {
+	s.Currency = val
+}
+
+// NewOptDateTime returns new OptDateTime with value set to v.
+func NewOptDateTime(v time.Time) OptDateTime {
+	return OptDateTime{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptDateTime is optional time.Time.
+type OptDateTime struct {
+	Value time.Time
+	Set   bool
+}
+
+// IsSet returns true if OptDateTime was set.
+func (o OptDateTime) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptDateTime) Reset() {
+	var v time.Time
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptDateTime) SetTo(v time.Time) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptDateTime) Get() (v time.Time, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptDateTime) Or(d time.Time) time.Time {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptInt returns new OptInt with value set to v.
+func NewOptInt(v int) OptInt {
+	return OptInt{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptInt is optional int.
+type OptInt struct {
+	Value int
+	Set   bool
+}
+
+// IsSet returns true if OptInt was set.
+func (o OptInt) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptInt) Reset() {
+	var v int
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptInt) SetTo(v int) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptInt) Get() (v int, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptInt) Or(d int) int {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptMoney returns new OptMoney with value set to v.
+func NewOptMoney(v Money) OptMoney {
+	return OptMoney{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptMoney is optional Money.
+type OptMoney struct {
+	Value Money
+	Set   bool
+}
+
+// IsSet returns true if OptMoney was set.
+func (o OptMoney) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptMoney) Reset() {
+	var v Money
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptMoney) SetTo(v Money) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptMoney) Get() (v Money, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptMoney) Or(d Money) Money {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptOrderCancel returns new OptOrderCancel with value set to v.
+func NewOptOrderCancel(v OrderCancel) OptOrderCancel {
+	return OptOrderCancel{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptOrderCancel is optional OrderCancel.
+type OptOrderCancel struct {
+	Value OrderCancel
+	Set   bool
+}
+
+// IsSet returns true if OptOrderCancel was set.
+func (o OptOrderCancel) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptOrderCancel) Reset() {
+	var v OrderCancel
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptOrderCancel) SetTo(v OrderCancel) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptOrderCancel) Get() (v OrderCancel, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptOrderCancel) Or(d OrderCancel) OrderCancel {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptOrderStatus returns new OptOrderStatus with value set to v.
+func NewOptOrderStatus(v OrderStatus) OptOrderStatus {
+	return OptOrderStatus{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptOrderStatus is optional OrderStatus.
+type OptOrderStatus struct {
+	Value OrderStatus
+	Set   bool
+}
+
+// IsSet returns true if OptOrderStatus was set.
+func (o OptOrderStatus) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptOrderStatus) Reset() {
+	var v OrderStatus
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptOrderStatus) SetTo(v OrderStatus) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptOrderStatus) Get() (v OrderStatus, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptOrderStatus) Or(d OrderStatus) OrderStatus {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptProductImage returns new OptProductImage with value set to v.
+func NewOptProductImage(v ProductImage) OptProductImage {
+	return OptProductImage{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptProductImage is optional ProductImage.
+type OptProductImage struct {
+	Value ProductImage
+	Set   bool
+}
+
+// IsSet returns true if OptProductImage was set.
+func (o OptProductImage) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptProductImage) Reset() {
+	var v ProductImage
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptProductImage) SetTo(v ProductImage) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptProductImage) Get() (v ProductImage, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptProductImage) Or(d ProductImage) ProductImage {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptString returns new OptString with value set to v.
+func NewOptString(v string) OptString {
+	return OptString{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptString is optional string.
+type OptString struct {
+	Value string
+	Set   bool
+}
+
+// IsSet returns true if OptString was set.
+func (o OptString) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptString) Reset() {
+	var v string
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptString) SetTo(v string) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptString) Get() (v string, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptString) Or(d string) string {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// Ref: #/components/schemas/Order
+type Order struct {
+	ID        OptString `json:"id"`
+	Total     OptMoney  `json:"total"`
+	Discounts OptMoney  `json:"discounts"`
+	// First coupon code the order was placed with, as typed; `coupons` lists all of them.
+	CouponCode OptString `json:"couponCode"`
+	// What each coupon contributed to `discounts`, in the order they were applied.
+	Coupons []AppliedCoupon `json:"coupons"`
+	// Time the order was placed.
+	CreatedAt OptDateTime    `json:"createdAt"`
+	Status    OptOrderStatus `json:"status"`
+	// Time of the last status change.
+	StatusUpdatedAt OptDateTime `json:"statusUpdatedAt"`
+	// Status changes, oldest first. Only returned for single-order lookups.
+	StatusHistory []OrderStatusTransition `json:"statusHistory"`
+	Items         []OrderItem             `json:"items"`
+	Products      []Product               `json:"products"`
+}
+
+// GetID returns the value of ID.
+func (s *Order) GetID() OptString {
+	return s.ID
+}
+
+// GetTotal returns the value of Total.
+func (s *Order) GetTotal() OptMoney {
+	return s.Total
+}
+
+// GetDiscounts returns the value of Discounts.
+func (s *Order) GetDiscounts() OptMoney {
+	return s.Discounts
+}
+
+// GetCouponCode returns the value of CouponCode.
+func (s *Order) GetCouponCode() OptString {
+	return s.CouponCode
+}
+
+// GetCoupons returns the value of Coupons.
+func (s *Order) GetCoupons() []AppliedCoupon {
+	return s.Coupons
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *Order) GetCreatedAt() OptDateTime {
+	return s.CreatedAt
+}
+
+// GetStatus returns the value of Status.
+func (s *Order) GetStatus() OptOrderStatus {
+	return s.Status
+}
+
+// GetStatusUpdatedAt returns the value of StatusUpdatedAt.
+func (s *Order) GetStatusUpdatedAt() OptDateTime {
+	return s.StatusUpdatedAt
+}
+
+// GetStatusHistory returns the value of StatusHistory.
+func (s *Order) GetStatusHistory() []OrderStatusTransition {
+	return s.StatusHistory
+}
+
+// GetItems returns the value of Items.
+func (s *Order) GetItems() []OrderItem {
+	return s.Items
+}
+
+// GetProducts returns the value of Products.
+func (s *Order) GetProducts() []Product {
+	return s.Products
+}
+
+// SetID sets the value of ID.
+func (s *Order) SetID(val OptString) {
+	s.ID = val
+}
+
+// SetTotal sets the value of Total.
+func (s *Order) SetTotal(val OptMoney) {
+	s.Total = val
+}
+
+// SetDiscounts sets the value of Discounts.
+func (s *Order) SetDiscounts(val OptMoney) {
+	s.Discounts = val
+}
+
+// SetCouponCode sets the value of CouponCode.
+func (s *Order) SetCouponCode(val OptString) {
+	s.CouponCode = val
+}
+
+// SetCoupons sets the value of Coupons.
+func (s *Order) SetCoupons(val []AppliedCoupon) {
+	s.Coupons = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *Order) SetCreatedAt(val OptDateTime) {
+	s.CreatedAt = val
+}
+
+// SetStatus sets the value of Status.
+func (s *Order) SetStatus(val OptOrderStatus) {
+	s.Status = val
+}
+
+// SetStatusUpdatedAt sets the value of StatusUpdatedAt.
+func (s *Order) SetStatusUpdatedAt(val OptDateTime) {
+	s.StatusUpdatedAt = val
+}
+
+// SetStatusHistory sets the value of StatusHistory.
+func (s *Order) SetStatusHistory(val []OrderStatusTransition) {
+	s.StatusHistory = val
+}
+
+// SetItems sets the value of Items.
+func (s *Order) SetItems(val []OrderItem) {
+	s.Items = val
+}
+
+// SetProducts sets the value of Products.
+func (s *Order) SetProducts(val []Product) {
+	s.Products = val
+}
+
+func (*Order) cancelOrderRes()       {}
+func (*Order) checkoutCartRes()      {}
+func (*Order) getOrderRes()          {}
+func (*Order) placeOrderRes()        {}
+func (*Order) updateOrderStatusRes() {}
+
+// Ref: #/components/schemas/OrderCancel
+type OrderCancel struct {
+	// Why the order is being cancelled; stored in the status history.
+	Reason OptString `json:"reason"`
+}
+
+// GetReason returns the value of Reason.
+func (s *OrderCancel) GetReason() OptString {
+	return s.Reason
+}
+
+// SetReason sets the value of Reason.
+func (s *OrderCancel) SetReason(val OptString) {
+	s.Reason = val
+}
+
+// Ref: #/components/schemas/OrderItem
+type OrderItem struct {
+	// ID of the product.
+	ProductId OptString `json:"productId"`
+	// Item count.
+	Quantity OptInt `json:"quantity"`
+	// Product name at the time of purchase.
+	Name OptString `json:"name"`
+	// Product category at the time of purchase.
+	Category  OptString `json:"category"`
+	UnitPrice OptMoney  `json:"unitPrice"`
+	Subtotal  OptMoney  `json:"subtotal"`
+	Discount  OptMoney  `json:"discount"`
+}
+
+// GetProductId returns the value of ProductId.
+func (s *OrderItem) GetProductId() OptString {
+	return s.ProductId
+}
+
+// GetQuantity returns the value of Quantity.
+func (s *OrderItem) GetQuantity() OptInt {
+	return s.Quantity
+}
+
+// GetName returns the value of Name.
+func (s *OrderItem) GetName() OptString {
+	return s.Name
+}
+
+// GetCategory returns the value of Category.
+func (s *OrderItem) GetCategory() OptString {
+	return s.Category
+}
+
+// GetUnitPrice returns the value of UnitPrice.
+func (s *OrderItem) GetUnitPrice() OptMoney {
+	return s.UnitPrice
+}
+
+// GetSubtotal returns the value of Subtotal.
+func (s *OrderItem) GetSubtotal() OptMoney {
+	return s.Subtotal
+}
+
+// GetDiscount returns the value of Discount.
+func (s *OrderItem) GetDiscount() OptMoney {
+	return s.Discount
+}
+
+// SetProductId sets the value of ProductId.
+func (s *OrderItem) SetProductId(val OptString) {
+	s.ProductId = val
+}
+
+// SetQuantity sets the value of Quantity.
+func (s *OrderItem) SetQuantity(val OptInt) {
+	s.Quantity = val
+}
+
+// SetName sets the value of Name.
+func (s *OrderItem) SetName(val OptString) {
+	s.Name = val
+}
+
+// SetCategory sets the value of Category.
+func (s *OrderItem) SetCategory(val OptString) {
+	s.Category = val
+}
+
+// SetUnitPrice sets the value of UnitPrice.
+func (s *OrderItem) SetUnitPrice(val OptMoney) {
+	s.UnitPrice = val
+}
+
+// SetSubtotal sets the value of Subtotal.
+func (s *OrderItem) SetSubtotal(val OptMoney) {
+	s.Subtotal = val
+}
+
+// SetDiscount sets the value of Discount.
+func (s *OrderItem) SetDiscount(val OptMoney) {
+	s.Discount = val
+}
+
+// Ref: #/components/schemas/OrderList
+type OrderList struct {
+	Orders []Order `json:"orders"`
+	// Cursor for the next page; absent on the last page.
+	NextCursor OptString `json:"nextCursor"`
+}
+
+// GetOrders returns the value of Orders.
+func (s *OrderList) GetOrders() []Order {
+	return s.Orders
+}
+
+// GetNextCursor returns the value of NextCursor.
+func (s *OrderList) GetNextCursor() OptString {
+	return s.NextCursor
+}
+
+// SetOrders sets the value of Orders.
+func (s *OrderList) SetOrders(val []Order) {
+	s.Orders = val
+}
+
+// SetNextCursor sets the value of NextCursor.
+func (s *OrderList) SetNextCursor(val OptString) {
+	s.NextCursor = val
+}
+
+func (*OrderList) listOrdersRes() {}
+
+// Place a new order.
+// Ref: #/components/schemas/OrderReq
+type OrderReq struct {
+	// Optional promo code applied to the order.
+	CouponCode OptString `json:"couponCode"`
+	// Further promo codes to combine with `couponCode`. Every coupon in a combination must be stackable.
+	// Item discounts apply first, then percentages, then fixed amounts.
+	CouponCodes []string            `json:"couponCodes"`
+	Items       []OrderReqItemsItem `json:"items"`
+}
+
+// GetCouponCode returns the value of CouponCode.
+func (s *OrderReq) GetCouponCode() OptString {
+	return s.CouponCode
+}
+
+// GetCouponCodes returns the value of CouponCodes.
+func (s *OrderReq) GetCouponCodes() []string {
+	return s.CouponCodes
+}
+
+// GetItems returns the value of Items.
+func (s *OrderReq) GetItems() []OrderReqItemsItem {
+	return s.Items
+}
+
+// SetCouponCode sets the value of CouponCode.
+func (s *OrderReq) SetCouponCode(val OptString) {
+	s.CouponCode = val
+}
+
+// SetCouponCodes sets the value of CouponCodes.
+func (s *OrderReq) SetCouponCodes(val []string) {
+	s.CouponCodes = val
+}
+
+// SetItems sets the value of Items.
+func (s *OrderReq) SetItems(val []OrderReqItemsItem) {
+	s.Items = val
+}
+
+type OrderReqItemsItem struct {
+	// ID of the product.
+	ProductId string `json:"productId"`
+	// Item count.
+	Quantity int `json:"quantity"`
+}
+
+// GetProductId returns the value of ProductId.
+func (s *OrderReqItemsItem) GetProductId() string {
+	return s.ProductId
+}
+
+// GetQuantity returns the value of Quantity.
+func (s *OrderReqItemsItem) GetQuantity() int {
+	return s.Quantity
+}
+
+// SetProductId sets the value of ProductId.
+func (s *OrderReqItemsItem) SetProductId(val string) {
+	s.ProductId = val
+}
+
+// SetQuantity sets the value of Quantity.
+func (s *OrderReqItemsItem) SetQuantity(val int) {
+	s.Quantity = val
+}
+
+// Order lifecycle state. Allowed transitions:
+// placed → accepted | rejected | cancelled,
+// accepted → preparing | cancelled,
+// preparing → ready | cancelled,
+// ready → completed.
+// Ref: #/components/schemas/OrderStatus
+type OrderStatus string
+
+const (
+	OrderStatusPlaced    OrderStatus = "placed"
+	OrderStatusAccepted  OrderStatus = "accepted"
+	OrderStatusPreparing OrderStatus = "preparing"
+	OrderStatusReady     OrderStatus = "ready"
+	OrderStatusCompleted OrderStatus = "completed"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusRejected  OrderStatus = "rejected"
+)
+
+// AllValues returns all OrderStatus values.
+func (OrderStatus) AllValues() []OrderStatus {
+	return []OrderStatus{
+		OrderStatusPlaced,
+		OrderStatusAccepted,
+		OrderStatusPreparing,
+		OrderStatusReady,
+		OrderStatusCompleted,
+		OrderStatusCancelled,
+		OrderStatusRejected,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s OrderStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case OrderStatusPlaced:
+		return []byte(s), nil
+	case OrderStatusAccepted:
+		return []byte(s), nil
+	case OrderStatusPreparing:
+		return []byte(s), nil
+	case OrderStatusReady:
+		return []byte(s), nil
+	case OrderStatusCompleted:
+		return []byte(s), nil
+	case OrderStatusCancelled:
+		return []byte(s), nil
+	case OrderStatusRejected:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *OrderStatus) UnmarshalText(data []byte) error {
+	switch OrderStatus(data) {
+	case OrderStatusPlaced:
+		*s = OrderStatusPlaced
+		return nil
+	case OrderStatusAccepted:
+		*s = OrderStatusAccepted
+		return nil
+	case OrderStatusPreparing:
+		*s = OrderStatusPreparing
+		return nil
+	case OrderStatusReady:
+		*s = OrderStatusReady
+		return nil
+	case OrderStatusCompleted:
+		*s = OrderStatusCompleted
+		return nil
+	case OrderStatusCancelled:
+		*s = OrderStatusCancelled
+		return nil
+	case OrderStatusRejected:
+		*s = OrderStatusRejected
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Ref: #/components/schemas/OrderStatusTransition
+type OrderStatusTransition struct {
+	From OptOrderStatus `json:"from"`
+	To   OrderStatus    `json:"to"`
+	// ID of the API key that made the change.
+	Actor  OptString `json:"actor"`
+	Reason OptString `json:"reason"`
+	At     time.Time `json:"at"`
+}
+
+// GetFrom returns the value of From.
+func (s *OrderStatusTransition) GetFrom() OptOrderStatus {
+	return s.From
+}
+
+// GetTo returns the value of To.
+func (s *OrderStatusTransition) GetTo() OrderStatus {
+	return s.To
+}
+
+// GetActor returns the value of Actor.
+func (s *OrderStatusTransition) GetActor() OptString {
+	return s.Actor
+}
+
+// GetReason returns the value of Reason.
+func (s *OrderStatusTransition) GetReason() OptString {
+	return s.Reason
+}
+
+// GetAt returns the value of At.
+func (s *OrderStatusTransition) GetAt() time.Time {
+	return s.At
+}
+
+// SetFrom sets the value of From.
+func (s *OrderStatusTransition) SetFrom(val OptOrderStatus) {
+	s.From = val
+}
+
+// SetTo sets the value of To.
+func (s *OrderStatusTransition) SetTo(val OrderStatus) {
+	s.To = val
+}
+
+// SetActor sets the value of Actor.
+func (s *OrderStatusTransition) SetActor(val OptString) {
+	s.Actor = val
+}
+
+// SetReason sets the value of Reason.
+func (s *OrderStatusTransition) SetReason(val OptString) {
+	s.Reason = val
+}
+
+// SetAt sets the value of At.
+func (s *OrderStatusTransition) SetAt(val time.Time) {
+	s.At = val
+}
+
+// Ref: #/components/schemas/OrderStatusUpdate
+type OrderStatusUpdate struct {
+	Status OrderStatus `json:"status"`
+	// Optional note stored with the transition.
+	Reason OptString `json:"reason"`
+}
+
+// GetStatus returns the value of Status.
+func (s *OrderStatusUpdate) GetStatus() OrderStatus {
+	return s.Status
+}
+
+// GetReason returns the value of Reason.
+func (s *OrderStatusUpdate) GetReason() OptString {
+	return s.Reason
+}
+
+// SetStatus sets the value of Status.
+func (s *OrderStatusUpdate) SetStatus(val OrderStatus) {
+	s.Status = val
+}
+
+// SetReason sets the value of Reason.
+func (s *OrderStatusUpdate) SetReason(val OptString) {
+	s.Reason = val
+}
+
+type PlaceOrderBadRequest Error
+
+func (*PlaceOrderBadRequest) placeOrderRes() {}
+
+type PlaceOrderConflict Error
+
+func (*PlaceOrderConflict) placeOrderRes() {}
+
+type PlaceOrderForbidden Error
+
+func (*PlaceOrderForbidden) placeOrderRes() {}
+
+type PlaceOrderUnauthorized Error
+
+func (*PlaceOrderUnauthorized) placeOrderRes() {}
+
+type PlaceOrderUnprocessableEntity Error
+
+func (*PlaceOrderUnprocessableEntity) placeOrderRes() {}
+
+// Ref: #/components/schemas/Product
+type Product struct {
+	ID       OptString       `json:"id"`
+	Name     OptString       `json:"name"`
+	Price    OptMoney        `json:"price"`
+	Category OptString       `json:"category"`
+	Image    OptProductImage `json:"image"`
+}
+
+// GetID returns the value of ID.
+func (s *Product) GetID() OptString {
+	return s.ID
+}
+
+// GetName returns the value of Name.
+func (s *Product) GetName() OptString {
+	return s.Name
+}
+
+// GetPrice returns the value of Price.
+func (s *Product) GetPrice() OptMoney {
+	return s.Price
+}
+
+// GetCategory returns the value of Category.
+func (s *Product) GetCategory() OptString {
+	return s.Category
+}
+
+// GetImage returns the value of Image.
+func (s *Product) GetImage() OptProductImage {
+	return s.Image
+}
+
+// SetID sets the value of ID.
+func (s *Product) SetID(val OptString) {
+	s.ID = val
+}
+
+// SetName sets the value of Name.
+func (s *Product) SetName(val OptString) {
+	s.Name = val
+}
+
+// SetPrice sets the value of Price.
+func (s *Product) SetPrice(val OptMoney) {
+	s.Price = val
+}
+
+// SetCategory sets the value of Category.
+func (s *Product) SetCategory(val OptString) {
+	s.Category = val
+}
+
+// SetImage sets the value of Image.
+func (s *Product) SetImage(val OptProductImage) {
+	s.Image = val
+}
+
+func (*Product) getProductRes() {}
+
+// Ref: #/components/schemas/ProductImage
+type ProductImage struct {
+	Thumbnail OptString `json:"thumbnail"`
+	Mobile    OptString `json:"mobile"`
+	Tablet    OptString `json:"tablet"`
+	Desktop   OptString `json:"desktop"`
+}
+
+// GetThumbnail returns the value of Thumbnail.
+func (s *ProductImage) GetThumbnail() OptString {
+	return s.Thumbnail
+}
+
+// GetMobile returns the value of Mobile.
+func (s *ProductImage) GetMobile() OptString {
+	return s.Mobile
+}
+
+// GetTablet returns the value of Tablet.
+func (s *ProductImage) GetTablet() OptString {
+	return s.Tablet
+}
+
+// GetDesktop returns the value of Desktop.
+func (s *ProductImage) GetDesktop() OptString {
+	return s.Desktop
+}
+
+// SetThumbnail sets the value of Thumbnail.
+func (s *ProductImage) SetThumbnail(val OptString) {
+	s.Thumbnail = val
+}
+
+// SetMobile sets the value of Mobile.
+func (s *ProductImage) SetMobile(val OptString) {
+	s.Mobile = val
+}
+
+// SetTablet sets the value of Tablet.
+func (s *ProductImage) SetTablet(val OptString) {
+	s.Tablet = val
+}
+
+// SetDesktop sets the value of Desktop.
+func (s *ProductImage) SetDesktop(val OptString) {
+	s.Desktop = val
+}
+
+// Ref: #/components/schemas/Quote
+type Quote struct {
+	Subtotal Money `json:"subtotal"`
+	Discount Money `json:"discount"`
+	Total    Money `json:"total"`
+	// First coupon code that was evaluated.
+	CouponCode OptString `json:"couponCode"`
+	// Human-readable description of the applied coupons.
+	CouponDescription OptString `json:"couponDescription"`
+	// What each coupon would contribute to `discount`, in the order they apply.
+	Coupons []AppliedCoupon `json:"coupons"`
+	// Why the coupons were not applied; absent when they were.
+	CouponError OptString   `json:"couponError"`
+	Items       []OrderItem `json:"items"`
+	Products    []Product   `json:"products"`
+}
+
+// GetSubtotal returns the value of Subtotal.
+func (s *Quote) GetSubtotal() Money {
+	return s.Subtotal
+}
+
+// GetDiscount returns the value of Discount.
+func (s *Quote) GetDiscount() Money {
+	return s.Discount
+}
+
+// GetTotal returns the value of Total.
+func (s *Quote) GetTotal() Money {
+	return s.Total
+}
+
+// GetCouponCode returns the value of CouponCode.
+func (s *Quote) GetCouponCode() OptString {
+	return s.CouponCode
+}
+
+// GetCouponDescription returns the value of CouponDescription.
+func (s *Quote) GetCouponDescription() OptString {
+	return s.CouponDescription
+}
+
+// GetCoupons returns the value of Coupons.
+func (s *Quote) GetCoupons() []AppliedCoupon {
+	return s.Coupons
+}
+
+// GetCouponError returns the value of CouponError.
+func (s *Quote) GetCouponError() OptString {
+	return s.CouponError
+}
+
+// GetItems returns the value of Items.
+func (s *Quote) GetItems() []OrderItem {
+	return s.Items
+}
+
+// GetProducts returns the value of Products.
+func (s *Quote) GetProducts() []Product {
+	return s.Products
+}
+
+// SetSubtotal sets the value of Subtotal.
+func (s *Quote) SetSubtotal(val Money) {
+	s.Subtotal = val
+}
+
+// SetDiscount sets the value of Discount.
+func (s *Quote) SetDiscount(val Money) {
+	s.Discount = val
+}
+
+// SetTotal sets the value of Total.
+func (s *Quote) SetTotal(val Money) {
+	s.Total = val
+}
+
+// SetCouponCode sets the value of CouponCode.
+func (s *Quote) SetCouponCode(val OptString) {
+	s.CouponCode = val
+}
+
+// SetCouponDescription sets the value of CouponDescription.
+func (s *Quote) SetCouponDescription(val OptString) {
+	s.CouponDescription = val
+}
+
+// SetCoupons sets the value of Coupons.
+func (s *Quote) SetCoupons(val []AppliedCoupon) {
+	s.Coupons = val
+}
+
+// SetCouponError sets the value of CouponError.
+func (s *Quote) SetCouponError(val OptString) {
+	s.CouponError = val
+}
+
+// SetItems sets the value of Items.
+func (s *Quote) SetItems(val []OrderItem) {
+	s.Items = val
+}
+
+// SetProducts sets the value of Products.
+func (s *Quote) SetProducts(val []Product) {
+	s.Products = val
+}
+
+func (*Quote) quoteOrderRes() {}
+
+type QuoteOrderBadRequest Error
+
+func (*QuoteOrderBadRequest) quoteOrderRes() {}
+
+type QuoteOrderForbidden Error
+
+func (*QuoteOrderForbidden) quoteOrderRes() {}
+
+type QuoteOrderUnauthorized Error
+
+func (*QuoteOrderUnauthorized) quoteOrderRes() {}
+
+type QuoteOrderUnprocessableEntity Error
+
+func (*QuoteOrderUnprocessableEntity) quoteOrderRes() {}
+
+type RemoveCartItemForbidden Error
+
+func (*RemoveCartItemForbidden) removeCartItemRes() {}
+
+type RemoveCartItemNotFound Error
+
+func (*RemoveCartItemNotFound) removeCartItemRes() {}
+
+type RemoveCartItemUnauthorized Error
+
+func (*RemoveCartItemUnauthorized) removeCartItemRes() {}
+
+type RemoveCartItemUnprocessableEntity Error
+
+func (*RemoveCartItemUnprocessableEntity) removeCartItemRes() {}
+
+type SetCartItemForbidden Error
+
+func (*SetCartItemForbidden) setCartItemRes() {}
+
+type SetCartItemNotFound Error
+
+func (*SetCartItemNotFound) setCartItemRes() {}
+
+type SetCartItemUnauthorized Error
+
+func (*SetCartItemUnauthorized) setCartItemRes() {}
+
+type SetCartItemUnprocessableEntity Error
+
+func (*SetCartItemUnprocessableEntity) setCartItemRes() {}
+
+type SignedRequest struct {
+	APIKey string
+	Roles  []string
+}
+
+// GetAPIKey returns the value of APIKey.
+func (s *SignedRequest) GetAPIKey() string {
+	return s.APIKey
+}
+
+// GetRoles returns the value of Roles.
+func (s *SignedRequest) GetRoles() []string {
+	return s.Roles
+}
+
+// SetAPIKey sets the value of APIKey.
+func (s *SignedRequest) SetAPIKey(val string) {
+	s.APIKey = val
+}
+
+// SetRoles sets the value of Roles.
+func (s *SignedRequest) SetRoles(val []string) {
+	s.Roles = val
+}
+
+type UpdateOrderStatusBadRequest Error
+
+func (*UpdateOrderStatusBadRequest) updateOrderStatusRes() {}
+
+type UpdateOrderStatusConflict Error
+
+func (*UpdateOrderStatusConflict) updateOrderStatusRes() {}
+
+type UpdateOrderStatusForbidden Error
+
+func (*UpdateOrderStatusForbidden) updateOrderStatusRes() {}
+
+type UpdateOrderStatusNotFound Error
+
+func (*UpdateOrderStatusNotFound) updateOrderStatusRes() {}
+
+type UpdateOrderStatusUnauthorized Error
+
+func (*UpdateOrderStatusUnauthorized) updateOrderStatusRes() {}
@@ -0,0 +1,247 @@
+// Code generated by ogen, DO NOT EDIT.
+
+package oasv2
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-faster/errors"
+	"github.com/ogen-go/ogen/ogenerrors"
+)
+
+// SecurityHandler is handler for security parameters.
+type SecurityHandler interface {
+	// HandleAPIKey handles api_key security.
+	HandleAPIKey(ctx context.Context, operationName OperationName, t APIKey) (context.Context, error)
+	// HandleBearerAuth handles bearerAuth security.
+	// RS256 or ES256 JWT signed by a key in the server's JWKS file. The
+	// `scope` claim (space-separated) or `scp` claim (array) grants the same
+	// scopes as API keys.
+	HandleBearerAuth(ctx context.Context, operationName OperationName, t BearerAuth) (context.Context, error)
+	// HandleSignedRequest handles signedRequest security.
+	// Hex HMAC-SHA256, under the key's signing key, of the lines METHOD,
+	// escaped path, raw query, X-Kart-Timestamp (Unix seconds), X-Kart-Nonce
+	// (16-128 characters) and the hex SHA-256 of the body, joined with "\n".
+	// The key is named by X-Kart-Key-Id. Its signing key is the
+	// HMAC-SHA256 of "kart-request-signing-v1" keyed with the key's secret.
+	// Timestamps more than the allowed skew from the server clock, and
+	// nonces the key has already used, are refused.
+	HandleSignedRequest(ctx context.Context, operationName OperationName, t SignedRequest) (context.Context, error)
+}
+
+func findAuthorization(h http.Header, prefix string) (string, bool) {
+	v, ok := h["Authorization"]
+	if !ok {
+		return "", false
+	}
+	for _, vv := range v {
+		scheme, value, ok := strings.Cut(vv, " ")
+		if !ok || !strings.EqualFold(scheme, prefix) {
+			continue
+		}
+		return value, true
+	}
+	return "", false
+}
+
+// operationRolesAPIKey is a private map storing roles per operation.
+var operationRolesAPIKey = map[string][]string{
+	AttachCartCouponOperation:  []string{},
+	CancelOrderOperation:       []string{},
+	CheckoutCartOperation:      []string{},
+	CreateCartOperation:        []string{},
+	DetachCartCouponOperation:  []string{},
+	GetCartOperation:           []string{},
+	GetOrderOperation:          []string{},
+	ListOrdersOperation:        []string{},
+	PlaceOrderOperation:        []string{},
+	QuoteOrderOperation:        []string{},
+	RemoveCartItemOperation:    []string{},
+	SetCartItemOperation:       []string{},
+	UpdateOrderStatusOperation: []string{},
+}
+
+// GetRolesForAPIKey returns the required roles for the given operation.
+//
+// This is useful for authorization scenarios where you need to know which roles
+// are required for an operation.
+//
+// Example:
+//
+//	requiredRoles := GetRolesForAPIKey(AddPetOperation)
+//
+// Returns nil if the operation has no role requirements or if the operation is unknown.
+func GetRolesForAPIKey(operation string) []string {
+	roles, ok := operationRolesAPIKey[operation]
+	if !ok {
+		return nil
+	}
+	// Return a copy to prevent external modification
+	result := make([]string, len(roles))
+	copy(result, roles)
+	return result
+}
+
+// operationRolesBearerAuth is a private map storing roles per operation.
+var operationRolesBearerAuth = map[string][]string{
+	AttachCartCouponOperation:  []string{},
+	CancelOrderOperation:       []string{},
+	CheckoutCartOperation:      []string{},
+	CreateCartOperation:        []string{},
+	DetachCartCouponOperation:  []string{},
+	GetCartOperation:           []string{},
+	GetOrderOperation:          []string{},
+	ListOrdersOperation:        []string{},
+	PlaceOrderOperation:        []string{},
+	QuoteOrderOperation:        []string{},
+	RemoveCartItemOperation:    []string{},
+	SetCartItemOperation:       []string{},
+	UpdateOrderStatusOperation: []string{},
+}
+
+// GetRolesForBearerAuth returns the required roles for the given operation.
+//
+// This is useful for authorization scenarios where you need to know which roles
+// are required for an operation.
+//
+// Example:
+//
+//	requiredRoles := GetRolesForBearerAuth(AddPetOperation)
+//
+// Returns nil if the operation has no role requirements or if the operation is unknown.
+func GetRolesForBearerAuth(operation string) []string {
+	roles, ok := operationRolesBearerAuth[operation]
+	if !ok {
+		return nil
+	}
+	// Return a copy to prevent external modification
+	result := make([]string, len(roles))
+	copy(result, roles)
+	return result
+}
+
+// operationRolesSignedRequest is a private map storing roles per operation.
+var operationRolesSignedRequest = map[string][]string{
+	PlaceOrderOperation: []string{},
+}
+
+// GetRolesForSignedRequest returns the required roles for the given operation.
+//
+// This is useful for authorization scenarios where you need to know which roles
+// are required for an operation.
+//
+// Example:
+//
+//	requiredRoles := GetRolesForSignedRequest(AddPetOperation)
+//
+// Returns nil if the operation has no role requirements or if the operation is unknown.
+func GetRolesForSignedRequest(operation string) []string {
+	roles, ok := operationRolesSignedRequest[operation]
+	if !ok {
+		return nil
+	}
+	// Return a copy to prevent external modification
+	result := make([]string, len(roles))
+	copy(result, roles)
+	return result
+}
+
+func (s *Server) securityAPIKey(ctx context.Context, operationName OperationName, req *http.Request) (context.Context, bool, error) {
+	var t APIKey
+	const parameterName = "api_key"
+	value := req.Header.Get(parameterName)
+	if value == "" {
+		return ctx, false, nil
+	}
+	t.APIKey = value
+	t.Roles = operationRolesAPIKey[operationName]
+	rctx, err := s.sec.HandleAPIKey(ctx, operationName, t)
+	if errors.Is(err, ogenerrors.ErrSkipServerSecurity) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return rctx, true, err
+}
+
+func (s *Server) securityBearerAuth(ctx context.Context, operationName OperationName, req *http.Request) (context.Context, bool, error) {
+	var t BearerAuth
+	token, ok := findAuthorization(req.Header, "Bearer")
+	if !ok {
+		return ctx, false, nil
+	}
+	t.Token = token
+	t.Roles = operationRolesBearerAuth[operationName]
+	rctx, err := s.sec.HandleBearerAuth(ctx, operationName, t)
+	if errors.Is(err, ogenerrors.ErrSkipServerSecurity) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return rctx, true, err
+}
+
+func (s *Server) securitySignedRequest(ctx context.Context, operationName OperationName, req *http.Request) (context.Context, bool, error) {
+	var t SignedRequest
+	const parameterName = "X-Kart-Signature"
+	value := req.Header.Get(parameterName)
+	if value == "" {
+		return ctx, false, nil
+	}
+	t.APIKey = value
+	t.Roles = operationRolesSignedRequest[operationName]
+	rctx, err := s.sec.HandleSignedRequest(ctx, operationName, t)
+	if errors.Is(err, ogenerrors.ErrSkipServerSecurity) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return rctx, true, err
+}
+
+// SecuritySource is provider of security values (tokens, passwords, etc.).
+type SecuritySource interface {
+	// APIKey provides api_key security value.
+	APIKey(ctx context.Context, operationName OperationName) (APIKey, error)
+	// BearerAuth provides bearerAuth security value.
+	// RS256 or ES256 JWT signed by a key in the server's JWKS file. The
+	// `scope` claim (space-separated) or `scp` claim (array) grants the same
+	// scopes as API keys.
+	BearerAuth(ctx context.Context, operationName OperationName) (BearerAuth, error)
+	// SignedRequest provides signedRequest security value.
+	// Hex HMAC-SHA256, under the key's signing key, of the lines METHOD,
+	// escaped path, raw query, X-Kart-Timestamp (Unix seconds), X-Kart-Nonce
+	// (16-128 characters) and the hex SHA-256 of the body, joined with "\n".
+	// The key is named by X-Kart-Key-Id. Its signing key is the
+	// HMAC-SHA256 of "kart-request-signing-v1" keyed with the key's secret.
+	// Timestamps more than the allowed skew from the server clock, and
+	// nonces the key has already used, are refused.
+	SignedRequest(ctx context.Context, operationName OperationName) (SignedRequest, error)
+}
+
+func (s *Client) securityAPIKey(ctx context.Context, operationName OperationName, req *http.Request) error {
+	t, err := s.sec.APIKey(ctx, operationName)
+	if err != nil {
+		return errors.Wrap(err, "security source \"APIKey\"")
+	}
+	req.Header.Set("api_key", t.APIKey)
+	return nil
+}
+func (s *Client) securityBearerAuth(ctx context.Context, operationName OperationName, req *http.Request) error {
+	t, err := s.sec.BearerAuth(ctx, operationName)
+	if err != nil {
+		return errors.Wrap(err, "security source \"BearerAuth\"")
+	}
+	req.Header.Set("Authorization", "Bearer "+t.Token)
+	return nil
+}
+func (s *Client) securitySignedRequest(ctx context.Context, operationName OperationName, req *http.Request) error {
+	t, err := s.sec.SignedRequest(ctx, operationName)
+	if err != nil {
+		return errors.Wrap(err, "security source \"SignedRequest\"")
+	}
+	req.Header.Set("X-Kart-Signature", t.APIKey)
+	return nil
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/go-faster/errors"
@@ -11,7 +12,12 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/xenking/oolio-kart-challenge/gen/oas"
+	"github.com/xenking/oolio-kart-challenge/gen/oasv2"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/audit"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/auth"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/cart"
 	"github.com/xenking/oolio-kart-challenge/internal/domain/coupon"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/idempotency"
 	"github.com/xenking/oolio-kart-challenge/internal/domain/order"
 	"github.com/xenking/oolio-kart-challenge/internal/handler"
 	"github.com/xenking/oolio-kart-challenge/internal/repository"
@@ -49,21 +55,139 @@ func Run(ctx context.Context, lg *zap.Logger, m *app.Telemetry, cfg *Config) err
 	couponRepo := repository.NewCouponRepository(pool)
 	orderRepo := repository.NewOrderRepository(pool)
 	apikeyRepo := repository.NewAPIKeyRepository(pool)
+	idempotencyRepo := repository.NewIdempotencyRepository(pool)
+	cartRepo := repository.NewCartRepository(pool)
+	auditRepo := repository.NewAuditRepository(pool)
+	transactor := repository.NewTransactor(pool)
 
 	// Domain services.
 	couponValidator := coupon.NewRepoValidator(couponRepo)
-	orderService := order.NewService(productRepo, couponValidator, orderRepo)
+	orderService := order.NewService(
+		order.ServiceConfig{CancelWindow: cfg.Orders.CancelWindow},
+		productRepo, couponValidator, orderRepo, transactor,
+	)
+	cartService := cart.NewService(
+		cart.ServiceConfig{TTL: cfg.Carts.TTL},
+		cartRepo, productRepo, orderService, transactor,
+	)
+	idempotencySvc := idempotency.NewService(idempotencyRepo, cfg.Idempotency.TTL)
+	auditSvc := audit.NewService(auditRepo)
+	couponSvc := coupon.NewService(couponRepo)
+	go runPruner(ctx, lg, "idempotency keys", cfg.Idempotency.PruneInterval, idempotencySvc.Prune)
+	go runPruner(ctx, lg, "carts", cfg.Carts.PruneInterval, cartService.Prune)
+	var couponGuard *coupon.Guard
+	if cfg.CouponGuard.MaxAttempts > 0 {
+		var attemptStore coupon.AttemptStore
+		switch cfg.CouponGuard.Store {
+		case "postgres":
+			attemptStore = repository.NewCouponAttemptRepository(pool)
+		case "memory":
+			attemptStore = coupon.NewMemoryAttemptStore()
+		default:
+			return errors.Errorf("unknown coupon guard store %q", cfg.CouponGuard.Store)
+		}
+		couponGuard, err = coupon.NewGuard(coupon.GuardConfig{
+			FreeAttempts: cfg.CouponGuard.FreeAttempts,
+			Backoff:      cfg.CouponGuard.Backoff,
+			MaxAttempts:  cfg.CouponGuard.MaxAttempts,
+			Lockout:      cfg.CouponGuard.Lockout,
+			Window:       cfg.CouponGuard.Window,
+		}, attemptStore, m.MeterProvider())
+		if err != nil {
+			return errors.Wrap(err, "create coupon guard")
+		}
+		go runPruner(ctx, lg, "coupon attempts", cfg.CouponGuard.PruneInterval, couponGuard.Prune)
+	}
 
 	// HTTP handlers.
 	h := handler.NewHandler(
-		handler.HandlerConfig{ImageBaseURL: cfg.ImageBaseURL},
+		handler.HandlerConfig{ImageBaseURL: cfg.ImageBaseURL, Currency: cfg.Currency},
 		productRepo,
 		orderService,
+		cartService,
+		idempotencySvc,
+		auditSvc,
+		couponSvc,
+		couponGuard,
 	)
-	securityHandler := handler.NewSecurityHandler(apikeyRepo, []byte(cfg.APIKeyPepper))
+	peppers, err := auth.ParsePeppers(cfg.APIKeyPeppers, cfg.APIKeyPepper)
+	if err != nil {
+		return errors.Wrap(err, "parse api key peppers")
+	}
+	var keyLookup auth.Repository = apikeyRepo
+	if cfg.APIKeys.CacheSize > 0 {
+		keyCache, err := auth.NewCachedRepository(apikeyRepo, auth.CacheConfig{
+			Size:        cfg.APIKeys.CacheSize,
+			TTL:         cfg.APIKeys.CacheTTL,
+			NegativeTTL: cfg.APIKeys.CacheNegativeTTL,
+		}, m.MeterProvider())
+		if err != nil {
+			return errors.Wrap(err, "create api key cache")
+		}
+		go runKeyCacheInvalidator(ctx, lg, apikeyRepo, keyCache)
+		keyLookup = keyCache
+	}
+	keyUsage := auth.NewUsageTracker(apikeyRepo)
+	go runUsageFlusher(ctx, lg, cfg.APIKeys.LastUsedFlushInterval, keyUsage)
+
+	var tokens *auth.TokenVerifier
+	if cfg.JWT.JWKSFile != "" {
+		jwks, err := auth.LoadJWKS(cfg.JWT.JWKSFile)
+		if err != nil {
+			return errors.Wrap(err, "load jwks")
+		}
+		tokens = auth.NewTokenVerifier(auth.TokenConfig{
+			Issuer:   cfg.JWT.Issuer,
+			Audience: cfg.JWT.Audience,
+			Leeway:   cfg.JWT.Leeway,
+		}, jwks)
+		go runJWKSReloader(ctx, lg, cfg.JWT.JWKSFile, cfg.JWT.ReloadInterval, tokens)
+	}
+	securityHandler := handler.NewSecurityHandler(keyLookup, peppers, keyUsage, tokens)
+	trustedProxies, err := httpmiddleware.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return errors.Wrap(err, "parse trusted proxies")
+	}
+	ratePlans, err := httpmiddleware.ParseRateLimitPolicies(cfg.RateLimit.Plans)
+	if err != nil {
+		return errors.Wrap(err, "parse rate limit plans")
+	}
+	rateOperations, err := httpmiddleware.ParseRateLimitPolicies(cfg.RateLimit.Operations)
+	if err != nil {
+		return errors.Wrap(err, "parse rate limit operations")
+	}
+	var rateStore interface {
+		httpmiddleware.RateLimitStore
+		Prune(context.Context) (int64, error)
+	}
+	switch cfg.RateLimit.Store {
+	case "postgres":
+		rateStore = repository.NewRateLimitRepository(pool)
+	case "memory":
+		rateStore = httpmiddleware.NewMemoryRateLimitStore()
+	default:
+		return errors.Errorf("unknown rate limit store %q", cfg.RateLimit.Store)
+	}
+	go runPruner(ctx, lg, "rate limit counters", cfg.RateLimit.PruneInterval, rateStore.Prune)
+
+	var nonces interface {
+		auth.NonceStore
+		Prune(context.Context) (int64, error)
+	}
+	switch cfg.Signing.NonceStore {
+	case "postgres":
+		nonces = repository.NewNonceRepository(pool)
+	case "memory":
+		nonces = auth.NewMemoryNonceStore()
+	default:
+		return errors.Errorf("unknown signing nonce store %q", cfg.Signing.NonceStore)
+	}
+	go runPruner(ctx, lg, "signed request nonces", cfg.Signing.NoncePruneInterval, nonces.Prune)
+	requestVerifier := auth.NewRequestVerifier(apikeyRepo, peppers, nonces, cfg.Signing.MaxSkew)
 
 	oasServer, err := oas.NewServer(h, securityHandler,
 		oas.WithPathPrefix("/api"),
+		oas.WithErrorHandler(handler.ErrorHandler),
 		oas.WithTracerProvider(m.TracerProvider()),
 		oas.WithMeterProvider(m.MeterProvider()),
 	)
@@ -71,12 +195,27 @@ func Run(ctx context.Context, lg *zap.Logger, m *app.Telemetry, cfg *Config) err
 		return errors.Wrap(err, "create oas server")
 	}
 
+	// /api/v2 serves the same operations with decimal-string money.
+	oasV2Server, err := oasv2.NewServer(handler.NewV2Handler(h), securityHandler.V2(),
+		oasv2.WithPathPrefix("/api/v2"),
+		oasv2.WithErrorHandler(handler.ErrorHandler),
+		oasv2.WithTracerProvider(m.TracerProvider()),
+		oasv2.WithMeterProvider(m.MeterProvider()),
+	)
+	if err != nil {
+		return errors.Wrap(err, "create oas v2 server")
+	}
+
 	// Mux: health endpoints + ogen API routes on one server.
-	routeFinder := httpmiddleware.MakeRouteFinder(oasServer)
+	routeFinder := httpmiddleware.ChainRouteFinders(
+		httpmiddleware.MakeRouteFinder(oasV2Server),
+		httpmiddleware.MakeRouteFinder(oasServer),
+	)
 	mux := http.NewServeMux()
 	mux.HandleFunc("/livez", healthSvc.LiveEndpoint)
 	mux.HandleFunc("/readyz", healthSvc.ReadyEndpoint)
 	mux.Handle("/api/", oasServer)
+	mux.Handle("/api/v2/", oasV2Server)
 
 	server := &http.Server{
 		ReadHeaderTimeout: time.Second,
@@ -88,20 +227,33 @@ func Run(ctx context.Context, lg *zap.Logger, m *app.Telemetry, cfg *Config) err
 		Handler: httpmiddleware.Wrap(mux,
 			httpmiddleware.Recovery(),
 			httpmiddleware.CORS(httpmiddleware.CORSConfig{
-				AllowOrigins:     cfg.CORS.Origins,
-				AllowHeaders:     []string{"Content-Type", "Authorization", "api_key"},
+				AllowOrigins: cfg.CORS.Origins,
+				AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+				AllowHeaders: []string{
+					"Content-Type", "Authorization", "api_key", "Idempotency-Key",
+					handler.HeaderKeyID, handler.HeaderTimestamp, handler.HeaderNonce, handler.HeaderSignature,
+				},
 				AllowCredentials: cfg.CORS.AllowCredentials,
 				MaxAge:           86400,
 			}),
-			httpmiddleware.RateLimitWithCleanup(ctx, httpmiddleware.RateLimitConfig{
-				Max:    cfg.RateLimit.Max,
-				Window: cfg.RateLimit.Window,
-			}),
 			httpmiddleware.RequestID(),
+			httpmiddleware.ClientIP(trustedProxies),
 			httpmiddleware.InjectLogger(zctx.From(ctx)),
+			securityHandler.ResolveAPIKeys(),
+			httpmiddleware.RateLimit(httpmiddleware.RateLimitConfig{
+				Max:        cfg.RateLimit.Max,
+				Window:     cfg.RateLimit.Window,
+				KeyFunc:    securityHandler.RateLimitKey,
+				Plans:      ratePlans,
+				Operations: rateOperations,
+				Routes:     routeFinder,
+				Store:      rateStore,
+			}),
 			httpmiddleware.Instrument("kart-api", routeFinder, m),
 			httpmiddleware.LogRequests(routeFinder),
 			httpmiddleware.Labeler(routeFinder),
+			handler.AuditMutations(auditSvc, routeFinder),
+			handler.VerifySignedRequests(requestVerifier),
 		),
 	}
 
@@ -120,6 +272,9 @@ func Run(ctx context.Context, lg *zap.Logger, m *app.Telemetry, cfg *Config) err
 		if err := server.Shutdown(shutdownCtx); err != nil {
 			lg.Error("Server shutdown error", zap.Error(err))
 		}
+		if _, err := keyUsage.Flush(shutdownCtx); err != nil {
+			lg.Warn("Failed to record API key use", zap.Error(err))
+		}
 		healthSvc.Stop()
 		close(shutdownDone)
 	}()
@@ -131,3 +286,103 @@ func Run(ctx context.Context, lg *zap.Logger, m *app.Telemetry, cfg *Config) err
 	<-shutdownDone
 	return nil
 }
+
+// runPruner calls prune every interval until ctx is cancelled. what names
+// the records being pruned in log messages.
+func runPruner(ctx context.Context, lg *zap.Logger, what string, interval time.Duration, prune func(context.Context) (int64, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := prune(ctx)
+			if err != nil {
+				lg.Warn("Failed to prune "+what, zap.Error(err))
+				continue
+			}
+			if n > 0 {
+				lg.Debug("Pruned "+what, zap.Int64("count", n))
+			}
+		}
+	}
+}
+
+// runUsageFlusher writes batched API key last-use times every interval until
+// ctx is cancelled. The final batch is flushed during shutdown.
+func runUsageFlusher(ctx context.Context, lg *zap.Logger, interval time.Duration, usage *auth.UsageTracker) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := usage.Flush(ctx); err != nil {
+				lg.Warn("Failed to record API key use", zap.Error(err))
+			}
+		}
+	}
+}
+
+// runJWKSReloader checks the JWKS file every interval until ctx is
+// cancelled and swaps in its keys when it changes. A file that fails to
+// load is logged and the previous keys stay in use.
+func runJWKSReloader(ctx context.Context, lg *zap.Logger, path string, interval time.Duration, tokens *auth.TokenVerifier) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last os.FileInfo
+	if fi, err := os.Stat(path); err == nil {
+		last = fi
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fi, err := os.Stat(path)
+			if err != nil {
+				lg.Warn("Failed to check JWKS file", zap.Error(err))
+				continue
+			}
+			if last != nil && fi.ModTime().Equal(last.ModTime()) && fi.Size() == last.Size() {
+				continue
+			}
+			jwks, err := auth.LoadJWKS(path)
+			if err != nil {
+				lg.Warn("Failed to reload JWKS file, keeping previous keys", zap.Error(err))
+				continue
+			}
+			last = fi
+			tokens.SetKeys(jwks)
+			lg.Info("Reloaded JWKS file", zap.String("path", path))
+		}
+	}
+}
+
+// keyCacheRelistenDelay is how long runKeyCacheInvalidator waits before
+// listening again after the connection fails.
+const keyCacheRelistenDelay = 5 * time.Second
+
+// runKeyCacheInvalidator drops cached API keys as change notifications
+// arrive, until ctx is cancelled. The cache is reset whenever listening
+// (re)starts, since changes made while not listening were missed.
+func runKeyCacheInvalidator(ctx context.Context, lg *zap.Logger, repo *repository.APIKeyRepository, cache *auth.CachedRepository) {
+	for {
+		err := repo.ListenKeyChanges(ctx, cache.Reset, cache.Invalidate)
+		if ctx.Err() != nil {
+			return
+		}
+		lg.Warn("API key change listener stopped", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(keyCacheRelistenDelay):
+		}
+	}
+}
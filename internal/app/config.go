@@ -16,15 +16,59 @@ type Config struct {
 	DatabaseURL  string `usage:"PostgreSQL connection URL (KART_DATABASE_URL or DATABASE_URL)" flag:"database-url"`
 	ImageBaseURL string `default:"" usage:"Base URL for product images (e.g. https://cdn.example.com/images)" flag:"image-base-url"`
 	APIKeyPepper string `usage:"HMAC pepper for API key hashing (KART_API_KEY_PEPPER)" flag:"api-key-pepper"`
-	RateLimit    RateLimitConfig
-	CORS         CORSConfig
-	Graceful     GracefulConfig
+	// APIKeyPeppers supersedes APIKeyPepper when set; see auth.ParsePeppers.
+	APIKeyPeppers []string `usage:"Versioned HMAC peppers as version:secret; the newest hashes new keys (KART_API_KEY_PEPPERS)" flag:"api-key-peppers"`
+	Currency      string   `default:"USD" usage:"ISO 4217 currency code reported with /api/v2 money amounts"`
+	APIKeys       APIKeysConfig
+	JWT           JWTConfig
+	Signing       SigningConfig
+	RateLimit     RateLimitConfig
+	CORS          CORSConfig
+	Orders        OrdersConfig
+	Carts         CartsConfig
+	CouponGuard   CouponGuardConfig
+	Idempotency   IdempotencyConfig
+	Graceful      GracefulConfig
+
+	// TrustedProxies lists the proxies whose forwarding headers are believed
+	// when resolving the client IP; see httpmiddleware.ClientIP.
+	TrustedProxies []string `usage:"CIDRs or IPs of reverse proxies allowed to set Forwarded/X-Forwarded-For" flag:"trusted-proxies"`
+}
+
+// APIKeysConfig controls API key bookkeeping.
+type APIKeysConfig struct {
+	LastUsedFlushInterval time.Duration `default:"30s" usage:"How often batched API key last-use times are written" flag:"api-key-last-used-flush-interval"`
+	CacheSize             int           `default:"10000" usage:"Max API key lookups cached in memory (0 disables the cache)" flag:"api-key-cache-size"`
+	CacheTTL              time.Duration `default:"1m" usage:"How long a found API key is served from the cache" flag:"api-key-cache-ttl"`
+	CacheNegativeTTL      time.Duration `default:"10s" usage:"How long an unknown API key is remembered (0 = not cached)" flag:"api-key-cache-negative-ttl"`
+}
+
+// JWTConfig controls bearer token authentication. It is disabled unless
+// JWKSFile is set.
+type JWTConfig struct {
+	JWKSFile       string        `usage:"Path to a JWKS file with the keys bearer tokens are signed with (empty disables bearer auth)" flag:"jwt-jwks-file"`
+	Issuer         string        `usage:"Required iss claim of bearer tokens (empty = any)" flag:"jwt-issuer"`
+	Audience       string        `usage:"Required aud claim of bearer tokens (empty = any)" flag:"jwt-audience"`
+	Leeway         time.Duration `default:"30s" usage:"Allowed clock skew when checking token times" flag:"jwt-leeway"`
+	ReloadInterval time.Duration `default:"1m" usage:"How often the JWKS file is checked for changes" flag:"jwt-reload-interval"`
+}
+
+// SigningConfig controls signed-request verification.
+type SigningConfig struct {
+	NonceStore         string        `default:"postgres" usage:"Where signed-request nonces are kept: postgres or memory (single instance only)" flag:"signing-nonce-store"`
+	MaxSkew            time.Duration `default:"5m" usage:"Max difference between a signed request's timestamp and the server clock" flag:"signing-max-skew"`
+	NoncePruneInterval time.Duration `default:"10m" usage:"How often expired signed-request nonces are deleted" flag:"signing-nonce-prune-interval"`
 }
 
 // RateLimitConfig controls the per-client sliding window rate limiter.
 type RateLimitConfig struct {
-	Max    int           `default:"100" usage:"Max requests per window"`
-	Window time.Duration `default:"1m"  usage:"Rate limit window duration"`
+	Max           int           `default:"100" usage:"Max requests per window"`
+	Window        time.Duration `default:"1m"  usage:"Rate limit window duration"`
+	Store         string        `default:"memory" usage:"Where request counts are kept: memory (per instance) or postgres (shared by all instances)"`
+	PruneInterval time.Duration `default:"1m"  usage:"How often expired rate limit counters are deleted"`
+	// Plans and Operations are name=max/window entries, e.g. "pro=1000/1m".
+	Plans      []string `usage:"Named limits API keys can be assigned to, as plan=max/window"`
+	Operations []string `usage:"Per-operation limits overriding any plan, as operationId=max/window"`
 }
 
 // CORSConfig controls Cross-Origin Resource Sharing headers.
@@ -33,6 +77,35 @@ type CORSConfig struct {
 	AllowCredentials bool     `default:"false" usage:"Allow credentials (cookies, auth headers)" flag:"cors-credentials"`
 }
 
+// OrdersConfig controls order lifecycle rules.
+type OrdersConfig struct {
+	CancelWindow time.Duration `default:"15m" usage:"How long after placement customers may cancel an order (0 = no limit)" flag:"cancel-window"`
+}
+
+// CouponGuardConfig controls the throttling of callers that present invalid
+// coupon codes; see coupon.Guard.
+type CouponGuardConfig struct {
+	FreeAttempts  int           `default:"3"   usage:"Distinct invalid coupon codes a caller may try before backing off"`
+	Backoff       time.Duration `default:"1s"  usage:"Wait after the first invalid code past the free attempts; doubles with each one"`
+	MaxAttempts   int           `default:"10"  usage:"Distinct invalid coupon codes that lock a caller out (0 = no throttling)"`
+	Lockout       time.Duration `default:"15m" usage:"How long a caller is refused coupon codes after MaxAttempts"`
+	Window        time.Duration `default:"1h"  usage:"How long invalid codes are remembered after a caller's last one"`
+	Store         string        `default:"memory" usage:"Where invalid coupon attempts are kept: memory (per instance) or postgres (shared by all instances)"`
+	PruneInterval time.Duration `default:"5m"  usage:"How often idle callers are forgotten"`
+}
+
+// CartsConfig controls retention of server-side carts.
+type CartsConfig struct {
+	TTL           time.Duration `default:"72h" usage:"How long a cart is kept after its last change" flag:"cart-ttl"`
+	PruneInterval time.Duration `default:"1h"  usage:"How often expired carts are deleted" flag:"cart-prune-interval"`
+}
+
+// IdempotencyConfig controls retention of Idempotency-Key records.
+type IdempotencyConfig struct {
+	TTL           time.Duration `default:"24h" usage:"How long Idempotency-Key responses are kept for replay" flag:"idempotency-ttl"`
+	PruneInterval time.Duration `default:"1h"  usage:"How often expired Idempotency-Key records are deleted" flag:"idempotency-prune-interval"`
+}
+
 // GracefulConfig controls graceful shutdown timing.
 type GracefulConfig struct {
 	ReadinessDelay  time.Duration `default:"3s"  usage:"Delay after readiness=false before shutdown" flag:"readiness-delay"`
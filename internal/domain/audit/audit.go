@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Outcome summarizes how an audited request ended.
+type Outcome string
+
+const (
+	// OutcomeSuccess is a request that was carried out (2xx).
+	OutcomeSuccess Outcome = "success"
+	// OutcomeDenied is a request refused for lack of a scope or key (401, 403).
+	OutcomeDenied Outcome = "denied"
+	// OutcomeRejected is a request refused as invalid or conflicting (other 4xx).
+	OutcomeRejected Outcome = "rejected"
+	// OutcomeFailed is a request that failed on the server (5xx).
+	OutcomeFailed Outcome = "failed"
+)
+
+// OutcomeForStatus maps an HTTP response status to an Outcome.
+func OutcomeForStatus(code int) Outcome {
+	switch {
+	case code < 400:
+		return OutcomeSuccess
+	case code == 401 || code == 403:
+		return OutcomeDenied
+	case code < 500:
+		return OutcomeRejected
+	default:
+		return OutcomeFailed
+	}
+}
+
+// Event is one audited request.
+type Event struct {
+	ID int64
+	// Actor is the API key ID (or jwt:<sub>) that made the request.
+	Actor string
+	// Operation is the OpenAPI operation ID, such as placeOrder.
+	Operation string
+	RequestID string
+	// ClientIP is the caller's address as resolved behind trusted proxies.
+	ClientIP string
+	// TargetType and TargetID name the entity acted on, such as an order.
+	// TargetID is empty when the request failed before one was known.
+	TargetType string
+	TargetID   string
+	Outcome    Outcome
+	StatusCode int
+	CreatedAt  time.Time
+}
+
+// ListFilter narrows and paginates event listings. Events are returned
+// newest first, ordered by (CreatedAt, ID) descending.
+type ListFilter struct {
+	// Actor matches events made by this key exactly.
+	Actor string
+	// From and To bound CreatedAt to the half-open range [From, To).
+	From *time.Time
+	To   *time.Time
+	// After resumes the listing strictly after the given position.
+	After *Cursor
+	// Limit is the maximum number of events to return.
+	Limit int
+}
+
+// Cursor identifies a position in an event listing.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+// Encode returns the opaque string form of the cursor.
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by Cursor.Encode. It returns
+// ErrInvalidCursor when s is malformed.
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	ts, rawID, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// Repository persists audit events.
+type Repository interface {
+	// Insert stores e, setting its ID and CreatedAt.
+	Insert(ctx context.Context, e *Event) error
+	// List returns events matching f, newest first.
+	List(ctx context.Context, f ListFilter) ([]Event, error)
+}
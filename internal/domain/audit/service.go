@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// Sentinel errors for event listings.
+var (
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidDateRange = errors.New("from must be before to")
+)
+
+// Page size bounds for List.
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+)
+
+// ListRequest is a page request for List.
+type ListRequest struct {
+	Actor  string
+	From   *time.Time
+	To     *time.Time
+	Cursor string
+	Limit  int
+}
+
+// ListResult is a page of events and the cursor of the next page, which is
+// empty on the last one.
+type ListResult struct {
+	Events     []Event
+	NextCursor string
+}
+
+// Service records and lists audit events.
+type Service struct {
+	repo Repository
+}
+
+// NewService creates a Service backed by repo.
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Record stores e.
+func (s *Service) Record(ctx context.Context, e *Event) error {
+	if err := s.repo.Insert(ctx, e); err != nil {
+		return errors.Wrap(err, "insert audit event")
+	}
+	return nil
+}
+
+// List returns a page of events, newest first.
+func (s *Service) List(ctx context.Context, req ListRequest) (*ListResult, error) {
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return nil, ErrInvalidDateRange
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	limit = min(limit, MaxListLimit)
+
+	filter := ListFilter{
+		Actor: req.Actor,
+		From:  req.From,
+		To:    req.To,
+		// Fetch one extra row to learn whether another page exists.
+		Limit: limit + 1,
+	}
+	if req.Cursor != "" {
+		c, err := DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = &c
+	}
+
+	events, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "list audit events")
+	}
+
+	var next string
+	if len(events) > limit {
+		events = events[:limit]
+		last := events[limit-1]
+		next = Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	return &ListResult{Events: events, NextCursor: next}, nil
+}
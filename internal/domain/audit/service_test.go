@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepo struct {
+	events []Event
+	filter ListFilter
+}
+
+func (m *mockRepo) Insert(_ context.Context, e *Event) error {
+	e.ID = int64(len(m.events) + 1)
+	m.events = append(m.events, *e)
+	return nil
+}
+
+func (m *mockRepo) List(_ context.Context, f ListFilter) ([]Event, error) {
+	m.filter = f
+	return m.events[:min(f.Limit, len(m.events))], nil
+}
+
+func TestOutcomeForStatus(t *testing.T) {
+	for code, want := range map[int]Outcome{
+		200: OutcomeSuccess,
+		201: OutcomeSuccess,
+		401: OutcomeDenied,
+		403: OutcomeDenied,
+		404: OutcomeRejected,
+		409: OutcomeRejected,
+		422: OutcomeRejected,
+		500: OutcomeFailed,
+	} {
+		assert.Equal(t, want, OutcomeForStatus(code), "%d", code)
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := Cursor{CreatedAt: time.Date(2025, 6, 15, 12, 0, 0, 123, time.UTC), ID: 42}
+	got, err := DecodeCursor(c.Encode())
+	require.NoError(t, err)
+	assert.True(t, c.CreatedAt.Equal(got.CreatedAt))
+	assert.Equal(t, c.ID, got.ID)
+
+	for _, bad := range []string{"!!", "bm8tc2VwYXJhdG9y", "MjAyNXxhYmM"} {
+		_, err := DecodeCursor(bad)
+		require.ErrorIs(t, err, ErrInvalidCursor, bad)
+	}
+}
+
+func TestService_List(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	repo := &mockRepo{}
+	svc := NewService(repo)
+	for i := range 3 {
+		require.NoError(t, svc.Record(ctx, &Event{Actor: "k1", Operation: "placeOrder", CreatedAt: at.Add(-time.Duration(i) * time.Minute)}))
+	}
+
+	t.Run("pages carry a cursor", func(t *testing.T) {
+		res, err := svc.List(ctx, ListRequest{Actor: "k1", Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, res.Events, 2)
+		assert.Equal(t, "k1", repo.filter.Actor)
+		assert.Equal(t, 3, repo.filter.Limit, "one extra row is fetched")
+
+		c, err := DecodeCursor(res.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), c.ID)
+
+		_, err = svc.List(ctx, ListRequest{Cursor: res.NextCursor})
+		require.NoError(t, err)
+		require.NotNil(t, repo.filter.After)
+		assert.Equal(t, DefaultListLimit+1, repo.filter.Limit)
+	})
+
+	t.Run("last page has no cursor", func(t *testing.T) {
+		res, err := svc.List(ctx, ListRequest{Limit: 10})
+		require.NoError(t, err)
+		assert.Len(t, res.Events, 3)
+		assert.Empty(t, res.NextCursor)
+	})
+
+	t.Run("limit is capped", func(t *testing.T) {
+		_, err := svc.List(ctx, ListRequest{Limit: 10_000})
+		require.NoError(t, err)
+		assert.Equal(t, MaxListLimit+1, repo.filter.Limit)
+	})
+
+	t.Run("invalid input is refused", func(t *testing.T) {
+		_, err := svc.List(ctx, ListRequest{From: &at, To: &at})
+		require.ErrorIs(t, err, ErrInvalidDateRange)
+		_, err = svc.List(ctx, ListRequest{Cursor: "!!"})
+		require.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
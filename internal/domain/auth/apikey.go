@@ -1,16 +1,46 @@
 package auth
 
-import "context"
+import (
+	"context"
+	"slices"
+	"time"
+)
 
-// APIKeyInfo holds the identity and permission data for a validated API key.
+// APIKeyInfo holds the identity and permission data for a validated API key,
+// or for a bearer token mapped onto the same model by TokenVerifier.
 type APIKeyInfo struct {
 	ID      string
 	KeyHash string
-	Name    string
-	Scopes  []string
+	// Prefix is the non-secret start of the key, safe to log.
+	Prefix string
+	Name   string
+	Scopes []string
+	// ExpiresAt is when the key stops authenticating; nil means never.
+	ExpiresAt *time.Time
+	// PepperVersion is the version of the pepper KeyHash was made with.
+	PepperVersion int
+	// RateLimitPlan names the rate limit plan the key is held to; empty
+	// means the default limits.
+	RateLimitPlan string
+}
+
+// Expired reports whether the key has expired at now.
+func (i *APIKeyInfo) Expired(now time.Time) bool {
+	return i.ExpiresAt != nil && !now.Before(*i.ExpiresAt)
+}
+
+// HasScope reports whether the key was granted scope.
+func (i *APIKeyInfo) HasScope(scope string) bool {
+	return slices.Contains(i.Scopes, scope)
 }
 
 // Repository provides lookup of API keys by their HMAC hash.
 type Repository interface {
+	// FindByHash returns the active key with the given hash, or
+	// ErrKeyNotFound.
 	FindByHash(ctx context.Context, hash string) (*APIKeyInfo, error)
+	// Rehash replaces a key's stored secret with one made under a newer
+	// pepper. It only applies while the stored hash is still oldHash, so a
+	// concurrent rotation wins; in that case it does nothing and returns nil.
+	Rehash(ctx context.Context, id, oldHash string, secret StoredSecret) error
 }
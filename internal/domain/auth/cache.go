@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"container/list"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// CacheConfig controls a CachedRepository.
+type CacheConfig struct {
+	// Size is the maximum number of cached lookups, found and not found.
+	Size int
+	// TTL bounds how long a found key is served from memory. It is the
+	// longest a revocation can go unnoticed if its notification is lost.
+	TTL time.Duration
+	// NegativeTTL bounds how long an unknown hash is remembered; zero
+	// disables negative caching.
+	NegativeTTL time.Duration
+}
+
+var (
+	_ Repository = (*CachedRepository)(nil)
+
+	cacheHit         = metric.WithAttributes(attribute.String("result", "hit"))
+	cacheNegativeHit = metric.WithAttributes(attribute.String("result", "negative_hit"))
+	cacheMiss        = metric.WithAttributes(attribute.String("result", "miss"))
+)
+
+// CachedRepository is a Repository decorator that keeps recent FindByHash
+// results in a bounded LRU so authentication does not query the database on
+// every request. Unknown hashes are cached too, for a shorter time, so
+// floods of bad keys stay off the database as well.
+//
+// Entries expire after their TTL; Invalidate drops them earlier when a key
+// changes. Lookups are counted in the kart.auth.api_key_cache.lookups
+// counter with a result attribute of hit, negative_hit or miss.
+type CachedRepository struct {
+	next Repository
+	cfg  CacheConfig
+	now  func() time.Time
+
+	lookups   metric.Int64Counter
+	evictions metric.Int64Counter
+
+	mu      sync.Mutex
+	lru     *list.List // of *cacheEntry, most recently used first
+	entries map[string]*list.Element
+	// gen is bumped by every invalidation. A lookup that started before one
+	// does not store its result, which may predate the change.
+	gen uint64
+}
+
+type cacheEntry struct {
+	hash    string
+	info    *APIKeyInfo // nil for a hash that matched no key
+	expires time.Time
+}
+
+// NewCachedRepository wraps next with a cache configured by cfg, reporting
+// metrics to mp.
+func NewCachedRepository(next Repository, cfg CacheConfig, mp metric.MeterProvider) (*CachedRepository, error) {
+	if cfg.Size <= 0 {
+		return nil, errors.Errorf("cache size %d must be positive", cfg.Size)
+	}
+	c := &CachedRepository{
+		next:    next,
+		cfg:     cfg,
+		now:     time.Now,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element, cfg.Size),
+	}
+
+	meter := mp.Meter("github.com/xenking/oolio-kart-challenge/internal/domain/auth")
+	var err error
+	if c.lookups, err = meter.Int64Counter("kart.auth.api_key_cache.lookups",
+		metric.WithDescription("API key lookups by cache result"),
+	); err != nil {
+		return nil, errors.Wrap(err, "create lookups counter")
+	}
+	if c.evictions, err = meter.Int64Counter("kart.auth.api_key_cache.evictions",
+		metric.WithDescription("API key cache entries evicted to make room"),
+	); err != nil {
+		return nil, errors.Wrap(err, "create evictions counter")
+	}
+	if _, err = meter.Int64ObservableGauge("kart.auth.api_key_cache.size",
+		metric.WithDescription("API key cache entries"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(int64(c.Len()))
+			return nil
+		}),
+	); err != nil {
+		return nil, errors.Wrap(err, "create size gauge")
+	}
+	return c, nil
+}
+
+// FindByHash returns the cached result for hash, or looks it up in the
+// wrapped repository and caches it. Only found keys and ErrKeyNotFound are
+// cached; other errors are returned as they are.
+func (c *CachedRepository) FindByHash(ctx context.Context, hash string) (*APIKeyInfo, error) {
+	now := c.now()
+
+	c.mu.Lock()
+	if el, ok := c.entries[hash]; ok {
+		e := el.Value.(*cacheEntry)
+		if now.Before(e.expires) {
+			c.lru.MoveToFront(el)
+			info := e.info
+			c.mu.Unlock()
+
+			if info == nil {
+				c.lookups.Add(ctx, 1, cacheNegativeHit)
+				return nil, ErrKeyNotFound
+			}
+			c.lookups.Add(ctx, 1, cacheHit)
+			return info.clone(), nil
+		}
+		c.remove(el)
+	}
+	gen := c.gen
+	c.mu.Unlock()
+
+	c.lookups.Add(ctx, 1, cacheMiss)
+	info, err := c.next.FindByHash(ctx, hash)
+	switch {
+	case errors.Is(err, ErrKeyNotFound):
+		if c.cfg.NegativeTTL > 0 {
+			c.store(ctx, gen, &cacheEntry{hash: hash, expires: now.Add(c.cfg.NegativeTTL)})
+		}
+		return nil, err
+	case err != nil:
+		return nil, err
+	}
+	c.store(ctx, gen, &cacheEntry{hash: hash, info: info.clone(), expires: now.Add(c.cfg.TTL)})
+	return info, nil
+}
+
+// Rehash updates the wrapped repository and invalidates the key, whose
+// cached hash no longer matches the database.
+func (c *CachedRepository) Rehash(ctx context.Context, id, oldHash string, secret StoredSecret) error {
+	defer c.Invalidate(id)
+	return c.next.Rehash(ctx, id, oldHash, secret)
+}
+
+// Invalidate drops every cached entry for key id, along with all cached
+// not-found results, since a created or rotated key may now match one.
+func (c *CachedRepository) Invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	for el := c.lru.Front(); el != nil; {
+		next := el.Next()
+		if e := el.Value.(*cacheEntry); e.info == nil || e.info.ID == id {
+			c.remove(el)
+		}
+		el = next
+	}
+}
+
+// Reset drops every cached entry. It is used when change notifications may
+// have been missed.
+func (c *CachedRepository) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	c.lru.Init()
+	clear(c.entries)
+}
+
+// Len returns the number of cached entries, expired ones included.
+func (c *CachedRepository) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// store caches e unless an invalidation happened since gen was read,
+// evicting the least recently used entry if the cache is full.
+func (c *CachedRepository) store(ctx context.Context, gen uint64, e *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if gen != c.gen {
+		return
+	}
+	if el, ok := c.entries[e.hash]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	if c.lru.Len() >= c.cfg.Size {
+		c.remove(c.lru.Back())
+		c.evictions.Add(ctx, 1)
+	}
+	c.entries[e.hash] = c.lru.PushFront(e)
+}
+
+func (c *CachedRepository) remove(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry).hash)
+}
+
+// clone returns a copy that shares no memory with i, so callers may modify
+// what the cache hands out.
+func (i *APIKeyInfo) clone() *APIKeyInfo {
+	c := *i
+	c.Scopes = slices.Clone(i.Scopes)
+	if i.ExpiresAt != nil {
+		t := *i.ExpiresAt
+		c.ExpiresAt = &t
+	}
+	return &c
+}
@@ -0,0 +1,261 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// --- Mock implementations ---
+
+type mockKeyRepo struct {
+	keys    map[string]*APIKeyInfo // by hash
+	err     error
+	lookups int
+	// during runs inside FindByHash, before the result is returned.
+	during func()
+}
+
+func (m *mockKeyRepo) FindByHash(_ context.Context, hash string) (*APIKeyInfo, error) {
+	m.lookups++
+	if m.during != nil {
+		m.during()
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	info, ok := m.keys[hash]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return info.clone(), nil
+}
+
+func (m *mockKeyRepo) Rehash(_ context.Context, id, oldHash string, secret StoredSecret) error {
+	info, ok := m.keys[oldHash]
+	if !ok || info.ID != id {
+		return nil
+	}
+	delete(m.keys, oldHash)
+	info.KeyHash = secret.Hash
+	info.PepperVersion = secret.PepperVersion
+	m.keys[secret.Hash] = info
+	return nil
+}
+
+// --- Tests ---
+
+func newTestCache(t *testing.T, repo Repository, cfg CacheConfig) (*CachedRepository, *time.Time) {
+	t.Helper()
+	c, err := NewCachedRepository(repo, cfg, noop.NewMeterProvider())
+	require.NoError(t, err)
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestCachedRepository_CachesFoundKeys(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockKeyRepo{keys: map[string]*APIKeyInfo{
+		"h1": {ID: "k1", KeyHash: "h1", Scopes: []string{ScopeOrdersRead}},
+	}}
+	c, now := newTestCache(t, repo, CacheConfig{Size: 10, TTL: time.Minute, NegativeTTL: time.Second})
+
+	info, err := c.FindByHash(ctx, "h1")
+	require.NoError(t, err)
+	assert.Equal(t, "k1", info.ID)
+
+	// Callers may modify what they get without affecting the cache.
+	info.Scopes[0] = ScopeOrdersAdmin
+	info.KeyHash = "changed"
+
+	info, err = c.FindByHash(ctx, "h1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{ScopeOrdersRead}, info.Scopes)
+	assert.Equal(t, "h1", info.KeyHash)
+	assert.Equal(t, 1, repo.lookups)
+
+	*now = now.Add(time.Minute)
+	_, err = c.FindByHash(ctx, "h1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, repo.lookups, "expired entry must be looked up again")
+}
+
+func TestCachedRepository_NegativeCaching(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unknown hash is remembered for NegativeTTL", func(t *testing.T) {
+		repo := &mockKeyRepo{keys: map[string]*APIKeyInfo{}}
+		c, now := newTestCache(t, repo, CacheConfig{Size: 10, TTL: time.Minute, NegativeTTL: time.Second})
+
+		for range 3 {
+			_, err := c.FindByHash(ctx, "bad")
+			require.ErrorIs(t, err, ErrKeyNotFound)
+		}
+		assert.Equal(t, 1, repo.lookups)
+
+		*now = now.Add(time.Second)
+		_, err := c.FindByHash(ctx, "bad")
+		require.ErrorIs(t, err, ErrKeyNotFound)
+		assert.Equal(t, 2, repo.lookups)
+	})
+
+	t.Run("zero NegativeTTL disables it", func(t *testing.T) {
+		repo := &mockKeyRepo{keys: map[string]*APIKeyInfo{}}
+		c, _ := newTestCache(t, repo, CacheConfig{Size: 10, TTL: time.Minute})
+
+		_, _ = c.FindByHash(ctx, "bad")
+		_, _ = c.FindByHash(ctx, "bad")
+		assert.Equal(t, 2, repo.lookups)
+		assert.Zero(t, c.Len())
+	})
+
+	t.Run("other errors are not cached", func(t *testing.T) {
+		repo := &mockKeyRepo{err: errors.New("db down")}
+		c, _ := newTestCache(t, repo, CacheConfig{Size: 10, TTL: time.Minute, NegativeTTL: time.Second})
+
+		_, err := c.FindByHash(ctx, "h1")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrKeyNotFound)
+		assert.Zero(t, c.Len())
+	})
+}
+
+func TestCachedRepository_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockKeyRepo{keys: map[string]*APIKeyInfo{
+		"h1": {ID: "k1", KeyHash: "h1"},
+		"h2": {ID: "k2", KeyHash: "h2"},
+		"h3": {ID: "k3", KeyHash: "h3"},
+	}}
+	c, _ := newTestCache(t, repo, CacheConfig{Size: 2, TTL: time.Minute})
+
+	_, _ = c.FindByHash(ctx, "h1")
+	_, _ = c.FindByHash(ctx, "h2")
+	_, _ = c.FindByHash(ctx, "h1") // h2 is now least recently used
+	_, _ = c.FindByHash(ctx, "h3")
+	assert.Equal(t, 2, c.Len())
+	assert.Equal(t, 3, repo.lookups)
+
+	_, _ = c.FindByHash(ctx, "h1")
+	assert.Equal(t, 3, repo.lookups, "h1 must still be cached")
+	_, _ = c.FindByHash(ctx, "h2")
+	assert.Equal(t, 4, repo.lookups, "h2 must have been evicted")
+}
+
+func TestCachedRepository_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockKeyRepo{keys: map[string]*APIKeyInfo{
+		"h1": {ID: "k1", KeyHash: "h1"},
+		"h2": {ID: "k2", KeyHash: "h2"},
+	}}
+	c, _ := newTestCache(t, repo, CacheConfig{Size: 10, TTL: time.Minute, NegativeTTL: time.Minute})
+
+	_, _ = c.FindByHash(ctx, "h1")
+	_, _ = c.FindByHash(ctx, "h2")
+	_, _ = c.FindByHash(ctx, "h3")
+	require.Equal(t, 3, c.Len())
+
+	// k1 is revoked; a key with hash h3 is created.
+	delete(repo.keys, "h1")
+	repo.keys["h3"] = &APIKeyInfo{ID: "k3", KeyHash: "h3"}
+	c.Invalidate("k1")
+	assert.Equal(t, 1, c.Len(), "k1 and the negative entry must be dropped")
+
+	_, err := c.FindByHash(ctx, "h1")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	info, err := c.FindByHash(ctx, "h3")
+	require.NoError(t, err)
+	assert.Equal(t, "k3", info.ID)
+
+	c.Reset()
+	assert.Zero(t, c.Len())
+}
+
+func TestCachedRepository_InvalidateDuringLookup(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockKeyRepo{keys: map[string]*APIKeyInfo{
+		"h1": {ID: "k1", KeyHash: "h1"},
+	}}
+	c, _ := newTestCache(t, repo, CacheConfig{Size: 10, TTL: time.Minute})
+
+	// The key is revoked while its lookup is in flight. The lookup's result
+	// predates the change and must not be cached.
+	repo.during = func() { c.Invalidate("k1") }
+	_, err := c.FindByHash(ctx, "h1")
+	require.NoError(t, err)
+	assert.Zero(t, c.Len())
+}
+
+func TestCachedRepository_Rehash(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockKeyRepo{keys: map[string]*APIKeyInfo{
+		"old": {ID: "k1", KeyHash: "old", PepperVersion: 1},
+	}}
+	c, _ := newTestCache(t, repo, CacheConfig{Size: 10, TTL: time.Minute, NegativeTTL: time.Minute})
+
+	// Lookup under the current pepper misses, then the old one matches.
+	_, err := c.FindByHash(ctx, "new")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	_, err = c.FindByHash(ctx, "old")
+	require.NoError(t, err)
+
+	require.NoError(t, c.Rehash(ctx, "k1", "old", StoredSecret{Hash: "new", PepperVersion: 2}))
+
+	info, err := c.FindByHash(ctx, "new")
+	require.NoError(t, err, "negative entry for the new hash must be dropped")
+	assert.Equal(t, 2, info.PepperVersion)
+	_, err = c.FindByHash(ctx, "old")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestCachedRepository_Metrics(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	repo := &mockKeyRepo{keys: map[string]*APIKeyInfo{
+		"h1": {ID: "k1", KeyHash: "h1"},
+	}}
+	c, err := NewCachedRepository(repo, CacheConfig{Size: 10, TTL: time.Minute, NegativeTTL: time.Minute}, mp)
+	require.NoError(t, err)
+
+	_, _ = c.FindByHash(ctx, "h1")
+	_, _ = c.FindByHash(ctx, "h1")
+	_, _ = c.FindByHash(ctx, "h1")
+	_, _ = c.FindByHash(ctx, "bad")
+	_, _ = c.FindByHash(ctx, "bad")
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+
+	lookups := map[string]int64{}
+	var size int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch m.Name {
+			case "kart.auth.api_key_cache.lookups":
+				for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					result, _ := dp.Attributes.Value(attribute.Key("result"))
+					lookups[result.AsString()] = dp.Value
+				}
+			case "kart.auth.api_key_cache.size":
+				size = m.Data.(metricdata.Gauge[int64]).DataPoints[0].Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{"hit": 2, "negative_hit": 1, "miss": 2}, lookups)
+	assert.Equal(t, int64(2), size)
+}
+
+func TestNewCachedRepository_RequiresSize(t *testing.T) {
+	_, err := NewCachedRepository(&mockKeyRepo{}, CacheConfig{TTL: time.Minute}, noop.NewMeterProvider())
+	require.Error(t, err)
+}
@@ -0,0 +1,16 @@
+package auth
+
+import "context"
+
+type ctxKey struct{}
+
+// WithAPIKey returns a copy of ctx carrying the authenticated API key.
+func WithAPIKey(ctx context.Context, info *APIKeyInfo) context.Context {
+	return context.WithValue(ctx, ctxKey{}, info)
+}
+
+// FromContext returns the API key stored by WithAPIKey, if any.
+func FromContext(ctx context.Context) (*APIKeyInfo, bool) {
+	info, ok := ctx.Value(ctxKey{}).(*APIKeyInfo)
+	return info, ok && info != nil
+}
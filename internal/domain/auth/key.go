@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// ErrKeyNotFound is returned when no API key has the requested ID.
+var ErrKeyNotFound = errors.New("api key not found")
+
+// ErrKeyRevoked is returned when rotating a key that has been revoked.
+var ErrKeyRevoked = errors.New("api key is revoked")
+
+const (
+	// secretPrefix marks generated secrets so they are easy to recognise in
+	// logs and secret scanners.
+	secretPrefix = "kart_live_"
+	// prefixRandomChars is how many random characters of a generated secret
+	// are kept in its display prefix.
+	prefixRandomChars = 8
+)
+
+// Key is a stored API key as administrators see it. The secret itself is
+// never stored, only its HMAC.
+type Key struct {
+	ID string `json:"id"`
+	// Prefix is the non-secret start of the secret, e.g. "kart_live_ab12cd34".
+	Prefix     string     `json:"prefix"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	Active     bool       `json:"active"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	// PepperVersion is the pepper the stored hash was made with. Keys move
+	// to the current pepper when they next authenticate or are rotated.
+	PepperVersion int `json:"pepper_version"`
+	// RateLimitPlan names the server's rate limit plan for the key; empty
+	// means the default limits.
+	RateLimitPlan string `json:"rate_limit_plan"`
+}
+
+// CreateKeyRequest describes a key to create.
+type CreateKeyRequest struct {
+	ID     string
+	Name   string
+	Scopes []string
+	// ExpiresAt is when the key stops authenticating; nil means never.
+	ExpiresAt *time.Time
+	// RateLimitPlan names the rate limit plan; empty means the default.
+	RateLimitPlan string
+}
+
+// KeyStore defines persistence operations for managing API keys.
+type KeyStore interface {
+	// CreateKey stores a new active key with what is kept of its secret and
+	// sets CreatedAt. key.Prefix is stored alongside.
+	CreateKey(ctx context.Context, key *Key, secret StoredSecret) error
+	// ListKeys returns every key, revoked ones included, oldest first.
+	ListKeys(ctx context.Context) ([]Key, error)
+	// GetKey returns a key by ID, or ErrKeyNotFound.
+	GetKey(ctx context.Context, id string) (*Key, error)
+	// RevokeKey marks a key inactive. Returns ErrKeyNotFound for an unknown
+	// ID; revoking a revoked key is not an error.
+	RevokeKey(ctx context.Context, id string) error
+	// SetKeySecret replaces the stored secret and prefix of an active key.
+	// Returns ErrKeyNotFound for an unknown or revoked key.
+	SetKeySecret(ctx context.Context, id, prefix string, secret StoredSecret) error
+	// SetKeyScopes replaces the scopes of a key. Returns ErrKeyNotFound for
+	// an unknown ID.
+	SetKeyScopes(ctx context.Context, id string, scopes []string) error
+	// SetKeyRateLimitPlan replaces the rate limit plan of a key. Returns
+	// ErrKeyNotFound for an unknown ID.
+	SetKeyRateLimitPlan(ctx context.Context, id, plan string) error
+}
+
+// HashKey returns the HMAC-SHA256 of an API key secret under pepper. This is
+// the value stored in api_keys.key_hash, hex-encoded.
+func HashKey(pepper []byte, secret string) []byte {
+	mac := hmac.New(sha256.New, pepper)
+	mac.Write([]byte(secret))
+	return mac.Sum(nil)
+}
+
+// GenerateSecret returns a new random API key secret.
+func GenerateSecret() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", errors.Wrap(err, "read random bytes")
+	}
+	return secretPrefix + base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+// KeyPrefix returns the part of a secret that may be shown and logged. For
+// generated secrets that is the fixed prefix plus a few random characters;
+// for hand-picked secrets (such as the seeded key) only a short start is
+// revealed.
+func KeyPrefix(secret string) string {
+	if strings.HasPrefix(secret, secretPrefix) && len(secret) >= len(secretPrefix)+prefixRandomChars {
+		return secret[:len(secretPrefix)+prefixRandomChars]
+	}
+	return secret[:min(4, len(secret)/4)]
+}
+
+// Manager creates and maintains API keys. Secrets are generated here and
+// handed back exactly once; only their hash and sealed signing key are
+// stored.
+type Manager struct {
+	keys   KeyStore
+	pepper Pepper
+}
+
+// NewManager creates a Manager that hashes secrets with the current pepper,
+// which must be one the API server knows for the keys to authenticate.
+func NewManager(keys KeyStore, peppers Peppers) *Manager {
+	return &Manager{keys: keys, pepper: peppers.Current()}
+}
+
+// Create stores a new key and returns it with its secret. Returns
+// *UnknownScopeError for a scope the API does not check.
+func (m *Manager) Create(ctx context.Context, req CreateKeyRequest) (*Key, string, error) {
+	if err := ValidateScopes(req.Scopes); err != nil {
+		return nil, "", err
+	}
+	secret, err := GenerateSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	stored, err := m.pepper.Protect(req.ID, secret)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &Key{
+		ID:            req.ID,
+		Prefix:        KeyPrefix(secret),
+		Name:          req.Name,
+		Scopes:        req.Scopes,
+		Active:        true,
+		ExpiresAt:     req.ExpiresAt,
+		PepperVersion: stored.PepperVersion,
+		RateLimitPlan: req.RateLimitPlan,
+	}
+	if err := m.keys.CreateKey(ctx, key, stored); err != nil {
+		return nil, "", errors.Wrap(err, "create key")
+	}
+	return key, secret, nil
+}
+
+// List returns every key, revoked ones included.
+func (m *Manager) List(ctx context.Context) ([]Key, error) {
+	return m.keys.ListKeys(ctx)
+}
+
+// Get returns a key by ID.
+func (m *Manager) Get(ctx context.Context, id string) (*Key, error) {
+	return m.keys.GetKey(ctx, id)
+}
+
+// Revoke disables a key. It stops authenticating immediately.
+func (m *Manager) Revoke(ctx context.Context, id string) (*Key, error) {
+	if err := m.keys.RevokeKey(ctx, id); err != nil {
+		return nil, err
+	}
+	return m.keys.GetKey(ctx, id)
+}
+
+// Rotate replaces a key's secret and returns the new one. The old secret
+// stops working at once; ID, name, scopes and expiry are kept. Returns
+// ErrKeyRevoked for a revoked key.
+func (m *Manager) Rotate(ctx context.Context, id string) (*Key, string, error) {
+	key, err := m.keys.GetKey(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if !key.Active {
+		return nil, "", ErrKeyRevoked
+	}
+
+	secret, err := GenerateSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	stored, err := m.pepper.Protect(id, secret)
+	if err != nil {
+		return nil, "", err
+	}
+	key.Prefix = KeyPrefix(secret)
+	key.PepperVersion = stored.PepperVersion
+	if err := m.keys.SetKeySecret(ctx, id, key.Prefix, stored); err != nil {
+		return nil, "", err
+	}
+	return key, secret, nil
+}
+
+// SetScopes replaces a key's scopes. Returns *UnknownScopeError for a scope
+// the API does not check.
+func (m *Manager) SetScopes(ctx context.Context, id string, scopes []string) (*Key, error) {
+	if err := ValidateScopes(scopes); err != nil {
+		return nil, err
+	}
+	if err := m.keys.SetKeyScopes(ctx, id, scopes); err != nil {
+		return nil, err
+	}
+	return m.keys.GetKey(ctx, id)
+}
+
+// SetRateLimitPlan assigns a key to a rate limit plan, or to the default
+// limits when plan is empty. Plans are defined in the server's
+// configuration; a name the server does not know gets the default limits.
+func (m *Manager) SetRateLimitPlan(ctx context.Context, id, plan string) (*Key, error) {
+	if err := m.keys.SetKeyRateLimitPlan(ctx, id, plan); err != nil {
+		return nil, err
+	}
+	return m.keys.GetKey(ctx, id)
+}
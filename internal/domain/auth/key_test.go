@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Mock implementations ---
+
+type mockKeyStore struct {
+	keys   map[string]*Key
+	hashes map[string]string
+}
+
+func newKeyStore() *mockKeyStore {
+	return &mockKeyStore{keys: make(map[string]*Key), hashes: make(map[string]string)}
+}
+
+func (m *mockKeyStore) CreateKey(_ context.Context, key *Key, secret StoredSecret) error {
+	key.CreatedAt = time.Now()
+	stored := *key
+	m.keys[key.ID] = &stored
+	m.hashes[key.ID] = secret.Hash
+	return nil
+}
+
+func (m *mockKeyStore) ListKeys(_ context.Context) ([]Key, error) {
+	var out []Key
+	for _, k := range m.keys {
+		out = append(out, *k)
+	}
+	return out, nil
+}
+
+func (m *mockKeyStore) GetKey(_ context.Context, id string) (*Key, error) {
+	k, ok := m.keys[id]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	out := *k
+	return &out, nil
+}
+
+func (m *mockKeyStore) RevokeKey(_ context.Context, id string) error {
+	k, ok := m.keys[id]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	k.Active = false
+	return nil
+}
+
+func (m *mockKeyStore) SetKeySecret(_ context.Context, id, prefix string, secret StoredSecret) error {
+	k, ok := m.keys[id]
+	if !ok || !k.Active {
+		return ErrKeyNotFound
+	}
+	k.Prefix = prefix
+	k.PepperVersion = secret.PepperVersion
+	m.hashes[id] = secret.Hash
+	return nil
+}
+
+func (m *mockKeyStore) SetKeyScopes(_ context.Context, id string, scopes []string) error {
+	k, ok := m.keys[id]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	k.Scopes = scopes
+	return nil
+}
+
+func (m *mockKeyStore) SetKeyRateLimitPlan(_ context.Context, id, plan string) error {
+	k, ok := m.keys[id]
+	if !ok {
+		return ErrKeyNotFound
+	}
+	k.RateLimitPlan = plan
+	return nil
+}
+
+var testPeppers = Peppers{{Version: 1, Secret: []byte("pepper")}}
+
+// --- Tests ---
+
+func TestManager_CreateStoresHashOnly(t *testing.T) {
+	ctx := context.Background()
+	store := newKeyStore()
+	peppers := Peppers{{Version: 2, Secret: []byte("new")}, {Version: 1, Secret: []byte("old")}}
+	m := NewManager(store, peppers)
+
+	key, secret, err := m.Create(ctx, CreateKeyRequest{ID: "k1", Name: "till 1", Scopes: []string{ScopeOrdersWrite}})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, secretPrefix))
+	assert.Equal(t, secret[:len(secretPrefix)+prefixRandomChars], key.Prefix)
+	assert.True(t, key.Active)
+	assert.Equal(t, []string{ScopeOrdersWrite}, key.Scopes)
+	assert.Equal(t, hex.EncodeToString(HashKey([]byte("new"), secret)), store.hashes["k1"])
+	assert.Equal(t, 2, key.PepperVersion)
+	assert.NotContains(t, store.hashes["k1"], secret)
+
+	_, _, err = m.Create(ctx, CreateKeyRequest{ID: "k2", Name: "bad", Scopes: []string{"orders:everything"}})
+	var scopeErr *UnknownScopeError
+	require.ErrorAs(t, err, &scopeErr)
+	assert.Equal(t, "orders:everything", scopeErr.Scope)
+}
+
+func TestManager_Rotate(t *testing.T) {
+	ctx := context.Background()
+	store := newKeyStore()
+	m := NewManager(store, testPeppers)
+
+	_, oldSecret, err := m.Create(ctx, CreateKeyRequest{ID: "k1", Name: "till 1"})
+	require.NoError(t, err)
+	oldHash := store.hashes["k1"]
+
+	key, newSecret, err := m.Rotate(ctx, "k1")
+	require.NoError(t, err)
+	assert.Equal(t, "k1", key.ID)
+	assert.NotEqual(t, oldSecret, newSecret)
+	assert.NotEqual(t, oldHash, store.hashes["k1"])
+	assert.Equal(t, KeyPrefix(newSecret), store.keys["k1"].Prefix)
+
+	newer := NewManager(store, Peppers{{Version: 2, Secret: []byte("newer")}, testPeppers[0]})
+	key, _, err = newer.Rotate(ctx, "k1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, key.PepperVersion)
+	assert.Equal(t, 2, store.keys["k1"].PepperVersion)
+
+	_, err = m.Revoke(ctx, "k1")
+	require.NoError(t, err)
+	_, _, err = m.Rotate(ctx, "k1")
+	require.ErrorIs(t, err, ErrKeyRevoked)
+
+	_, _, err = m.Rotate(ctx, "missing")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestManager_RevokeAndSetScopes(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(newKeyStore(), testPeppers)
+
+	_, _, err := m.Create(ctx, CreateKeyRequest{ID: "k1", Name: "till 1", Scopes: []string{ScopeOrdersRead}})
+	require.NoError(t, err)
+
+	key, err := m.SetScopes(ctx, "k1", []string{ScopeOrdersRead, ScopeOrdersAdmin})
+	require.NoError(t, err)
+	assert.Equal(t, []string{ScopeOrdersRead, ScopeOrdersAdmin}, key.Scopes)
+
+	_, err = m.SetScopes(ctx, "k1", []string{"admin"})
+	var scopeErr *UnknownScopeError
+	require.ErrorAs(t, err, &scopeErr)
+
+	key, err = m.Revoke(ctx, "k1")
+	require.NoError(t, err)
+	assert.False(t, key.Active)
+
+	_, err = m.Revoke(ctx, "missing")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestManager_SetRateLimitPlan(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(newKeyStore(), testPeppers)
+
+	key, _, err := m.Create(ctx, CreateKeyRequest{ID: "k1", Name: "till 1", RateLimitPlan: "free"})
+	require.NoError(t, err)
+	assert.Equal(t, "free", key.RateLimitPlan)
+
+	key, err = m.SetRateLimitPlan(ctx, "k1", "pro")
+	require.NoError(t, err)
+	assert.Equal(t, "pro", key.RateLimitPlan)
+
+	_, err = m.SetRateLimitPlan(ctx, "missing", "pro")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestAPIKeyInfo_HasScope(t *testing.T) {
+	info := &APIKeyInfo{Scopes: []string{ScopeOrdersRead}}
+	assert.True(t, info.HasScope(ScopeOrdersRead))
+	assert.False(t, info.HasScope(ScopeOrdersWrite))
+}
+
+func TestKeyPrefix(t *testing.T) {
+	assert.Equal(t, "kart_live_ab12cd34", KeyPrefix("kart_live_ab12cd34efgh5678"))
+	assert.Equal(t, "dev-", KeyPrefix("dev-api-key-not-for-production"))
+	assert.Equal(t, "", KeyPrefix("abc"))
+}
+
+func TestAPIKeyInfo_Expired(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	assert.False(t, (&APIKeyInfo{}).Expired(now))
+
+	exp := now.Add(time.Minute)
+	info := &APIKeyInfo{ExpiresAt: &exp}
+	assert.False(t, info.Expired(now))
+	assert.True(t, info.Expired(exp))
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var _ NonceStore = (*MemoryNonceStore)(nil)
+
+// MemoryNonceStore keeps signed-request nonces in process memory. It suits a
+// single server; with several replicas a nonce could be replayed against
+// another one, so those should share the Postgres store.
+type MemoryNonceStore struct {
+	now func() time.Time
+
+	mu     sync.Mutex
+	nonces map[memoryNonce]time.Time
+}
+
+type memoryNonce struct {
+	id, nonce string
+}
+
+// NewMemoryNonceStore creates an empty MemoryNonceStore.
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{
+		now:    time.Now,
+		nonces: make(map[memoryNonce]time.Time),
+	}
+}
+
+// UseNonce records nonce for key id until expiresAt. An expired record does
+// not count as a reuse.
+func (s *MemoryNonceStore) UseNonce(_ context.Context, id, nonce string, expiresAt time.Time) error {
+	k := memoryNonce{id: id, nonce: nonce}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if exp, ok := s.nonces[k]; ok && s.now().Before(exp) {
+		return ErrNonceReused
+	}
+	s.nonces[k] = expiresAt
+	return nil
+}
+
+// Prune forgets expired nonces and returns how many were removed.
+func (s *MemoryNonceStore) Prune(_ context.Context) (int64, error) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	for k, exp := range s.nonces {
+		if !now.Before(exp) {
+			delete(s.nonces, k)
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"cmp"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-faster/errors"
+)
+
+// Pepper is one version of the secret mixed into API key hashes.
+type Pepper struct {
+	Version int
+	Secret  []byte
+}
+
+// Hash returns the hex-encoded HMAC of secret under this pepper, as stored
+// in api_keys.key_hash.
+func (p Pepper) Hash(secret string) string {
+	return hex.EncodeToString(HashKey(p.Secret, secret))
+}
+
+// Peppers is a set of pepper versions ordered newest first. The first one
+// hashes new and rotated keys; older ones are kept so keys hashed with them
+// still authenticate until they are rehashed.
+type Peppers []Pepper
+
+// Current returns the newest pepper.
+func (p Peppers) Current() Pepper {
+	return p[0]
+}
+
+// NewPeppers sorts peppers newest first and checks that versions are
+// positive and unique.
+func NewPeppers(peppers ...Pepper) (Peppers, error) {
+	if len(peppers) == 0 {
+		return nil, errors.New("at least one pepper is required")
+	}
+	out := slices.Clone(peppers)
+	slices.SortFunc(out, func(a, b Pepper) int { return cmp.Compare(b.Version, a.Version) })
+	for i, p := range out {
+		if p.Version <= 0 {
+			return nil, errors.Errorf("pepper version %d must be positive", p.Version)
+		}
+		if i > 0 && out[i-1].Version == p.Version {
+			return nil, errors.Errorf("duplicate pepper version %d", p.Version)
+		}
+	}
+	return out, nil
+}
+
+// ParsePeppers builds Peppers from "version:secret" entries. When entries is
+// empty, legacy is used as version 1 so single-pepper deployments keep
+// working unchanged.
+func ParsePeppers(entries []string, legacy string) (Peppers, error) {
+	if len(entries) == 0 {
+		return NewPeppers(Pepper{Version: 1, Secret: []byte(legacy)})
+	}
+
+	peppers := make([]Pepper, 0, len(entries))
+	for _, e := range entries {
+		v, secret, ok := strings.Cut(e, ":")
+		if !ok {
+			return nil, errors.Errorf("pepper %q: want version:secret", redact(e))
+		}
+		version, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, errors.Errorf("pepper %q: invalid version", redact(e))
+		}
+		peppers = append(peppers, Pepper{Version: version, Secret: []byte(secret)})
+	}
+	return NewPeppers(peppers...)
+}
+
+// redact keeps pepper secrets out of error messages.
+func redact(entry string) string {
+	if v, _, ok := strings.Cut(entry, ":"); ok {
+		return v + ":…"
+	}
+	return "…"
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePeppers(t *testing.T) {
+	t.Run("legacy single pepper is version 1", func(t *testing.T) {
+		p, err := ParsePeppers(nil, "old")
+		require.NoError(t, err)
+		assert.Equal(t, Peppers{{Version: 1, Secret: []byte("old")}}, p)
+	})
+
+	t.Run("newest version is current", func(t *testing.T) {
+		p, err := ParsePeppers([]string{"1:old", "3:newest", "2:new"}, "ignored")
+		require.NoError(t, err)
+		assert.Equal(t, 3, p.Current().Version)
+		assert.Equal(t, []byte("newest"), p.Current().Secret)
+		assert.Equal(t, []int{3, 2, 1}, []int{p[0].Version, p[1].Version, p[2].Version})
+	})
+
+	t.Run("secret may contain colons", func(t *testing.T) {
+		p, err := ParsePeppers([]string{"2:a:b"}, "")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("a:b"), p.Current().Secret)
+	})
+
+	for _, bad := range [][]string{{"hunter2"}, {"x:hunter2"}, {"0:hunter2"}, {"1:hunter2", "1:b"}} {
+		_, err := ParsePeppers(bad, "")
+		require.Error(t, err, "%v", bad)
+		assert.NotContains(t, err.Error(), "hunter2")
+	}
+}
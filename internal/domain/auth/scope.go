@@ -0,0 +1,41 @@
+package auth
+
+import "slices"
+
+// Scopes an API key can be granted in the api_keys.scopes column.
+const (
+	// ScopeOrdersRead allows reading orders.
+	ScopeOrdersRead = "orders:read"
+	// ScopeOrdersWrite allows quoting, placing and cancelling orders and
+	// building carts.
+	ScopeOrdersWrite = "orders:write"
+	// ScopeOrdersAdmin allows moving orders through their lifecycle.
+	ScopeOrdersAdmin = "orders:admin"
+	// ScopeAuditRead allows reading the audit log.
+	ScopeAuditRead = "audit:read"
+	// ScopeCouponsAdmin allows creating, changing and listing coupons.
+	ScopeCouponsAdmin = "coupons:admin"
+)
+
+// KnownScopes lists every scope the API checks, in display order.
+var KnownScopes = []string{ScopeOrdersRead, ScopeOrdersWrite, ScopeOrdersAdmin, ScopeAuditRead, ScopeCouponsAdmin}
+
+// UnknownScopeError is returned when granting a scope the API never checks.
+type UnknownScopeError struct {
+	Scope string
+}
+
+func (e *UnknownScopeError) Error() string {
+	return "unknown scope " + e.Scope
+}
+
+// ValidateScopes returns an *UnknownScopeError for the first scope that is
+// not in KnownScopes.
+func ValidateScopes(scopes []string) error {
+	for _, s := range scopes {
+		if !slices.Contains(KnownScopes, s) {
+			return &UnknownScopeError{Scope: s}
+		}
+	}
+	return nil
+}
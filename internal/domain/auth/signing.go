@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+var (
+	// ErrSignatureInvalid is returned for a signed request whose signature
+	// does not match, or whose key cannot sign.
+	ErrSignatureInvalid = errors.New("invalid request signature")
+	// ErrRequestTooOld is returned for a signed request whose timestamp is
+	// further from the server clock than the allowed skew.
+	ErrRequestTooOld = errors.New("request timestamp outside allowed skew")
+	// ErrNonceReused is returned for a signed request whose nonce the key has
+	// already used.
+	ErrNonceReused = errors.New("request nonce already used")
+)
+
+const (
+	// signingKeyLabel derives a key's request-signing key from its secret.
+	signingKeyLabel = "kart-request-signing-v1"
+	// sealKeyLabel derives the key that seals signing keys from a pepper.
+	sealKeyLabel = "kart-signing-key-seal-v1"
+	// maxNonceLen bounds the nonce a client may send.
+	maxNonceLen = 128
+	// minNonceLen keeps nonces from being trivially guessable or colliding.
+	minNonceLen = 16
+)
+
+// SigningKey returns the key requests are signed with for an API key
+// secret: HMAC-SHA256 keyed with the secret over "kart-request-signing-v1".
+// Clients derive it once and never send the secret itself.
+func SigningKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingKeyLabel))
+	return mac.Sum(nil)
+}
+
+// StoredSecret is what the database keeps of a key's secret: its hash and
+// its sealed signing key, both made with one pepper version.
+type StoredSecret struct {
+	Hash          string
+	SigningKey    []byte
+	PepperVersion int
+}
+
+// Protect hashes secret and seals its signing key for key id.
+func (p Pepper) Protect(id, secret string) (StoredSecret, error) {
+	sealed, err := p.seal(id, SigningKey(secret))
+	if err != nil {
+		return StoredSecret{}, err
+	}
+	return StoredSecret{Hash: p.Hash(secret), SigningKey: sealed, PepperVersion: p.Version}, nil
+}
+
+// seal encrypts a signing key with AES-256-GCM under a key derived from the
+// pepper, bound to the key ID so a sealed value is useless on another row.
+func (p Pepper) seal(id string, key []byte) ([]byte, error) {
+	aead, err := p.aead()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(key)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "read random bytes")
+	}
+	return aead.Seal(nonce, nonce, key, []byte(id)), nil
+}
+
+// open reverses seal.
+func (p Pepper) open(id string, sealed []byte) ([]byte, error) {
+	aead, err := p.aead()
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("sealed signing key too short")
+	}
+	nonce, ct := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	key, err := aead.Open(nil, nonce, ct, []byte(id))
+	if err != nil {
+		return nil, errors.Wrap(err, "open signing key")
+	}
+	return key, nil
+}
+
+func (p Pepper) aead() (cipher.AEAD, error) {
+	mac := hmac.New(sha256.New, p.Secret)
+	mac.Write([]byte(sealKeyLabel))
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, errors.Wrap(err, "create cipher")
+	}
+	return cipher.NewGCM(block)
+}
+
+// Version returns the pepper with the given version.
+func (p Peppers) Version(v int) (Pepper, bool) {
+	for _, pp := range p {
+		if pp.Version == v {
+			return pp, true
+		}
+	}
+	return Pepper{}, false
+}
+
+// SignedRequest is a request signed with a key's signing key.
+type SignedRequest struct {
+	KeyID  string
+	Method string
+	// Path is the escaped request path, and Query the raw query string
+	// without the "?".
+	Path  string
+	Query string
+	// Timestamp is the signing time in Unix seconds, as sent.
+	Timestamp string
+	Nonce     string
+	// BodyHash is the SHA-256 of the request body.
+	BodyHash [sha256.Size]byte
+	// Signature is the hex HMAC-SHA256 of StringToSign under the signing key.
+	Signature string
+}
+
+// StringToSign returns the canonical form of the request that is signed:
+// method, path, query, timestamp, nonce and hex body hash, one per line.
+func (r *SignedRequest) StringToSign() string {
+	return strings.Join([]string{
+		strings.ToUpper(r.Method),
+		r.Path,
+		r.Query,
+		r.Timestamp,
+		r.Nonce,
+		hex.EncodeToString(r.BodyHash[:]),
+	}, "\n")
+}
+
+// Sign returns the signature of r under signingKey.
+func (r *SignedRequest) Sign(signingKey []byte) string {
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write([]byte(r.StringToSign()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SigningKeyStore looks up keys for signed requests.
+type SigningKeyStore interface {
+	// FindSigningKey returns the active key with the given ID and its sealed
+	// signing key, which is nil for keys created before request signing.
+	// Returns ErrKeyNotFound for an unknown or revoked key.
+	FindSigningKey(ctx context.Context, id string) (*APIKeyInfo, []byte, error)
+}
+
+// NonceStore remembers the nonces of signed requests.
+type NonceStore interface {
+	// UseNonce records nonce for key id until expiresAt. Returns
+	// ErrNonceReused if it is already recorded.
+	UseNonce(ctx context.Context, id, nonce string, expiresAt time.Time) error
+}
+
+// RequestVerifier authenticates signed requests. A request is accepted when
+// its signature matches, its timestamp is within maxSkew of the server
+// clock, and its nonce has not been used by the key within that window.
+// The secret never travels with the request, so a request copied from logs
+// cannot be replayed or altered.
+type RequestVerifier struct {
+	keys    SigningKeyStore
+	peppers Peppers
+	nonces  NonceStore
+	maxSkew time.Duration
+	now     func() time.Time
+}
+
+// NewRequestVerifier creates a RequestVerifier. peppers must include the
+// versions signing keys were sealed with.
+func NewRequestVerifier(keys SigningKeyStore, peppers Peppers, nonces NonceStore, maxSkew time.Duration) *RequestVerifier {
+	return &RequestVerifier{
+		keys:    keys,
+		peppers: peppers,
+		nonces:  nonces,
+		maxSkew: maxSkew,
+		now:     time.Now,
+	}
+}
+
+// Verify checks req and returns the key that signed it. The nonce is only
+// recorded once the signature is valid, so unsigned traffic cannot use up a
+// client's nonces.
+func (v *RequestVerifier) Verify(ctx context.Context, req *SignedRequest) (*APIKeyInfo, error) {
+	now := v.now()
+	sec, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(ErrSignatureInvalid, "malformed timestamp")
+	}
+	signedAt := time.Unix(sec, 0)
+	if d := now.Sub(signedAt); d > v.maxSkew || d < -v.maxSkew {
+		return nil, ErrRequestTooOld
+	}
+	if n := len(req.Nonce); n < minNonceLen || n > maxNonceLen {
+		return nil, errors.Wrapf(ErrSignatureInvalid, "nonce must be %d-%d characters", minNonceLen, maxNonceLen)
+	}
+	got, err := hex.DecodeString(req.Signature)
+	if err != nil {
+		return nil, errors.Wrap(ErrSignatureInvalid, "malformed signature")
+	}
+
+	info, sealed, err := v.keys.FindSigningKey(ctx, req.KeyID)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, ErrSignatureInvalid
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "find signing key")
+	}
+	if sealed == nil {
+		return nil, errors.Wrap(ErrSignatureInvalid, "key has no signing key; rotate it to enable request signing")
+	}
+	pepper, ok := v.peppers.Version(info.PepperVersion)
+	if !ok {
+		return nil, errors.Wrapf(ErrSignatureInvalid, "pepper version %d is not configured", info.PepperVersion)
+	}
+	signingKey, err := pepper.open(info.ID, sealed)
+	if err != nil {
+		return nil, errors.Wrap(err, "open signing key")
+	}
+
+	want, _ := hex.DecodeString(req.Sign(signingKey))
+	if !hmac.Equal(got, want) {
+		return nil, ErrSignatureInvalid
+	}
+	if info.Expired(now) {
+		return nil, errors.Wrap(ErrSignatureInvalid, "key expired")
+	}
+
+	// A nonce only needs remembering while its timestamp is acceptable.
+	if err := v.nonces.UseNonce(ctx, info.ID, req.Nonce, signedAt.Add(v.maxSkew)); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
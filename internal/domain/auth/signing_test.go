@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Mock implementations ---
+
+type mockSigningKeys struct {
+	info   *APIKeyInfo
+	sealed []byte
+}
+
+func (m *mockSigningKeys) FindSigningKey(_ context.Context, id string) (*APIKeyInfo, []byte, error) {
+	if m.info == nil || m.info.ID != id {
+		return nil, nil, ErrKeyNotFound
+	}
+	return m.info.clone(), m.sealed, nil
+}
+
+// --- Tests ---
+
+func TestPepper_Protect(t *testing.T) {
+	p := Pepper{Version: 2, Secret: []byte("pepper")}
+	stored, err := p.Protect("k1", "kart_secret")
+	require.NoError(t, err)
+	assert.Equal(t, p.Hash("kart_secret"), stored.Hash)
+	assert.Equal(t, 2, stored.PepperVersion)
+	assert.NotContains(t, string(stored.SigningKey), string(SigningKey("kart_secret")))
+
+	key, err := p.open("k1", stored.SigningKey)
+	require.NoError(t, err)
+	assert.Equal(t, SigningKey("kart_secret"), key)
+
+	_, err = p.open("k2", stored.SigningKey)
+	require.Error(t, err, "sealed key must be bound to its ID")
+	_, err = Pepper{Version: 2, Secret: []byte("other")}.open("k1", stored.SigningKey)
+	require.Error(t, err)
+}
+
+func TestRequestVerifier(t *testing.T) {
+	ctx := context.Background()
+	const secret = "kart_secret"
+	pepper := Pepper{Version: 1, Secret: []byte("pepper")}
+	stored, err := pepper.Protect("k1", secret)
+	require.NoError(t, err)
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	newVerifier := func(keys *mockSigningKeys) *RequestVerifier {
+		nonces := NewMemoryNonceStore()
+		nonces.now = func() time.Time { return now }
+		v := NewRequestVerifier(keys, Peppers{pepper}, nonces, 5*time.Minute)
+		v.now = nonces.now
+		return v
+	}
+	validKeys := func() *mockSigningKeys {
+		return &mockSigningKeys{
+			info:   &APIKeyInfo{ID: "k1", Scopes: []string{ScopeOrdersWrite}, PepperVersion: 1},
+			sealed: stored.SigningKey,
+		}
+	}
+	signed := func(at time.Time, nonce, body string) *SignedRequest {
+		req := &SignedRequest{
+			KeyID:     "k1",
+			Method:    "post",
+			Path:      "/api/order",
+			Timestamp: strconv.FormatInt(at.Unix(), 10),
+			Nonce:     nonce,
+			BodyHash:  sha256.Sum256([]byte(body)),
+		}
+		req.Signature = req.Sign(SigningKey(secret))
+		return req
+	}
+
+	t.Run("valid request returns key", func(t *testing.T) {
+		info, err := newVerifier(validKeys()).Verify(ctx, signed(now.Add(-time.Minute), "nonce-0000000001", `{}`))
+		require.NoError(t, err)
+		assert.Equal(t, "k1", info.ID)
+	})
+
+	t.Run("nonce cannot be reused", func(t *testing.T) {
+		v := newVerifier(validKeys())
+		_, err := v.Verify(ctx, signed(now, "nonce-0000000001", `{}`))
+		require.NoError(t, err)
+		_, err = v.Verify(ctx, signed(now, "nonce-0000000001", `{}`))
+		require.ErrorIs(t, err, ErrNonceReused)
+		_, err = v.Verify(ctx, signed(now, "nonce-0000000002", `{}`))
+		require.NoError(t, err)
+	})
+
+	t.Run("timestamp outside skew is refused", func(t *testing.T) {
+		v := newVerifier(validKeys())
+		_, err := v.Verify(ctx, signed(now.Add(-6*time.Minute), "nonce-0000000001", `{}`))
+		require.ErrorIs(t, err, ErrRequestTooOld)
+		_, err = v.Verify(ctx, signed(now.Add(6*time.Minute), "nonce-0000000001", `{}`))
+		require.ErrorIs(t, err, ErrRequestTooOld)
+	})
+
+	t.Run("tampered request is refused", func(t *testing.T) {
+		v := newVerifier(validKeys())
+		req := signed(now, "nonce-0000000001", `{"items":[]}`)
+		req.BodyHash = sha256.Sum256([]byte(`{"items":[{"productId":"1","quantity":99}]}`))
+		_, err := v.Verify(ctx, req)
+		require.ErrorIs(t, err, ErrSignatureInvalid)
+
+		req = signed(now, "nonce-0000000002", `{}`)
+		req.Path = "/api/v2/order"
+		_, err = v.Verify(ctx, req)
+		require.ErrorIs(t, err, ErrSignatureInvalid)
+
+		// A refused request does not use up its nonce.
+		_, err = v.Verify(ctx, signed(now, "nonce-0000000001", `{"items":[]}`))
+		require.NoError(t, err)
+	})
+
+	t.Run("malformed fields are refused", func(t *testing.T) {
+		v := newVerifier(validKeys())
+		for name, mutate := range map[string]func(*SignedRequest){
+			"timestamp": func(r *SignedRequest) { r.Timestamp = "yesterday" },
+			"short":     func(r *SignedRequest) { r.Nonce = "abc" },
+			"signature": func(r *SignedRequest) { r.Signature = "not-hex" },
+		} {
+			req := signed(now, "nonce-0000000001", `{}`)
+			mutate(req)
+			_, err := v.Verify(ctx, req)
+			require.ErrorIs(t, err, ErrSignatureInvalid, name)
+		}
+	})
+
+	t.Run("unknown key is refused", func(t *testing.T) {
+		req := signed(now, "nonce-0000000001", `{}`)
+		req.KeyID = "k2"
+		_, err := newVerifier(validKeys()).Verify(ctx, req)
+		require.ErrorIs(t, err, ErrSignatureInvalid)
+	})
+
+	t.Run("key without signing key is refused", func(t *testing.T) {
+		keys := validKeys()
+		keys.sealed = nil
+		_, err := newVerifier(keys).Verify(ctx, signed(now, "nonce-0000000001", `{}`))
+		require.ErrorIs(t, err, ErrSignatureInvalid)
+	})
+
+	t.Run("expired key is refused", func(t *testing.T) {
+		keys := validKeys()
+		expired := now.Add(-time.Hour)
+		keys.info.ExpiresAt = &expired
+		_, err := newVerifier(keys).Verify(ctx, signed(now, "nonce-0000000001", `{}`))
+		require.ErrorIs(t, err, ErrSignatureInvalid)
+	})
+}
+
+func TestMemoryNonceStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryNonceStore()
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.UseNonce(ctx, "k1", "n1", now.Add(time.Minute)))
+	require.ErrorIs(t, s.UseNonce(ctx, "k1", "n1", now.Add(time.Minute)), ErrNonceReused)
+	require.NoError(t, s.UseNonce(ctx, "k2", "n1", now.Add(time.Minute)), "nonces are per key")
+
+	now = now.Add(time.Minute)
+	require.NoError(t, s.UseNonce(ctx, "k1", "n1", now.Add(time.Minute)), "expired nonce may be used again")
+
+	n, err := s.Prune(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
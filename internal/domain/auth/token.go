@@ -0,0 +1,259 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenSubjectPrefix is prepended to a token's sub claim to form the
+// APIKeyInfo ID, so token subjects never collide with API key IDs.
+const TokenSubjectPrefix = "jwt:"
+
+// tokenAlgorithms are the only signing algorithms accepted for bearer
+// tokens. Pinning them rules out "none" and HMAC algorithm confusion.
+var tokenAlgorithms = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}
+
+// minRSABits is the smallest RSA modulus accepted in a JWKS.
+const minRSABits = 2048
+
+// JWKS is a set of public keys that bearer tokens may be signed with.
+type JWKS struct {
+	keys []jwk
+}
+
+type jwk struct {
+	kid string
+	alg string
+	key crypto.PublicKey
+}
+
+// LoadJWKS reads and parses a JWKS file.
+func LoadJWKS(path string) (*JWKS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read jwks")
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS parses a JSON Web Key Set (RFC 7517) holding RSA keys of at
+// least 2048 bits or P-256 EC keys. Keys marked for encryption are skipped;
+// any other key type is an error.
+func ParseJWKS(data []byte) (*JWKS, error) {
+	var raw struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			Alg string `json:"alg"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Wrap(err, "decode jwks")
+	}
+
+	set := &JWKS{}
+	kids := make(map[string]struct{}, len(raw.Keys))
+	for i, k := range raw.Keys {
+		if k.Use == "enc" {
+			continue
+		}
+		if _, dup := kids[k.Kid]; dup {
+			return nil, errors.Errorf("key %d: duplicate kid %q", i, k.Kid)
+		}
+		kids[k.Kid] = struct{}{}
+
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch k.Kty {
+		case "RSA":
+			key, err = parseRSAKey(k.N, k.E)
+		case "EC":
+			key, err = parseECKey(k.Crv, k.X, k.Y)
+		default:
+			err = errors.Errorf("unsupported kty %q", k.Kty)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "key %d (kid %q)", i, k.Kid)
+		}
+		set.keys = append(set.keys, jwk{kid: k.Kid, alg: k.Alg, key: key})
+	}
+	if len(set.keys) == 0 {
+		return nil, errors.New("jwks has no signing keys")
+	}
+	return set, nil
+}
+
+func parseRSAKey(n, e string) (*rsa.PublicKey, error) {
+	nb, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode n")
+	}
+	eb, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode e")
+	}
+	key := &rsa.PublicKey{N: new(big.Int).SetBytes(nb)}
+	exp := new(big.Int).SetBytes(eb)
+	if !exp.IsInt64() || exp.Int64() < 3 || exp.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid exponent")
+	}
+	key.E = int(exp.Int64())
+	if key.N.BitLen() < minRSABits {
+		return nil, errors.Errorf("rsa key has %d bits, want at least %d", key.N.BitLen(), minRSABits)
+	}
+	return key, nil
+}
+
+func parseECKey(crv, x, y string) (*ecdsa.PublicKey, error) {
+	if crv != "P-256" {
+		return nil, errors.Errorf("unsupported crv %q", crv)
+	}
+	xb, err := base64.RawURLEncoding.DecodeString(x)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode x")
+	}
+	yb, err := base64.RawURLEncoding.DecodeString(y)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode y")
+	}
+	if len(xb) != 32 || len(yb) != 32 {
+		return nil, errors.New("invalid P-256 coordinates")
+	}
+	key, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), append(append([]byte{4}, xb...), yb...))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid P-256 point")
+	}
+	return key, nil
+}
+
+// key returns the key a token with the given kid and alg must be verified
+// with. A token without a kid is only accepted when the set has one key.
+func (s *JWKS) key(kid, alg string) (crypto.PublicKey, error) {
+	var found *jwk
+	switch {
+	case kid != "":
+		for i := range s.keys {
+			if s.keys[i].kid == kid {
+				found = &s.keys[i]
+				break
+			}
+		}
+	case len(s.keys) == 1:
+		found = &s.keys[0]
+	}
+	if found == nil {
+		return nil, errors.Errorf("no key for kid %q", kid)
+	}
+	if found.alg != "" && found.alg != alg {
+		return nil, errors.Errorf("key %q is for %s, token uses %s", kid, found.alg, alg)
+	}
+	return found.key, nil
+}
+
+// TokenConfig controls which bearer tokens a TokenVerifier accepts.
+type TokenConfig struct {
+	// Issuer, when set, must equal the iss claim.
+	Issuer string
+	// Audience, when set, must be in the aud claim.
+	Audience string
+	// Leeway allows for clock skew when checking exp, nbf and iat.
+	Leeway time.Duration
+}
+
+// TokenVerifier validates RS256 and ES256 bearer tokens against a JWKS and
+// maps their claims onto an APIKeyInfo, so the scope checks made for API
+// keys apply to tokens unchanged. The key set can be swapped at any time
+// with SetKeys.
+type TokenVerifier struct {
+	cfg  TokenConfig
+	keys atomic.Pointer[JWKS]
+	now  func() time.Time
+}
+
+// NewTokenVerifier creates a TokenVerifier trusting keys.
+func NewTokenVerifier(cfg TokenConfig, keys *JWKS) *TokenVerifier {
+	v := &TokenVerifier{cfg: cfg, now: time.Now}
+	v.keys.Store(keys)
+	return v
+}
+
+// SetKeys replaces the trusted key set. Tokens being verified concurrently
+// use either the old or the new set.
+func (v *TokenVerifier) SetKeys(keys *JWKS) {
+	v.keys.Store(keys)
+}
+
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	// Scope is the OAuth 2.0 space-separated form (RFC 8693).
+	Scope string `json:"scope,omitempty"`
+	// Scp is the array form some identity providers use instead.
+	Scp []string `json:"scp,omitempty"`
+}
+
+// Verify checks token's signature, algorithm, expiry, issuer and audience
+// and returns the identity it carries. The ID is the sub claim prefixed with
+// TokenSubjectPrefix; scopes come from the scope or scp claim.
+func (v *TokenVerifier) Verify(token string) (*APIKeyInfo, error) {
+	keys := v.keys.Load()
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(tokenAlgorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(v.cfg.Leeway),
+		jwt.WithTimeFunc(v.now),
+	}
+	if v.cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(v.cfg.Issuer))
+	}
+	if v.cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(v.cfg.Audience))
+	}
+
+	var claims tokenClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return keys.key(kid, t.Method.Alg())
+	}, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "verify token")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("verify token: missing sub claim")
+	}
+
+	scopes := claims.Scp
+	if claims.Scope != "" {
+		scopes = strings.Fields(claims.Scope)
+	}
+	info := &APIKeyInfo{
+		ID:     TokenSubjectPrefix + claims.Subject,
+		Name:   claims.Subject,
+		Scopes: scopes,
+	}
+	if claims.ExpiresAt != nil {
+		exp := claims.ExpiresAt.Time
+		info.ExpiresAt = &exp
+	}
+	return info, nil
+}
@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Helpers ---
+
+var (
+	testRSAKey = mustRSAKey()
+	testECKey  = mustECKey()
+)
+
+func mustRSAKey() *rsa.PrivateKey {
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+func mustECKey() *ecdsa.PrivateKey {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+func rsaJWK(kid string, k *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA", "kid": kid, "use": "sig", "alg": "RS256",
+		"n": b64(k.N.Bytes()), "e": b64(big.NewInt(int64(k.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, k *ecdsa.PublicKey) map[string]string {
+	raw, err := k.Bytes()
+	if err != nil {
+		panic(err)
+	}
+	return map[string]string{
+		"kty": "EC", "kid": kid, "crv": "P-256",
+		"x": b64(raw[1:33]), "y": b64(raw[33:]),
+	}
+}
+
+func jwksJSON(t *testing.T, keys ...map[string]string) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	require.NoError(t, err)
+	return data
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		tok.Header["kid"] = kid
+	}
+	s, err := tok.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+// --- Tests ---
+
+func TestParseJWKS(t *testing.T) {
+	t.Run("rsa and ec keys", func(t *testing.T) {
+		set, err := ParseJWKS(jwksJSON(t,
+			rsaJWK("r1", &testRSAKey.PublicKey),
+			ecJWK("e1", &testECKey.PublicKey),
+			map[string]string{"kty": "RSA", "kid": "enc", "use": "enc"},
+		))
+		require.NoError(t, err)
+		assert.Len(t, set.keys, 2, "encryption keys are skipped")
+	})
+
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	badPoint := ecJWK("e1", &testECKey.PublicKey)
+	badPoint["y"] = badPoint["x"]
+
+	for name, data := range map[string][]byte{
+		"invalid json":  []byte("{"),
+		"no keys":       jwksJSON(t),
+		"small rsa key": jwksJSON(t, rsaJWK("r1", &small.PublicKey)),
+		"duplicate kid": jwksJSON(t, rsaJWK("k", &testRSAKey.PublicKey), ecJWK("k", &testECKey.PublicKey)),
+		"unknown kty":   jwksJSON(t, map[string]string{"kty": "oct", "k": "c2VjcmV0"}),
+		"P-384 curve": jwksJSON(t, map[string]string{
+			"kty": "EC", "crv": "P-384", "x": b64(p384.X.Bytes()), "y": b64(p384.Y.Bytes()),
+		}),
+		"point off curve": jwksJSON(t, badPoint),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseJWKS(data)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestTokenVerifier(t *testing.T) {
+	set, err := ParseJWKS(jwksJSON(t,
+		rsaJWK("r1", &testRSAKey.PublicKey),
+		ecJWK("e1", &testECKey.PublicKey),
+	))
+	require.NoError(t, err)
+
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	v := NewTokenVerifier(TokenConfig{Issuer: "https://idp.internal", Audience: "kart", Leeway: time.Second}, set)
+	v.now = func() time.Time { return now }
+
+	claims := func(extra jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"sub": "billing-service",
+			"iss": "https://idp.internal",
+			"aud": "kart",
+			"iat": now.Unix(),
+			"exp": now.Add(5 * time.Minute).Unix(),
+		}
+		for k, val := range extra {
+			if val == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = val
+		}
+		return c
+	}
+
+	t.Run("RS256 with scope claim", func(t *testing.T) {
+		tok := signToken(t, jwt.SigningMethodRS256, "r1", testRSAKey,
+			claims(jwt.MapClaims{"scope": "orders:read orders:write"}))
+		info, err := v.Verify(tok)
+		require.NoError(t, err)
+		assert.Equal(t, "jwt:billing-service", info.ID)
+		assert.Equal(t, []string{ScopeOrdersRead, ScopeOrdersWrite}, info.Scopes)
+		require.NotNil(t, info.ExpiresAt)
+		assert.True(t, info.ExpiresAt.Equal(now.Add(5*time.Minute)))
+	})
+
+	t.Run("ES256 with scp claim", func(t *testing.T) {
+		tok := signToken(t, jwt.SigningMethodES256, "e1", testECKey,
+			claims(jwt.MapClaims{"scp": []string{ScopeOrdersAdmin}}))
+		info, err := v.Verify(tok)
+		require.NoError(t, err)
+		assert.Equal(t, []string{ScopeOrdersAdmin}, info.Scopes)
+	})
+
+	otherRSA := mustRSAKey()
+	for name, tok := range map[string]string{
+		"expired":          signToken(t, jwt.SigningMethodRS256, "r1", testRSAKey, claims(jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()})),
+		"no exp":           signToken(t, jwt.SigningMethodRS256, "r1", testRSAKey, claims(jwt.MapClaims{"exp": nil})),
+		"no sub":           signToken(t, jwt.SigningMethodRS256, "r1", testRSAKey, claims(jwt.MapClaims{"sub": nil})),
+		"wrong issuer":     signToken(t, jwt.SigningMethodRS256, "r1", testRSAKey, claims(jwt.MapClaims{"iss": "https://evil"})),
+		"wrong audience":   signToken(t, jwt.SigningMethodRS256, "r1", testRSAKey, claims(jwt.MapClaims{"aud": "other"})),
+		"unknown kid":      signToken(t, jwt.SigningMethodRS256, "r2", testRSAKey, claims(nil)),
+		"no kid":           signToken(t, jwt.SigningMethodRS256, "", testRSAKey, claims(nil)),
+		"wrong signer":     signToken(t, jwt.SigningMethodRS256, "r1", otherRSA, claims(nil)),
+		"alg of wrong key": signToken(t, jwt.SigningMethodES256, "r1", testECKey, claims(nil)),
+		"RS384":            signToken(t, jwt.SigningMethodRS384, "r1", testRSAKey, claims(nil)),
+		"HS256 with public key as secret": signToken(t, jwt.SigningMethodHS256, "r1",
+			testRSAKey.PublicKey.N.Bytes(), claims(nil)),
+		"garbage": "not.a.token",
+	} {
+		t.Run(name+" is refused", func(t *testing.T) {
+			_, err := v.Verify(tok)
+			require.Error(t, err)
+		})
+	}
+
+	t.Run("token without kid is accepted by a single-key set", func(t *testing.T) {
+		single, err := ParseJWKS(jwksJSON(t, ecJWK("", &testECKey.PublicKey)))
+		require.NoError(t, err)
+		v := NewTokenVerifier(TokenConfig{}, single)
+		v.now = func() time.Time { return now }
+
+		_, err = v.Verify(signToken(t, jwt.SigningMethodES256, "", testECKey, claims(nil)))
+		require.NoError(t, err)
+	})
+
+	t.Run("SetKeys swaps the trusted keys", func(t *testing.T) {
+		v := NewTokenVerifier(TokenConfig{}, set)
+		v.now = func() time.Time { return now }
+		tok := signToken(t, jwt.SigningMethodRS256, "r1", testRSAKey, claims(nil))
+		_, err := v.Verify(tok)
+		require.NoError(t, err)
+
+		rotated, err := ParseJWKS(jwksJSON(t, rsaJWK("r2", &otherRSA.PublicKey)))
+		require.NoError(t, err)
+		v.SetKeys(rotated)
+
+		_, err = v.Verify(tok)
+		require.Error(t, err)
+		_, err = v.Verify(signToken(t, jwt.SigningMethodRS256, "r2", otherRSA, claims(nil)))
+		require.NoError(t, err)
+	})
+}
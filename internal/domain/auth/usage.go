@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// UsageStore persists when keys were last used.
+type UsageStore interface {
+	// TouchKeys sets last_used_at for each key ID in one write. A stored
+	// time later than the one given is kept.
+	TouchKeys(ctx context.Context, usedAt map[string]time.Time) error
+}
+
+// UsageTracker batches API key last-use times so authentication never
+// waits on a database write. Touch only updates an in-memory map; Flush
+// writes it out, and app.runUsageFlusher calls Flush periodically, one
+// write per interval however many requests arrived.
+type UsageTracker struct {
+	store UsageStore
+	now   func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]time.Time
+}
+
+// NewUsageTracker creates a UsageTracker writing to store.
+func NewUsageTracker(store UsageStore) *UsageTracker {
+	return &UsageTracker{
+		store:   store,
+		now:     time.Now,
+		pending: make(map[string]time.Time),
+	}
+}
+
+// Touch records that key id was used now.
+func (t *UsageTracker) Touch(id string) {
+	now := t.now()
+	t.mu.Lock()
+	t.pending[id] = now
+	t.mu.Unlock()
+}
+
+// Flush writes pending last-use times and returns how many keys were
+// written. On failure the batch is put back, unless newer uses have
+// replaced it in the meantime, so it is retried on the next flush.
+func (t *UsageTracker) Flush(ctx context.Context) (int64, error) {
+	t.mu.Lock()
+	batch := t.pending
+	t.pending = make(map[string]time.Time, len(batch))
+	t.mu.Unlock()
+
+	if len(batch) == 0 {
+		return 0, nil
+	}
+	if err := t.store.TouchKeys(ctx, batch); err != nil {
+		t.mu.Lock()
+		for id, at := range batch {
+			if _, ok := t.pending[id]; !ok {
+				t.pending[id] = at
+			}
+		}
+		t.mu.Unlock()
+		return 0, err
+	}
+	return int64(len(batch)), nil
+}
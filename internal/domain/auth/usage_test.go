@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Mock implementations ---
+
+type mockUsageStore struct {
+	writes []map[string]time.Time
+	err    error
+}
+
+func (m *mockUsageStore) TouchKeys(_ context.Context, usedAt map[string]time.Time) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.writes = append(m.writes, usedAt)
+	return nil
+}
+
+// --- Tests ---
+
+func TestUsageTracker_BatchesTouches(t *testing.T) {
+	ctx := context.Background()
+	store := &mockUsageStore{}
+	tracker := NewUsageTracker(store)
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	tracker.Touch("k1")
+	now = now.Add(time.Second)
+	tracker.Touch("k1")
+	tracker.Touch("k2")
+
+	n, err := tracker.Flush(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	require.Len(t, store.writes, 1)
+	assert.Equal(t, map[string]time.Time{"k1": now, "k2": now}, store.writes[0])
+
+	n, err = tracker.Flush(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Len(t, store.writes, 1, "empty batch must not write")
+}
+
+func TestUsageTracker_RetriesFailedBatch(t *testing.T) {
+	ctx := context.Background()
+	store := &mockUsageStore{err: errors.New("db down")}
+	tracker := NewUsageTracker(store)
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	tracker.Touch("k1")
+	_, err := tracker.Flush(ctx)
+	require.Error(t, err)
+
+	store.err = nil
+	_, err = tracker.Flush(ctx)
+	require.NoError(t, err)
+	require.Len(t, store.writes, 1)
+	assert.Equal(t, now, store.writes[0]["k1"])
+}
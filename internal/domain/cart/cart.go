@@ -0,0 +1,74 @@
+package cart
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+// ErrNotFound is returned when a cart does not exist, has expired, or belongs
+// to a different API key.
+var ErrNotFound = errors.New("cart not found")
+
+// Cart is a server-side shopping cart owned by a single API key. Carts expire
+// after a period of inactivity; every change pushes ExpiresAt forward.
+type Cart struct {
+	ID string
+	// Owner is the ID of the API key that created the cart.
+	Owner      string
+	Items      []Item
+	CouponCode string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ExpiresAt  time.Time
+}
+
+// Item is a product and quantity held in a cart. Prices are not stored; they
+// are read from the catalog whenever the cart is priced.
+type Item struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+// SetItem sets the quantity of productID, adding a line when the product is
+// not in the cart yet. Existing lines keep their position.
+func (c *Cart) SetItem(productID string, quantity int) {
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID {
+			c.Items[i].Quantity = quantity
+			return
+		}
+	}
+	c.Items = append(c.Items, Item{ProductID: productID, Quantity: quantity})
+}
+
+// RemoveItem drops the line for productID. It reports whether the line was
+// present.
+func (c *Cart) RemoveItem(productID string) bool {
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID {
+			c.Items = append(c.Items[:i], c.Items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Repository defines persistence operations for carts.
+type Repository interface {
+	// Create persists a new cart and sets CreatedAt and UpdatedAt.
+	Create(ctx context.Context, cart *Cart) error
+	// Get returns a cart that has not expired. When called within a
+	// transaction the row stays locked until it ends, so read-modify-write
+	// cycles do not lose updates. Returns ErrNotFound otherwise.
+	Get(ctx context.Context, id string) (*Cart, error)
+	// Update stores the cart's items, coupon and expiry and sets UpdatedAt.
+	// Returns ErrNotFound when the cart no longer exists.
+	Update(ctx context.Context, cart *Cart) error
+	// Delete removes a cart. Deleting a missing cart is not an error.
+	Delete(ctx context.Context, id string) error
+	// DeleteExpired removes carts whose expiry is before now and returns how
+	// many were deleted.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
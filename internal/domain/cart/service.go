@@ -0,0 +1,255 @@
+package cart
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/google/uuid"
+
+	"github.com/xenking/oolio-kart-challenge/internal/domain/order"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/product"
+)
+
+// Orders is the part of order.Service that carts are priced and checked out
+// through.
+type Orders interface {
+	Quote(ctx context.Context, req order.QuoteRequest) (*order.Quote, error)
+	PlaceOrder(ctx context.Context, req order.PlaceOrderRequest) (*order.PlaceOrderResult, error)
+}
+
+// ServiceConfig holds tunables for the cart Service.
+type ServiceConfig struct {
+	// TTL is how long a cart is kept after its last change.
+	TTL time.Duration
+}
+
+// Details holds a cart together with its current pricing.
+type Details struct {
+	Cart *Cart
+	// Quote prices the cart against the current catalog and coupon rules.
+	// For an empty cart all amounts are zero and Items is empty.
+	Quote *order.Quote
+}
+
+// Service manages server-side carts and turns them into orders.
+type Service struct {
+	carts    Repository
+	products product.Repository
+	orders   Orders
+	tx       order.Transactor
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+// NewService creates a cart Service with the required domain dependencies.
+func NewService(
+	cfg ServiceConfig,
+	carts Repository,
+	products product.Repository,
+	orders Orders,
+	tx order.Transactor,
+) *Service {
+	return &Service{
+		carts:    carts,
+		products: products,
+		orders:   orders,
+		tx:       tx,
+		ttl:      cfg.TTL,
+		now:      time.Now,
+	}
+}
+
+// Create starts an empty cart owned by owner.
+func (s *Service) Create(ctx context.Context, owner string) (*Details, error) {
+	c := &Cart{
+		ID:        uuid.New().String(),
+		Owner:     owner,
+		ExpiresAt: s.now().Add(s.ttl),
+	}
+	if err := s.carts.Create(ctx, c); err != nil {
+		return nil, fmt.Errorf("create cart: %w", err)
+	}
+	return s.details(ctx, c)
+}
+
+// Get returns a cart with its current pricing. Returns ErrNotFound when the
+// cart does not exist, has expired, or is not owned by owner.
+func (s *Service) Get(ctx context.Context, owner, id string) (*Details, error) {
+	c, err := s.get(ctx, owner, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.details(ctx, c)
+}
+
+// SetItem sets the quantity of a product in the cart, adding it when absent.
+// Returns *order.InvalidQuantityError for a non-positive quantity and
+// *order.ProductNotFoundError for a product missing from the catalog.
+func (s *Service) SetItem(ctx context.Context, owner, id, productID string, quantity int) (*Details, error) {
+	if quantity <= 0 {
+		return nil, &order.InvalidQuantityError{ProductID: productID}
+	}
+	if _, err := s.products.GetByID(ctx, productID); err != nil {
+		if errors.Is(err, product.ErrNotFound) {
+			return nil, &order.ProductNotFoundError{ProductID: productID}
+		}
+		return nil, fmt.Errorf("get product: %w", err)
+	}
+
+	return s.update(ctx, owner, id, func(c *Cart) error {
+		c.SetItem(productID, quantity)
+		return nil
+	})
+}
+
+// RemoveItem drops a product from the cart. Removing a product that is not
+// in the cart is a no-op.
+func (s *Service) RemoveItem(ctx context.Context, owner, id, productID string) (*Details, error) {
+	return s.update(ctx, owner, id, func(c *Cart) error {
+		c.RemoveItem(productID)
+		return nil
+	})
+}
+
+// AttachCoupon sets the coupon code applied to the cart, replacing any
+// previous one. The code is not redeemed until checkout; if it cannot be
+// applied the cart's quote says why.
+func (s *Service) AttachCoupon(ctx context.Context, owner, id, code string) (*Details, error) {
+	code = strings.TrimSpace(code)
+	return s.update(ctx, owner, id, func(c *Cart) error {
+		c.CouponCode = code
+		return nil
+	})
+}
+
+// DetachCoupon removes the coupon code from the cart.
+func (s *Service) DetachCoupon(ctx context.Context, owner, id string) (*Details, error) {
+	return s.update(ctx, owner, id, func(c *Cart) error {
+		c.CouponCode = ""
+		return nil
+	})
+}
+
+// Checkout places an order for the cart's contents on behalf of owner and
+// deletes the cart. The order, coupon redemption and cart deletion share one
+// transaction, so a cart is checked out at most once.
+func (s *Service) Checkout(ctx context.Context, owner, id string) (*order.PlaceOrderResult, error) {
+	var result *order.PlaceOrderResult
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		c, err := s.get(ctx, owner, id)
+		if err != nil {
+			return err
+		}
+
+		result, err = s.orders.PlaceOrder(ctx, order.PlaceOrderRequest{
+			Items:       orderItems(c.Items),
+			CouponCodes: couponCodes(c),
+			Actor:       owner,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := s.carts.Delete(ctx, c.ID); err != nil {
+			return fmt.Errorf("delete cart: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Prune deletes expired carts and returns how many were removed.
+func (s *Service) Prune(ctx context.Context) (int64, error) {
+	n, err := s.carts.DeleteExpired(ctx, s.now())
+	if err != nil {
+		return 0, fmt.Errorf("delete expired carts: %w", err)
+	}
+	return n, nil
+}
+
+// update applies fn to the cart in a transaction, extends its expiry and
+// stores it.
+func (s *Service) update(ctx context.Context, owner, id string, fn func(*Cart) error) (*Details, error) {
+	var c *Cart
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		c, err = s.get(ctx, owner, id)
+		if err != nil {
+			return err
+		}
+		if err := fn(c); err != nil {
+			return err
+		}
+
+		c.ExpiresAt = s.now().Add(s.ttl)
+		if err := s.carts.Update(ctx, c); err != nil {
+			return fmt.Errorf("update cart: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.details(ctx, c)
+}
+
+// get loads a cart and hides carts owned by other API keys.
+func (s *Service) get(ctx context.Context, owner, id string) (*Cart, error) {
+	c, err := s.carts.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get cart: %w", err)
+	}
+	if c.Owner != owner {
+		return nil, ErrNotFound
+	}
+	return c, nil
+}
+
+// details prices the cart through the order service.
+func (s *Service) details(ctx context.Context, c *Cart) (*Details, error) {
+	if len(c.Items) == 0 {
+		return &Details{
+			Cart:  c,
+			Quote: &order.Quote{CouponCodes: couponCodes(c)},
+		}, nil
+	}
+
+	q, err := s.orders.Quote(ctx, order.QuoteRequest{
+		Items:       orderItems(c.Items),
+		CouponCodes: couponCodes(c),
+		Customer:    c.Owner,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Details{Cart: c, Quote: q}, nil
+}
+
+func orderItems(items []Item) []order.OrderItem {
+	out := make([]order.OrderItem, len(items))
+	for i, item := range items {
+		out[i] = order.OrderItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+	}
+	return out
+}
+
+// couponCodes returns the cart's coupon, if any, as the code list orders
+// take. A cart holds at most one coupon.
+func couponCodes(c *Cart) []string {
+	if c.CouponCode == "" {
+		return nil
+	}
+	return []string{c.CouponCode}
+}
@@ -0,0 +1,268 @@
+package cart
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/xenking/oolio-kart-challenge/internal/domain/order"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/product"
+)
+
+// --- Mock implementations ---
+
+type mockCartRepo struct {
+	carts   map[string]Cart
+	deleted []string
+}
+
+func newCartRepo() *mockCartRepo {
+	return &mockCartRepo{carts: make(map[string]Cart)}
+}
+
+func (m *mockCartRepo) Create(_ context.Context, c *Cart) error {
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = c.CreatedAt
+	m.carts[c.ID] = *c
+	return nil
+}
+
+func (m *mockCartRepo) Get(_ context.Context, id string) (*Cart, error) {
+	c, ok := m.carts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	c.Items = append([]Item(nil), c.Items...)
+	return &c, nil
+}
+
+func (m *mockCartRepo) Update(_ context.Context, c *Cart) error {
+	if _, ok := m.carts[c.ID]; !ok {
+		return ErrNotFound
+	}
+	c.UpdatedAt = time.Now()
+	m.carts[c.ID] = *c
+	return nil
+}
+
+func (m *mockCartRepo) Delete(_ context.Context, id string) error {
+	delete(m.carts, id)
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func (m *mockCartRepo) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	var n int64
+	for id, c := range m.carts {
+		if c.ExpiresAt.Before(now) {
+			delete(m.carts, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+type mockProductRepo struct {
+	byID map[string]product.Product
+}
+
+func (m *mockProductRepo) List(_ context.Context) ([]product.Product, error) {
+	return nil, nil
+}
+
+func (m *mockProductRepo) GetByID(_ context.Context, id string) (*product.Product, error) {
+	p, ok := m.byID[id]
+	if !ok {
+		return nil, product.ErrNotFound
+	}
+	return &p, nil
+}
+
+func (m *mockProductRepo) GetByIDs(_ context.Context, _ []string) ([]product.Product, error) {
+	return nil, nil
+}
+
+type mockOrders struct {
+	quoteReq order.QuoteRequest
+	placeReq order.PlaceOrderRequest
+	placed   int
+	placeErr error
+}
+
+func (m *mockOrders) Quote(_ context.Context, req order.QuoteRequest) (*order.Quote, error) {
+	m.quoteReq = req
+	return &order.Quote{
+		Items:       req.Items,
+		Subtotal:    decimal.NewFromInt(10),
+		Total:       decimal.NewFromInt(10),
+		CouponCodes: req.CouponCodes,
+	}, nil
+}
+
+func (m *mockOrders) PlaceOrder(_ context.Context, req order.PlaceOrderRequest) (*order.PlaceOrderResult, error) {
+	m.placeReq = req
+	if m.placeErr != nil {
+		return nil, m.placeErr
+	}
+	m.placed++
+	return &order.PlaceOrderResult{Order: &order.Order{ID: "o1"}}, nil
+}
+
+type mockTransactor struct {
+	calls int
+}
+
+func (m *mockTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+func newTestService(carts *mockCartRepo, orders *mockOrders) *Service {
+	products := &mockProductRepo{byID: map[string]product.Product{
+		"p1": {ID: "p1", Name: "Widget", Price: decimal.NewFromInt(5)},
+		"p2": {ID: "p2", Name: "Gadget", Price: decimal.NewFromInt(7)},
+	}}
+	return NewService(ServiceConfig{TTL: time.Hour}, carts, products, orders, &mockTransactor{})
+}
+
+// --- Tests ---
+
+func TestService_CreateAndEdit(t *testing.T) {
+	ctx := context.Background()
+	carts := newCartRepo()
+	orders := &mockOrders{}
+	svc := newTestService(carts, orders)
+
+	d, err := svc.Create(ctx, "key-1")
+	require.NoError(t, err)
+	id := d.Cart.ID
+	assert.Equal(t, "key-1", d.Cart.Owner)
+	assert.Empty(t, d.Cart.Items)
+	require.NotNil(t, d.Quote)
+	assert.True(t, d.Quote.Total.IsZero())
+
+	_, err = svc.SetItem(ctx, "key-1", id, "p1", 2)
+	require.NoError(t, err)
+	_, err = svc.SetItem(ctx, "key-1", id, "p2", 1)
+	require.NoError(t, err)
+	d, err = svc.SetItem(ctx, "key-1", id, "p1", 3)
+	require.NoError(t, err)
+	assert.Equal(t, []Item{{ProductID: "p1", Quantity: 3}, {ProductID: "p2", Quantity: 1}}, d.Cart.Items)
+	assert.Equal(t, []order.OrderItem{{ProductID: "p1", Quantity: 3}, {ProductID: "p2", Quantity: 1}}, orders.quoteReq.Items)
+
+	d, err = svc.AttachCoupon(ctx, "key-1", id, " HAPPYHOURS ")
+	require.NoError(t, err)
+	assert.Equal(t, "HAPPYHOURS", d.Cart.CouponCode)
+	assert.Equal(t, []string{"HAPPYHOURS"}, orders.quoteReq.CouponCodes)
+
+	d, err = svc.RemoveItem(ctx, "key-1", id, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, []Item{{ProductID: "p2", Quantity: 1}}, d.Cart.Items)
+
+	d, err = svc.DetachCoupon(ctx, "key-1", id)
+	require.NoError(t, err)
+	assert.Empty(t, d.Cart.CouponCode)
+	assert.Empty(t, orders.quoteReq.CouponCodes)
+}
+
+func TestService_SetItemValidation(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(newCartRepo(), &mockOrders{})
+	d, err := svc.Create(ctx, "key-1")
+	require.NoError(t, err)
+
+	_, err = svc.SetItem(ctx, "key-1", d.Cart.ID, "p1", 0)
+	var iqErr *order.InvalidQuantityError
+	require.ErrorAs(t, err, &iqErr)
+
+	_, err = svc.SetItem(ctx, "key-1", d.Cart.ID, "nope", 1)
+	var pnfErr *order.ProductNotFoundError
+	require.ErrorAs(t, err, &pnfErr)
+	assert.Equal(t, "nope", pnfErr.ProductID)
+}
+
+func TestService_OtherOwnerSeesNotFound(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(newCartRepo(), &mockOrders{})
+	d, err := svc.Create(ctx, "key-1")
+	require.NoError(t, err)
+
+	_, err = svc.Get(ctx, "key-2", d.Cart.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = svc.SetItem(ctx, "key-2", d.Cart.ID, "p1", 1)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = svc.Checkout(ctx, "key-2", d.Cart.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestService_UpdateExtendsExpiry(t *testing.T) {
+	ctx := context.Background()
+	carts := newCartRepo()
+	svc := newTestService(carts, &mockOrders{})
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	d, err := svc.Create(ctx, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), d.Cart.ExpiresAt)
+
+	now = now.Add(30 * time.Minute)
+	d, err = svc.SetItem(ctx, "key-1", d.Cart.ID, "p1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), d.Cart.ExpiresAt)
+
+	now = now.Add(2 * time.Hour)
+	n, err := svc.Prune(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+func TestService_Checkout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("places order and deletes cart", func(t *testing.T) {
+		carts := newCartRepo()
+		orders := &mockOrders{}
+		svc := newTestService(carts, orders)
+		d, err := svc.Create(ctx, "key-1")
+		require.NoError(t, err)
+		_, err = svc.SetItem(ctx, "key-1", d.Cart.ID, "p1", 2)
+		require.NoError(t, err)
+		_, err = svc.AttachCoupon(ctx, "key-1", d.Cart.ID, "HAPPYHOURS")
+		require.NoError(t, err)
+
+		res, err := svc.Checkout(ctx, "key-1", d.Cart.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "o1", res.Order.ID)
+		assert.Equal(t, order.PlaceOrderRequest{
+			Items:       []order.OrderItem{{ProductID: "p1", Quantity: 2}},
+			CouponCodes: []string{"HAPPYHOURS"},
+			Actor:       "key-1",
+		}, orders.placeReq)
+		assert.Equal(t, []string{d.Cart.ID}, carts.deleted)
+
+		_, err = svc.Checkout(ctx, "key-1", d.Cart.ID)
+		require.ErrorIs(t, err, ErrNotFound)
+		assert.Equal(t, 1, orders.placed)
+	})
+
+	t.Run("failed order keeps cart", func(t *testing.T) {
+		carts := newCartRepo()
+		orders := &mockOrders{placeErr: errors.New("boom")}
+		svc := newTestService(carts, orders)
+		d, err := svc.Create(ctx, "key-1")
+		require.NoError(t, err)
+
+		_, err = svc.Checkout(ctx, "key-1", d.Cart.ID)
+		require.Error(t, err)
+		assert.Empty(t, carts.deleted)
+		assert.Contains(t, carts.carts, d.Cart.ID)
+	})
+}
@@ -0,0 +1,115 @@
+package coupon
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AttemptStore keeps the invalid codes Guard has seen from each subject.
+// Implementations must update a subject in one atomic step, so that servers
+// sharing a store enforce the limits between them.
+type AttemptStore interface {
+	// Fail records that subject presented code at now. If its last code was
+	// presented at or before forgetBefore, its codes and any block are
+	// forgotten first. Returns how many distinct codes subject has presented
+	// since its count started, or 0 if code was already among them.
+	Fail(ctx context.Context, subject, code string, now, forgetBefore time.Time) (int, error)
+	// Block blocks subject until until, unless it already is for longer.
+	// With reset, its codes are forgotten so the count starts over.
+	Block(ctx context.Context, subject string, until time.Time, reset bool) error
+	// BlockedUntil returns the latest time any of subjects is blocked until,
+	// or the zero time if none has been blocked.
+	BlockedUntil(ctx context.Context, subjects []string) (time.Time, error)
+	// Prune forgets subjects whose last code was presented at or before
+	// forgetBefore and that are no longer blocked at now, and returns how
+	// many were removed.
+	Prune(ctx context.Context, now, forgetBefore time.Time) (int64, error)
+}
+
+var _ AttemptStore = (*MemoryAttemptStore)(nil)
+
+// MemoryAttemptStore keeps invalid coupon attempts in process memory. Each
+// server keeps its own, so behind N replicas a caller gets up to N times
+// the attempts; those should share the Postgres store.
+type MemoryAttemptStore struct {
+	mu       sync.Mutex
+	subjects map[string]*attempts
+}
+
+// attempts is what MemoryAttemptStore remembers about one subject.
+type attempts struct {
+	codes        map[string]struct{}
+	last         time.Time
+	blockedUntil time.Time
+}
+
+// NewMemoryAttemptStore creates an empty MemoryAttemptStore.
+func NewMemoryAttemptStore() *MemoryAttemptStore {
+	return &MemoryAttemptStore{subjects: make(map[string]*attempts)}
+}
+
+// Fail records that subject presented code at now.
+func (s *MemoryAttemptStore) Fail(_ context.Context, subject, code string, now, forgetBefore time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.subjects[subject]
+	if !ok || !a.last.After(forgetBefore) {
+		a = &attempts{codes: make(map[string]struct{})}
+		s.subjects[subject] = a
+	}
+	a.last = now
+	if _, seen := a.codes[code]; seen {
+		return 0, nil
+	}
+	a.codes[code] = struct{}{}
+	return len(a.codes), nil
+}
+
+// Block blocks subject until until, unless it already is for longer.
+func (s *MemoryAttemptStore) Block(_ context.Context, subject string, until time.Time, reset bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.subjects[subject]
+	if !ok {
+		return nil
+	}
+	if until.After(a.blockedUntil) {
+		a.blockedUntil = until
+	}
+	if reset {
+		a.codes = make(map[string]struct{})
+	}
+	return nil
+}
+
+// BlockedUntil returns the latest time any of subjects is blocked until.
+func (s *MemoryAttemptStore) BlockedUntil(_ context.Context, subjects []string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var until time.Time
+	for _, subject := range subjects {
+		if a, ok := s.subjects[subject]; ok && a.blockedUntil.After(until) {
+			until = a.blockedUntil
+		}
+	}
+	return until, nil
+}
+
+// Prune forgets idle subjects that are no longer blocked.
+func (s *MemoryAttemptStore) Prune(_ context.Context, now, forgetBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	for subject, a := range s.subjects {
+		if !a.last.After(forgetBefore) && !now.Before(a.blockedUntil) {
+			delete(s.subjects, subject)
+			n++
+		}
+	}
+	return n, nil
+}
@@ -2,6 +2,8 @@ package coupon
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
 	"time"
 
 	"github.com/go-faster/errors"
@@ -21,15 +23,52 @@ const (
 )
 
 var (
-	// ErrInvalidCoupon is returned when a coupon code is not found or
-	// the cart does not satisfy the coupon's minimum item requirement.
+	// ErrInvalidCoupon is returned when a coupon code is not found, the
+	// cart holds none of the items the coupon targets, or it does not
+	// satisfy the coupon's minimum item requirement.
 	ErrInvalidCoupon = errors.New("invalid coupon code")
 	// ErrCouponExpired is returned when a coupon is outside its valid time window.
 	ErrCouponExpired = errors.New("coupon expired")
 	// ErrCouponUsageLimitReached is returned when a coupon has exhausted its allowed uses.
 	ErrCouponUsageLimitReached = errors.New("coupon usage limit reached")
+	// ErrCouponAlreadyUsed is returned when the customer has used up the
+	// coupon's per-customer allowance.
+	ErrCouponAlreadyUsed = errors.New("coupon already used")
+	// ErrCouponNotStackable is returned when a coupon that must be used
+	// alone is combined with other coupons.
+	ErrCouponNotStackable = errors.New("coupon cannot be combined with other coupons")
+	// ErrNotFound is returned by administrative lookups when no coupon,
+	// active or not, has the code.
+	ErrNotFound = errors.New("coupon not found")
+	// ErrExists is returned when creating a coupon whose code is taken.
+	ErrExists = errors.New("coupon code already exists")
 )
 
+// IsRejection reports whether err means the coupon cannot be applied to the
+// cart, as opposed to a failure looking it up.
+func IsRejection(err error) bool {
+	return errors.Is(err, ErrInvalidCoupon) ||
+		errors.Is(err, ErrCouponExpired) ||
+		errors.Is(err, ErrCouponUsageLimitReached) ||
+		errors.Is(err, ErrCouponAlreadyUsed) ||
+		errors.Is(err, ErrCouponNotStackable)
+}
+
+// CodeError is a rejection of one of the codes in a Request. It unwraps to
+// the rejection, so errors.Is and IsRejection see through it.
+type CodeError struct {
+	Code string
+	Err  error
+}
+
+func (e *CodeError) Error() string {
+	return fmt.Sprintf("coupon %s: %v", e.Code, e.Err)
+}
+
+func (e *CodeError) Unwrap() error {
+	return e.Err
+}
+
 // Rule defines a coupon's discount behaviour and eligibility constraints.
 type Rule struct {
 	Code         string
@@ -42,23 +81,160 @@ type Rule struct {
 	MaxUses      int
 	Uses         int
 	MaxDiscount  decimal.Decimal
+	// MaxUsesPerCustomer limits how many orders one customer may place with
+	// the coupon. Zero means no limit.
+	MaxUsesPerCustomer int
+	// Stackable coupons can be combined with other stackable coupons on one
+	// order. Other coupons must be used alone.
+	Stackable bool
+	// Priority orders coupons of the same kind within a stack; higher
+	// priorities are applied first.
+	Priority int
+	// IncludeProducts and IncludeCategories, when either is set, limit the
+	// coupon to items with one of the product IDs or in one of the
+	// categories. ExcludeProducts and ExcludeCategories take items out
+	// again and win over the include lists. Categories match
+	// product.Product.Category case-insensitively.
+	IncludeProducts   []string
+	IncludeCategories []string
+	ExcludeProducts   []string
+	ExcludeCategories []string
+}
+
+// Request names the coupons to apply to one order together and who applies
+// them.
+type Request struct {
+	// Codes are the coupon codes as the customer typed them.
+	Codes []string
+	// Customer is checked against per-customer limits and recorded with
+	// each redemption.
+	Customer string
+	// OrderID is the order the redemptions are recorded for. Preview does
+	// not use it.
+	OrderID string
+}
+
+// Redemption is one use of a coupon: who used it on which order.
+type Redemption struct {
+	Code string
+	// Customer is the API key ID, or jwt:<sub>, that placed the order.
+	Customer string
+	OrderID  string
+}
+
+// Coupon is a rule as administrators see it, with its status and usage.
+type Coupon struct {
+	Rule
+	Active    bool
+	CreatedAt time.Time
+	Usage     Usage
+}
+
+// Usage summarizes the orders a coupon was redeemed on.
+type Usage struct {
+	// Orders counts orders placed with the coupon that were not cancelled
+	// or rejected.
+	Orders int
+	// DiscountTotal is the discount granted on those orders.
+	DiscountTotal decimal.Decimal
+}
+
+// ListFilter narrows and paginates coupon listings. Coupons are returned
+// ordered by code.
+type ListFilter struct {
+	// Active, when set, matches only active or only inactive coupons.
+	Active *bool
+	// DiscountType, when set, matches coupons of that type.
+	DiscountType DiscountType
+	// ValidFrom and ValidUntil match coupons that are valid at some point
+	// in [ValidFrom, ValidUntil). Either bound may be nil.
+	ValidFrom  *time.Time
+	ValidUntil *time.Time
+	// After resumes the listing strictly after the given code.
+	After string
+	// Limit is the maximum number of coupons to return.
+	Limit int
+}
+
+// EncodeCursor returns the opaque cursor for a listing that resumes after
+// code.
+func EncodeCursor(code string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(code))
+}
+
+// DecodeCursor returns the code a cursor produced by EncodeCursor resumes
+// after. It returns ErrInvalidCursor when s is malformed.
+func DecodeCursor(s string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(raw) == 0 {
+		return "", ErrInvalidCursor
+	}
+	return string(raw), nil
 }
 
 // Discount holds the computed discount amount and a human-readable description.
 type Discount struct {
 	Amount      decimal.Decimal
 	Description string
+	// Coupons breaks Amount down by coupon, in the order they were applied.
+	Coupons []Applied
+}
+
+// Applied is one coupon's part of a combined discount.
+type Applied struct {
+	// Code is the coupon's canonical code, as stored.
+	Code        string
+	Description string
+	Amount      decimal.Decimal
+	// Items are the positions, in the items the discount was computed
+	// for, of those the coupon targets. Nil when it applies to the whole
+	// order.
+	Items []int
 }
 
 // Item represents a line item in the cart for discount calculation purposes.
 type Item struct {
 	ProductID string
-	Price     decimal.Decimal
-	Quantity  int
+	// Category is the product's category, matched against the rule's
+	// targeting.
+	Category string
+	Price    decimal.Decimal
+	Quantity int
 }
 
 // Repository provides lookup and mutation of coupon rules.
 type Repository interface {
 	FindByCode(ctx context.Context, code string) (*Rule, error)
+	// IncrementUses consumes one use of the coupon. Implementations must
+	// apply the increment only while the usage limit has not been reached
+	// and return ErrCouponUsageLimitReached otherwise.
 	IncrementUses(ctx context.Context, code string) error
+	// DecrementUses gives back one use of the coupon, matching code
+	// case-insensitively. It never drops the counter below zero and is a
+	// no-op for unknown codes.
+	DecrementUses(ctx context.Context, code string) error
+	// CountRedemptions returns how many uses of the coupon with the exact
+	// code are recorded for customer.
+	CountRedemptions(ctx context.Context, code, customer string) (int, error)
+	// RecordRedemption adds r to the redemption ledger.
+	RecordRedemption(ctx context.Context, r Redemption) error
+	// DeleteRedemption removes the ledger entry of the order for the
+	// coupon, matching code case-insensitively. It is a no-op if there is
+	// none.
+	DeleteRedemption(ctx context.Context, code, orderID string) error
+
+	// Get returns the coupon with the given code, active or not, matching
+	// code case-insensitively. It returns ErrNotFound if there is none.
+	Get(ctx context.Context, code string) (*Coupon, error)
+	// List returns coupons matching f, ordered by code.
+	List(ctx context.Context, f ListFilter) ([]Coupon, error)
+	// Create stores a new active coupon with no uses. It returns ErrExists
+	// if the code is taken, compared case-insensitively.
+	Create(ctx context.Context, rule *Rule) error
+	// Update replaces the rule of the coupon with rule.Code, keeping its
+	// uses and active flag. It returns ErrNotFound if there is none.
+	Update(ctx context.Context, rule *Rule) error
+	// Deactivate stops the coupon from being redeemed. It returns
+	// ErrNotFound if there is none.
+	Deactivate(ctx context.Context, code string) error
 }
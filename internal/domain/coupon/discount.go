@@ -1,6 +1,9 @@
 package coupon
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/go-faster/errors"
 	"github.com/shopspring/decimal"
 )
@@ -10,30 +13,46 @@ var (
 	zero    = decimal.Zero
 )
 
-// Apply calculates the discount for the given rule and cart items.
-// It returns ErrInvalidCoupon when the cart does not satisfy the rule's
-// minimum item count requirement. When MaxDiscount > 0, the computed
-// discount is clamped to that ceiling.
+// Apply calculates the discount for the given rule and cart items. Only
+// the items the rule targets are considered: they make up the subtotal,
+// count towards the minimum and compete for free_lowest. It returns
+// ErrInvalidCoupon when a targeted rule matches none of the items or the
+// cart does not satisfy the rule's minimum item count requirement. When
+// MaxDiscount > 0, the computed discount is clamped to that ceiling.
 func Apply(rule *Rule, items []Item) (Discount, error) {
-	totalQty := totalQuantity(items)
+	return apply(rule, items, calcSubtotal(items))
+}
+
+// apply is Apply for a rule that only gets base, what earlier coupons in a
+// stack left of the subtotal. Earlier discounts are taken to be spread over
+// the cart pro rata, so the targeted items keep their share of base. The
+// discount never exceeds that share.
+func apply(rule *Rule, items []Item, base decimal.Decimal) (Discount, error) {
+	eligible := rule.eligible(items)
+	if rule.targeted() && len(eligible) == 0 {
+		return Discount{}, ErrInvalidCoupon
+	}
+
+	totalQty := totalQuantity(eligible)
 	if rule.MinItems > 0 && totalQty < rule.MinItems {
 		return Discount{}, ErrInvalidCoupon
 	}
 
-	subtotal := calcSubtotal(items)
+	base = share(base, calcSubtotal(eligible), calcSubtotal(items))
 
 	var d Discount
 
 	switch rule.DiscountType {
 	case DiscountPercentage:
-		d = applyPercentage(rule, subtotal)
+		d = applyPercentage(rule, base)
 	case DiscountFixed:
-		d = applyFixed(rule, subtotal)
+		d = applyFixed(rule, base)
 	case DiscountFreeLowest:
-		d = applyFreeLowest(rule, items)
+		d = applyFreeLowest(rule, eligible)
 	default:
 		return Discount{}, errors.Errorf("unsupported discount type: %q", rule.DiscountType)
 	}
+	d.Amount = decimal.Min(d.Amount, floorAtZero(base).Round(2))
 
 	// Clamp to MaxDiscount when set.
 	if rule.MaxDiscount.IsPositive() && d.Amount.GreaterThan(rule.MaxDiscount) {
@@ -72,6 +91,67 @@ func applyFreeLowest(rule *Rule, items []Item) Discount {
 	}
 }
 
+// targeted reports whether the rule is limited to some of the cart's items.
+func (r *Rule) targeted() bool {
+	return len(r.IncludeProducts) > 0 || len(r.IncludeCategories) > 0 ||
+		len(r.ExcludeProducts) > 0 || len(r.ExcludeCategories) > 0
+}
+
+// eligible returns the items the rule may discount.
+func (r *Rule) eligible(items []Item) []Item {
+	if !r.targeted() {
+		return items
+	}
+	out := make([]Item, 0, len(items))
+	for _, item := range items {
+		if r.targets(item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// targetedPositions returns the positions in items of those the rule may
+// discount, or nil when the rule is not targeted.
+func (r *Rule) targetedPositions(items []Item) []int {
+	if !r.targeted() {
+		return nil
+	}
+	out := make([]int, 0, len(items))
+	for i, item := range items {
+		if r.targets(item) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// targets reports whether the rule may discount item. Exclusions win over
+// inclusions; without include lists every item not excluded is targeted.
+func (r *Rule) targets(item Item) bool {
+	if slices.Contains(r.ExcludeProducts, item.ProductID) || containsFold(r.ExcludeCategories, item.Category) {
+		return false
+	}
+	if len(r.IncludeProducts) == 0 && len(r.IncludeCategories) == 0 {
+		return true
+	}
+	return slices.Contains(r.IncludeProducts, item.ProductID) || containsFold(r.IncludeCategories, item.Category)
+}
+
+// containsFold reports whether list holds s, ignoring case.
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+// share returns part's pro rata share of base, where base is what is left
+// of whole.
+func share(base, part, whole decimal.Decimal) decimal.Decimal {
+	if part.Equal(whole) || whole.IsZero() {
+		return base
+	}
+	return base.Mul(part).Div(whole)
+}
+
 // calcSubtotal returns the sum of price * quantity across all items.
 func calcSubtotal(items []Item) decimal.Decimal {
 	sum := zero
@@ -304,3 +304,116 @@ func TestApply(t *testing.T) {
 		})
 	}
 }
+
+func TestApply_Targeting(t *testing.T) {
+	// Subtotal 45: waffles 20, desserts 25.
+	cart := []Item{
+		{ProductID: "w1", Category: "Waffle", Price: d("10"), Quantity: 2},
+		{ProductID: "d1", Category: "Dessert", Price: d("8"), Quantity: 1},
+		{ProductID: "d2", Category: "Dessert", Price: d("17"), Quantity: 1},
+	}
+
+	tests := []struct {
+		name       string
+		rule       *Rule
+		wantAmount decimal.Decimal
+		wantErr    error
+	}{
+		{
+			name: "percentage of an included category",
+			rule: &Rule{
+				DiscountType:      DiscountPercentage,
+				Value:             d("20"),
+				IncludeCategories: []string{"Waffle"},
+			},
+			wantAmount: d("4"),
+		},
+		{
+			name: "categories match ignoring case",
+			rule: &Rule{
+				DiscountType:      DiscountPercentage,
+				Value:             d("20"),
+				IncludeCategories: []string{"waffle"},
+			},
+			wantAmount: d("4"),
+		},
+		{
+			name: "free lowest of an included category",
+			rule: &Rule{
+				DiscountType:      DiscountFreeLowest,
+				IncludeCategories: []string{"Dessert"},
+			},
+			wantAmount: d("8"),
+		},
+		{
+			name: "included products and categories combine",
+			rule: &Rule{
+				DiscountType:      DiscountPercentage,
+				Value:             d("10"),
+				IncludeProducts:   []string{"d2"},
+				IncludeCategories: []string{"Waffle"},
+			},
+			wantAmount: d("3.7"),
+		},
+		{
+			name: "excluded product",
+			rule: &Rule{
+				DiscountType:    DiscountPercentage,
+				Value:           d("10"),
+				ExcludeProducts: []string{"d2"},
+			},
+			wantAmount: d("2.8"),
+		},
+		{
+			name: "exclusion wins over inclusion",
+			rule: &Rule{
+				DiscountType:      DiscountFreeLowest,
+				IncludeCategories: []string{"Dessert"},
+				ExcludeProducts:   []string{"d1"},
+			},
+			wantAmount: d("17"),
+		},
+		{
+			name: "fixed capped at the targeted subtotal",
+			rule: &Rule{
+				DiscountType:    DiscountFixed,
+				Value:           d("30"),
+				IncludeProducts: []string{"d1"},
+			},
+			wantAmount: d("8"),
+		},
+		{
+			name: "nothing targeted",
+			rule: &Rule{
+				DiscountType:      DiscountPercentage,
+				Value:             d("20"),
+				IncludeCategories: []string{"Macaron"},
+			},
+			wantErr: ErrInvalidCoupon,
+		},
+		{
+			name: "minimum counts targeted items only",
+			rule: &Rule{
+				DiscountType:      DiscountFreeLowest,
+				MinItems:          3,
+				IncludeCategories: []string{"Dessert"},
+			},
+			wantErr: ErrInvalidCoupon,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(tt.rule, cart)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, tt.wantAmount.Equal(got.Amount),
+				"expected amount %s, got %s", tt.wantAmount, got.Amount)
+		})
+	}
+}
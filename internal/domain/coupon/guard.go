@@ -0,0 +1,146 @@
+package coupon
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// GuardConfig tunes how Guard slows down callers that present invalid codes.
+type GuardConfig struct {
+	// FreeAttempts is how many distinct invalid codes a caller may present
+	// before it has to wait between attempts.
+	FreeAttempts int
+	// Backoff is the wait after the first invalid code past FreeAttempts.
+	// It doubles with each further one, up to Lockout.
+	Backoff time.Duration
+	// MaxAttempts is the number of distinct invalid codes after which the
+	// caller is locked out for Lockout. The count then starts over.
+	MaxAttempts int
+	Lockout     time.Duration
+	// Window is how long a caller's invalid codes are remembered after the
+	// last one.
+	Window time.Duration
+}
+
+// Guard tracks invalid coupon codes per subject (an API key or a client IP)
+// to make guessing codes slow. Presenting the same invalid code again does
+// not count, so a customer retrying a mistyped code is not penalized; an
+// enumeration has to try many distinct codes and soon backs off.
+type Guard struct {
+	cfg   GuardConfig
+	store AttemptStore
+	now   func() time.Time
+
+	failures  metric.Int64Counter
+	throttled metric.Int64Counter
+	lockouts  metric.Int64Counter
+}
+
+// NewGuard creates a Guard with the given limits, keeping attempts in store
+// and reporting to mp. A nil store means a new MemoryAttemptStore.
+func NewGuard(cfg GuardConfig, store AttemptStore, mp metric.MeterProvider) (*Guard, error) {
+	if cfg.MaxAttempts <= 0 {
+		return nil, errors.Errorf("max attempts %d must be positive", cfg.MaxAttempts)
+	}
+	if store == nil {
+		store = NewMemoryAttemptStore()
+	}
+	g := &Guard{
+		cfg:   cfg,
+		store: store,
+		now:   time.Now,
+	}
+
+	meter := mp.Meter("github.com/xenking/oolio-kart-challenge/internal/domain/coupon")
+	var err error
+	if g.failures, err = meter.Int64Counter("kart.coupon.invalid_attempts",
+		metric.WithDescription("Invalid coupon codes presented, by subject kind"),
+	); err != nil {
+		return nil, errors.Wrap(err, "create invalid attempts counter")
+	}
+	if g.throttled, err = meter.Int64Counter("kart.coupon.throttled",
+		metric.WithDescription("Coupon attempts refused while the caller was backing off or locked out"),
+	); err != nil {
+		return nil, errors.Wrap(err, "create throttled counter")
+	}
+	if g.lockouts, err = meter.Int64Counter("kart.coupon.lockouts",
+		metric.WithDescription("Callers locked out for presenting too many invalid coupon codes, by subject kind"),
+	); err != nil {
+		return nil, errors.Wrap(err, "create lockouts counter")
+	}
+	return g, nil
+}
+
+// Check returns how long the caller identified by subjects must wait before
+// presenting another code, or 0 if it may go ahead. The longest wait of any
+// subject applies.
+func (g *Guard) Check(ctx context.Context, subjects ...string) (time.Duration, error) {
+	now := g.now()
+	until, err := g.store.BlockedUntil(ctx, subjects)
+	if err != nil {
+		return 0, errors.Wrap(err, "read coupon attempts")
+	}
+	wait := until.Sub(now)
+	if wait <= 0 {
+		return 0, nil
+	}
+	g.throttled.Add(ctx, 1)
+	return wait, nil
+}
+
+// Fail charges the rejected code to each subject. It returns the subjects
+// that were locked out as a result.
+func (g *Guard) Fail(ctx context.Context, code string, subjects ...string) ([]string, error) {
+	now := g.now()
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	var locked []string
+	for _, s := range subjects {
+		n, err := g.store.Fail(ctx, s, code, now, now.Add(-g.cfg.Window))
+		if err != nil {
+			return locked, errors.Wrap(err, "record coupon attempt")
+		}
+		if n == 0 {
+			continue
+		}
+		kind := attribute.String("subject", subjectKind(s))
+		g.failures.Add(ctx, 1, metric.WithAttributes(kind))
+
+		switch {
+		case n >= g.cfg.MaxAttempts:
+			if err := g.store.Block(ctx, s, now.Add(g.cfg.Lockout), true); err != nil {
+				return locked, errors.Wrap(err, "lock out coupon subject")
+			}
+			g.lockouts.Add(ctx, 1, metric.WithAttributes(kind))
+			locked = append(locked, s)
+		case n > g.cfg.FreeAttempts:
+			backoff := g.cfg.Backoff << min(n-g.cfg.FreeAttempts-1, 30)
+			if backoff <= 0 || backoff > g.cfg.Lockout {
+				backoff = g.cfg.Lockout
+			}
+			if err := g.store.Block(ctx, s, now.Add(backoff), false); err != nil {
+				return locked, errors.Wrap(err, "back off coupon subject")
+			}
+		}
+	}
+	return locked, nil
+}
+
+// Prune forgets subjects that are neither blocked nor within Window of
+// their last invalid code, and returns how many were removed.
+func (g *Guard) Prune(ctx context.Context) (int64, error) {
+	now := g.now()
+	return g.store.Prune(ctx, now, now.Add(-g.cfg.Window))
+}
+
+// subjectKind returns the part of a subject before the colon, such as
+// "key" or "ip", for use as a low-cardinality metric attribute.
+func subjectKind(s string) string {
+	kind, _, _ := strings.Cut(s, ":")
+	return kind
+}
@@ -0,0 +1,159 @@
+package coupon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+func newTestGuard(t *testing.T, cfg GuardConfig) (*Guard, *time.Time) {
+	t.Helper()
+	g, err := NewGuard(cfg, nil, noop.NewMeterProvider())
+	require.NoError(t, err)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	g.now = func() time.Time { return now }
+	return g, &now
+}
+
+func mustCheck(t *testing.T, g *Guard, subjects ...string) time.Duration {
+	t.Helper()
+	wait, err := g.Check(context.Background(), subjects...)
+	require.NoError(t, err)
+	return wait
+}
+
+func mustFail(t *testing.T, g *Guard, code string, subjects ...string) []string {
+	t.Helper()
+	locked, err := g.Fail(context.Background(), code, subjects...)
+	require.NoError(t, err)
+	return locked
+}
+
+func TestGuard(t *testing.T) {
+	cfg := GuardConfig{
+		FreeAttempts: 2,
+		Backoff:      time.Second,
+		MaxAttempts:  5,
+		Lockout:      time.Minute,
+		Window:       time.Hour,
+	}
+
+	t.Run("free attempts then doubling backoff", func(t *testing.T) {
+		g, _ := newTestGuard(t, cfg)
+
+		mustFail(t, g, "AAAA", "key:k1")
+		mustFail(t, g, "BBBB", "key:k1")
+		assert.Zero(t, mustCheck(t, g, "key:k1"))
+
+		mustFail(t, g, "CCCC", "key:k1")
+		assert.Equal(t, time.Second, mustCheck(t, g, "key:k1"))
+		mustFail(t, g, "DDDD", "key:k1")
+		assert.Equal(t, 2*time.Second, mustCheck(t, g, "key:k1"))
+	})
+
+	t.Run("repeated code is not counted", func(t *testing.T) {
+		g, _ := newTestGuard(t, cfg)
+
+		for range 10 {
+			mustFail(t, g, "typo", "key:k1")
+		}
+		mustFail(t, g, " TYPO ", "key:k1")
+		assert.Zero(t, mustCheck(t, g, "key:k1"))
+	})
+
+	t.Run("lockout after max attempts", func(t *testing.T) {
+		g, now := newTestGuard(t, cfg)
+
+		var locked []string
+		for _, code := range []string{"A", "B", "C", "D", "E"} {
+			locked = mustFail(t, g, code, "key:k1", "ip:203.0.113.9")
+		}
+		assert.Equal(t, []string{"key:k1", "ip:203.0.113.9"}, locked)
+		assert.Equal(t, time.Minute, mustCheck(t, g, "ip:203.0.113.9"))
+
+		// Another key from the same address is held back too.
+		assert.Equal(t, time.Minute, mustCheck(t, g, "key:k2", "ip:203.0.113.9"))
+		assert.Zero(t, mustCheck(t, g, "key:k2", "ip:198.51.100.1"))
+
+		*now = now.Add(time.Minute)
+		assert.Zero(t, mustCheck(t, g, "key:k1"))
+
+		// The count started over after the lockout.
+		mustFail(t, g, "F", "key:k1")
+		assert.Zero(t, mustCheck(t, g, "key:k1"))
+	})
+
+	t.Run("codes are forgotten after window", func(t *testing.T) {
+		g, now := newTestGuard(t, cfg)
+
+		mustFail(t, g, "A", "key:k1")
+		mustFail(t, g, "B", "key:k1")
+		*now = now.Add(time.Hour)
+		mustFail(t, g, "C", "key:k1")
+		assert.Zero(t, mustCheck(t, g, "key:k1"))
+	})
+}
+
+func TestGuard_Prune(t *testing.T) {
+	ctx := context.Background()
+	g, now := newTestGuard(t, GuardConfig{
+		MaxAttempts: 1,
+		Lockout:     2 * time.Hour,
+		Window:      time.Hour,
+	})
+
+	mustFail(t, g, "A", "key:a")
+	mustFail(t, g, "A", "key:b")
+	mustFail(t, g, "A", "ip:recent")
+	*now = now.Add(90 * time.Minute)
+	mustFail(t, g, "B", "ip:recent")
+
+	// Both keys are idle, but they stay until their lockout ends.
+	n, err := g.Prune(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	*now = now.Add(30 * time.Minute)
+	n, err = g.Prune(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.Contains(t, g.store.(*MemoryAttemptStore).subjects, "ip:recent")
+}
+
+type failingAttemptStore struct {
+	AttemptStore
+	err error
+}
+
+func (s failingAttemptStore) Fail(context.Context, string, string, time.Time, time.Time) (int, error) {
+	return 0, s.err
+}
+
+func (s failingAttemptStore) BlockedUntil(context.Context, []string) (time.Time, error) {
+	return time.Time{}, s.err
+}
+
+func TestGuard_StoreErrors(t *testing.T) {
+	ctx := context.Background()
+	dbErr := errors.New("connection refused")
+	g, err := NewGuard(GuardConfig{MaxAttempts: 1}, failingAttemptStore{err: dbErr}, noop.NewMeterProvider())
+	require.NoError(t, err)
+
+	wait, err := g.Check(ctx, "key:k1")
+	require.ErrorIs(t, err, dbErr)
+	assert.Zero(t, wait)
+
+	locked, err := g.Fail(ctx, "A", "key:k1")
+	require.ErrorIs(t, err, dbErr)
+	assert.Empty(t, locked)
+}
+
+func TestNewGuard_RequiresMaxAttempts(t *testing.T) {
+	_, err := NewGuard(GuardConfig{}, nil, noop.NewMeterProvider())
+	assert.Error(t, err)
+}
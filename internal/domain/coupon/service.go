@@ -0,0 +1,258 @@
+package coupon
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Sentinel errors for coupon listings.
+var (
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidDateRange = errors.New("validFrom must be before validUntil")
+)
+
+// Page size bounds for List.
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+)
+
+// maxCodeLength bounds the codes administrators can create. Ingested codes
+// are 8-10 characters.
+const maxCodeLength = 32
+
+// maxTargets bounds each of a rule's include and exclude lists.
+const maxTargets = 100
+
+// maxAmount is the first amount the coupons table's NUMERIC(10,2) columns
+// cannot hold.
+var maxAmount = decimal.New(1, 8)
+
+// RuleError indicates a coupon rule that makes no sense, such as a
+// percentage above 100. Field is the name of the offending API field.
+type RuleError struct {
+	Field  string
+	Reason string
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Reason)
+}
+
+// ListRequest is a page request for List.
+type ListRequest struct {
+	Active       *bool
+	DiscountType DiscountType
+	ValidFrom    *time.Time
+	ValidUntil   *time.Time
+	Cursor       string
+	Limit        int
+}
+
+// ListResult is a page of coupons and the cursor of the next page, which is
+// empty on the last one.
+type ListResult struct {
+	Coupons    []Coupon
+	NextCursor string
+}
+
+// Service lets administrators manage coupons.
+type Service struct {
+	repo Repository
+}
+
+// NewService creates a Service backed by repo.
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Get returns the coupon with the given code, active or not.
+func (s *Service) Get(ctx context.Context, code string) (*Coupon, error) {
+	c, err := s.repo.Get(ctx, code)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, errors.Wrap(err, "get coupon")
+	}
+	return c, nil
+}
+
+// List returns a page of coupons ordered by code.
+func (s *Service) List(ctx context.Context, req ListRequest) (*ListResult, error) {
+	if req.ValidFrom != nil && req.ValidUntil != nil && !req.ValidFrom.Before(*req.ValidUntil) {
+		return nil, ErrInvalidDateRange
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	limit = min(limit, MaxListLimit)
+
+	filter := ListFilter{
+		Active:       req.Active,
+		DiscountType: req.DiscountType,
+		ValidFrom:    req.ValidFrom,
+		ValidUntil:   req.ValidUntil,
+		// Fetch one extra row to learn whether another page exists.
+		Limit: limit + 1,
+	}
+	if req.Cursor != "" {
+		after, err := DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+
+	coupons, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "list coupons")
+	}
+
+	var next string
+	if len(coupons) > limit {
+		coupons = coupons[:limit]
+		next = EncodeCursor(coupons[limit-1].Code)
+	}
+	return &ListResult{Coupons: coupons, NextCursor: next}, nil
+}
+
+// Create validates rule and stores it as a new active coupon. The code is
+// stored upper-cased; lookups ignore case anyway.
+func (s *Service) Create(ctx context.Context, rule Rule) (*Coupon, error) {
+	rule.Code = strings.ToUpper(strings.TrimSpace(rule.Code))
+	if err := validateCode(rule.Code); err != nil {
+		return nil, err
+	}
+	if err := ValidateRule(&rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, &rule); err != nil {
+		if errors.Is(err, ErrExists) {
+			return nil, ErrExists
+		}
+		return nil, errors.Wrap(err, "create coupon")
+	}
+	return s.Get(ctx, rule.Code)
+}
+
+// Update validates rule and replaces the rule of the coupon with
+// rule.Code. Uses so far and the active flag are kept.
+func (s *Service) Update(ctx context.Context, rule Rule) (*Coupon, error) {
+	if err := ValidateRule(&rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, &rule); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, errors.Wrap(err, "update coupon")
+	}
+	return s.Get(ctx, rule.Code)
+}
+
+// Deactivate stops the coupon from being redeemed. Orders that already used
+// it are unaffected. Deactivating an inactive coupon succeeds.
+func (s *Service) Deactivate(ctx context.Context, code string) (*Coupon, error) {
+	if err := s.repo.Deactivate(ctx, code); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, errors.Wrap(err, "deactivate coupon")
+	}
+	return s.Get(ctx, code)
+}
+
+// validateCode checks a new coupon code: letters and digits only, so codes
+// are easy to type and can't be confused with whitespace variants.
+func validateCode(code string) error {
+	if code == "" {
+		return &RuleError{Field: "code", Reason: "is required"}
+	}
+	if len(code) > maxCodeLength {
+		return &RuleError{Field: "code", Reason: fmt.Sprintf("must be at most %d characters", maxCodeLength)}
+	}
+	for _, c := range code {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return &RuleError{Field: "code", Reason: "must contain only letters and digits"}
+		}
+	}
+	return nil
+}
+
+// ValidateRule returns a *RuleError if rule could never be applied
+// sensibly. The code is not checked.
+func ValidateRule(rule *Rule) error {
+	switch rule.DiscountType {
+	case DiscountPercentage:
+		if !rule.Value.IsPositive() || rule.Value.GreaterThan(hundred) {
+			return &RuleError{Field: "value", Reason: "must be greater than 0 and at most 100 for percentage discounts"}
+		}
+	case DiscountFixed:
+		if !rule.Value.IsPositive() {
+			return &RuleError{Field: "value", Reason: "must be greater than 0 for fixed discounts"}
+		}
+	case DiscountFreeLowest:
+		if !rule.Value.IsZero() {
+			return &RuleError{Field: "value", Reason: "must be 0 for free_lowest discounts"}
+		}
+	default:
+		return &RuleError{Field: "discountType", Reason: fmt.Sprintf("%q is not supported", rule.DiscountType)}
+	}
+	if !rule.Value.Equal(rule.Value.Round(2)) {
+		return &RuleError{Field: "value", Reason: "must have at most 2 decimal places"}
+	}
+	if rule.Value.GreaterThanOrEqual(maxAmount) {
+		return &RuleError{Field: "value", Reason: "must be less than " + maxAmount.String()}
+	}
+
+	switch {
+	case rule.MinItems < 0:
+		return &RuleError{Field: "minItems", Reason: "must not be negative"}
+	case rule.MaxUses < 0:
+		return &RuleError{Field: "maxUses", Reason: "must not be negative"}
+	case rule.MaxUsesPerCustomer < 0:
+		return &RuleError{Field: "maxUsesPerCustomer", Reason: "must not be negative"}
+	case rule.Priority < math.MinInt32 || rule.Priority > math.MaxInt32:
+		return &RuleError{Field: "priority", Reason: "must fit in a 32-bit integer"}
+	case rule.MaxDiscount.IsNegative():
+		return &RuleError{Field: "maxDiscount", Reason: "must not be negative"}
+	case !rule.MaxDiscount.Equal(rule.MaxDiscount.Round(2)):
+		return &RuleError{Field: "maxDiscount", Reason: "must have at most 2 decimal places"}
+	case rule.MaxDiscount.GreaterThanOrEqual(maxAmount):
+		return &RuleError{Field: "maxDiscount", Reason: "must be less than " + maxAmount.String()}
+	case rule.ValidFrom != nil && rule.ValidUntil != nil && !rule.ValidUntil.After(*rule.ValidFrom):
+		return &RuleError{Field: "validUntil", Reason: "must be after validFrom"}
+	}
+
+	targets := []struct {
+		field  string
+		values []string
+	}{
+		{"includeProducts", rule.IncludeProducts},
+		{"includeCategories", rule.IncludeCategories},
+		{"excludeProducts", rule.ExcludeProducts},
+		{"excludeCategories", rule.ExcludeCategories},
+	}
+	for _, t := range targets {
+		if len(t.values) > maxTargets {
+			return &RuleError{Field: t.field, Reason: fmt.Sprintf("must have at most %d entries", maxTargets)}
+		}
+		for _, v := range t.values {
+			if strings.TrimSpace(v) == "" {
+				return &RuleError{Field: t.field, Reason: "must not contain blank entries"}
+			}
+		}
+	}
+	return nil
+}
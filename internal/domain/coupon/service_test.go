@@ -0,0 +1,201 @@
+package coupon
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRule(t *testing.T) {
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	until := from.Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		rule      Rule
+		wantField string
+	}{
+		{
+			name: "valid percentage",
+			rule: Rule{DiscountType: DiscountPercentage, Value: decimal.NewFromInt(100)},
+		},
+		{
+			name: "valid free lowest",
+			rule: Rule{DiscountType: DiscountFreeLowest, MinItems: 3, MaxDiscount: decimal.RequireFromString("12.50")},
+		},
+		{
+			name:      "percentage above 100",
+			rule:      Rule{DiscountType: DiscountPercentage, Value: decimal.NewFromInt(101)},
+			wantField: "value",
+		},
+		{
+			name:      "zero fixed amount",
+			rule:      Rule{DiscountType: DiscountFixed},
+			wantField: "value",
+		},
+		{
+			name:      "free lowest with a value",
+			rule:      Rule{DiscountType: DiscountFreeLowest, Value: decimal.NewFromInt(5)},
+			wantField: "value",
+		},
+		{
+			name:      "fractional cents",
+			rule:      Rule{DiscountType: DiscountFixed, Value: decimal.RequireFromString("1.005")},
+			wantField: "value",
+		},
+		{
+			name:      "unknown type",
+			rule:      Rule{DiscountType: "bogo", Value: decimal.NewFromInt(1)},
+			wantField: "discountType",
+		},
+		{
+			name:      "negative max discount",
+			rule:      Rule{DiscountType: DiscountFixed, Value: decimal.NewFromInt(5), MaxDiscount: decimal.NewFromInt(-1)},
+			wantField: "maxDiscount",
+		},
+		{
+			name:      "negative max uses",
+			rule:      Rule{DiscountType: DiscountFixed, Value: decimal.NewFromInt(5), MaxUses: -1},
+			wantField: "maxUses",
+		},
+		{
+			name: "valid targeting",
+			rule: Rule{
+				DiscountType:      DiscountPercentage,
+				Value:             decimal.NewFromInt(20),
+				IncludeCategories: []string{"Waffle"},
+				ExcludeProducts:   []string{"3"},
+			},
+		},
+		{
+			name:      "blank target",
+			rule:      Rule{DiscountType: DiscountFixed, Value: decimal.NewFromInt(5), IncludeCategories: []string{" "}},
+			wantField: "includeCategories",
+		},
+		{
+			name:      "too many targets",
+			rule:      Rule{DiscountType: DiscountFixed, Value: decimal.NewFromInt(5), ExcludeProducts: make([]string, 101)},
+			wantField: "excludeProducts",
+		},
+		{
+			name:      "priority out of range",
+			rule:      Rule{DiscountType: DiscountFixed, Value: decimal.NewFromInt(5), Priority: math.MaxInt32 + 1},
+			wantField: "priority",
+		},
+		{
+			name:      "valid until before valid from",
+			rule:      Rule{DiscountType: DiscountFixed, Value: decimal.NewFromInt(5), ValidFrom: &from, ValidUntil: &until},
+			wantField: "validUntil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRule(&tt.rule)
+			if tt.wantField == "" {
+				require.NoError(t, err)
+				return
+			}
+			var ruleErr *RuleError
+			require.ErrorAs(t, err, &ruleErr)
+			assert.Equal(t, tt.wantField, ruleErr.Field)
+		})
+	}
+}
+
+func TestService_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores the code upper-cased", func(t *testing.T) {
+		repo := &mockCouponRepo{coupon: &Coupon{Active: true}}
+		svc := NewService(repo)
+
+		_, err := svc.Create(ctx, Rule{Code: " spring18 ", DiscountType: DiscountPercentage, Value: decimal.NewFromInt(18)})
+		require.NoError(t, err)
+		require.NotNil(t, repo.saved)
+		assert.Equal(t, "SPRING18", repo.saved.Code)
+	})
+
+	t.Run("rejects codes with other characters", func(t *testing.T) {
+		repo := &mockCouponRepo{}
+		svc := NewService(repo)
+
+		_, err := svc.Create(ctx, Rule{Code: "SPRING-18", DiscountType: DiscountPercentage, Value: decimal.NewFromInt(18)})
+		var ruleErr *RuleError
+		require.ErrorAs(t, err, &ruleErr)
+		assert.Equal(t, "code", ruleErr.Field)
+		assert.Nil(t, repo.saved)
+	})
+
+	t.Run("rejects invalid rules before storing", func(t *testing.T) {
+		repo := &mockCouponRepo{}
+		svc := NewService(repo)
+
+		_, err := svc.Create(ctx, Rule{Code: "SPRING18", DiscountType: DiscountPercentage, Value: decimal.NewFromInt(180)})
+		var ruleErr *RuleError
+		require.ErrorAs(t, err, &ruleErr)
+		assert.Nil(t, repo.saved)
+	})
+
+	t.Run("existing code", func(t *testing.T) {
+		svc := NewService(&mockCouponRepo{err: ErrExists})
+
+		_, err := svc.Create(ctx, Rule{Code: "SPRING18", DiscountType: DiscountFixed, Value: decimal.NewFromInt(5)})
+		require.ErrorIs(t, err, ErrExists)
+	})
+}
+
+func TestService_UpdateAndDeactivate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("update unknown coupon", func(t *testing.T) {
+		svc := NewService(&mockCouponRepo{err: ErrNotFound})
+
+		_, err := svc.Update(ctx, Rule{Code: "NOPE", DiscountType: DiscountFixed, Value: decimal.NewFromInt(5)})
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("deactivate returns the coupon", func(t *testing.T) {
+		repo := &mockCouponRepo{coupon: &Coupon{Rule: Rule{Code: "SPRING18"}}}
+		svc := NewService(repo)
+
+		c, err := svc.Deactivate(ctx, "spring18")
+		require.NoError(t, err)
+		assert.Equal(t, "spring18", repo.deactivated)
+		assert.Equal(t, "SPRING18", c.Code)
+	})
+}
+
+func TestService_List(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockCouponRepo{coupons: []Coupon{
+		{Rule: Rule{Code: "A"}}, {Rule: Rule{Code: "B"}}, {Rule: Rule{Code: "C"}},
+	}}
+	svc := NewService(repo)
+
+	active := true
+	page, err := svc.List(ctx, ListRequest{Active: &active, DiscountType: DiscountFixed, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Coupons, 2)
+	assert.Equal(t, 3, repo.listFilter.Limit)
+	assert.Equal(t, DiscountFixed, repo.listFilter.DiscountType)
+	assert.Equal(t, &active, repo.listFilter.Active)
+
+	repo.coupons = repo.coupons[2:]
+	page, err = svc.List(ctx, ListRequest{Cursor: page.NextCursor, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "B", repo.listFilter.After)
+	assert.Empty(t, page.NextCursor)
+
+	_, err = svc.List(ctx, ListRequest{Cursor: "!!"})
+	require.ErrorIs(t, err, ErrInvalidCursor)
+
+	at := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	_, err = svc.List(ctx, ListRequest{ValidFrom: &at, ValidUntil: &at})
+	require.ErrorIs(t, err, ErrInvalidDateRange)
+}
@@ -0,0 +1,101 @@
+package coupon
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/go-faster/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Combine applies rules to items as one stack and returns the total
+// discount with its breakdown. The coupons are applied one after another,
+// each to what the previous ones left of the subtotal:
+//
+//   - item-level coupons (free_lowest) before order-level ones,
+//   - percentage before fixed among order-level coupons,
+//   - higher Priority first among coupons of the same kind, then by code.
+//
+// Several rules can only be combined if all of them are Stackable. Each
+// rule's MaxDiscount caps its own part, and the smallest MaxDiscount in the
+// stack also caps the total, so stacking never grants more than one of its
+// coupons allows. Rejections are returned as *CodeError.
+func Combine(rules []*Rule, items []Item) (Discount, error) {
+	if len(rules) > 1 {
+		for _, r := range rules {
+			if !r.Stackable {
+				return Discount{}, &CodeError{Code: r.Code, Err: ErrCouponNotStackable}
+			}
+		}
+	}
+
+	ordered := slices.Clone(rules)
+	slices.SortFunc(ordered, compareApplication)
+
+	var limit decimal.Decimal
+	for _, r := range ordered {
+		if r.MaxDiscount.IsPositive() && (limit.IsZero() || r.MaxDiscount.LessThan(limit)) {
+			limit = r.MaxDiscount.Round(2)
+		}
+	}
+
+	var (
+		remaining    = calcSubtotal(items)
+		total        = zero
+		applied      = make([]Applied, 0, len(ordered))
+		descriptions = make([]string, 0, len(ordered))
+	)
+	for _, r := range ordered {
+		d, err := apply(r, items, remaining)
+		if err != nil {
+			if errors.Is(err, ErrInvalidCoupon) {
+				return Discount{}, &CodeError{Code: r.Code, Err: err}
+			}
+			return Discount{}, err
+		}
+		if limit.IsPositive() {
+			d.Amount = decimal.Min(d.Amount, limit.Sub(total))
+		}
+
+		remaining = remaining.Sub(d.Amount)
+		total = total.Add(d.Amount)
+		applied = append(applied, Applied{
+			Code:        r.Code,
+			Description: d.Description,
+			Amount:      d.Amount,
+			Items:       r.targetedPositions(items),
+		})
+		if d.Description != "" {
+			descriptions = append(descriptions, d.Description)
+		}
+	}
+
+	return Discount{
+		Amount:      total,
+		Description: strings.Join(descriptions, "; "),
+		Coupons:     applied,
+	}, nil
+}
+
+// compareApplication orders rules the way Combine applies them.
+func compareApplication(a, b *Rule) int {
+	return cmp.Or(
+		cmp.Compare(stage(a.DiscountType), stage(b.DiscountType)),
+		cmp.Compare(b.Priority, a.Priority),
+		strings.Compare(a.Code, b.Code),
+	)
+}
+
+// stage ranks discount types by when they apply in a stack: discounts on
+// single items first, then percentages of the order, then fixed amounts.
+func stage(t DiscountType) int {
+	switch t {
+	case DiscountFreeLowest:
+		return 0
+	case DiscountPercentage:
+		return 1
+	default:
+		return 2
+	}
+}
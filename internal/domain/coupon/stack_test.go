@@ -0,0 +1,211 @@
+package coupon
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCombine(t *testing.T) {
+	percentage := func(code, value string, priority int) *Rule {
+		return &Rule{
+			Code:         code,
+			DiscountType: DiscountPercentage,
+			Value:        d(value),
+			Description:  value + "% off",
+			Stackable:    true,
+			Priority:     priority,
+		}
+	}
+	fixed := func(code, value string) *Rule {
+		return &Rule{
+			Code:         code,
+			DiscountType: DiscountFixed,
+			Value:        d(value),
+			Description:  "$" + value + " off",
+			Stackable:    true,
+		}
+	}
+	freeLowest := &Rule{
+		Code:         "FREE",
+		DiscountType: DiscountFreeLowest,
+		Description:  "cheapest item free",
+		Stackable:    true,
+	}
+	// Subtotal 50, cheapest item 10.
+	cart := []Item{
+		{ProductID: "p1", Price: d("10"), Quantity: 2},
+		{ProductID: "p2", Price: d("30"), Quantity: 1},
+	}
+
+	tests := []struct {
+		name       string
+		rules      []*Rule
+		items      []Item
+		wantAmount decimal.Decimal
+		wantDesc   string
+		// wantCoupons is the breakdown in application order.
+		wantCoupons []Applied
+		wantErr     error
+		wantCode    string
+	}{
+		{
+			name:       "single coupon",
+			rules:      []*Rule{percentage("TEN", "10", 0)},
+			items:      cart,
+			wantAmount: d("5"),
+			wantDesc:   "10% off",
+			wantCoupons: []Applied{
+				{Code: "TEN", Amount: d("5")},
+			},
+		},
+		{
+			name:       "item-level, then percentage, then fixed",
+			rules:      []*Rule{fixed("FIVE", "5"), percentage("TEN", "10", 0), freeLowest},
+			items:      cart,
+			wantAmount: d("19"),
+			wantDesc:   "cheapest item free; 10% off; $5 off",
+			wantCoupons: []Applied{
+				{Code: "FREE", Amount: d("10")},
+				{Code: "TEN", Amount: d("4")},
+				{Code: "FIVE", Amount: d("5")},
+			},
+		},
+		{
+			name:       "higher priority first",
+			rules:      []*Rule{percentage("A", "10", 0), percentage("B", "20", 5)},
+			items:      []Item{{ProductID: "p1", Price: d("100"), Quantity: 1}},
+			wantAmount: d("28"),
+			wantDesc:   "20% off; 10% off",
+			wantCoupons: []Applied{
+				{Code: "B", Amount: d("20")},
+				{Code: "A", Amount: d("8")},
+			},
+		},
+		{
+			name:       "equal priority by code",
+			rules:      []*Rule{percentage("B", "20", 0), percentage("A", "10", 0)},
+			items:      []Item{{ProductID: "p1", Price: d("100"), Quantity: 1}},
+			wantAmount: d("28"),
+			wantDesc:   "10% off; 20% off",
+			wantCoupons: []Applied{
+				{Code: "A", Amount: d("10")},
+				{Code: "B", Amount: d("18")},
+			},
+		},
+		{
+			name: "smallest max discount caps the stack",
+			rules: []*Rule{
+				fixed("TWENTY", "20"),
+				func() *Rule {
+					r := percentage("HALF", "50", 0)
+					r.MaxDiscount = d("30")
+					return r
+				}(),
+			},
+			items:      []Item{{ProductID: "p1", Price: d("100"), Quantity: 1}},
+			wantAmount: d("30"),
+			wantDesc:   "50% off; $20 off",
+			wantCoupons: []Applied{
+				{Code: "HALF", Amount: d("30")},
+				{Code: "TWENTY", Amount: d("0")},
+			},
+		},
+		{
+			name: "targeted coupon gets its items' share of what is left",
+			rules: []*Rule{
+				func() *Rule {
+					r := percentage("WAFFLES", "50", 0)
+					r.IncludeCategories = []string{"Waffle"}
+					return r
+				}(),
+				percentage("TEN", "10", 5),
+			},
+			items: []Item{
+				{ProductID: "w1", Category: "Waffle", Price: d("20"), Quantity: 1},
+				{ProductID: "d1", Category: "Dessert", Price: d("80"), Quantity: 1},
+			},
+			wantAmount: d("19"),
+			wantDesc:   "10% off; 50% off",
+			wantCoupons: []Applied{
+				{Code: "TEN", Amount: d("10")},
+				{Code: "WAFFLES", Amount: d("9")},
+			},
+		},
+		{
+			name:       "never more than the subtotal",
+			rules:      []*Rule{fixed("X", "30"), fixed("Y", "30")},
+			items:      []Item{{ProductID: "p1", Price: d("40"), Quantity: 1}},
+			wantAmount: d("40"),
+			wantDesc:   "$30 off; $30 off",
+			wantCoupons: []Applied{
+				{Code: "X", Amount: d("30")},
+				{Code: "Y", Amount: d("10")},
+			},
+		},
+		{
+			name: "non-stackable coupon alone",
+			rules: []*Rule{{
+				Code:         "SOLO",
+				DiscountType: DiscountFixed,
+				Value:        d("5"),
+				Description:  "$5 off",
+			}},
+			items:      cart,
+			wantAmount: d("5"),
+			wantDesc:   "$5 off",
+			wantCoupons: []Applied{
+				{Code: "SOLO", Amount: d("5")},
+			},
+		},
+		{
+			name: "non-stackable coupon in a stack",
+			rules: []*Rule{percentage("TEN", "10", 0), {
+				Code:         "SOLO",
+				DiscountType: DiscountFixed,
+				Value:        d("5"),
+			}},
+			items:    cart,
+			wantErr:  ErrCouponNotStackable,
+			wantCode: "SOLO",
+		},
+		{
+			name: "minimum items not met",
+			rules: []*Rule{percentage("TEN", "10", 0), func() *Rule {
+				r := fixed("BULK", "5")
+				r.MinItems = 10
+				return r
+			}()},
+			items:    cart,
+			wantErr:  ErrInvalidCoupon,
+			wantCode: "BULK",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Combine(tt.rules, tt.items)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				var codeErr *CodeError
+				require.ErrorAs(t, err, &codeErr)
+				assert.Equal(t, tt.wantCode, codeErr.Code)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, tt.wantAmount.Equal(got.Amount),
+				"expected amount %s, got %s", tt.wantAmount, got.Amount)
+			assert.Equal(t, tt.wantDesc, got.Description)
+			require.Len(t, got.Coupons, len(tt.wantCoupons))
+			for i, want := range tt.wantCoupons {
+				assert.Equal(t, want.Code, got.Coupons[i].Code)
+				assert.True(t, want.Amount.Equal(got.Coupons[i].Amount),
+					"coupon %s: expected amount %s, got %s", want.Code, want.Amount, got.Coupons[i].Amount)
+			}
+		})
+	}
+}
@@ -2,19 +2,26 @@ package coupon
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-faster/errors"
 )
 
-// Validator validates a coupon code against a set of cart items and returns
-// the computed discount.
+// Validator validates coupon codes against a set of cart items and returns
+// the computed discount. Validate redeems the coupons for a customer's
+// order; Preview runs the same checks without consuming a use. Release
+// undoes one redemption made by a successful Validate, e.g. when the order
+// is cancelled.
 type Validator interface {
-	Validate(ctx context.Context, code string, items []Item) (*Discount, error)
+	Validate(ctx context.Context, req Request, items []Item) (*Discount, error)
+	Preview(ctx context.Context, req Request, items []Item) (*Discount, error)
+	Release(ctx context.Context, code, orderID string) error
 }
 
 // RepoValidator implements Validator by looking up coupon rules from a
-// Repository and applying them via the Apply function.
+// Repository and applying them via the Combine function.
 type RepoValidator struct {
 	repo Repository
 	now  func() time.Time
@@ -25,10 +32,85 @@ func NewRepoValidator(repo Repository) *RepoValidator {
 	return &RepoValidator{repo: repo, now: time.Now}
 }
 
-// Validate looks up the coupon rule for the given code, checks temporal
-// validity and usage limits, applies it to the cart items, and increments
-// the usage counter on success.
-func (v *RepoValidator) Validate(ctx context.Context, code string, items []Item) (*Discount, error) {
+// Validate looks up the coupon rule for each of req.Codes, checks temporal
+// validity and usage limits, combines them over the cart items, and on
+// success increments each usage counter and records each redemption in the
+// ledger. The increments are guarded by the repository, so callers that
+// need the redemption to be atomic with other writes should call Validate
+// inside a transaction.
+//
+// Inside a transaction each increment also locks the coupon row until
+// commit, so the per-customer count taken after it cannot miss a concurrent
+// redemption by the same customer. Coupons are redeemed in code order, so
+// concurrent orders stacking the same coupons lock them in the same order.
+func (v *RepoValidator) Validate(ctx context.Context, req Request, items []Item) (*Discount, error) {
+	rules, d, err := v.evaluate(ctx, req, items)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(rules, func(a, b *Rule) int { return strings.Compare(a.Code, b.Code) })
+	for _, rule := range rules {
+		if err := v.redeem(ctx, rule, req); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// redeem consumes one use of rule for req's customer and order.
+func (v *RepoValidator) redeem(ctx context.Context, rule *Rule, req Request) error {
+	if err := v.repo.IncrementUses(ctx, rule.Code); err != nil {
+		return rejectCode(rule.Code, errors.Wrap(err, "increment coupon uses"))
+	}
+	if err := v.checkCustomerUses(ctx, rule, req.Customer); err != nil {
+		return rejectCode(rule.Code, err)
+	}
+
+	err := v.repo.RecordRedemption(ctx, Redemption{
+		Code:     rule.Code,
+		Customer: req.Customer,
+		OrderID:  req.OrderID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "record coupon redemption")
+	}
+	return nil
+}
+
+// Preview performs the same checks and calculation as Validate but leaves
+// the usage counters untouched. A successful preview does not reserve a
+// use, so a later Validate can still fail with ErrCouponUsageLimitReached.
+func (v *RepoValidator) Preview(ctx context.Context, req Request, items []Item) (*Discount, error) {
+	_, d, err := v.evaluate(ctx, req, items)
+	return d, err
+}
+
+// evaluate looks up the rules for req.Codes and combines them over items,
+// checking temporal validity and the global and per-customer usage limits
+// along the way. It stops at the first code that is rejected.
+func (v *RepoValidator) evaluate(ctx context.Context, req Request, items []Item) ([]*Rule, *Discount, error) {
+	rules := make([]*Rule, 0, len(req.Codes))
+	for _, code := range req.Codes {
+		rule, err := v.check(ctx, code, req.Customer)
+		if err != nil {
+			return nil, nil, rejectCode(code, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	d, err := Combine(rules, items)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rules, &d, nil
+}
+
+// check looks up the rule for code and checks that it can be redeemed now
+// by customer.
+func (v *RepoValidator) check(ctx context.Context, code, customer string) (*Rule, error) {
 	rule, err := v.repo.FindByCode(ctx, code)
 	if err != nil {
 		if errors.Is(err, ErrInvalidCoupon) {
@@ -49,15 +131,48 @@ func (v *RepoValidator) Validate(ctx context.Context, code string, items []Item)
 	if rule.MaxUses > 0 && rule.Uses >= rule.MaxUses {
 		return nil, ErrCouponUsageLimitReached
 	}
-
-	d, err := Apply(rule, items)
-	if err != nil {
+	if err := v.checkCustomerUses(ctx, rule, customer); err != nil {
 		return nil, err
 	}
 
-	if err := v.repo.IncrementUses(ctx, code); err != nil {
-		return nil, errors.Wrap(err, "increment coupon uses")
+	return rule, nil
+}
+
+// rejectCode wraps err in a *CodeError for code if it is a rejection.
+func rejectCode(code string, err error) error {
+	if IsRejection(err) {
+		return &CodeError{Code: code, Err: err}
 	}
+	return err
+}
 
-	return &d, nil
+// checkCustomerUses returns ErrCouponAlreadyUsed when customer has used up
+// the rule's per-customer allowance. Orders without a customer are not
+// limited.
+func (v *RepoValidator) checkCustomerUses(ctx context.Context, rule *Rule, customer string) error {
+	if rule.MaxUsesPerCustomer <= 0 || customer == "" {
+		return nil
+	}
+	n, err := v.repo.CountRedemptions(ctx, rule.Code, customer)
+	if err != nil {
+		return errors.Wrap(err, "count coupon redemptions")
+	}
+	if n >= rule.MaxUsesPerCustomer {
+		return ErrCouponAlreadyUsed
+	}
+	return nil
+}
+
+// Release returns one use of the coupon to the pool and removes the order's
+// entry for it from the redemption ledger, so the customer may use the coupon
+// again. Like Validate, it should run in the same transaction as the order
+// change that motivates it.
+func (v *RepoValidator) Release(ctx context.Context, code, orderID string) error {
+	if err := v.repo.DecrementUses(ctx, code); err != nil {
+		return errors.Wrap(err, "decrement coupon uses")
+	}
+	if err := v.repo.DeleteRedemption(ctx, code, orderID); err != nil {
+		return errors.Wrap(err, "delete coupon redemption")
+	}
+	return nil
 }
@@ -2,6 +2,7 @@ package coupon
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,21 +13,96 @@ import (
 )
 
 type mockCouponRepo struct {
-	rule          *Rule
+	rule *Rule
+	// rules, when set, is looked up by upper-cased code instead of rule.
+	rules         map[string]*Rule
 	err           error
 	incrementErr  error
 	incrementCode string
+	incremented   []string
+	decrementCode string
+
+	// Redemption ledger.
+	redemptions []Redemption
+	deleted     Redemption
+
+	// Administrative methods.
+	coupon      *Coupon
+	coupons     []Coupon
+	listFilter  ListFilter
+	saved       *Rule
+	deactivated string
 }
 
-func (m *mockCouponRepo) FindByCode(_ context.Context, _ string) (*Rule, error) {
+func (m *mockCouponRepo) FindByCode(_ context.Context, code string) (*Rule, error) {
+	if m.rules != nil {
+		rule, ok := m.rules[strings.ToUpper(code)]
+		if !ok {
+			return nil, ErrInvalidCoupon
+		}
+		return rule, nil
+	}
 	return m.rule, m.err
 }
 
 func (m *mockCouponRepo) IncrementUses(_ context.Context, code string) error {
 	m.incrementCode = code
+	m.incremented = append(m.incremented, code)
 	return m.incrementErr
 }
 
+func (m *mockCouponRepo) DecrementUses(_ context.Context, code string) error {
+	m.decrementCode = code
+	return nil
+}
+
+func (m *mockCouponRepo) CountRedemptions(_ context.Context, code, customer string) (int, error) {
+	n := 0
+	for _, r := range m.redemptions {
+		if r.Code == code && r.Customer == customer {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *mockCouponRepo) RecordRedemption(_ context.Context, r Redemption) error {
+	m.redemptions = append(m.redemptions, r)
+	return nil
+}
+
+func (m *mockCouponRepo) DeleteRedemption(_ context.Context, code, orderID string) error {
+	m.deleted = Redemption{Code: code, OrderID: orderID}
+	return nil
+}
+
+func (m *mockCouponRepo) Get(_ context.Context, _ string) (*Coupon, error) {
+	if m.coupon == nil {
+		return nil, ErrNotFound
+	}
+	return m.coupon, nil
+}
+
+func (m *mockCouponRepo) List(_ context.Context, f ListFilter) ([]Coupon, error) {
+	m.listFilter = f
+	return m.coupons, m.err
+}
+
+func (m *mockCouponRepo) Create(_ context.Context, rule *Rule) error {
+	m.saved = rule
+	return m.err
+}
+
+func (m *mockCouponRepo) Update(_ context.Context, rule *Rule) error {
+	m.saved = rule
+	return m.err
+}
+
+func (m *mockCouponRepo) Deactivate(_ context.Context, code string) error {
+	m.deactivated = code
+	return m.err
+}
+
 func TestRepoValidator_Validate(t *testing.T) {
 	fixedNow := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
 	pastTime := fixedNow.Add(-24 * time.Hour)
@@ -233,7 +309,7 @@ func TestRepoValidator_Validate(t *testing.T) {
 			v := NewRepoValidator(tt.repo)
 			v.now = func() time.Time { return fixedNow }
 
-			got, err := v.Validate(context.Background(), tt.code, tt.items)
+			got, err := v.Validate(context.Background(), Request{Codes: []string{tt.code}}, tt.items)
 
 			if tt.wantErr != nil {
 				require.ErrorIs(t, err, tt.wantErr)
@@ -260,7 +336,7 @@ func TestRepoValidator_IncrementUsesCalledOnSuccess(t *testing.T) {
 	}
 
 	v := NewRepoValidator(repo)
-	_, err := v.Validate(context.Background(), "INC", []Item{
+	_, err := v.Validate(context.Background(), Request{Codes: []string{"INC"}}, []Item{
 		{ProductID: "p1", Price: decimal.NewFromInt(100), Quantity: 1},
 	})
 
@@ -268,6 +344,49 @@ func TestRepoValidator_IncrementUsesCalledOnSuccess(t *testing.T) {
 	assert.Equal(t, "INC", repo.incrementCode)
 }
 
+func TestRepoValidator_IncrementUsesUsesCanonicalCode(t *testing.T) {
+	repo := &mockCouponRepo{
+		rule: &Rule{
+			Code:         "HAPPYHOURS",
+			DiscountType: DiscountPercentage,
+			Value:        decimal.NewFromInt(18),
+			Description:  "18% off",
+		},
+	}
+
+	v := NewRepoValidator(repo)
+	_, err := v.Validate(context.Background(), Request{Codes: []string{"happyhours"}}, []Item{
+		{ProductID: "p1", Price: decimal.NewFromInt(100), Quantity: 1},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "HAPPYHOURS", repo.incrementCode)
+}
+
+func TestRepoValidator_IncrementUsesLimitReached(t *testing.T) {
+	// The rule snapshot still has room, but a concurrent redemption consumed
+	// the last use before the guarded increment ran.
+	repo := &mockCouponRepo{
+		rule: &Rule{
+			Code:         "LAST",
+			DiscountType: DiscountFixed,
+			Value:        decimal.NewFromInt(5),
+			MaxUses:      1,
+			Uses:         0,
+			Description:  "last use",
+		},
+		incrementErr: ErrCouponUsageLimitReached,
+	}
+
+	v := NewRepoValidator(repo)
+	got, err := v.Validate(context.Background(), Request{Codes: []string{"LAST"}}, []Item{
+		{ProductID: "p1", Price: decimal.NewFromInt(100), Quantity: 1},
+	})
+
+	require.ErrorIs(t, err, ErrCouponUsageLimitReached)
+	assert.Nil(t, got)
+}
+
 func TestRepoValidator_IncrementUsesError(t *testing.T) {
 	repo := &mockCouponRepo{
 		rule: &Rule{
@@ -280,10 +399,141 @@ func TestRepoValidator_IncrementUsesError(t *testing.T) {
 	}
 
 	v := NewRepoValidator(repo)
-	_, err := v.Validate(context.Background(), "FAIL", []Item{
+	_, err := v.Validate(context.Background(), Request{Codes: []string{"FAIL"}}, []Item{
 		{ProductID: "p1", Price: decimal.NewFromInt(100), Quantity: 1},
 	})
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "increment coupon uses")
 }
+
+func TestRepoValidator_Release(t *testing.T) {
+	repo := &mockCouponRepo{}
+	v := NewRepoValidator(repo)
+
+	require.NoError(t, v.Release(context.Background(), "happyhours", "order-1"))
+	assert.Equal(t, "happyhours", repo.decrementCode)
+	assert.Equal(t, Redemption{Code: "happyhours", OrderID: "order-1"}, repo.deleted)
+}
+
+func TestRepoValidator_PerCustomerLimit(t *testing.T) {
+	repo := &mockCouponRepo{rule: &Rule{
+		Code:               "ONCE",
+		DiscountType:       DiscountFixed,
+		Value:              decimal.NewFromInt(5),
+		Description:        "once per customer",
+		MaxUsesPerCustomer: 1,
+	}}
+	v := NewRepoValidator(repo)
+	ctx := context.Background()
+	items := []Item{{ProductID: "p1", Price: decimal.NewFromInt(20), Quantity: 1}}
+
+	_, err := v.Validate(ctx, Request{Codes: []string{"once"}, Customer: "key-a", OrderID: "order-1"}, items)
+	require.NoError(t, err)
+	assert.Equal(t, []Redemption{{Code: "ONCE", Customer: "key-a", OrderID: "order-1"}}, repo.redemptions)
+
+	_, err = v.Preview(ctx, Request{Codes: []string{"once"}, Customer: "key-a"}, items)
+	require.ErrorIs(t, err, ErrCouponAlreadyUsed)
+	_, err = v.Validate(ctx, Request{Codes: []string{"ONCE"}, Customer: "key-a", OrderID: "order-2"}, items)
+	require.ErrorIs(t, err, ErrCouponAlreadyUsed)
+	assert.True(t, IsRejection(err))
+
+	// Other customers, and callers without an identity, are not limited.
+	_, err = v.Validate(ctx, Request{Codes: []string{"ONCE"}, Customer: "key-b", OrderID: "order-3"}, items)
+	require.NoError(t, err)
+	_, err = v.Preview(ctx, Request{Codes: []string{"once"}}, items)
+	require.NoError(t, err)
+}
+
+func TestRepoValidator_PreviewDoesNotIncrement(t *testing.T) {
+	repo := &mockCouponRepo{rule: &Rule{
+		Code:         "SAVE10",
+		DiscountType: DiscountPercentage,
+		Value:        decimal.NewFromInt(10),
+		Description:  "10% off",
+		MaxUses:      5,
+		Uses:         4,
+	}}
+	v := NewRepoValidator(repo)
+	items := []Item{{ProductID: "p1", Price: decimal.NewFromInt(20), Quantity: 1}}
+
+	d, err := v.Preview(context.Background(), Request{Codes: []string{"save10"}}, items)
+	require.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(2).Equal(d.Amount))
+	assert.Empty(t, repo.incrementCode)
+
+	repo.rule.Uses = 5
+	_, err = v.Preview(context.Background(), Request{Codes: []string{"save10"}}, items)
+	require.ErrorIs(t, err, ErrCouponUsageLimitReached)
+}
+
+func TestRepoValidator_Stack(t *testing.T) {
+	newRepo := func() *mockCouponRepo {
+		return &mockCouponRepo{rules: map[string]*Rule{
+			"TEN": {
+				Code:         "TEN",
+				DiscountType: DiscountPercentage,
+				Value:        decimal.NewFromInt(10),
+				Description:  "10% off",
+				Stackable:    true,
+			},
+			"FIVE": {
+				Code:         "FIVE",
+				DiscountType: DiscountFixed,
+				Value:        decimal.NewFromInt(5),
+				Description:  "$5 off",
+				Stackable:    true,
+			},
+			"SPENT": {
+				Code:         "SPENT",
+				DiscountType: DiscountFixed,
+				Value:        decimal.NewFromInt(5),
+				Stackable:    true,
+				MaxUses:      1,
+				Uses:         1,
+			},
+		}}
+	}
+	ctx := context.Background()
+	items := []Item{{ProductID: "p1", Price: decimal.NewFromInt(100), Quantity: 1}}
+
+	t.Run("redeems every coupon in code order", func(t *testing.T) {
+		repo := newRepo()
+		v := NewRepoValidator(repo)
+
+		got, err := v.Validate(ctx, Request{Codes: []string{"ten", "five"}, Customer: "key-a", OrderID: "order-1"}, items)
+		require.NoError(t, err)
+		assert.True(t, decimal.NewFromInt(15).Equal(got.Amount))
+		require.Len(t, got.Coupons, 2)
+		assert.Equal(t, "TEN", got.Coupons[0].Code)
+		assert.Equal(t, "FIVE", got.Coupons[1].Code)
+		assert.Equal(t, []string{"FIVE", "TEN"}, repo.incremented)
+		assert.Equal(t, []Redemption{
+			{Code: "FIVE", Customer: "key-a", OrderID: "order-1"},
+			{Code: "TEN", Customer: "key-a", OrderID: "order-1"},
+		}, repo.redemptions)
+	})
+
+	t.Run("rejection names the code", func(t *testing.T) {
+		for _, tt := range []struct {
+			code    string
+			wantErr error
+		}{
+			{code: "nope", wantErr: ErrInvalidCoupon},
+			{code: "spent", wantErr: ErrCouponUsageLimitReached},
+		} {
+			repo := newRepo()
+			v := NewRepoValidator(repo)
+
+			_, err := v.Validate(ctx, Request{Codes: []string{"TEN", tt.code, "FIVE"}}, items)
+			require.ErrorIs(t, err, tt.wantErr)
+			var codeErr *CodeError
+			require.ErrorAs(t, err, &codeErr)
+			assert.Equal(t, tt.code, codeErr.Code)
+			assert.Empty(t, repo.incremented)
+
+			_, err = v.Preview(ctx, Request{Codes: []string{"TEN", tt.code}}, items)
+			require.ErrorIs(t, err, tt.wantErr)
+		}
+	})
+}
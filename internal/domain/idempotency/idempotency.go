@@ -0,0 +1,64 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+var (
+	// ErrKeyReused is returned when a key is presented again with a request
+	// body that differs from the one it was first used with.
+	ErrKeyReused = errors.New("idempotency key was used with a different request")
+	// ErrInProgress is returned when the original request for a key has not
+	// finished yet, so there is no response to replay.
+	ErrInProgress = errors.New("a request with this idempotency key is in progress")
+)
+
+// Record is a stored Idempotency-Key together with the response it produced.
+// Keys are namespaced by Scope (the caller's API key ID) so two clients can
+// pick the same key without colliding.
+type Record struct {
+	Scope       string
+	Key         string
+	Fingerprint string
+	// StatusCode is zero while the original request is still running.
+	StatusCode int
+	Response   []byte
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+}
+
+// Completed reports whether the original request has finished and its
+// response can be replayed.
+func (r *Record) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// Repository persists idempotency records.
+type Repository interface {
+	// Reserve atomically claims rec.Scope/rec.Key for a new request. It returns
+	// (nil, nil) when the claim succeeded, or the live record holding the key
+	// otherwise. Expired records are treated as free.
+	Reserve(ctx context.Context, rec Record) (*Record, error)
+	// Complete stores the final response for a reserved key.
+	Complete(ctx context.Context, scope, key string, statusCode int, response []byte) error
+	// Release drops a reservation so the key can be retried from scratch.
+	Release(ctx context.Context, scope, key string) error
+	// Abandon brings the expiry of a reservation that was never completed
+	// forward to expiresAt, unless it already expires earlier.
+	Abandon(ctx context.Context, scope, key string, expiresAt time.Time) error
+	// DeleteExpired removes records whose expiry is before now and returns
+	// how many were deleted.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Fingerprint returns a stable digest of a serialized request body, used to
+// detect a key being reused for a different request.
+func Fingerprint(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,114 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-faster/errors"
+)
+
+const (
+	// completeAttempts bounds how often Complete tries to store a response.
+	completeAttempts = 3
+	// abandonedTTL is how long an abandoned key keeps answering in progress.
+	abandonedTTL = time.Minute
+)
+
+// Service implements the Idempotency-Key protocol on top of a Repository:
+// the first request with a key executes and its response is stored, identical
+// retries get the stored response back, and mismatched or concurrent reuse is
+// rejected.
+type Service struct {
+	repo       Repository
+	ttl        time.Duration
+	retryDelay time.Duration
+	now        func() time.Time
+}
+
+// NewService creates a Service that remembers keys for ttl.
+func NewService(repo Repository, ttl time.Duration) *Service {
+	return &Service{
+		repo:       repo,
+		ttl:        ttl,
+		retryDelay: 50 * time.Millisecond,
+		now:        time.Now,
+	}
+}
+
+// Begin claims key for a request with the given fingerprint.
+//
+// When the key is new, Begin returns (nil, nil) and the caller owns the key:
+// it must call Complete with the response, or Release if the request failed
+// in a way that should not be replayed. When an identical request already
+// completed, Begin returns its record so the caller can replay the response.
+// Otherwise it returns ErrKeyReused or ErrInProgress.
+func (s *Service) Begin(ctx context.Context, scope, key, fingerprint string) (*Record, error) {
+	existing, err := s.repo.Reserve(ctx, Record{
+		Scope:       scope,
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   s.now().Add(s.ttl),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "reserve idempotency key")
+	}
+	if existing == nil {
+		return nil, nil
+	}
+
+	if existing.Fingerprint != fingerprint {
+		return nil, ErrKeyReused
+	}
+	if !existing.Completed() {
+		return nil, ErrInProgress
+	}
+	return existing, nil
+}
+
+// Complete records the response for a key claimed by Begin. By then the
+// request has taken effect and a lost response leaves the key in progress,
+// so a failed write is retried a few times.
+func (s *Service) Complete(ctx context.Context, scope, key string, statusCode int, response []byte) error {
+	var err error
+	for attempt := range completeAttempts {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return errors.Wrap(ctx.Err(), "complete idempotency key")
+			case <-time.After(time.Duration(attempt) * s.retryDelay):
+			}
+		}
+		if err = s.repo.Complete(ctx, scope, key, statusCode, response); err == nil {
+			return nil
+		}
+	}
+	return errors.Wrap(err, "complete idempotency key")
+}
+
+// Abandon gives up on storing a response for a key claimed by Begin. Retries
+// keep getting ErrInProgress for a short while, so one racing the original
+// request doesn't run it again, and then the key is free instead of being
+// held for the whole TTL.
+func (s *Service) Abandon(ctx context.Context, scope, key string) error {
+	if err := s.repo.Abandon(ctx, scope, key, s.now().Add(abandonedTTL)); err != nil {
+		return errors.Wrap(err, "abandon idempotency key")
+	}
+	return nil
+}
+
+// Release frees a key claimed by Begin without recording a response.
+func (s *Service) Release(ctx context.Context, scope, key string) error {
+	if err := s.repo.Release(ctx, scope, key); err != nil {
+		return errors.Wrap(err, "release idempotency key")
+	}
+	return nil
+}
+
+// Prune deletes expired records and returns how many were removed.
+func (s *Service) Prune(ctx context.Context) (int64, error) {
+	n, err := s.repo.DeleteExpired(ctx, s.now())
+	if err != nil {
+		return 0, errors.Wrap(err, "delete expired idempotency keys")
+	}
+	return n, nil
+}
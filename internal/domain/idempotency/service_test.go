@@ -0,0 +1,185 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepo struct {
+	existing    *Record
+	reserveErr  error
+	reserved    *Record
+	deletedAt   time.Time
+	completeErr []error
+	completes   int
+	abandonedAt time.Time
+}
+
+func (m *mockRepo) Reserve(_ context.Context, rec Record) (*Record, error) {
+	if m.reserveErr != nil {
+		return nil, m.reserveErr
+	}
+	if m.existing != nil {
+		return m.existing, nil
+	}
+	m.reserved = &rec
+	return nil, nil
+}
+
+func (m *mockRepo) Complete(_ context.Context, _, _ string, _ int, _ []byte) error {
+	m.completes++
+	if len(m.completeErr) > 0 {
+		err := m.completeErr[0]
+		m.completeErr = m.completeErr[1:]
+		return err
+	}
+	return nil
+}
+
+func (m *mockRepo) Abandon(_ context.Context, _, _ string, expiresAt time.Time) error {
+	m.abandonedAt = expiresAt
+	return nil
+}
+
+func (m *mockRepo) Release(_ context.Context, _, _ string) error {
+	return nil
+}
+
+func (m *mockRepo) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	m.deletedAt = now
+	return 3, nil
+}
+
+func TestService_Begin(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	fp := Fingerprint([]byte(`{"items":[]}`))
+
+	tests := []struct {
+		name       string
+		repo       *mockRepo
+		wantReplay bool
+		wantErr    error
+	}{
+		{
+			name: "new key is reserved",
+			repo: &mockRepo{},
+		},
+		{
+			name: "completed identical request is replayed",
+			repo: &mockRepo{existing: &Record{
+				Fingerprint: fp,
+				StatusCode:  200,
+				Response:    []byte(`{"id":"o1"}`),
+			}},
+			wantReplay: true,
+		},
+		{
+			name:    "different fingerprint is rejected",
+			repo:    &mockRepo{existing: &Record{Fingerprint: "other", StatusCode: 200}},
+			wantErr: ErrKeyReused,
+		},
+		{
+			name:    "different fingerprint wins over in progress",
+			repo:    &mockRepo{existing: &Record{Fingerprint: "other"}},
+			wantErr: ErrKeyReused,
+		},
+		{
+			name:    "unfinished request is in progress",
+			repo:    &mockRepo{existing: &Record{Fingerprint: fp}},
+			wantErr: ErrInProgress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(tt.repo, time.Hour)
+			svc.now = func() time.Time { return now }
+
+			rec, err := svc.Begin(context.Background(), "key-1", "k", fp)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, rec)
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.wantReplay {
+				require.NotNil(t, rec)
+				assert.Equal(t, 200, rec.StatusCode)
+				return
+			}
+			assert.Nil(t, rec)
+			require.NotNil(t, tt.repo.reserved)
+			assert.Equal(t, "key-1", tt.repo.reserved.Scope)
+			assert.Equal(t, fp, tt.repo.reserved.Fingerprint)
+			assert.Equal(t, now.Add(time.Hour), tt.repo.reserved.ExpiresAt)
+		})
+	}
+}
+
+func TestService_BeginRepositoryError(t *testing.T) {
+	svc := NewService(&mockRepo{reserveErr: errors.New("db down")}, time.Hour)
+
+	_, err := svc.Begin(context.Background(), "key-1", "k", "fp")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reserve idempotency key")
+}
+
+func TestService_Complete(t *testing.T) {
+	dbErr := errors.New("connection reset")
+
+	t.Run("transient failure is retried", func(t *testing.T) {
+		repo := &mockRepo{completeErr: []error{dbErr, dbErr}}
+		svc := NewService(repo, time.Hour)
+		svc.retryDelay = 0
+
+		require.NoError(t, svc.Complete(context.Background(), "key-1", "k", 200, []byte(`{}`)))
+		assert.Equal(t, 3, repo.completes)
+	})
+
+	t.Run("gives up after a few attempts", func(t *testing.T) {
+		repo := &mockRepo{completeErr: []error{dbErr, dbErr, dbErr, dbErr}}
+		svc := NewService(repo, time.Hour)
+		svc.retryDelay = 0
+
+		err := svc.Complete(context.Background(), "key-1", "k", 200, []byte(`{}`))
+		require.ErrorIs(t, err, dbErr)
+		assert.Equal(t, completeAttempts, repo.completes)
+	})
+}
+
+func TestService_Abandon(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	repo := &mockRepo{}
+	svc := NewService(repo, 24*time.Hour)
+	svc.now = func() time.Time { return now }
+
+	require.NoError(t, svc.Abandon(context.Background(), "key-1", "k"))
+	assert.Equal(t, now.Add(abandonedTTL), repo.abandonedAt)
+}
+
+func TestService_Prune(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	repo := &mockRepo{}
+	svc := NewService(repo, time.Hour)
+	svc.now = func() time.Time { return now }
+
+	n, err := svc.Prune(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	assert.Equal(t, now, repo.deletedAt)
+}
+
+func TestFingerprint(t *testing.T) {
+	a := Fingerprint([]byte(`{"items":[{"productId":"1","quantity":1}]}`))
+	b := Fingerprint([]byte(`{"items":[{"productId":"1","quantity":2}]}`))
+
+	assert.Len(t, a, 64)
+	assert.Equal(t, a, Fingerprint([]byte(`{"items":[{"productId":"1","quantity":1}]}`)))
+	assert.NotEqual(t, a, b)
+}
@@ -2,28 +2,121 @@ package order
 
 import (
 	"context"
+	"encoding/base64"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
 )
 
 // Order represents a completed customer order with pricing and discount details.
+// CouponCode is the first coupon code the order was placed with, as typed;
+// Coupons lists every coupon applied.
 type Order struct {
-	ID         string
-	Items      []OrderItem
-	Total      decimal.Decimal
-	Discounts  decimal.Decimal
-	CouponCode string
-	CreatedAt  time.Time
+	ID              string
+	Items           []OrderItem
+	Total           decimal.Decimal
+	Discounts       decimal.Decimal
+	CouponCode      string
+	Coupons         []AppliedCoupon
+	Status          Status
+	StatusUpdatedAt time.Time
+	CreatedAt       time.Time
 }
 
-// OrderItem represents a single line item in an order.
+// OrderItem represents a single line item in an order. On placement only
+// ProductID and Quantity are read; the remaining fields snapshot the product
+// and pricing at the time of purchase so historical totals stay explainable
+// after catalog changes.
 type OrderItem struct {
-	ProductID string `json:"product_id"`
-	Quantity  int    `json:"quantity"`
+	ProductID string          `json:"product_id"`
+	Quantity  int             `json:"quantity"`
+	Name      string          `json:"name,omitempty"`
+	Category  string          `json:"category,omitempty"`
+	UnitPrice decimal.Decimal `json:"unit_price"`
+	// Subtotal is UnitPrice * Quantity before discounts.
+	Subtotal decimal.Decimal `json:"subtotal"`
+	// Discount is this line's share of the order discount.
+	Discount decimal.Decimal `json:"discount"`
+}
+
+// AppliedCoupon is one coupon's part of an order's discount. Coupons are
+// listed in the order they were applied.
+type AppliedCoupon struct {
+	Code        string          `json:"code"`
+	Description string          `json:"description"`
+	Discount    decimal.Decimal `json:"discount"`
+}
+
+// ListFilter narrows and paginates order listings. Orders are returned newest
+// first, ordered by (CreatedAt, ID) descending.
+type ListFilter struct {
+	// CouponCode matches orders that this coupon was applied to,
+	// case-insensitively.
+	CouponCode string
+	// From and To bound CreatedAt to the half-open range [From, To).
+	From *time.Time
+	To   *time.Time
+	// After resumes the listing strictly after the given position.
+	After *Cursor
+	// Limit is the maximum number of orders to return.
+	Limit int
+}
+
+// Cursor identifies a position in an order listing.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Encode returns the opaque string form of the cursor.
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by Cursor.Encode. It returns
+// ErrInvalidCursor when s is malformed.
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{CreatedAt: createdAt, ID: id}, nil
 }
 
 // Repository defines persistence operations for orders.
 type Repository interface {
-	Create(ctx context.Context, order *Order) error
+	// Create persists a new order, records its initial transition into
+	// order.Status on behalf of actor, and sets CreatedAt and StatusUpdatedAt.
+	Create(ctx context.Context, order *Order, actor string) error
+	// GetByID returns a single order. Returns ErrNotFound when no order
+	// with the given ID exists.
+	GetByID(ctx context.Context, id string) (*Order, error)
+	// List returns orders matching the filter, newest first.
+	List(ctx context.Context, filter ListFilter) ([]Order, error)
+	// UpdateStatus moves an order from t.From to t.To and appends t to its
+	// history, setting t.At. Returns ErrStatusConflict when the order is no
+	// longer in t.From.
+	UpdateStatus(ctx context.Context, t *StatusTransition) error
+	// ListTransitions returns an order's status history, oldest first.
+	ListTransitions(ctx context.Context, orderID string) ([]StatusTransition, error)
+}
+
+// Transactor runs a unit of work atomically. Repository calls made with the
+// context passed to fn take part in the same transaction, which is committed
+// when fn returns nil and rolled back otherwise.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
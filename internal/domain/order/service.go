@@ -3,6 +3,9 @@ package order
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -11,10 +14,39 @@ import (
 	"github.com/xenking/oolio-kart-challenge/internal/domain/product"
 )
 
+// MaxCoupons is the most coupon codes one order can combine.
+const MaxCoupons = 5
+
 // Sentinel errors for order validation.
 var (
 	ErrEmptyItems      = fmt.Errorf("items required")
 	ErrInvalidQuantity = fmt.Errorf("quantity must be greater than 0")
+	ErrTooManyCoupons  = fmt.Errorf("at most %d coupon codes per order", MaxCoupons)
+	ErrDuplicateCoupon = fmt.Errorf("coupon code given more than once")
+)
+
+// Sentinel errors for order lookups.
+var (
+	ErrNotFound         = fmt.Errorf("order not found")
+	ErrInvalidCursor    = fmt.Errorf("invalid cursor")
+	ErrInvalidDateRange = fmt.Errorf("from must be before to")
+)
+
+// Sentinel errors for status changes.
+var (
+	ErrInvalidStatus = fmt.Errorf("invalid order status")
+	// ErrStatusConflict is returned when the order's status changed
+	// concurrently between being read and being updated.
+	ErrStatusConflict = fmt.Errorf("order status was changed concurrently")
+	// ErrCancelWindowExpired is returned when Cancel is called after
+	// ServiceConfig.CancelWindow has elapsed since the order was placed.
+	ErrCancelWindowExpired = fmt.Errorf("cancellation window has expired")
+)
+
+// Page size bounds for ListOrders.
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
 )
 
 // ProductNotFoundError indicates a requested product does not exist.
@@ -37,8 +69,12 @@ func (e *InvalidQuantityError) Error() string {
 
 // PlaceOrderRequest holds the input for placing an order.
 type PlaceOrderRequest struct {
-	Items      []OrderItem
-	CouponCode string
+	Items []OrderItem
+	// CouponCodes are combined under the stacking rules of coupon.Combine.
+	CouponCodes []string
+	// Actor identifies the caller in the order's status history and is the
+	// customer the coupon redemptions are recorded for.
+	Actor string
 }
 
 // PlaceOrderResult holds the output of a successfully placed order.
@@ -47,36 +83,259 @@ type PlaceOrderResult struct {
 	Products []product.Product
 }
 
+// OrderDetails holds a stored order together with the products of its line
+// items, in item order.
+type OrderDetails struct {
+	Order    *Order
+	Products []product.Product
+	// History is the order's status history, oldest first. It is only
+	// loaded for single-order lookups.
+	History []StatusTransition
+}
+
+// QuoteRequest holds the input for pricing a cart without placing it.
+type QuoteRequest struct {
+	Items       []OrderItem
+	CouponCodes []string
+	// Customer is checked against the coupons' per-customer limits, as the
+	// Actor of PlaceOrderRequest would be.
+	Customer string
+}
+
+// Quote is the priced preview of a cart.
+type Quote struct {
+	// Items carry the same snapshots and discount shares PlaceOrder would
+	// store.
+	Items             []OrderItem
+	Products          []product.Product
+	Subtotal          decimal.Decimal
+	Discount          decimal.Decimal
+	Total             decimal.Decimal
+	CouponCodes       []string
+	CouponDescription string
+	// Coupons breaks Discount down by coupon, as PlaceOrder would store it.
+	Coupons []AppliedCoupon
+	// CouponError is why the coupons were not applied: a rejection from the
+	// coupon package, usually a *coupon.CodeError naming the code. Nil when
+	// the coupons applied or none were given. Coupons are applied all
+	// together or not at all, as placing the order would.
+	CouponError error
+}
+
+// UpdateStatusRequest holds the input for changing an order's status.
+type UpdateStatusRequest struct {
+	OrderID string
+	Status  Status
+	Actor   string
+	Reason  string
+}
+
+// ListOrdersRequest holds the input for listing orders.
+type ListOrdersRequest struct {
+	CouponCode string
+	From       *time.Time
+	To         *time.Time
+	// Cursor is the opaque NextCursor of a previous page, or empty for the
+	// first page.
+	Cursor string
+	// Limit defaults to DefaultListLimit and is capped at MaxListLimit.
+	Limit int
+}
+
+// ListOrdersResult holds one page of orders.
+type ListOrdersResult struct {
+	Orders []OrderDetails
+	// NextCursor is empty when there are no further pages.
+	NextCursor string
+}
+
+// CancelRequest holds the input for cancelling an order.
+type CancelRequest struct {
+	OrderID string
+	Actor   string
+	Reason  string
+}
+
+// ServiceConfig holds non-dependency configuration for the Service.
+type ServiceConfig struct {
+	// CancelWindow is how long after placement Cancel is allowed. Zero means
+	// orders can be cancelled at any time the state machine permits.
+	CancelWindow time.Duration
+}
+
 // Service encapsulates order placement business logic.
 type Service struct {
-	products product.Repository
-	coupons  coupon.Validator
-	orders   Repository
+	products     product.Repository
+	coupons      coupon.Validator
+	orders       Repository
+	tx           Transactor
+	cancelWindow time.Duration
+	now          func() time.Time
 }
 
 // NewService creates an order Service with the required domain dependencies.
 func NewService(
+	cfg ServiceConfig,
 	products product.Repository,
 	coupons coupon.Validator,
 	orders Repository,
+	tx Transactor,
 ) *Service {
 	return &Service{
-		products: products,
-		coupons:  coupons,
-		orders:   orders,
+		products:     products,
+		coupons:      coupons,
+		orders:       orders,
+		tx:           tx,
+		cancelWindow: cfg.CancelWindow,
+		now:          time.Now,
 	}
 }
 
 // PlaceOrder validates items, fetches products in a single batch, applies
-// coupons, persists the order, and returns the result.
+// coupons, persists the order, and returns the result. Coupon redemption and
+// the order insert run in one transaction, so a failed insert never consumes
+// a coupon use.
 func (s *Service) PlaceOrder(ctx context.Context, req PlaceOrderRequest) (*PlaceOrderResult, error) {
-	if len(req.Items) == 0 {
+	if err := checkCouponCodes(req.CouponCodes); err != nil {
+		return nil, err
+	}
+	c, err := s.priceItems(ctx, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &Order{
+		ID:     uuid.New().String(),
+		Items:  c.lines,
+		Status: StatusPlaced,
+	}
+	if len(req.CouponCodes) > 0 {
+		o.CouponCode = req.CouponCodes[0]
+	}
+
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Apply coupon discounts when codes are provided.
+		var discount *coupon.Discount
+		if len(req.CouponCodes) > 0 {
+			var err error
+			discount, err = s.coupons.Validate(ctx, coupon.Request{
+				Codes:    req.CouponCodes,
+				Customer: req.Actor,
+				OrderID:  o.ID,
+			}, c.couponItems)
+			if err != nil {
+				return fmt.Errorf("validate coupon: %w", err)
+			}
+			o.Coupons = appliedCoupons(discount)
+		}
+		o.Total, o.Discounts = c.applyDiscount(discount)
+
+		// Persist order.
+		if err := s.orders.Create(ctx, o, req.Actor); err != nil {
+			return fmt.Errorf("create order: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PlaceOrderResult{
+		Order:    o,
+		Products: c.products,
+	}, nil
+}
+
+// Quote prices items exactly as PlaceOrder would, without redeeming the
+// coupons or persisting anything. Coupons that cannot be applied do not fail
+// the quote: the totals are computed without them and the reason is
+// returned in Quote.CouponError.
+func (s *Service) Quote(ctx context.Context, req QuoteRequest) (*Quote, error) {
+	if err := checkCouponCodes(req.CouponCodes); err != nil {
+		return nil, err
+	}
+	c, err := s.priceItems(ctx, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Quote{
+		Items:       c.lines,
+		Products:    c.products,
+		Subtotal:    c.subtotal.Round(2),
+		CouponCodes: req.CouponCodes,
+	}
+
+	var applied *coupon.Discount
+	if len(req.CouponCodes) > 0 {
+		discount, err := s.coupons.Preview(ctx, coupon.Request{
+			Codes:    req.CouponCodes,
+			Customer: req.Customer,
+		}, c.couponItems)
+		switch {
+		case coupon.IsRejection(err):
+			q.CouponError = err
+		case err != nil:
+			return nil, fmt.Errorf("preview coupon: %w", err)
+		default:
+			applied = discount
+			q.CouponDescription = discount.Description
+			q.Coupons = appliedCoupons(discount)
+		}
+	}
+	q.Total, q.Discount = c.applyDiscount(applied)
+
+	return q, nil
+}
+
+// checkCouponCodes rejects more than MaxCoupons codes and codes given twice.
+// Codes are compared the way coupons are looked up, ignoring case.
+func checkCouponCodes(codes []string) error {
+	if len(codes) > MaxCoupons {
+		return ErrTooManyCoupons
+	}
+	seen := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		key := strings.ToUpper(code)
+		if _, ok := seen[key]; ok {
+			return ErrDuplicateCoupon
+		}
+		seen[key] = struct{}{}
+	}
+	return nil
+}
+
+// appliedCoupons converts the breakdown of a combined discount.
+func appliedCoupons(d *coupon.Discount) []AppliedCoupon {
+	out := make([]AppliedCoupon, len(d.Coupons))
+	for i, a := range d.Coupons {
+		out[i] = AppliedCoupon{
+			Code:        a.Code,
+			Description: a.Description,
+			Discount:    a.Amount.Round(2),
+		}
+	}
+	return out
+}
+
+// pricedCart is a validated set of line items priced against the catalog.
+type pricedCart struct {
+	lines       []OrderItem
+	products    []product.Product
+	couponItems []coupon.Item
+	subtotal    decimal.Decimal
+}
+
+// priceItems validates items, fetches their products in a single batch and
+// builds the line snapshots and coupon input shared by PlaceOrder and Quote.
+func (s *Service) priceItems(ctx context.Context, items []OrderItem) (*pricedCart, error) {
+	if len(items) == 0 {
 		return nil, ErrEmptyItems
 	}
 
 	// Validate quantities and collect product IDs.
-	ids := make([]string, len(req.Items))
-	for i, item := range req.Items {
+	ids := make([]string, len(items))
+	for i, item := range items {
 		if item.Quantity <= 0 {
 			return nil, &InvalidQuantityError{ProductID: item.ProductID}
 		}
@@ -95,8 +354,8 @@ func (s *Service) PlaceOrder(ctx context.Context, req PlaceOrderRequest) (*Place
 	}
 
 	// Verify every requested product was found.
-	products := make([]product.Product, 0, len(req.Items))
-	for _, item := range req.Items {
+	products := make([]product.Product, 0, len(items))
+	for _, item := range items {
 		p, ok := productMap[item.ProductID]
 		if !ok {
 			return nil, &ProductNotFoundError{ProductID: item.ProductID}
@@ -104,53 +363,327 @@ func (s *Service) PlaceOrder(ctx context.Context, req PlaceOrderRequest) (*Place
 		products = append(products, p)
 	}
 
-	// Build coupon items and calculate subtotal.
-	couponItems := make([]coupon.Item, len(req.Items))
-	subtotal := decimal.Zero
-	for i, item := range req.Items {
-		price := products[i].Price
-		qty := decimal.NewFromInt(int64(item.Quantity))
+	// Build coupon items, line snapshots and the subtotal.
+	c := &pricedCart{
+		lines:       make([]OrderItem, len(items)),
+		products:    products,
+		couponItems: make([]coupon.Item, len(items)),
+		subtotal:    decimal.Zero,
+	}
+	for i, item := range items {
+		p := products[i]
+		lineTotal := p.Price.Mul(decimal.NewFromInt(int64(item.Quantity)))
 
-		couponItems[i] = coupon.Item{
+		c.couponItems[i] = coupon.Item{
 			ProductID: item.ProductID,
-			Price:     price,
+			Category:  p.Category,
+			Price:     p.Price,
 			Quantity:  item.Quantity,
 		}
-		subtotal = subtotal.Add(price.Mul(qty))
+		c.lines[i] = OrderItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Name:      p.Name,
+			Category:  p.Category,
+			UnitPrice: p.Price,
+			Subtotal:  lineTotal.Round(2),
+		}
+		c.subtotal = c.subtotal.Add(lineTotal)
+	}
+	return c, nil
+}
+
+// applyDiscount computes the total for the cart after d, floored at zero,
+// and spreads the discount over the lines. A coupon targeted at some items
+// is spread over their lines only; the rest of the discount over all lines.
+// Both returned amounts are rounded to 2 decimal places. A nil d is no
+// discount.
+func (c *pricedCart) applyDiscount(d *coupon.Discount) (total, discount decimal.Decimal) {
+	amount := decimal.Zero
+	if d != nil {
+		amount = d.Amount
+	}
+	total = c.subtotal.Sub(amount)
+	if total.IsNegative() {
+		total = decimal.Zero
+	}
+	discount = amount.Round(2)
+
+	rest := decimal.Min(discount, c.subtotal.Round(2))
+	var targeted []coupon.Applied
+	if d != nil {
+		for _, a := range d.Coupons {
+			if a.Items != nil {
+				a.Amount = decimal.Min(a.Amount.Round(2), rest)
+				rest = rest.Sub(a.Amount)
+				targeted = append(targeted, a)
+			}
+		}
 	}
+	allocateDiscount(c.lines, rest)
+	for _, a := range targeted {
+		addDiscount(c.lines, a.Items, a.Amount)
+	}
+	return total.Round(2), discount
+}
 
-	// Apply coupon discount when a code is provided.
-	discountAmount := decimal.Zero
-	if req.CouponCode != "" {
-		discount, err := s.coupons.Validate(ctx, req.CouponCode, couponItems)
+// GetOrder returns a stored order with its products and status history.
+// Returns ErrNotFound when the order does not exist.
+func (s *Service) GetOrder(ctx context.Context, id string) (*OrderDetails, error) {
+	o, err := s.orders.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get order: %w", err)
+	}
+	return s.details(ctx, o)
+}
+
+// UpdateStatus moves an order to a new status if the state machine allows
+// it, recording the transition. Returns ErrInvalidStatus for unknown
+// statuses, *InvalidTransitionError for disallowed moves, ErrStatusConflict
+// when racing another update, and ErrNotFound for unknown orders.
+//
+// Moving an order to cancelled or rejected gives its coupon uses back, as
+// Cancel does, but is not bound by the cancellation window.
+func (s *Service) UpdateStatus(ctx context.Context, req UpdateStatusRequest) (*OrderDetails, error) {
+	if !req.Status.Valid() {
+		return nil, ErrInvalidStatus
+	}
+	return s.transition(ctx, req, nil)
+}
+
+// Cancel cancels an order on behalf of the customer, provided it is still
+// within the cancellation window, and gives back the coupon uses it
+// consumed. Cancelling an already cancelled order returns
+// *InvalidTransitionError, so the coupons are released at most once.
+func (s *Service) Cancel(ctx context.Context, req CancelRequest) (*OrderDetails, error) {
+	return s.transition(ctx, UpdateStatusRequest{
+		OrderID: req.OrderID,
+		Status:  StatusCancelled,
+		Actor:   req.Actor,
+		Reason:  req.Reason,
+	}, s.checkCancelWindow)
+}
+
+// transition applies a status change in a transaction. check, when non-nil,
+// runs after the state machine accepts the move and can veto it.
+func (s *Service) transition(ctx context.Context, req UpdateStatusRequest, check func(*Order) error) (*OrderDetails, error) {
+	var o *Order
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		cur, err := s.orders.GetByID(ctx, req.OrderID)
 		if err != nil {
-			return nil, fmt.Errorf("validate coupon: %w", err)
+			return fmt.Errorf("get order: %w", err)
+		}
+		if !cur.Status.CanTransitionTo(req.Status) {
+			return &InvalidTransitionError{From: cur.Status, To: req.Status}
+		}
+		if check != nil {
+			if err := check(cur); err != nil {
+				return err
+			}
+		}
+
+		t := &StatusTransition{
+			OrderID: cur.ID,
+			From:    cur.Status,
+			To:      req.Status,
+			Actor:   req.Actor,
+			Reason:  req.Reason,
+		}
+		if err := s.orders.UpdateStatus(ctx, t); err != nil {
+			return fmt.Errorf("update status: %w", err)
+		}
+
+		// The guarded update above succeeds at most once per order, so the
+		// coupons cannot be released twice.
+		if releasesCoupon(t.To) {
+			for _, ac := range cur.Coupons {
+				if err := s.coupons.Release(ctx, ac.Code, cur.ID); err != nil {
+					return fmt.Errorf("release coupon %s: %w", ac.Code, err)
+				}
+			}
 		}
-		discountAmount = discount.Amount
+
+		cur.Status = t.To
+		cur.StatusUpdatedAt = t.At
+		o = cur
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return s.details(ctx, o)
+}
 
-	// Total = subtotal - discount, floored at zero and rounded to 2 decimal places.
-	total := subtotal.Sub(discountAmount)
-	if total.IsNegative() {
-		total = decimal.Zero
+func (s *Service) checkCancelWindow(o *Order) error {
+	if s.cancelWindow > 0 && s.now().Sub(o.CreatedAt) > s.cancelWindow {
+		return ErrCancelWindowExpired
 	}
-	total = total.Round(2)
-	discountAmount = discountAmount.Round(2)
+	return nil
+}
 
-	// Persist order.
-	o := &Order{
-		ID:         uuid.New().String(),
-		Items:      req.Items,
-		Total:      total,
-		Discounts:  discountAmount,
+// releasesCoupon reports whether moving to status means the order will never
+// be fulfilled, so its coupon uses should be returned.
+func releasesCoupon(status Status) bool {
+	return status == StatusCancelled || status == StatusRejected
+}
+
+// details hydrates a single order with its products and status history.
+func (s *Service) details(ctx context.Context, o *Order) (*OrderDetails, error) {
+	details, err := s.hydrate(ctx, []Order{*o})
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.orders.ListTransitions(ctx, o.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list status history: %w", err)
+	}
+	details[0].History = history
+	return &details[0], nil
+}
+
+// ListOrders returns one page of stored orders, newest first, with their
+// products.
+func (s *Service) ListOrders(ctx context.Context, req ListOrdersRequest) (*ListOrdersResult, error) {
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return nil, ErrInvalidDateRange
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	limit = min(limit, MaxListLimit)
+
+	filter := ListFilter{
 		CouponCode: req.CouponCode,
+		From:       req.From,
+		To:         req.To,
+		// Fetch one extra row to learn whether another page exists.
+		Limit: limit + 1,
 	}
-	if err := s.orders.Create(ctx, o); err != nil {
-		return nil, fmt.Errorf("create order: %w", err)
+	if req.Cursor != "" {
+		c, err := DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = &c
 	}
 
-	return &PlaceOrderResult{
-		Order:    o,
-		Products: products,
+	orders, err := s.orders.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list orders: %w", err)
+	}
+
+	var next string
+	if len(orders) > limit {
+		orders = orders[:limit]
+		last := orders[limit-1]
+		next = Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	details, err := s.hydrate(ctx, orders)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListOrdersResult{
+		Orders:     details,
+		NextCursor: next,
 	}, nil
 }
+
+// hydrate fetches the products referenced by the given orders in a single
+// batch. Products that no longer exist are omitted from the result.
+func (s *Service) hydrate(ctx context.Context, orders []Order) ([]OrderDetails, error) {
+	var ids []string
+	seen := make(map[string]struct{})
+	for _, o := range orders {
+		for _, item := range o.Items {
+			if _, ok := seen[item.ProductID]; !ok {
+				seen[item.ProductID] = struct{}{}
+				ids = append(ids, item.ProductID)
+			}
+		}
+	}
+
+	productMap := make(map[string]product.Product, len(ids))
+	if len(ids) > 0 {
+		fetched, err := s.products.GetByIDs(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("get products: %w", err)
+		}
+		for _, p := range fetched {
+			productMap[p.ID] = p
+		}
+	}
+
+	details := make([]OrderDetails, len(orders))
+	for i := range orders {
+		o := &orders[i]
+		products := make([]product.Product, 0, len(o.Items))
+		for _, item := range o.Items {
+			if p, ok := productMap[item.ProductID]; ok {
+				products = append(products, p)
+			}
+		}
+		details[i] = OrderDetails{Order: o, Products: products}
+	}
+	return details, nil
+}
+
+// allocateDiscount splits discount across items pro rata to their subtotals
+// using the largest-remainder method, so the per-line shares are whole cents
+// and sum exactly to discount. Ties go to the earlier line.
+func allocateDiscount(items []OrderItem, discount decimal.Decimal) {
+	for i := range items {
+		items[i].Discount = decimal.Zero
+	}
+	lines := make([]int, len(items))
+	for i := range lines {
+		lines[i] = i
+	}
+	addDiscount(items, lines, discount)
+}
+
+// addDiscount splits discount across the items at positions lines the way
+// allocateDiscount does, adding each share to what the line already has.
+func addDiscount(items []OrderItem, lines []int, discount decimal.Decimal) {
+	subtotal := decimal.Zero
+	for _, i := range lines {
+		subtotal = subtotal.Add(items[i].Subtotal)
+	}
+	if !discount.IsPositive() || !subtotal.IsPositive() {
+		return
+	}
+
+	cent := decimal.New(1, -2)
+	shares := make([]decimal.Decimal, len(lines))
+	remainders := make([]decimal.Decimal, len(lines))
+	allocated := decimal.Zero
+	for j, i := range lines {
+		exact := discount.Mul(items[i].Subtotal).Div(subtotal)
+		shares[j] = exact.RoundFloor(2)
+		remainders[j] = exact.Sub(shares[j])
+		allocated = allocated.Add(shares[j])
+	}
+
+	// Hand out the leftover cents to the lines with the largest remainders.
+	order := make([]int, len(lines))
+	for j := range order {
+		order[j] = j
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].GreaterThan(remainders[order[b]])
+	})
+	for _, j := range order {
+		if !allocated.LessThan(discount) {
+			break
+		}
+		shares[j] = shares[j].Add(cent)
+		allocated = allocated.Add(cent)
+	}
+	for j, i := range lines {
+		items[i].Discount = items[i].Discount.Add(shares[j])
+	}
+}
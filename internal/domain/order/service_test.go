@@ -2,7 +2,9 @@ package order
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/go-faster/errors"
 	"github.com/shopspring/decimal"
@@ -50,24 +52,113 @@ func (m *mockProductRepo) GetByIDs(_ context.Context, ids []string) ([]product.P
 }
 
 type mockCouponValidator struct {
-	discount *coupon.Discount
-	err      error
+	discount     *coupon.Discount
+	err          error
+	calls        int
+	previewCalls int
+	lastReq      coupon.Request
+	lastItems    []coupon.Item
+	released     []string
 }
 
-func (m *mockCouponValidator) Validate(_ context.Context, _ string, _ []coupon.Item) (*coupon.Discount, error) {
+func (m *mockCouponValidator) Validate(_ context.Context, req coupon.Request, items []coupon.Item) (*coupon.Discount, error) {
+	m.calls++
+	m.lastReq = req
+	m.lastItems = items
 	return m.discount, m.err
 }
 
+func (m *mockCouponValidator) Preview(_ context.Context, req coupon.Request, _ []coupon.Item) (*coupon.Discount, error) {
+	m.previewCalls++
+	m.lastReq = req
+	return m.discount, m.err
+}
+
+func (m *mockCouponValidator) Release(_ context.Context, code, _ string) error {
+	m.released = append(m.released, code)
+	return nil
+}
+
 type mockOrderRepo struct {
-	lastOrder *Order
-	err       error
+	lastOrder   *Order
+	lastActor   string
+	err         error
+	orders      []Order // newest first
+	lastFilter  ListFilter
+	transitions []StatusTransition
+	statusErr   error
 }
 
-func (m *mockOrderRepo) Create(_ context.Context, o *Order) error {
+func (m *mockOrderRepo) Create(_ context.Context, o *Order, actor string) error {
 	m.lastOrder = o
+	m.lastActor = actor
 	return m.err
 }
 
+func (m *mockOrderRepo) GetByID(_ context.Context, id string) (*Order, error) {
+	for i := range m.orders {
+		if m.orders[i].ID == id {
+			return &m.orders[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (m *mockOrderRepo) List(_ context.Context, f ListFilter) ([]Order, error) {
+	m.lastFilter = f
+	var out []Order
+	for _, o := range m.orders {
+		if f.After != nil && !o.CreatedAt.Before(f.After.CreatedAt) {
+			continue
+		}
+		out = append(out, o)
+		if len(out) == f.Limit {
+			break
+		}
+	}
+	return out, m.err
+}
+
+func (m *mockOrderRepo) UpdateStatus(_ context.Context, t *StatusTransition) error {
+	if m.statusErr != nil {
+		return m.statusErr
+	}
+	for i := range m.orders {
+		if m.orders[i].ID == t.OrderID {
+			if m.orders[i].Status != t.From {
+				return ErrStatusConflict
+			}
+			t.At = time.Date(2025, 6, 15, 13, 0, 0, 0, time.UTC)
+			m.orders[i].Status = t.To
+			m.transitions = append(m.transitions, *t)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (m *mockOrderRepo) ListTransitions(_ context.Context, orderID string) ([]StatusTransition, error) {
+	var out []StatusTransition
+	for _, t := range m.transitions {
+		if t.OrderID == orderID {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+type mockTransactor struct {
+	calls      int
+	rolledBack bool
+}
+
+func (m *mockTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	err := fn(ctx)
+	m.rolledBack = err != nil
+	return err
+}
+
 // --- Helpers ---
 
 func newTestProduct(id, name string, price decimal.Decimal) product.Product {
@@ -96,7 +187,7 @@ func newProductRepo(products ...product.Product) *mockProductRepo {
 // --- Tests ---
 
 func TestPlaceOrder_EmptyItems(t *testing.T) {
-	svc := NewService(newProductRepo(), &mockCouponValidator{}, &mockOrderRepo{})
+	svc := NewService(ServiceConfig{}, newProductRepo(), &mockCouponValidator{}, &mockOrderRepo{}, &mockTransactor{})
 
 	_, err := svc.PlaceOrder(context.Background(), PlaceOrderRequest{})
 	require.ErrorIs(t, err, ErrEmptyItems)
@@ -104,7 +195,7 @@ func TestPlaceOrder_EmptyItems(t *testing.T) {
 
 func TestPlaceOrder_InvalidQuantity(t *testing.T) {
 	p1 := newTestProduct("p1", "Widget", decimal.NewFromInt(10))
-	svc := NewService(newProductRepo(p1), &mockCouponValidator{}, &mockOrderRepo{})
+	svc := NewService(ServiceConfig{}, newProductRepo(p1), &mockCouponValidator{}, &mockOrderRepo{}, &mockTransactor{})
 
 	_, err := svc.PlaceOrder(context.Background(), PlaceOrderRequest{
 		Items: []OrderItem{{ProductID: "p1", Quantity: 0}},
@@ -116,7 +207,7 @@ func TestPlaceOrder_InvalidQuantity(t *testing.T) {
 }
 
 func TestPlaceOrder_ProductNotFound(t *testing.T) {
-	svc := NewService(newProductRepo(), &mockCouponValidator{}, &mockOrderRepo{})
+	svc := NewService(ServiceConfig{}, newProductRepo(), &mockCouponValidator{}, &mockOrderRepo{}, &mockTransactor{})
 
 	_, err := svc.PlaceOrder(context.Background(), PlaceOrderRequest{
 		Items: []OrderItem{{ProductID: "missing", Quantity: 1}},
@@ -130,7 +221,7 @@ func TestPlaceOrder_ProductNotFound(t *testing.T) {
 func TestPlaceOrder_NoCoupon(t *testing.T) {
 	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
 	p2 := newTestProduct("p2", "Gadget", decimal.RequireFromString("20.00"))
-	svc := NewService(newProductRepo(p1, p2), &mockCouponValidator{}, &mockOrderRepo{})
+	svc := NewService(ServiceConfig{}, newProductRepo(p1, p2), &mockCouponValidator{}, &mockOrderRepo{}, &mockTransactor{})
 
 	result, err := svc.PlaceOrder(context.Background(), PlaceOrderRequest{
 		Items: []OrderItem{
@@ -154,29 +245,226 @@ func TestPlaceOrder_WithCoupon(t *testing.T) {
 			Description: "$5 off",
 		},
 	}
-	svc := NewService(newProductRepo(p1, p2), cv, &mockOrderRepo{})
+	svc := NewService(ServiceConfig{}, newProductRepo(p1, p2), cv, &mockOrderRepo{}, &mockTransactor{})
 
 	result, err := svc.PlaceOrder(context.Background(), PlaceOrderRequest{
 		Items: []OrderItem{
 			{ProductID: "p1", Quantity: 2},
 			{ProductID: "p2", Quantity: 1},
 		},
-		CouponCode: "SAVE5",
+		CouponCodes: []string{"SAVE5"},
 	})
 
 	require.NoError(t, err)
 	assert.True(t, decimal.RequireFromString("35.00").Equal(result.Order.Total))
 	assert.True(t, decimal.RequireFromString("5.00").Equal(result.Order.Discounts))
+
+	// Coupons see each line's product and category for targeting.
+	require.Len(t, cv.lastItems, 2)
+	assert.Equal(t, "p1", cv.lastItems[0].ProductID)
+	assert.Equal(t, "test", cv.lastItems[0].Category)
+	assert.Equal(t, 2, cv.lastItems[0].Quantity)
+}
+
+func TestPlaceOrder_StackedCoupons(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
+	cv := &mockCouponValidator{
+		discount: &coupon.Discount{
+			Amount:      decimal.RequireFromString("7.00"),
+			Description: "10% off; $5 off",
+			Coupons: []coupon.Applied{
+				{Code: "TENOFF", Description: "10% off", Amount: decimal.RequireFromString("2.00")},
+				{Code: "FIVEOFF", Description: "$5 off", Amount: decimal.RequireFromString("5.00")},
+			},
+		},
+	}
+	svc := NewService(ServiceConfig{}, newProductRepo(p1), cv, &mockOrderRepo{}, &mockTransactor{})
+
+	result, err := svc.PlaceOrder(context.Background(), PlaceOrderRequest{
+		Items:       []OrderItem{{ProductID: "p1", Quantity: 2}},
+		CouponCodes: []string{"fiveoff", "tenoff"},
+		Actor:       "key-1",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"fiveoff", "tenoff"}, cv.lastReq.Codes)
+	assert.Equal(t, "key-1", cv.lastReq.Customer)
+	assert.Equal(t, result.Order.ID, cv.lastReq.OrderID)
+	assert.Equal(t, "fiveoff", result.Order.CouponCode)
+	require.Len(t, result.Order.Coupons, 2)
+	assert.Equal(t, "TENOFF", result.Order.Coupons[0].Code)
+	assert.True(t, decimal.RequireFromString("2.00").Equal(result.Order.Coupons[0].Discount))
+	assert.Equal(t, "FIVEOFF", result.Order.Coupons[1].Code)
+	assert.True(t, decimal.RequireFromString("13.00").Equal(result.Order.Total))
+}
+
+func TestPlaceOrder_TargetedCouponDiscountsOnlyTargetedLines(t *testing.T) {
+	p1 := newTestProduct("p1", "Waffle", decimal.RequireFromString("10.00"))
+	p2 := newTestProduct("p2", "Coffee", decimal.RequireFromString("5.00"))
+	cv := &mockCouponValidator{
+		discount: &coupon.Discount{
+			Amount:      decimal.RequireFromString("3.00"),
+			Description: "$3 off waffles",
+			Coupons: []coupon.Applied{
+				{Code: "WAFFLE3", Description: "$3 off waffles", Amount: decimal.RequireFromString("3.00"), Items: []int{0}},
+			},
+		},
+	}
+	svc := NewService(ServiceConfig{}, newProductRepo(p1, p2), cv, &mockOrderRepo{}, &mockTransactor{})
+
+	result, err := svc.PlaceOrder(context.Background(), PlaceOrderRequest{
+		Items:       []OrderItem{{ProductID: "p1", Quantity: 1}, {ProductID: "p2", Quantity: 2}},
+		CouponCodes: []string{"WAFFLE3"},
+	})
+	require.NoError(t, err)
+
+	items := result.Order.Items
+	require.Len(t, items, 2)
+	assert.True(t, decimal.RequireFromString("3.00").Equal(items[0].Discount), "got %s", items[0].Discount)
+	assert.True(t, items[1].Discount.IsZero(), "got %s", items[1].Discount)
+	assert.True(t, decimal.RequireFromString("17.00").Equal(result.Order.Total), "got %s", result.Order.Total)
+}
+
+func TestPlaceOrder_CouponCodeLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		codes   []string
+		wantErr error
+	}{
+		{
+			name:    "too many codes",
+			codes:   []string{"A1", "A2", "A3", "A4", "A5", "A6"},
+			wantErr: ErrTooManyCoupons,
+		},
+		{
+			name:    "same code twice",
+			codes:   []string{"SAVE5", "save5"},
+			wantErr: ErrDuplicateCoupon,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p1 := newTestProduct("p1", "Widget", decimal.NewFromInt(10))
+			cv := &mockCouponValidator{}
+			svc := NewService(ServiceConfig{}, newProductRepo(p1), cv, &mockOrderRepo{}, &mockTransactor{})
+			items := []OrderItem{{ProductID: "p1", Quantity: 1}}
+
+			_, err := svc.PlaceOrder(context.Background(), PlaceOrderRequest{Items: items, CouponCodes: tt.codes})
+			require.ErrorIs(t, err, tt.wantErr)
+
+			_, err = svc.Quote(context.Background(), QuoteRequest{Items: items, CouponCodes: tt.codes})
+			require.ErrorIs(t, err, tt.wantErr)
+
+			assert.Zero(t, cv.calls)
+			assert.Zero(t, cv.previewCalls)
+		})
+	}
+}
+
+func TestPlaceOrder_SnapshotsLineItems(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
+	p2 := newTestProduct("p2", "Gadget", decimal.RequireFromString("20.00"))
+	cv := &mockCouponValidator{
+		discount: &coupon.Discount{
+			Amount:      decimal.RequireFromString("5.00"),
+			Description: "$5 off",
+		},
+	}
+	orders := &mockOrderRepo{}
+	svc := NewService(ServiceConfig{}, newProductRepo(p1, p2), cv, orders, &mockTransactor{})
+
+	result, err := svc.PlaceOrder(context.Background(), PlaceOrderRequest{
+		Items: []OrderItem{
+			{ProductID: "p1", Quantity: 2},
+			{ProductID: "p2", Quantity: 1},
+		},
+		CouponCodes: []string{"SAVE5"},
+	})
+	require.NoError(t, err)
+	assert.Same(t, result.Order, orders.lastOrder)
+
+	items := result.Order.Items
+	require.Len(t, items, 2)
+	assert.Equal(t, "Widget", items[0].Name)
+	assert.Equal(t, "test", items[0].Category)
+	assert.True(t, decimal.RequireFromString("10.00").Equal(items[0].UnitPrice))
+	assert.True(t, decimal.RequireFromString("20.00").Equal(items[0].Subtotal))
+	assert.True(t, decimal.RequireFromString("2.50").Equal(items[0].Discount))
+	assert.Equal(t, "Gadget", items[1].Name)
+	assert.True(t, decimal.RequireFromString("20.00").Equal(items[1].Subtotal))
+	assert.True(t, decimal.RequireFromString("2.50").Equal(items[1].Discount))
+}
+
+func TestAllocateDiscount(t *testing.T) {
+	tests := []struct {
+		name      string
+		subtotals []string
+		discount  string
+		want      []string
+	}{
+		{
+			name:      "even split",
+			subtotals: []string{"10.00", "10.00"},
+			discount:  "4.00",
+			want:      []string{"2.00", "2.00"},
+		},
+		{
+			name:      "leftover cent goes to largest remainder",
+			subtotals: []string{"10.00", "10.00", "10.00"},
+			discount:  "1.00",
+			want:      []string{"0.34", "0.33", "0.33"},
+		},
+		{
+			name:      "proportional to subtotal",
+			subtotals: []string{"6.50", "13.00"},
+			discount:  "3.51",
+			want:      []string{"1.17", "2.34"},
+		},
+		{
+			name:      "no discount",
+			subtotals: []string{"6.50"},
+			discount:  "0",
+			want:      []string{"0"},
+		},
+		{
+			name:      "zero subtotal",
+			subtotals: []string{"0"},
+			discount:  "5.00",
+			want:      []string{"0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]OrderItem, len(tt.subtotals))
+			for i, s := range tt.subtotals {
+				items[i].Subtotal = decimal.RequireFromString(s)
+			}
+
+			allocateDiscount(items, decimal.RequireFromString(tt.discount))
+
+			sum := decimal.Zero
+			for i, want := range tt.want {
+				assert.True(t, decimal.RequireFromString(want).Equal(items[i].Discount),
+					"line %d: want %s, got %s", i, want, items[i].Discount)
+				sum = sum.Add(items[i].Discount)
+			}
+			if decimal.RequireFromString(tt.subtotals[0]).IsPositive() {
+				assert.True(t, decimal.RequireFromString(tt.discount).Equal(sum))
+			}
+		})
+	}
 }
 
 func TestPlaceOrder_InvalidCoupon(t *testing.T) {
 	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
 	cv := &mockCouponValidator{err: coupon.ErrInvalidCoupon}
-	svc := NewService(newProductRepo(p1), cv, &mockOrderRepo{})
+	svc := NewService(ServiceConfig{}, newProductRepo(p1), cv, &mockOrderRepo{}, &mockTransactor{})
 
 	_, err := svc.PlaceOrder(context.Background(), PlaceOrderRequest{
-		Items:      []OrderItem{{ProductID: "p1", Quantity: 1}},
-		CouponCode: "BOGUS",
+		Items:       []OrderItem{{ProductID: "p1", Quantity: 1}},
+		CouponCodes: []string{"BOGUS"},
 	})
 
 	require.Error(t, err)
@@ -191,24 +479,28 @@ func TestPlaceOrder_DiscountFlooredAtZero(t *testing.T) {
 			Description: "huge discount",
 		},
 	}
-	svc := NewService(newProductRepo(p1), cv, &mockOrderRepo{})
+	svc := NewService(ServiceConfig{}, newProductRepo(p1), cv, &mockOrderRepo{}, &mockTransactor{})
 
 	result, err := svc.PlaceOrder(context.Background(), PlaceOrderRequest{
-		Items:      []OrderItem{{ProductID: "p1", Quantity: 1}},
-		CouponCode: "HUGE",
+		Items:       []OrderItem{{ProductID: "p1", Quantity: 1}},
+		CouponCodes: []string{"HUGE"},
 	})
 
 	require.NoError(t, err)
 	assert.True(t, decimal.Zero.Equal(result.Order.Total))
 	assert.True(t, decimal.RequireFromString("999.00").Equal(result.Order.Discounts))
+	// The line allocation never exceeds what was actually charged.
+	assert.True(t, decimal.RequireFromString("10.00").Equal(result.Order.Items[0].Discount))
 }
 
 func TestPlaceOrder_OrderCreateError(t *testing.T) {
 	p1 := newTestProduct("p1", "Widget", decimal.NewFromInt(10))
 	svc := NewService(
+		ServiceConfig{},
 		newProductRepo(p1),
 		&mockCouponValidator{},
 		&mockOrderRepo{err: errors.New("db write failed")},
+		&mockTransactor{},
 	)
 
 	_, err := svc.PlaceOrder(context.Background(), PlaceOrderRequest{
@@ -218,3 +510,431 @@ func TestPlaceOrder_OrderCreateError(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "create order")
 }
+
+func TestPlaceOrder_CouponAndCreateShareTransaction(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.NewFromInt(10))
+	cv := &mockCouponValidator{
+		discount: &coupon.Discount{Amount: decimal.NewFromInt(1), Description: "$1 off"},
+	}
+	orders := &mockOrderRepo{err: errors.New("db write failed")}
+	tx := &mockTransactor{}
+	svc := NewService(ServiceConfig{}, newProductRepo(p1), cv, orders, tx)
+
+	_, err := svc.PlaceOrder(context.Background(), PlaceOrderRequest{
+		Items:       []OrderItem{{ProductID: "p1", Quantity: 1}},
+		CouponCodes: []string{"SAVE1"},
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, tx.calls)
+	assert.True(t, tx.rolledBack, "failed insert must roll back the coupon redemption")
+	assert.Equal(t, 1, cv.calls)
+}
+
+func TestPlaceOrder_ValidationErrorsSkipTransaction(t *testing.T) {
+	tx := &mockTransactor{}
+	svc := NewService(ServiceConfig{}, newProductRepo(), &mockCouponValidator{}, &mockOrderRepo{}, tx)
+
+	_, err := svc.PlaceOrder(context.Background(), PlaceOrderRequest{
+		Items: []OrderItem{{ProductID: "missing", Quantity: 1}},
+	})
+
+	require.Error(t, err)
+	assert.Zero(t, tx.calls)
+}
+
+func TestQuote(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
+	p2 := newTestProduct("p2", "Gadget", decimal.RequireFromString("20.00"))
+	items := []OrderItem{
+		{ProductID: "p1", Quantity: 2},
+		{ProductID: "p2", Quantity: 1},
+	}
+
+	t.Run("applies coupon without redeeming or persisting", func(t *testing.T) {
+		cv := &mockCouponValidator{discount: &coupon.Discount{
+			Amount:      decimal.RequireFromString("7.20"),
+			Description: "18% off",
+		}}
+		orders := &mockOrderRepo{}
+		tx := &mockTransactor{}
+		svc := NewService(ServiceConfig{}, newProductRepo(p1, p2), cv, orders, tx)
+
+		q, err := svc.Quote(context.Background(), QuoteRequest{Items: items, CouponCodes: []string{"HAPPYHOURS"}})
+		require.NoError(t, err)
+
+		assert.True(t, decimal.RequireFromString("40.00").Equal(q.Subtotal))
+		assert.True(t, decimal.RequireFromString("7.20").Equal(q.Discount))
+		assert.True(t, decimal.RequireFromString("32.80").Equal(q.Total))
+		assert.Equal(t, "18% off", q.CouponDescription)
+		assert.NoError(t, q.CouponError)
+		require.Len(t, q.Items, 2)
+		assert.True(t, decimal.RequireFromString("3.60").Equal(q.Items[0].Discount))
+		require.Len(t, q.Products, 2)
+
+		assert.Equal(t, 1, cv.previewCalls)
+		assert.Zero(t, cv.calls, "Validate must not be called")
+		assert.Zero(t, tx.calls, "no transaction should be opened")
+		assert.Nil(t, orders.lastOrder, "no order should be created")
+	})
+
+	t.Run("rejected coupon is reported, not returned", func(t *testing.T) {
+		cv := &mockCouponValidator{err: coupon.ErrCouponExpired}
+		svc := NewService(ServiceConfig{}, newProductRepo(p1, p2), cv, &mockOrderRepo{}, &mockTransactor{})
+
+		q, err := svc.Quote(context.Background(), QuoteRequest{Items: items, CouponCodes: []string{"OLD"}})
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, q.CouponError, coupon.ErrCouponExpired)
+		assert.True(t, decimal.Zero.Equal(q.Discount))
+		assert.True(t, decimal.RequireFromString("40.00").Equal(q.Total))
+		assert.Empty(t, q.CouponDescription)
+	})
+
+	t.Run("lookup failures are errors", func(t *testing.T) {
+		cv := &mockCouponValidator{err: errors.New("db down")}
+		svc := NewService(ServiceConfig{}, newProductRepo(p1, p2), cv, &mockOrderRepo{}, &mockTransactor{})
+
+		_, err := svc.Quote(context.Background(), QuoteRequest{Items: items, CouponCodes: []string{"ANY"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "preview coupon")
+	})
+
+	t.Run("item validation matches PlaceOrder", func(t *testing.T) {
+		svc := NewService(ServiceConfig{}, newProductRepo(p1), &mockCouponValidator{}, &mockOrderRepo{}, &mockTransactor{})
+
+		_, err := svc.Quote(context.Background(), QuoteRequest{})
+		require.ErrorIs(t, err, ErrEmptyItems)
+
+		_, err = svc.Quote(context.Background(), QuoteRequest{Items: []OrderItem{{ProductID: "nope", Quantity: 1}}})
+		var pnfErr *ProductNotFoundError
+		require.ErrorAs(t, err, &pnfErr)
+	})
+}
+
+func TestGetOrder(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.NewFromInt(10))
+	p2 := newTestProduct("p2", "Gadget", decimal.NewFromInt(20))
+	orders := &mockOrderRepo{orders: []Order{{
+		ID: "o1",
+		Items: []OrderItem{
+			{ProductID: "p2", Quantity: 1},
+			{ProductID: "p1", Quantity: 3},
+			{ProductID: "gone", Quantity: 1},
+		},
+		Total: decimal.NewFromInt(50),
+	}}}
+	svc := NewService(ServiceConfig{}, newProductRepo(p1, p2), &mockCouponValidator{}, orders, &mockTransactor{})
+
+	t.Run("found hydrates products in item order", func(t *testing.T) {
+		got, err := svc.GetOrder(context.Background(), "o1")
+		require.NoError(t, err)
+		assert.Equal(t, "o1", got.Order.ID)
+		require.Len(t, got.Products, 2, "deleted products are omitted")
+		assert.Equal(t, "p2", got.Products[0].ID)
+		assert.Equal(t, "p1", got.Products[1].ID)
+	})
+
+	t.Run("missing returns ErrNotFound", func(t *testing.T) {
+		_, err := svc.GetOrder(context.Background(), "nope")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestPlaceOrder_StartsPlaced(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
+	orders := &mockOrderRepo{}
+	svc := NewService(ServiceConfig{}, newProductRepo(p1), &mockCouponValidator{}, orders, &mockTransactor{})
+
+	result, err := svc.PlaceOrder(context.Background(), PlaceOrderRequest{
+		Items: []OrderItem{{ProductID: "p1", Quantity: 1}},
+		Actor: "key-1",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusPlaced, result.Order.Status)
+	assert.Equal(t, "key-1", orders.lastActor)
+}
+
+func TestUpdateStatus(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
+
+	tests := []struct {
+		name      string
+		current   Status
+		next      Status
+		statusErr error
+		wantErr   error
+		wantIT    bool
+	}{
+		{name: "allowed transition", current: StatusPlaced, next: StatusAccepted},
+		{name: "skipping a step is rejected", current: StatusPlaced, next: StatusReady, wantIT: true},
+		{name: "terminal status is final", current: StatusCompleted, next: StatusCancelled, wantIT: true},
+		{name: "unknown status", current: StatusPlaced, next: Status("lost"), wantErr: ErrInvalidStatus},
+		{
+			name:      "concurrent change",
+			current:   StatusPlaced,
+			next:      StatusAccepted,
+			statusErr: ErrStatusConflict,
+			wantErr:   ErrStatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := &mockOrderRepo{
+				orders:    []Order{{ID: "o1", Items: []OrderItem{{ProductID: "p1", Quantity: 1}}, Status: tt.current}},
+				statusErr: tt.statusErr,
+			}
+			tx := &mockTransactor{}
+			svc := NewService(ServiceConfig{}, newProductRepo(p1), &mockCouponValidator{}, orders, tx)
+
+			details, err := svc.UpdateStatus(context.Background(), UpdateStatusRequest{
+				OrderID: "o1",
+				Status:  tt.next,
+				Actor:   "key-1",
+				Reason:  "kitchen busy",
+			})
+
+			switch {
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr)
+			case tt.wantIT:
+				var itErr *InvalidTransitionError
+				require.ErrorAs(t, err, &itErr)
+				assert.Equal(t, tt.current, itErr.From)
+				assert.Equal(t, tt.next, itErr.To)
+				assert.True(t, tx.rolledBack)
+				assert.Empty(t, orders.transitions)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.next, details.Order.Status)
+				assert.False(t, details.Order.StatusUpdatedAt.IsZero())
+				require.Len(t, details.History, 1)
+				assert.Equal(t, StatusTransition{
+					OrderID: "o1",
+					From:    tt.current,
+					To:      tt.next,
+					Actor:   "key-1",
+					Reason:  "kitchen busy",
+					At:      details.Order.StatusUpdatedAt,
+				}, details.History[0])
+				require.Len(t, details.Products, 1)
+			}
+		})
+	}
+}
+
+func TestUpdateStatus_NotFound(t *testing.T) {
+	svc := NewService(ServiceConfig{}, newProductRepo(), &mockCouponValidator{}, &mockOrderRepo{}, &mockTransactor{})
+
+	_, err := svc.UpdateStatus(context.Background(), UpdateStatusRequest{OrderID: "missing", Status: StatusAccepted})
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestCancel(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		status       Status
+		couponCode   string
+		age          time.Duration
+		wantErr      error
+		wantIT       bool
+		wantReleased []string
+	}{
+		{
+			name:         "within window releases coupon",
+			status:       StatusAccepted,
+			couponCode:   "happyhours",
+			age:          5 * time.Minute,
+			wantReleased: []string{"happyhours"},
+		},
+		{
+			name:   "without coupon",
+			status: StatusPlaced,
+			age:    time.Minute,
+		},
+		{
+			name:       "after window",
+			status:     StatusPlaced,
+			couponCode: "HAPPYHOURS",
+			age:        16 * time.Minute,
+			wantErr:    ErrCancelWindowExpired,
+		},
+		{
+			name:       "already cancelled",
+			status:     StatusCancelled,
+			couponCode: "HAPPYHOURS",
+			age:        time.Minute,
+			wantIT:     true,
+		},
+		{
+			name:   "too late in the lifecycle",
+			status: StatusReady,
+			age:    time.Minute,
+			wantIT: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := &mockOrderRepo{orders: []Order{{
+				ID:         "o1",
+				Items:      []OrderItem{{ProductID: "p1", Quantity: 1}},
+				CouponCode: tt.couponCode,
+				Status:     tt.status,
+				CreatedAt:  now.Add(-tt.age),
+			}}}
+			if tt.couponCode != "" {
+				orders.orders[0].Coupons = []AppliedCoupon{{Code: tt.couponCode}}
+			}
+			cv := &mockCouponValidator{}
+			svc := NewService(ServiceConfig{CancelWindow: 15 * time.Minute},
+				newProductRepo(p1), cv, orders, &mockTransactor{})
+			svc.now = func() time.Time { return now }
+
+			details, err := svc.Cancel(context.Background(), CancelRequest{
+				OrderID: "o1",
+				Actor:   "key-1",
+				Reason:  "changed my mind",
+			})
+
+			switch {
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr)
+			case tt.wantIT:
+				var itErr *InvalidTransitionError
+				require.ErrorAs(t, err, &itErr)
+				assert.Equal(t, StatusCancelled, itErr.To)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, StatusCancelled, details.Order.Status)
+				require.Len(t, details.History, 1)
+				assert.Equal(t, "changed my mind", details.History[0].Reason)
+			}
+			assert.Equal(t, tt.wantReleased, cv.released)
+		})
+	}
+}
+
+func TestCancel_NoWindow(t *testing.T) {
+	orders := &mockOrderRepo{orders: []Order{{
+		ID:        "o1",
+		Status:    StatusPreparing,
+		CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}}}
+	svc := NewService(ServiceConfig{}, newProductRepo(), &mockCouponValidator{}, orders, &mockTransactor{})
+
+	details, err := svc.Cancel(context.Background(), CancelRequest{OrderID: "o1"})
+	require.NoError(t, err)
+	assert.Equal(t, StatusCancelled, details.Order.Status)
+}
+
+func TestUpdateStatus_ReleasesCouponOnRejectAndCancel(t *testing.T) {
+	for _, next := range []Status{StatusRejected, StatusCancelled} {
+		t.Run(string(next), func(t *testing.T) {
+			orders := &mockOrderRepo{orders: []Order{{
+				ID:         "o1",
+				CouponCode: "HAPPYHOURS",
+				Coupons:    []AppliedCoupon{{Code: "HAPPYHOURS"}},
+				Status:     StatusPlaced,
+				// Far outside any cancel window: operators are not bound by it.
+				CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			}}}
+			cv := &mockCouponValidator{}
+			svc := NewService(ServiceConfig{CancelWindow: time.Minute},
+				newProductRepo(), cv, orders, &mockTransactor{})
+
+			_, err := svc.UpdateStatus(context.Background(), UpdateStatusRequest{OrderID: "o1", Status: next})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"HAPPYHOURS"}, cv.released)
+		})
+	}
+}
+
+func TestUpdateStatus_KeepsCouponOnProgress(t *testing.T) {
+	orders := &mockOrderRepo{orders: []Order{{
+		ID:         "o1",
+		CouponCode: "HAPPYHOURS",
+		Coupons:    []AppliedCoupon{{Code: "HAPPYHOURS"}},
+		Status:     StatusPlaced,
+	}}}
+	cv := &mockCouponValidator{}
+	svc := NewService(ServiceConfig{}, newProductRepo(), cv, orders, &mockTransactor{})
+
+	_, err := svc.UpdateStatus(context.Background(), UpdateStatusRequest{OrderID: "o1", Status: StatusAccepted})
+	require.NoError(t, err)
+	assert.Empty(t, cv.released)
+}
+
+func TestListOrders_Pagination(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.NewFromInt(10))
+	base := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	var stored []Order
+	for i := range 5 {
+		stored = append(stored, Order{
+			ID:        fmt.Sprintf("o%d", 5-i),
+			Items:     []OrderItem{{ProductID: "p1", Quantity: 1}},
+			CreatedAt: base.Add(-time.Duration(i) * time.Minute),
+		})
+	}
+	orders := &mockOrderRepo{orders: stored}
+	svc := NewService(ServiceConfig{}, newProductRepo(p1), &mockCouponValidator{}, orders, &mockTransactor{})
+
+	page1, err := svc.ListOrders(context.Background(), ListOrdersRequest{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page1.Orders, 2)
+	assert.Equal(t, "o5", page1.Orders[0].Order.ID)
+	assert.Equal(t, "o4", page1.Orders[1].Order.ID)
+	require.NotEmpty(t, page1.NextCursor)
+	assert.Len(t, page1.Orders[0].Products, 1)
+
+	page2, err := svc.ListOrders(context.Background(), ListOrdersRequest{Limit: 2, Cursor: page1.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page2.Orders, 2)
+	assert.Equal(t, "o3", page2.Orders[0].Order.ID)
+
+	page3, err := svc.ListOrders(context.Background(), ListOrdersRequest{Limit: 2, Cursor: page2.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page3.Orders, 1)
+	assert.Equal(t, "o1", page3.Orders[0].Order.ID)
+	assert.Empty(t, page3.NextCursor, "last page has no cursor")
+}
+
+func TestListOrders_Validation(t *testing.T) {
+	orders := &mockOrderRepo{}
+	svc := NewService(ServiceConfig{}, newProductRepo(), &mockCouponValidator{}, orders, &mockTransactor{})
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	_, err := svc.ListOrders(context.Background(), ListOrdersRequest{Cursor: "not a cursor!"})
+	require.ErrorIs(t, err, ErrInvalidCursor)
+
+	_, err = svc.ListOrders(context.Background(), ListOrdersRequest{From: &now, To: &earlier})
+	require.ErrorIs(t, err, ErrInvalidDateRange)
+
+	_, err = svc.ListOrders(context.Background(), ListOrdersRequest{Limit: 1000, CouponCode: "SAVE"})
+	require.NoError(t, err)
+	assert.Equal(t, MaxListLimit+1, orders.lastFilter.Limit)
+	assert.Equal(t, "SAVE", orders.lastFilter.CouponCode)
+
+	_, err = svc.ListOrders(context.Background(), ListOrdersRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultListLimit+1, orders.lastFilter.Limit)
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := Cursor{
+		CreatedAt: time.Date(2025, 6, 15, 12, 0, 0, 123456000, time.UTC),
+		ID:        "7d6f0d7e-8a0b-4c3e-9d7a-1f2e3d4c5b6a",
+	}
+
+	got, err := DecodeCursor(c.Encode())
+	require.NoError(t, err)
+	assert.True(t, c.CreatedAt.Equal(got.CreatedAt))
+	assert.Equal(t, c.ID, got.ID)
+}
@@ -0,0 +1,82 @@
+package order
+
+import (
+	"fmt"
+	"time"
+)
+
+// Status is the lifecycle state of an order.
+type Status string
+
+const (
+	// StatusPlaced is the initial state of every new order.
+	StatusPlaced Status = "placed"
+	// StatusAccepted means the store has taken the order on.
+	StatusAccepted Status = "accepted"
+	// StatusPreparing means the order is being made.
+	StatusPreparing Status = "preparing"
+	// StatusReady means the order is waiting for pickup or delivery.
+	StatusReady Status = "ready"
+	// StatusCompleted means the order was handed over. Terminal.
+	StatusCompleted Status = "completed"
+	// StatusCancelled means the order was called off before completion. Terminal.
+	StatusCancelled Status = "cancelled"
+	// StatusRejected means the store declined the order. Terminal.
+	StatusRejected Status = "rejected"
+)
+
+// transitions lists the statuses reachable from each non-terminal status.
+var transitions = map[Status][]Status{
+	StatusPlaced:    {StatusAccepted, StatusRejected, StatusCancelled},
+	StatusAccepted:  {StatusPreparing, StatusCancelled},
+	StatusPreparing: {StatusReady, StatusCancelled},
+	StatusReady:     {StatusCompleted},
+}
+
+// Valid reports whether s is a known status.
+func (s Status) Valid() bool {
+	switch s {
+	case StatusPlaced, StatusAccepted, StatusPreparing, StatusReady,
+		StatusCompleted, StatusCancelled, StatusRejected:
+		return true
+	}
+	return false
+}
+
+// Terminal reports whether no further transitions are possible from s.
+func (s Status) Terminal() bool {
+	return s.Valid() && len(transitions[s]) == 0
+}
+
+// CanTransitionTo reports whether moving from s to next is allowed.
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, to := range transitions[s] {
+		if to == next {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusTransition is one recorded change of an order's status.
+type StatusTransition struct {
+	OrderID string
+	// From is empty for the initial transition into StatusPlaced.
+	From Status
+	To   Status
+	// Actor identifies who made the change, e.g. an API key ID.
+	Actor  string
+	Reason string
+	At     time.Time
+}
+
+// InvalidTransitionError indicates a status change the state machine does
+// not allow.
+type InvalidTransitionError struct {
+	From Status
+	To   Status
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("cannot change order status from %s to %s", e.From, e.To)
+}
@@ -0,0 +1,55 @@
+package order
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from Status
+		to   Status
+		want bool
+	}{
+		{StatusPlaced, StatusAccepted, true},
+		{StatusPlaced, StatusRejected, true},
+		{StatusPlaced, StatusCancelled, true},
+		{StatusPlaced, StatusPreparing, false},
+		{StatusPlaced, StatusCompleted, false},
+		{StatusAccepted, StatusPreparing, true},
+		{StatusAccepted, StatusCancelled, true},
+		{StatusAccepted, StatusRejected, false},
+		{StatusPreparing, StatusReady, true},
+		{StatusPreparing, StatusCancelled, true},
+		{StatusPreparing, StatusAccepted, false},
+		{StatusReady, StatusCompleted, true},
+		{StatusReady, StatusCancelled, false},
+		{StatusCompleted, StatusPlaced, false},
+		{StatusCancelled, StatusAccepted, false},
+		{StatusRejected, StatusAccepted, false},
+		{StatusPlaced, StatusPlaced, false},
+		{Status("bogus"), StatusAccepted, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestStatus_Terminal(t *testing.T) {
+	for _, s := range []Status{StatusCompleted, StatusCancelled, StatusRejected} {
+		assert.True(t, s.Terminal(), s)
+	}
+	for _, s := range []Status{StatusPlaced, StatusAccepted, StatusPreparing, StatusReady, Status("bogus")} {
+		assert.False(t, s.Terminal(), s)
+	}
+}
+
+func TestStatus_Valid(t *testing.T) {
+	assert.True(t, StatusPreparing.Valid())
+	assert.False(t, Status("").Valid())
+	assert.False(t, Status("PLACED").Valid())
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/sdk/zctx"
+	"go.uber.org/zap"
+
+	"github.com/xenking/oolio-kart-challenge/gen/oas"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/audit"
+	"github.com/xenking/oolio-kart-challenge/pkg/httpmiddleware"
+)
+
+// auditedOperations maps each state-changing operation to the type of
+// entity it acts on. Operation names are shared by the v1 and v2 servers.
+// The entity ID is the first path parameter, or set by the handler with
+// setAuditTarget for operations that create it.
+var auditedOperations = map[string]string{
+	"PlaceOrder":        "order",
+	"UpdateOrderStatus": "order",
+	"CancelOrder":       "order",
+	"CreateCart":        "cart",
+	"SetCartItem":       "cart",
+	"RemoveCartItem":    "cart",
+	"AttachCartCoupon":  "cart",
+	"DetachCartCoupon":  "cart",
+	"CheckoutCart":      "cart",
+	"CreateCoupon":      "coupon",
+	"UpdateCoupon":      "coupon",
+	"DeactivateCoupon":  "coupon",
+}
+
+type auditEntryKey struct{}
+
+// auditEntry collects what AuditMutations can only learn from inside the
+// ogen server: the authenticated actor and the ID of a created entity.
+type auditEntry struct {
+	actor    string
+	targetID string
+}
+
+// setAuditActor records the authenticated key for the audit event of the
+// current request, if it is audited.
+func setAuditActor(ctx context.Context, id string) {
+	if e, ok := ctx.Value(auditEntryKey{}).(*auditEntry); ok {
+		e.actor = id
+	}
+}
+
+// setAuditTarget records the ID of the entity the current request created.
+func setAuditTarget(ctx context.Context, id string) {
+	if e, ok := ctx.Value(auditEntryKey{}).(*auditEntry); ok {
+		e.targetID = id
+	}
+}
+
+// statusRecorder captures the status code written by the wrapped handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// AuditMutations returns middleware that records an audit event for every
+// request to an operation in auditedOperations once it has been answered.
+// Requests that never authenticated have no actor and are not recorded.
+// A failure to record is logged; the response has already been sent.
+func AuditMutations(events *audit.Service, find httpmiddleware.RouteFinder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, ok := find(r.Method, r.URL)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			targetType, ok := auditedOperations[route.Name()]
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			entry := &auditEntry{}
+			if args := route.Args(); len(args) > 0 {
+				entry.targetID = args[0]
+			}
+			rec := &statusRecorder{ResponseWriter: w}
+			ctx := context.WithValue(r.Context(), auditEntryKey{}, entry)
+			next.ServeHTTP(rec, r.WithContext(ctx))
+
+			if entry.actor == "" {
+				return
+			}
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			err := events.Record(context.WithoutCancel(ctx), &audit.Event{
+				Actor:      entry.actor,
+				Operation:  route.OperationID(),
+				RequestID:  httpmiddleware.RequestIDFromContext(ctx),
+				ClientIP:   httpmiddleware.ClientIPFromContext(ctx),
+				TargetType: targetType,
+				TargetID:   entry.targetID,
+				Outcome:    audit.OutcomeForStatus(rec.status),
+				StatusCode: rec.status,
+			})
+			if err != nil {
+				zctx.From(ctx).Error("Failed to record audit event",
+					zap.String("operationId", route.OperationID()), zap.Error(err))
+			}
+		})
+	}
+}
+
+// ListAuditEvents returns a page of audit events, newest first.
+func (h *Handler) ListAuditEvents(ctx context.Context, params oas.ListAuditEventsParams) (oas.ListAuditEventsRes, error) {
+	req := audit.ListRequest{
+		Actor:  params.Actor.Or(""),
+		Cursor: params.Cursor.Or(""),
+		Limit:  params.Limit.Or(audit.DefaultListLimit),
+	}
+	if from, ok := params.From.Get(); ok {
+		req.From = &from
+	}
+	if to, ok := params.To.Get(); ok {
+		req.To = &to
+	}
+
+	result, err := h.audit.List(ctx, req)
+	if err != nil {
+		if errors.Is(err, audit.ErrInvalidCursor) || errors.Is(err, audit.ErrInvalidDateRange) {
+			return &oas.ListAuditEventsBadRequest{
+				Code:    400,
+				Message: err.Error(),
+			}, nil
+		}
+		return nil, errors.Wrap(err, "list audit events")
+	}
+
+	out := &oas.AuditEventList{
+		Events: make([]oas.AuditEvent, len(result.Events)),
+	}
+	for i, e := range result.Events {
+		out.Events[i] = domainToOASAuditEvent(e)
+	}
+	if result.NextCursor != "" {
+		out.NextCursor = oas.NewOptString(result.NextCursor)
+	}
+	return out, nil
+}
+
+func domainToOASAuditEvent(e audit.Event) oas.AuditEvent {
+	resp := oas.AuditEvent{
+		ID:         e.ID,
+		Actor:      e.Actor,
+		Operation:  e.Operation,
+		Outcome:    oas.AuditEventOutcome(e.Outcome),
+		StatusCode: e.StatusCode,
+		CreatedAt:  e.CreatedAt,
+	}
+	if e.RequestID != "" {
+		resp.RequestId = oas.NewOptString(e.RequestID)
+	}
+	if e.ClientIP != "" {
+		resp.ClientIp = oas.NewOptString(e.ClientIP)
+	}
+	if e.TargetType != "" {
+		resp.TargetType = oas.NewOptAuditEventTargetType(oas.AuditEventTargetType(e.TargetType))
+	}
+	if e.TargetID != "" {
+		resp.TargetId = oas.NewOptString(e.TargetID)
+	}
+	return resp
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+
+	"github.com/xenking/oolio-kart-challenge/gen/oas"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/cart"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/order"
+)
+
+// CreateCart starts an empty cart owned by the calling API key.
+func (h *Handler) CreateCart(ctx context.Context) (oas.CreateCartRes, error) {
+	d, err := h.carts.Create(ctx, actorFromContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "create cart")
+	}
+	setAuditTarget(ctx, d.Cart.ID)
+	return h.domainToOASCart(d), nil
+}
+
+// GetCart returns a cart with its current pricing.
+func (h *Handler) GetCart(ctx context.Context, params oas.GetCartParams) (oas.GetCartRes, error) {
+	d, err := h.carts.Get(ctx, actorFromContext(ctx), params.CartId)
+	if err != nil {
+		code, msg, ok := cartErrorStatus(err)
+		switch {
+		case !ok:
+			return nil, errors.Wrap(err, "get cart")
+		case code == 404:
+			return &oas.GetCartNotFound{Code: code, Message: msg}, nil
+		default:
+			return &oas.GetCartUnprocessableEntity{Code: code, Message: msg}, nil
+		}
+	}
+	return h.domainToOASCart(d), nil
+}
+
+// SetCartItem adds a product to a cart or changes its quantity.
+func (h *Handler) SetCartItem(ctx context.Context, req *oas.CartItemUpdate, params oas.SetCartItemParams) (oas.SetCartItemRes, error) {
+	d, err := h.carts.SetItem(ctx, actorFromContext(ctx), params.CartId, params.ProductId, req.Quantity)
+	if err != nil {
+		code, msg, ok := cartErrorStatus(err)
+		switch {
+		case !ok:
+			return nil, errors.Wrap(err, "set cart item")
+		case code == 404:
+			return &oas.SetCartItemNotFound{Code: code, Message: msg}, nil
+		default:
+			return &oas.SetCartItemUnprocessableEntity{Code: code, Message: msg}, nil
+		}
+	}
+	return h.domainToOASCart(d), nil
+}
+
+// RemoveCartItem removes a product from a cart.
+func (h *Handler) RemoveCartItem(ctx context.Context, params oas.RemoveCartItemParams) (oas.RemoveCartItemRes, error) {
+	d, err := h.carts.RemoveItem(ctx, actorFromContext(ctx), params.CartId, params.ProductId)
+	if err != nil {
+		code, msg, ok := cartErrorStatus(err)
+		switch {
+		case !ok:
+			return nil, errors.Wrap(err, "remove cart item")
+		case code == 404:
+			return &oas.RemoveCartItemNotFound{Code: code, Message: msg}, nil
+		default:
+			return &oas.RemoveCartItemUnprocessableEntity{Code: code, Message: msg}, nil
+		}
+	}
+	return h.domainToOASCart(d), nil
+}
+
+// AttachCartCoupon sets the coupon code applied to a cart.
+func (h *Handler) AttachCartCoupon(ctx context.Context, req *oas.CartCoupon, params oas.AttachCartCouponParams) (oas.AttachCartCouponRes, error) {
+	if wait := h.couponRetryAfter(ctx, []string{req.Code}); wait > 0 {
+		return couponAttemptsExceeded(wait), nil
+	}
+	d, err := h.carts.AttachCoupon(ctx, actorFromContext(ctx), params.CartId, req.Code)
+	if err != nil {
+		code, msg, ok := cartErrorStatus(err)
+		switch {
+		case !ok:
+			return nil, errors.Wrap(err, "attach cart coupon")
+		case code == 404:
+			return &oas.AttachCartCouponNotFound{Code: code, Message: msg}, nil
+		default:
+			return &oas.AttachCartCouponUnprocessableEntity{Code: code, Message: msg}, nil
+		}
+	}
+	h.recordCouponResult(ctx, []string{req.Code}, d.Quote.CouponError)
+	return h.domainToOASCart(d), nil
+}
+
+// DetachCartCoupon removes the coupon code from a cart.
+func (h *Handler) DetachCartCoupon(ctx context.Context, params oas.DetachCartCouponParams) (oas.DetachCartCouponRes, error) {
+	d, err := h.carts.DetachCoupon(ctx, actorFromContext(ctx), params.CartId)
+	if err != nil {
+		code, msg, ok := cartErrorStatus(err)
+		switch {
+		case !ok:
+			return nil, errors.Wrap(err, "detach cart coupon")
+		case code == 404:
+			return &oas.DetachCartCouponNotFound{Code: code, Message: msg}, nil
+		default:
+			return &oas.DetachCartCouponUnprocessableEntity{Code: code, Message: msg}, nil
+		}
+	}
+	return h.domainToOASCart(d), nil
+}
+
+// CheckoutCart places an order for a cart's contents and deletes the cart.
+func (h *Handler) CheckoutCart(ctx context.Context, params oas.CheckoutCartParams) (oas.CheckoutCartRes, error) {
+	result, err := h.carts.Checkout(ctx, actorFromContext(ctx), params.CartId)
+	if err != nil {
+		if errors.Is(err, order.ErrEmptyItems) {
+			return &oas.CheckoutCartBadRequest{
+				Code:    400,
+				Message: err.Error(),
+			}, nil
+		}
+		if msg, ok := couponErrorMessage(err); ok {
+			return &oas.CheckoutCartUnprocessableEntity{
+				Code:    422,
+				Message: msg,
+			}, nil
+		}
+		code, msg, ok := cartErrorStatus(err)
+		switch {
+		case !ok:
+			return nil, errors.Wrap(err, "checkout cart")
+		case code == 404:
+			return &oas.CheckoutCartNotFound{Code: code, Message: msg}, nil
+		default:
+			return &oas.CheckoutCartUnprocessableEntity{Code: code, Message: msg}, nil
+		}
+	}
+	return h.domainToOASOrder(result.Order, result.Products), nil
+}
+
+// cartErrorStatus returns the HTTP status and message for errors shared by
+// all cart operations, and false for any other error.
+func cartErrorStatus(err error) (int32, string, bool) {
+	if errors.Is(err, cart.ErrNotFound) {
+		return 404, err.Error(), true
+	}
+
+	var iqErr *order.InvalidQuantityError
+	if errors.As(err, &iqErr) {
+		return 422, iqErr.Error(), true
+	}
+
+	var pnfErr *order.ProductNotFoundError
+	if errors.As(err, &pnfErr) {
+		return 422, pnfErr.Error(), true
+	}
+
+	return 0, "", false
+}
+
+// domainToOASCart converts a priced cart into the ogen response type.
+func (h *Handler) domainToOASCart(d *cart.Details) *oas.Cart {
+	c, q := d.Cart, d.Quote
+
+	respProducts := make([]oas.Product, len(q.Products))
+	for i, p := range q.Products {
+		respProducts[i] = h.domainToOASProduct(p)
+	}
+
+	resp := &oas.Cart{
+		ID:        c.ID,
+		Items:     domainToOASItems(q.Items),
+		Products:  respProducts,
+		Subtotal:  q.Subtotal.InexactFloat64(),
+		Discount:  q.Discount.InexactFloat64(),
+		Total:     q.Total.InexactFloat64(),
+		ExpiresAt: c.ExpiresAt,
+	}
+	if c.CouponCode != "" {
+		resp.CouponCode = oas.NewOptString(c.CouponCode)
+	}
+	if q.CouponDescription != "" {
+		resp.CouponDescription = oas.NewOptString(q.CouponDescription)
+	}
+	if q.CouponError != nil {
+		msg, _ := couponErrorMessage(q.CouponError)
+		resp.CouponError = oas.NewOptString(msg)
+	}
+	if !c.CreatedAt.IsZero() {
+		resp.CreatedAt = oas.NewOptDateTime(c.CreatedAt)
+	}
+	if !c.UpdatedAt.IsZero() {
+		resp.UpdatedAt = oas.NewOptDateTime(c.UpdatedAt)
+	}
+	return resp
+}
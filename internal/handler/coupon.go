@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/sdk/zctx"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+
+	"github.com/xenking/oolio-kart-challenge/gen/oas"
+	"github.com/xenking/oolio-kart-challenge/gen/oasv2"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/coupon"
+	"github.com/xenking/oolio-kart-challenge/pkg/httpmiddleware"
+)
+
+// couponAttemptsExceededMessage is the body of every 429 for coupon
+// attempts. It does not say which subject is blocked.
+const couponAttemptsExceededMessage = "too many invalid coupon codes, try again later"
+
+// couponSubjects returns who a coupon attempt is charged to: the API key
+// and the client IP, so neither rotating keys nor rotating addresses gets
+// around the limit.
+func couponSubjects(ctx context.Context) []string {
+	var subjects []string
+	if actor := actorFromContext(ctx); actor != "" {
+		subjects = append(subjects, "key:"+actor)
+	}
+	if ip := httpmiddleware.ClientIPFromContext(ctx); ip != "" {
+		subjects = append(subjects, "ip:"+ip)
+	}
+	return subjects
+}
+
+// couponRetryAfter returns how many seconds the caller must wait before
+// presenting codes, or 0 if it may go ahead. Requests without a code are
+// never held back, and neither are requests the guard can't check, so an
+// outage of a shared attempt store does not stop coupons being used.
+func (h *Handler) couponRetryAfter(ctx context.Context, codes []string) int {
+	if h.couponGuard == nil || len(codes) == 0 {
+		return 0
+	}
+	wait, err := h.couponGuard.Check(ctx, couponSubjects(ctx)...)
+	if err != nil {
+		zctx.From(ctx).Warn("Coupon attempt check failed, allowing request", zap.Error(err))
+		return 0
+	}
+	return int(math.Ceil(wait.Seconds()))
+}
+
+// recordCouponResult charges the rejected code to the caller if err
+// rejected one of codes. Lookup failures are not the caller's fault and are
+// not counted, and neither are a coupon the caller has already used or
+// coupons that can't be stacked, which are real codes.
+func (h *Handler) recordCouponResult(ctx context.Context, codes []string, err error) {
+	if h.couponGuard == nil || len(codes) == 0 || !coupon.IsRejection(err) ||
+		errors.Is(err, coupon.ErrCouponAlreadyUsed) || errors.Is(err, coupon.ErrCouponNotStackable) {
+		return
+	}
+	// Validation stops at the first rejected code, so a stack of guesses
+	// costs as much as guessing one code.
+	code := codes[0]
+	var codeErr *coupon.CodeError
+	if errors.As(err, &codeErr) {
+		code = codeErr.Code
+	}
+	locked, err := h.couponGuard.Fail(ctx, code, couponSubjects(ctx)...)
+	if err != nil {
+		zctx.From(ctx).Warn("Failed to record invalid coupon code", zap.Error(err))
+	}
+	for _, s := range locked {
+		zctx.From(ctx).Warn("Locked out after too many invalid coupon codes", zap.String("subject", s))
+	}
+}
+
+func couponAttemptsExceeded(retryAfter int) *oas.CouponAttemptsExceededHeaders {
+	return &oas.CouponAttemptsExceededHeaders{
+		RetryAfter: retryAfter,
+		Response:   oas.Error{Code: 429, Message: couponAttemptsExceededMessage},
+	}
+}
+
+func couponAttemptsExceededV2(retryAfter int) *oasv2.CouponAttemptsExceededHeaders {
+	return &oasv2.CouponAttemptsExceededHeaders{
+		RetryAfter: retryAfter,
+		Response:   oasv2.Error{Code: 429, Message: couponAttemptsExceededMessage},
+	}
+}
+
+// ListCoupons returns a page of coupons ordered by code.
+func (h *Handler) ListCoupons(ctx context.Context, params oas.ListCouponsParams) (oas.ListCouponsRes, error) {
+	req := coupon.ListRequest{
+		ValidFrom:  optTime(params.ValidFrom),
+		ValidUntil: optTime(params.ValidUntil),
+		Cursor:     params.Cursor.Or(""),
+		Limit:      params.Limit.Or(coupon.DefaultListLimit),
+	}
+	if active, ok := params.Active.Get(); ok {
+		req.Active = &active
+	}
+	if t, ok := params.DiscountType.Get(); ok {
+		req.DiscountType = coupon.DiscountType(t)
+	}
+
+	result, err := h.coupons.List(ctx, req)
+	if err != nil {
+		if errors.Is(err, coupon.ErrInvalidCursor) || errors.Is(err, coupon.ErrInvalidDateRange) {
+			return &oas.ListCouponsBadRequest{
+				Code:    400,
+				Message: err.Error(),
+			}, nil
+		}
+		return nil, errors.Wrap(err, "list coupons")
+	}
+
+	out := &oas.CouponList{
+		Coupons: make([]oas.Coupon, len(result.Coupons)),
+	}
+	for i, c := range result.Coupons {
+		out.Coupons[i] = *domainToOASCoupon(&c)
+	}
+	if result.NextCursor != "" {
+		out.NextCursor = oas.NewOptString(result.NextCursor)
+	}
+	return out, nil
+}
+
+// GetCoupon returns a coupon, active or not.
+func (h *Handler) GetCoupon(ctx context.Context, params oas.GetCouponParams) (oas.GetCouponRes, error) {
+	c, err := h.coupons.Get(ctx, params.Code)
+	if err != nil {
+		if errors.Is(err, coupon.ErrNotFound) {
+			return &oas.GetCouponNotFound{Code: 404, Message: err.Error()}, nil
+		}
+		return nil, errors.Wrap(err, "get coupon")
+	}
+	return domainToOASCoupon(c), nil
+}
+
+// CreateCoupon creates an active coupon.
+func (h *Handler) CreateCoupon(ctx context.Context, req *oas.CouponCreate) (oas.CreateCouponRes, error) {
+	rule := oasToDomainRule(&oas.CouponRule{
+		DiscountType: req.DiscountType,
+		Value:        req.Value,
+		MinItems:     req.MinItems,
+		Description:  req.Description,
+		ValidFrom:    req.ValidFrom,
+		ValidUntil:   req.ValidUntil,
+		MaxUses:      req.MaxUses,
+		MaxDiscount:  req.MaxDiscount,
+
+		MaxUsesPerCustomer: req.MaxUsesPerCustomer,
+		Stackable:          req.Stackable,
+		Priority:           req.Priority,
+		IncludeProducts:    req.IncludeProducts,
+		IncludeCategories:  req.IncludeCategories,
+		ExcludeProducts:    req.ExcludeProducts,
+		ExcludeCategories:  req.ExcludeCategories,
+	})
+	rule.Code = req.Code
+
+	c, err := h.coupons.Create(ctx, rule)
+	if err != nil {
+		var ruleErr *coupon.RuleError
+		switch {
+		case errors.As(err, &ruleErr):
+			return &oas.CreateCouponUnprocessableEntity{Code: 422, Message: err.Error()}, nil
+		case errors.Is(err, coupon.ErrExists):
+			return &oas.CreateCouponConflict{Code: 409, Message: err.Error()}, nil
+		}
+		return nil, errors.Wrap(err, "create coupon")
+	}
+	setAuditTarget(ctx, c.Code)
+	return domainToOASCoupon(c), nil
+}
+
+// UpdateCoupon replaces the rule of a coupon.
+func (h *Handler) UpdateCoupon(ctx context.Context, req *oas.CouponRule, params oas.UpdateCouponParams) (oas.UpdateCouponRes, error) {
+	rule := oasToDomainRule(req)
+	rule.Code = params.Code
+
+	c, err := h.coupons.Update(ctx, rule)
+	if err != nil {
+		var ruleErr *coupon.RuleError
+		switch {
+		case errors.As(err, &ruleErr):
+			return &oas.UpdateCouponUnprocessableEntity{Code: 422, Message: err.Error()}, nil
+		case errors.Is(err, coupon.ErrNotFound):
+			return &oas.UpdateCouponNotFound{Code: 404, Message: err.Error()}, nil
+		}
+		return nil, errors.Wrap(err, "update coupon")
+	}
+	return domainToOASCoupon(c), nil
+}
+
+// DeactivateCoupon stops a coupon from being applied.
+func (h *Handler) DeactivateCoupon(ctx context.Context, params oas.DeactivateCouponParams) (oas.DeactivateCouponRes, error) {
+	c, err := h.coupons.Deactivate(ctx, params.Code)
+	if err != nil {
+		if errors.Is(err, coupon.ErrNotFound) {
+			return &oas.DeactivateCouponNotFound{Code: 404, Message: err.Error()}, nil
+		}
+		return nil, errors.Wrap(err, "deactivate coupon")
+	}
+	return domainToOASCoupon(c), nil
+}
+
+func oasToDomainRule(r *oas.CouponRule) coupon.Rule {
+	return coupon.Rule{
+		DiscountType: coupon.DiscountType(r.DiscountType),
+		Value:        decimal.NewFromFloat(r.Value),
+		MinItems:     r.MinItems.Or(0),
+		Description:  r.Description.Or(""),
+		ValidFrom:    optTime(r.ValidFrom),
+		ValidUntil:   optTime(r.ValidUntil),
+		MaxUses:      r.MaxUses.Or(0),
+		MaxDiscount:  decimal.NewFromFloat(r.MaxDiscount.Or(0)),
+
+		MaxUsesPerCustomer: r.MaxUsesPerCustomer.Or(0),
+		Stackable:          r.Stackable.Or(false),
+		Priority:           r.Priority.Or(0),
+		IncludeProducts:    r.IncludeProducts,
+		IncludeCategories:  r.IncludeCategories,
+		ExcludeProducts:    r.ExcludeProducts,
+		ExcludeCategories:  r.ExcludeCategories,
+	}
+}
+
+func domainToOASCoupon(c *coupon.Coupon) *oas.Coupon {
+	resp := &oas.Coupon{
+		Code:         c.Code,
+		Active:       c.Active,
+		CreatedAt:    c.CreatedAt,
+		DiscountType: oas.DiscountType(c.DiscountType),
+		Value:        c.Value.InexactFloat64(),
+		MinItems:     oas.NewOptInt(c.MinItems),
+		Description:  oas.NewOptString(c.Description),
+		MaxUses:      oas.NewOptInt(c.MaxUses),
+		MaxDiscount:  oas.NewOptFloat64(c.MaxDiscount.InexactFloat64()),
+
+		MaxUsesPerCustomer: oas.NewOptInt(c.MaxUsesPerCustomer),
+		Stackable:          oas.NewOptBool(c.Stackable),
+		Priority:           oas.NewOptInt(c.Priority),
+		IncludeProducts:    c.IncludeProducts,
+		IncludeCategories:  c.IncludeCategories,
+		ExcludeProducts:    c.ExcludeProducts,
+		ExcludeCategories:  c.ExcludeCategories,
+		Usage: oas.CouponUsage{
+			Uses:          c.Uses,
+			Orders:        c.Usage.Orders,
+			DiscountTotal: c.Usage.DiscountTotal.InexactFloat64(),
+		},
+	}
+	if c.ValidFrom != nil {
+		resp.ValidFrom = oas.NewOptDateTime(*c.ValidFrom)
+	}
+	if c.ValidUntil != nil {
+		resp.ValidUntil = oas.NewOptDateTime(*c.ValidUntil)
+	}
+	if c.MaxUses > 0 {
+		resp.Usage.Remaining = oas.NewOptInt(max(c.MaxUses-c.Uses, 0))
+	}
+	return resp
+}
+
+func optTime(t oas.OptDateTime) *time.Time {
+	if v, ok := t.Get(); ok {
+		return &v
+	}
+	return nil
+}
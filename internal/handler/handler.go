@@ -2,6 +2,10 @@ package handler
 
 import (
 	"github.com/xenking/oolio-kart-challenge/gen/oas"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/audit"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/cart"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/coupon"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/idempotency"
 	"github.com/xenking/oolio-kart-challenge/internal/domain/order"
 	"github.com/xenking/oolio-kart-challenge/internal/domain/product"
 )
@@ -14,27 +18,50 @@ type HandlerConfig struct {
 	// ImageBaseURL is prepended to relative image paths in product responses.
 	// When empty, image paths are returned as stored in the database.
 	ImageBaseURL string
+	// Currency is the ISO 4217 code reported alongside /api/v2 amounts.
+	// Catalog prices are stored without a currency, so it applies to all.
+	Currency string
 }
 
 // Handler implements the ogen-generated Handler interface, delegating business
-// logic to the order service and product repository.
+// logic to the order, cart, coupon and audit services and the product
+// repository.
 type Handler struct {
 	oas.UnimplementedHandler
 
 	products     product.Repository
 	orderService *order.Service
+	carts        *cart.Service
+	idempotency  *idempotency.Service
+	audit        *audit.Service
+	coupons      *coupon.Service
+	couponGuard  *coupon.Guard
 	imageBaseURL string
+	currency     string
 }
 
 // NewHandler constructs a Handler with the required domain dependencies.
+// Callers presenting coupon codes are throttled by couponGuard; a nil guard
+// lets every attempt through.
 func NewHandler(
 	cfg HandlerConfig,
 	products product.Repository,
 	orderService *order.Service,
+	cartService *cart.Service,
+	idempotencySvc *idempotency.Service,
+	auditSvc *audit.Service,
+	couponService *coupon.Service,
+	couponGuard *coupon.Guard,
 ) *Handler {
 	return &Handler{
 		products:     products,
 		orderService: orderService,
+		carts:        cartService,
+		idempotency:  idempotencySvc,
+		audit:        auditSvc,
+		coupons:      couponService,
+		couponGuard:  couponGuard,
 		imageBaseURL: cfg.ImageBaseURL,
+		currency:     cfg.Currency,
 	}
 }
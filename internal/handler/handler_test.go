@@ -2,21 +2,40 @@ package handler
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-faster/errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/ogen-go/ogen/ogenerrors"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
 
 	"github.com/xenking/oolio-kart-challenge/gen/oas"
+	"github.com/xenking/oolio-kart-challenge/gen/oasv2"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/audit"
 	"github.com/xenking/oolio-kart-challenge/internal/domain/auth"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/cart"
 	"github.com/xenking/oolio-kart-challenge/internal/domain/coupon"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/idempotency"
 	"github.com/xenking/oolio-kart-challenge/internal/domain/order"
 	"github.com/xenking/oolio-kart-challenge/internal/domain/product"
+	"github.com/xenking/oolio-kart-challenge/pkg/httpmiddleware"
 )
 
 // --- Mock implementations ---
@@ -62,27 +81,291 @@ type mockCouponValidator struct {
 	err      error
 }
 
-func (m *mockCouponValidator) Validate(_ context.Context, _ string, _ []coupon.Item) (*coupon.Discount, error) {
+func (m *mockCouponValidator) Validate(_ context.Context, _ coupon.Request, _ []coupon.Item) (*coupon.Discount, error) {
 	return m.discount, m.err
 }
 
+func (m *mockCouponValidator) Preview(_ context.Context, _ coupon.Request, _ []coupon.Item) (*coupon.Discount, error) {
+	return m.discount, m.err
+}
+
+func (m *mockCouponValidator) Release(_ context.Context, _, _ string) error {
+	return nil
+}
+
 type mockOrderRepo struct {
-	lastOrder *order.Order
-	err       error
+	lastOrder   *order.Order
+	err         error
+	orders      []order.Order
+	transitions []order.StatusTransition
 }
 
-func (m *mockOrderRepo) Create(_ context.Context, o *order.Order) error {
+func (m *mockOrderRepo) Create(_ context.Context, o *order.Order, _ string) error {
 	m.lastOrder = o
 	return m.err
 }
 
+func (m *mockOrderRepo) GetByID(_ context.Context, id string) (*order.Order, error) {
+	for i := range m.orders {
+		if m.orders[i].ID == id {
+			return &m.orders[i], nil
+		}
+	}
+	return nil, order.ErrNotFound
+}
+
+func (m *mockOrderRepo) List(_ context.Context, f order.ListFilter) ([]order.Order, error) {
+	if len(m.orders) > f.Limit {
+		return m.orders[:f.Limit], m.err
+	}
+	return m.orders, m.err
+}
+
+func (m *mockOrderRepo) UpdateStatus(_ context.Context, t *order.StatusTransition) error {
+	for i := range m.orders {
+		if m.orders[i].ID == t.OrderID {
+			if m.orders[i].Status != t.From {
+				return order.ErrStatusConflict
+			}
+			t.At = time.Date(2025, 6, 15, 13, 0, 0, 0, time.UTC)
+			m.orders[i].Status = t.To
+			m.transitions = append(m.transitions, *t)
+			return nil
+		}
+	}
+	return order.ErrNotFound
+}
+
+func (m *mockOrderRepo) ListTransitions(_ context.Context, orderID string) ([]order.StatusTransition, error) {
+	var out []order.StatusTransition
+	for _, t := range m.transitions {
+		if t.OrderID == orderID {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+type mockCartRepo struct {
+	carts map[string]cart.Cart
+}
+
+func newCartRepo() *mockCartRepo {
+	return &mockCartRepo{carts: make(map[string]cart.Cart)}
+}
+
+func (m *mockCartRepo) Create(_ context.Context, c *cart.Cart) error {
+	c.CreatedAt = time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	c.UpdatedAt = c.CreatedAt
+	m.carts[c.ID] = *c
+	return nil
+}
+
+func (m *mockCartRepo) Get(_ context.Context, id string) (*cart.Cart, error) {
+	c, ok := m.carts[id]
+	if !ok {
+		return nil, cart.ErrNotFound
+	}
+	c.Items = append([]cart.Item(nil), c.Items...)
+	return &c, nil
+}
+
+func (m *mockCartRepo) Update(_ context.Context, c *cart.Cart) error {
+	m.carts[c.ID] = *c
+	return nil
+}
+
+func (m *mockCartRepo) Delete(_ context.Context, id string) error {
+	delete(m.carts, id)
+	return nil
+}
+
+func (m *mockCartRepo) DeleteExpired(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+type mockTransactor struct{}
+
+func (mockTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
 type mockAPIKeyRepo struct {
-	info *auth.APIKeyInfo
-	err  error
+	info    *auth.APIKeyInfo
+	sealed  []byte // signing key returned by FindSigningKey
+	err     error
+	lookups int
+	rehashs int
+}
+
+func (m *mockAPIKeyRepo) FindByHash(_ context.Context, hash string) (*auth.APIKeyInfo, error) {
+	m.lookups++
+	if m.err != nil {
+		return nil, m.err
+	}
+	if hash != m.info.KeyHash {
+		return nil, auth.ErrKeyNotFound
+	}
+	info := *m.info
+	return &info, nil
+}
+
+func (m *mockAPIKeyRepo) Rehash(_ context.Context, _, oldHash string, secret auth.StoredSecret) error {
+	if m.info.KeyHash == oldHash {
+		m.rehashs++
+		m.info.KeyHash = secret.Hash
+		m.info.PepperVersion = secret.PepperVersion
+	}
+	return nil
+}
+
+func (m *mockAPIKeyRepo) FindSigningKey(_ context.Context, id string) (*auth.APIKeyInfo, []byte, error) {
+	if m.info == nil || m.info.ID != id {
+		return nil, nil, auth.ErrKeyNotFound
+	}
+	info := *m.info
+	return &info, m.sealed, nil
+}
+
+type mockUsageStore struct {
+	batches []map[string]time.Time
+}
+
+func (m *mockUsageStore) TouchKeys(_ context.Context, usedAt map[string]time.Time) error {
+	m.batches = append(m.batches, usedAt)
+	return nil
+}
+
+type mockIdempotencyRepo struct {
+	records     map[string]*idempotency.Record
+	completeErr error
+}
+
+func (m *mockIdempotencyRepo) Reserve(_ context.Context, rec idempotency.Record) (*idempotency.Record, error) {
+	if m.records == nil {
+		m.records = make(map[string]*idempotency.Record)
+	}
+	id := rec.Scope + "/" + rec.Key
+	if existing, ok := m.records[id]; ok {
+		return existing, nil
+	}
+	m.records[id] = &rec
+	return nil, nil
+}
+
+func (m *mockIdempotencyRepo) Complete(_ context.Context, scope, key string, statusCode int, response []byte) error {
+	if m.completeErr != nil {
+		return m.completeErr
+	}
+	rec := m.records[scope+"/"+key]
+	rec.StatusCode = statusCode
+	rec.Response = response
+	return nil
+}
+
+func (m *mockIdempotencyRepo) Release(_ context.Context, scope, key string) error {
+	delete(m.records, scope+"/"+key)
+	return nil
+}
+
+func (m *mockIdempotencyRepo) Abandon(_ context.Context, scope, key string, expiresAt time.Time) error {
+	if rec, ok := m.records[scope+"/"+key]; ok && rec.StatusCode == 0 && expiresAt.Before(rec.ExpiresAt) {
+		rec.ExpiresAt = expiresAt
+	}
+	return nil
+}
+
+func (m *mockIdempotencyRepo) DeleteExpired(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+type mockAuditRepo struct {
+	events []audit.Event
+}
+
+func (m *mockAuditRepo) Insert(_ context.Context, e *audit.Event) error {
+	e.ID = int64(len(m.events) + 1)
+	e.CreatedAt = time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	m.events = append(m.events, *e)
+	return nil
+}
+
+func (m *mockAuditRepo) List(_ context.Context, f audit.ListFilter) ([]audit.Event, error) {
+	var out []audit.Event
+	for i := len(m.events) - 1; i >= 0 && len(out) < f.Limit; i-- {
+		if f.Actor == "" || m.events[i].Actor == f.Actor {
+			out = append(out, m.events[i])
+		}
+	}
+	return out, nil
+}
+
+// mockCouponRepo keeps coupons by upper-cased code for the admin endpoints.
+type mockCouponRepo struct {
+	coupons map[string]*coupon.Coupon
+}
+
+func (m *mockCouponRepo) FindByCode(_ context.Context, code string) (*coupon.Rule, error) {
+	c, ok := m.coupons[strings.ToUpper(code)]
+	if !ok || !c.Active {
+		return nil, coupon.ErrInvalidCoupon
+	}
+	return &c.Rule, nil
+}
+
+func (m *mockCouponRepo) IncrementUses(_ context.Context, _ string) error { return nil }
+
+func (m *mockCouponRepo) DecrementUses(_ context.Context, _ string) error { return nil }
+
+func (m *mockCouponRepo) CountRedemptions(_ context.Context, _, _ string) (int, error) { return 0, nil }
+
+func (m *mockCouponRepo) RecordRedemption(_ context.Context, _ coupon.Redemption) error { return nil }
+
+func (m *mockCouponRepo) DeleteRedemption(_ context.Context, _, _ string) error { return nil }
+
+func (m *mockCouponRepo) Get(_ context.Context, code string) (*coupon.Coupon, error) {
+	c, ok := m.coupons[strings.ToUpper(code)]
+	if !ok {
+		return nil, coupon.ErrNotFound
+	}
+	return c, nil
+}
+
+func (m *mockCouponRepo) List(_ context.Context, f coupon.ListFilter) ([]coupon.Coupon, error) {
+	var out []coupon.Coupon
+	for _, c := range m.coupons {
+		out = append(out, *c)
+	}
+	return out[:min(f.Limit, len(out))], nil
+}
+
+func (m *mockCouponRepo) Create(_ context.Context, rule *coupon.Rule) error {
+	if _, ok := m.coupons[rule.Code]; ok {
+		return coupon.ErrExists
+	}
+	m.coupons[rule.Code] = &coupon.Coupon{Rule: *rule, Active: true}
+	return nil
+}
+
+func (m *mockCouponRepo) Update(_ context.Context, rule *coupon.Rule) error {
+	c, ok := m.coupons[strings.ToUpper(rule.Code)]
+	if !ok {
+		return coupon.ErrNotFound
+	}
+	uses := c.Uses
+	c.Rule = *rule
+	c.Code = strings.ToUpper(rule.Code)
+	c.Uses = uses
+	return nil
 }
 
-func (m *mockAPIKeyRepo) FindByHash(_ context.Context, _ string) (*auth.APIKeyInfo, error) {
-	return m.info, m.err
+func (m *mockCouponRepo) Deactivate(_ context.Context, code string) error {
+	c, ok := m.coupons[strings.ToUpper(code)]
+	if !ok {
+		return coupon.ErrNotFound
+	}
+	c.Active = false
+	return nil
 }
 
 // --- Helpers ---
@@ -118,8 +401,10 @@ func newTestHandler(
 	coupons *mockCouponValidator,
 	orders *mockOrderRepo,
 ) *Handler {
-	svc := order.NewService(products, coupons, orders)
-	return NewHandler(HandlerConfig{}, products, svc)
+	svc := order.NewService(order.ServiceConfig{}, products, coupons, orders, mockTransactor{})
+	carts := cart.NewService(cart.ServiceConfig{TTL: time.Hour}, newCartRepo(), products, svc, mockTransactor{})
+	idem := idempotency.NewService(&mockIdempotencyRepo{}, time.Hour)
+	return NewHandler(HandlerConfig{}, products, svc, carts, idem, audit.NewService(&mockAuditRepo{}), nil, nil)
 }
 
 // --- Tests ---
@@ -191,6 +476,7 @@ func TestPlaceOrder(t *testing.T) {
 		wantType       string // "order", "bad_request", "unprocessable"
 		wantTotal      float64
 		wantDiscounts  float64
+		wantCoupons    []string
 		wantBadCode    int32
 		wantBadMessage string
 	}{
@@ -286,6 +572,82 @@ func TestPlaceOrder(t *testing.T) {
 			wantBadCode:    422,
 			wantBadMessage: "invalid coupon code",
 		},
+		{
+			name:     "coupon used up by the customer returns 422",
+			products: newProductRepo(p1),
+			coupons: &mockCouponValidator{
+				err: coupon.ErrCouponAlreadyUsed,
+			},
+			orders: &mockOrderRepo{},
+			req: &oas.OrderReq{
+				CouponCode: oas.NewOptString("ONCE"),
+				Items: []oas.OrderReqItemsItem{
+					{ProductId: "p1", Quantity: 1},
+				},
+			},
+			wantType:       "unprocessable",
+			wantBadCode:    422,
+			wantBadMessage: "coupon already used",
+		},
+		{
+			name:     "stacked coupons apply together",
+			products: newProductRepo(p1),
+			coupons: &mockCouponValidator{
+				discount: &coupon.Discount{
+					Amount:      decimal.RequireFromString("6.00"),
+					Description: "10% off; $5 off",
+					Coupons: []coupon.Applied{
+						{Code: "TEN", Description: "10% off", Amount: decimal.RequireFromString("1.00")},
+						{Code: "SAVE5", Description: "$5 off", Amount: decimal.RequireFromString("5.00")},
+					},
+				},
+			},
+			orders: &mockOrderRepo{},
+			req: &oas.OrderReq{
+				CouponCode:  oas.NewOptString("SAVE5"),
+				CouponCodes: []string{"TEN"},
+				Items: []oas.OrderReqItemsItem{
+					{ProductId: "p1", Quantity: 1},
+				},
+			},
+			wantType:      "order",
+			wantTotal:     4.00, // 10 - 6
+			wantDiscounts: 6.00,
+			wantCoupons:   []string{"TEN", "SAVE5"},
+		},
+		{
+			name:     "coupons that cannot be combined return 422",
+			products: newProductRepo(p1),
+			coupons: &mockCouponValidator{
+				err: &coupon.CodeError{Code: "SOLO", Err: coupon.ErrCouponNotStackable},
+			},
+			orders: &mockOrderRepo{},
+			req: &oas.OrderReq{
+				CouponCodes: []string{"SAVE5", "SOLO"},
+				Items: []oas.OrderReqItemsItem{
+					{ProductId: "p1", Quantity: 1},
+				},
+			},
+			wantType:       "unprocessable",
+			wantBadCode:    422,
+			wantBadMessage: "coupons cannot be combined",
+		},
+		{
+			name:     "same coupon twice returns 422",
+			products: newProductRepo(p1),
+			coupons:  &mockCouponValidator{},
+			orders:   &mockOrderRepo{},
+			req: &oas.OrderReq{
+				CouponCode:  oas.NewOptString("SAVE5"),
+				CouponCodes: []string{"save5"},
+				Items: []oas.OrderReqItemsItem{
+					{ProductId: "p1", Quantity: 1},
+				},
+			},
+			wantType:       "unprocessable",
+			wantBadCode:    422,
+			wantBadMessage: "coupon code given more than once",
+		},
 		{
 			name:     "expired coupon returns 422",
 			products: newProductRepo(p1),
@@ -301,7 +663,7 @@ func TestPlaceOrder(t *testing.T) {
 			},
 			wantType:       "unprocessable",
 			wantBadCode:    422,
-			wantBadMessage: "coupon expired",
+			wantBadMessage: "invalid coupon code",
 		},
 		{
 			name:     "coupon usage limit returns 422",
@@ -318,7 +680,7 @@ func TestPlaceOrder(t *testing.T) {
 			},
 			wantType:       "unprocessable",
 			wantBadCode:    422,
-			wantBadMessage: "coupon usage limit reached",
+			wantBadMessage: "invalid coupon code",
 		},
 		{
 			name:     "discount larger than subtotal floors total at 0",
@@ -346,7 +708,7 @@ func TestPlaceOrder(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			h := newTestHandler(tt.products, tt.coupons, tt.orders)
 
-			result, err := h.PlaceOrder(context.Background(), tt.req)
+			result, err := h.PlaceOrder(context.Background(), tt.req, oas.PlaceOrderParams{})
 			require.NoError(t, err)
 
 			switch tt.wantType {
@@ -373,6 +735,11 @@ func TestPlaceOrder(t *testing.T) {
 				assert.NotEmpty(t, resp.ID.Value)
 				assert.InDelta(t, tt.wantTotal, resp.Total.Value, 0.01)
 				assert.InDelta(t, tt.wantDiscounts, resp.Discounts.Value, 0.01)
+				var codes []string
+				for _, c := range resp.Coupons {
+					codes = append(codes, c.Code)
+				}
+				assert.Equal(t, tt.wantCoupons, codes)
 			}
 		})
 	}
@@ -392,50 +759,1400 @@ func TestPlaceOrder_OrderCreateError(t *testing.T) {
 		},
 	}
 
-	result, err := h.PlaceOrder(context.Background(), req)
+	result, err := h.PlaceOrder(context.Background(), req, oas.PlaceOrderParams{})
 	require.Error(t, err)
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "create order")
 }
 
-func TestHandleAPIKey(t *testing.T) {
-	pepper := []byte("test-pepper-secret")
+func TestPlaceOrder_Idempotency(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
+	p2 := newTestProduct("p2", "Gadget", decimal.RequireFromString("20.00"))
+	ctx := auth.WithAPIKey(context.Background(), &auth.APIKeyInfo{ID: "key-1"})
+	req := &oas.OrderReq{Items: []oas.OrderReqItemsItem{{ProductId: "p1", Quantity: 1}}}
+	withKey := oas.PlaceOrderParams{IdempotencyKey: oas.NewOptString("retry-1")}
 
-	t.Run("valid key returns context", func(t *testing.T) {
-		apiKey := "my-secret-key"
-		mac := hmac.New(sha256.New, pepper)
-		mac.Write([]byte(apiKey))
-		hexHash := hex.EncodeToString(mac.Sum(nil))
+	t.Run("identical retry replays the stored order", func(t *testing.T) {
+		orders := &mockOrderRepo{}
+		h := newTestHandler(newProductRepo(p1, p2), &mockCouponValidator{}, orders)
 
-		sh := NewSecurityHandler(
-			&mockAPIKeyRepo{
-				info: &auth.APIKeyInfo{
-					ID:      "key-1",
-					KeyHash: hexHash,
-					Name:    "test-key",
-					Scopes:  []string{"orders:write"},
-				},
-			},
-			pepper,
-		)
+		first, err := h.PlaceOrder(ctx, req, withKey)
+		require.NoError(t, err)
+		placed := orders.lastOrder
 
-		ctx := context.Background()
-		resultCtx, err := sh.HandleAPIKey(ctx, oas.PlaceOrderOperation, oas.APIKey{APIKey: apiKey})
+		orders.lastOrder = nil
+		second, err := h.PlaceOrder(ctx, req, withKey)
 		require.NoError(t, err)
-		assert.NotNil(t, resultCtx)
+		assert.Nil(t, orders.lastOrder, "retry must not create another order")
+
+		firstOrder, ok := first.(*oas.Order)
+		require.True(t, ok, "expected *oas.Order, got %T", first)
+		secondOrder, ok := second.(*oas.Order)
+		require.True(t, ok, "expected *oas.Order, got %T", second)
+		assert.Equal(t, placed.ID, secondOrder.ID.Value)
+		assert.Equal(t, firstOrder.Total, secondOrder.Total)
 	})
 
-	t.Run("invalid key returns error", func(t *testing.T) {
-		sh := NewSecurityHandler(
-			&mockAPIKeyRepo{
-				err: errors.New("not found"),
-			},
-			pepper,
+	t.Run("validation errors are replayed too", func(t *testing.T) {
+		h := newTestHandler(newProductRepo(p1), &mockCouponValidator{}, &mockOrderRepo{})
+		bad := &oas.OrderReq{Items: []oas.OrderReqItemsItem{{ProductId: "nope", Quantity: 1}}}
+
+		for range 2 {
+			result, err := h.PlaceOrder(ctx, bad, withKey)
+			require.NoError(t, err)
+			resp, ok := result.(*oas.PlaceOrderUnprocessableEntity)
+			require.True(t, ok, "expected *oas.PlaceOrderUnprocessableEntity, got %T", result)
+			assert.Equal(t, "product nope not found", resp.Message)
+		}
+	})
+
+	t.Run("different body returns 422", func(t *testing.T) {
+		h := newTestHandler(newProductRepo(p1, p2), &mockCouponValidator{}, &mockOrderRepo{})
+
+		_, err := h.PlaceOrder(ctx, req, withKey)
+		require.NoError(t, err)
+
+		other := &oas.OrderReq{Items: []oas.OrderReqItemsItem{{ProductId: "p2", Quantity: 1}}}
+		result, err := h.PlaceOrder(ctx, other, withKey)
+		require.NoError(t, err)
+		resp, ok := result.(*oas.PlaceOrderUnprocessableEntity)
+		require.True(t, ok, "expected *oas.PlaceOrderUnprocessableEntity, got %T", result)
+		assert.Equal(t, int32(422), resp.Code)
+	})
+
+	t.Run("same key from another api key is independent", func(t *testing.T) {
+		orders := &mockOrderRepo{}
+		h := newTestHandler(newProductRepo(p1, p2), &mockCouponValidator{}, orders)
+
+		_, err := h.PlaceOrder(ctx, req, withKey)
+		require.NoError(t, err)
+		first := orders.lastOrder
+
+		otherCtx := auth.WithAPIKey(context.Background(), &auth.APIKeyInfo{ID: "key-2"})
+		_, err = h.PlaceOrder(otherCtx, req, withKey)
+		require.NoError(t, err)
+		assert.NotSame(t, first, orders.lastOrder)
+	})
+
+	t.Run("request in progress returns 409", func(t *testing.T) {
+		repo := &mockIdempotencyRepo{records: map[string]*idempotency.Record{}}
+		h := NewHandler(HandlerConfig{}, newProductRepo(p1),
+			order.NewService(order.ServiceConfig{}, newProductRepo(p1), &mockCouponValidator{}, &mockOrderRepo{}, mockTransactor{}),
+			nil,
+			idempotency.NewService(repo, time.Hour),
+			nil,
+			nil,
+			nil,
 		)
+		body, err := req.MarshalJSON()
+		require.NoError(t, err)
+		repo.records["key-1/retry-1"] = &idempotency.Record{
+			Scope:       "key-1",
+			Key:         "retry-1",
+			Fingerprint: idempotency.Fingerprint(body),
+		}
 
-		ctx := context.Background()
-		_, err := sh.HandleAPIKey(ctx, oas.PlaceOrderOperation, oas.APIKey{APIKey: "bad-key"})
+		result, err := h.PlaceOrder(ctx, req, withKey)
+		require.NoError(t, err)
+		resp, ok := result.(*oas.PlaceOrderConflict)
+		require.True(t, ok, "expected *oas.PlaceOrderConflict, got %T", result)
+		assert.Equal(t, int32(409), resp.Code)
+	})
+
+	t.Run("server error releases the key", func(t *testing.T) {
+		orders := &mockOrderRepo{err: errors.New("db write failed")}
+		h := newTestHandler(newProductRepo(p1), &mockCouponValidator{}, orders)
+
+		_, err := h.PlaceOrder(ctx, req, withKey)
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "unauthorized")
+
+		orders.err = nil
+		result, err := h.PlaceOrder(ctx, req, withKey)
+		require.NoError(t, err)
+		_, ok := result.(*oas.Order)
+		assert.True(t, ok, "retry after a server error should execute, got %T", result)
+	})
+
+	t.Run("failure to store the response abandons the key", func(t *testing.T) {
+		repo := &mockIdempotencyRepo{records: map[string]*idempotency.Record{}, completeErr: errors.New("db down")}
+		orders := &mockOrderRepo{}
+		h := NewHandler(HandlerConfig{}, newProductRepo(p1),
+			order.NewService(order.ServiceConfig{}, newProductRepo(p1), &mockCouponValidator{}, orders, mockTransactor{}),
+			nil,
+			idempotency.NewService(repo, 24*time.Hour),
+			nil,
+			nil,
+			nil,
+		)
+
+		result, err := h.PlaceOrder(ctx, req, withKey)
+		require.NoError(t, err)
+		placed, ok := result.(*oas.Order)
+		require.True(t, ok, "the order was placed and must still be returned, got %T", result)
+		assert.Equal(t, orders.lastOrder.ID, placed.ID.Value)
+
+		rec := repo.records["key-1/retry-1"]
+		require.NotNil(t, rec)
+		assert.Zero(t, rec.StatusCode)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), rec.ExpiresAt, 5*time.Second)
+	})
+}
+
+func TestQuoteOrder(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
+	req := &oas.OrderReq{
+		Items:      []oas.OrderReqItemsItem{{ProductId: "p1", Quantity: 3}},
+		CouponCode: oas.NewOptString("SAVE5"),
+	}
+
+	t.Run("applied coupon", func(t *testing.T) {
+		cv := &mockCouponValidator{discount: &coupon.Discount{
+			Amount:      decimal.RequireFromString("5.00"),
+			Description: "$5 off",
+		}}
+		orders := &mockOrderRepo{}
+		h := newTestHandler(newProductRepo(p1), cv, orders)
+
+		result, err := h.QuoteOrder(context.Background(), req)
+		require.NoError(t, err)
+
+		resp, ok := result.(*oas.Quote)
+		require.True(t, ok, "expected *oas.Quote, got %T", result)
+		assert.InDelta(t, 30.00, resp.Subtotal, 0.001)
+		assert.InDelta(t, 5.00, resp.Discount, 0.001)
+		assert.InDelta(t, 25.00, resp.Total, 0.001)
+		assert.Equal(t, "SAVE5", resp.CouponCode.Value)
+		assert.Equal(t, "$5 off", resp.CouponDescription.Value)
+		assert.False(t, resp.CouponError.IsSet())
+		require.Len(t, resp.Items, 1)
+		assert.InDelta(t, 5.00, resp.Items[0].Discount.Value, 0.001)
+		assert.Nil(t, orders.lastOrder)
+	})
+
+	t.Run("rejected coupon", func(t *testing.T) {
+		cv := &mockCouponValidator{err: coupon.ErrCouponUsageLimitReached}
+		h := newTestHandler(newProductRepo(p1), cv, &mockOrderRepo{})
+
+		result, err := h.QuoteOrder(context.Background(), req)
+		require.NoError(t, err)
+
+		resp, ok := result.(*oas.Quote)
+		require.True(t, ok, "expected *oas.Quote, got %T", result)
+		assert.Equal(t, "invalid coupon code", resp.CouponError.Value)
+		assert.InDelta(t, 30.00, resp.Total, 0.001)
+		assert.Zero(t, resp.Discount)
+	})
+
+	t.Run("unknown product returns 422", func(t *testing.T) {
+		h := newTestHandler(newProductRepo(), &mockCouponValidator{}, &mockOrderRepo{})
+
+		result, err := h.QuoteOrder(context.Background(), req)
+		require.NoError(t, err)
+
+		resp, ok := result.(*oas.QuoteOrderUnprocessableEntity)
+		require.True(t, ok, "expected *oas.QuoteOrderUnprocessableEntity, got %T", result)
+		assert.Equal(t, "product p1 not found", resp.Message)
 	})
 }
+
+func TestCouponGuard(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
+	ctx := auth.WithAPIKey(context.Background(), &auth.APIKeyInfo{ID: "key-1"})
+	guard, err := coupon.NewGuard(coupon.GuardConfig{
+		FreeAttempts: 2,
+		MaxAttempts:  2,
+		Lockout:      time.Minute,
+		Window:       time.Hour,
+	}, nil, noop.NewMeterProvider())
+	require.NoError(t, err)
+
+	h := newTestHandler(newProductRepo(p1), &mockCouponValidator{err: coupon.ErrInvalidCoupon}, &mockOrderRepo{})
+	h.couponGuard = guard
+	quote := func(code string) oas.QuoteOrderRes {
+		req := &oas.OrderReq{Items: []oas.OrderReqItemsItem{{ProductId: "p1", Quantity: 1}}}
+		if code != "" {
+			req.CouponCode = oas.NewOptString(code)
+		}
+		result, err := h.QuoteOrder(ctx, req)
+		require.NoError(t, err)
+		return result
+	}
+
+	for _, code := range []string{"GUESS1", "GUESS2"} {
+		resp, ok := quote(code).(*oas.Quote)
+		require.True(t, ok, "expected *oas.Quote for %s", code)
+		assert.Equal(t, "invalid coupon code", resp.CouponError.Value)
+	}
+
+	resp, ok := quote("GUESS3").(*oas.CouponAttemptsExceededHeaders)
+	require.True(t, ok, "expected *oas.CouponAttemptsExceededHeaders")
+	assert.Equal(t, 60, resp.RetryAfter)
+	assert.Equal(t, couponAttemptsExceededMessage, resp.Response.Message)
+
+	placed, err := h.PlaceOrder(ctx, &oas.OrderReq{
+		Items:      []oas.OrderReqItemsItem{{ProductId: "p1", Quantity: 1}},
+		CouponCode: oas.NewOptString("GUESS4"),
+	}, oas.PlaceOrderParams{})
+	require.NoError(t, err)
+	assert.IsType(t, &oas.CouponAttemptsExceededHeaders{}, placed)
+
+	// Orders without a coupon are not held back.
+	_, ok = quote("").(*oas.Quote)
+	assert.True(t, ok)
+}
+
+func TestCouponGuard_ReplaysCompletedOrder(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
+	ctx := auth.WithAPIKey(context.Background(), &auth.APIKeyInfo{ID: "key-1"})
+	guard, err := coupon.NewGuard(coupon.GuardConfig{
+		FreeAttempts: 2,
+		MaxAttempts:  2,
+		Lockout:      time.Minute,
+		Window:       time.Hour,
+	}, nil, noop.NewMeterProvider())
+	require.NoError(t, err)
+
+	cv := &mockCouponValidator{discount: &coupon.Discount{
+		Amount:      decimal.RequireFromString("1.00"),
+		Description: "$1 off",
+	}}
+	orders := &mockOrderRepo{}
+	h := newTestHandler(newProductRepo(p1), cv, orders)
+	h.couponGuard = guard
+	v := NewV2Handler(h)
+
+	req := &oas.OrderReq{
+		Items:      []oas.OrderReqItemsItem{{ProductId: "p1", Quantity: 1}},
+		CouponCode: oas.NewOptString("SAVE1"),
+	}
+	reqV2 := &oasv2.OrderReq{
+		Items:      []oasv2.OrderReqItemsItem{{ProductId: "p1", Quantity: 1}},
+		CouponCode: oasv2.NewOptString("SAVE1"),
+	}
+	withKey := oas.PlaceOrderParams{IdempotencyKey: oas.NewOptString("retry-1")}
+	withKeyV2 := oasv2.PlaceOrderParams{IdempotencyKey: oasv2.NewOptString("retry-2")}
+
+	first, err := h.PlaceOrder(ctx, req, withKey)
+	require.NoError(t, err)
+	firstOrder, ok := first.(*oas.Order)
+	require.True(t, ok, "expected *oas.Order, got %T", first)
+	firstV2, err := v.PlaceOrder(ctx, reqV2, withKeyV2)
+	require.NoError(t, err)
+	firstOrderV2, ok := firstV2.(*oasv2.Order)
+	require.True(t, ok, "expected *oasv2.Order, got %T", firstV2)
+
+	// Lock the caller out by guessing codes.
+	cv.discount, cv.err = nil, coupon.ErrInvalidCoupon
+	for _, code := range []string{"GUESS1", "GUESS2"} {
+		_, err := h.QuoteOrder(ctx, &oas.OrderReq{
+			Items:      []oas.OrderReqItemsItem{{ProductId: "p1", Quantity: 1}},
+			CouponCode: oas.NewOptString(code),
+		})
+		require.NoError(t, err)
+	}
+
+	orders.lastOrder = nil
+	replayed, err := h.PlaceOrder(ctx, req, withKey)
+	require.NoError(t, err)
+	replayedOrder, ok := replayed.(*oas.Order)
+	require.True(t, ok, "a completed order must be replayed while locked out, got %T", replayed)
+	assert.Equal(t, firstOrder.ID, replayedOrder.ID)
+
+	replayedV2, err := v.PlaceOrder(ctx, reqV2, withKeyV2)
+	require.NoError(t, err)
+	replayedOrderV2, ok := replayedV2.(*oasv2.Order)
+	require.True(t, ok, "a completed order must be replayed while locked out, got %T", replayedV2)
+	assert.Equal(t, firstOrderV2.ID, replayedOrderV2.ID)
+	assert.Nil(t, orders.lastOrder)
+
+	// A new key is refused, and the refusal is not stored: once the lockout
+	// is over, the same key places the order.
+	withNewKey := oas.PlaceOrderParams{IdempotencyKey: oas.NewOptString("retry-3")}
+	refused, err := h.PlaceOrder(ctx, req, withNewKey)
+	require.NoError(t, err)
+	assert.IsType(t, &oas.CouponAttemptsExceededHeaders{}, refused)
+
+	h.couponGuard = nil
+	cv.discount, cv.err = &coupon.Discount{Amount: decimal.RequireFromString("1.00")}, nil
+	placed, err := h.PlaceOrder(ctx, req, withNewKey)
+	require.NoError(t, err)
+	assert.IsType(t, &oas.Order{}, placed)
+	assert.NotNil(t, orders.lastOrder)
+}
+
+type unavailableAttemptStore struct {
+	coupon.AttemptStore
+}
+
+func (unavailableAttemptStore) Fail(context.Context, string, string, time.Time, time.Time) (int, error) {
+	return 0, errors.New("attempt store unavailable")
+}
+
+func (unavailableAttemptStore) BlockedUntil(context.Context, []string) (time.Time, error) {
+	return time.Time{}, errors.New("attempt store unavailable")
+}
+
+func TestCouponGuard_StoreUnavailable(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
+	ctx := auth.WithAPIKey(context.Background(), &auth.APIKeyInfo{ID: "key-1"})
+	guard, err := coupon.NewGuard(coupon.GuardConfig{MaxAttempts: 1, Lockout: time.Minute},
+		unavailableAttemptStore{}, noop.NewMeterProvider())
+	require.NoError(t, err)
+
+	h := newTestHandler(newProductRepo(p1), &mockCouponValidator{err: coupon.ErrInvalidCoupon}, &mockOrderRepo{})
+	h.couponGuard = guard
+	req := &oas.OrderReq{
+		Items:      []oas.OrderReqItemsItem{{ProductId: "p1", Quantity: 1}},
+		CouponCode: oas.NewOptString("GUESS1"),
+	}
+
+	for range 3 {
+		result, err := h.QuoteOrder(ctx, req)
+		require.NoError(t, err)
+		resp, ok := result.(*oas.Quote)
+		require.True(t, ok, "an unavailable store must not block coupons, got %T", result)
+		assert.Equal(t, "invalid coupon code", resp.CouponError.Value)
+	}
+}
+
+func TestCouponAdmin(t *testing.T) {
+	ctx := context.Background()
+	h := newTestHandler(newProductRepo(), &mockCouponValidator{}, &mockOrderRepo{})
+	h.coupons = coupon.NewService(&mockCouponRepo{coupons: map[string]*coupon.Coupon{}})
+
+	created, err := h.CreateCoupon(ctx, &oas.CouponCreate{
+		Code:         "spring18",
+		DiscountType: oas.DiscountTypePercentage,
+		Value:        18,
+		MaxUses:      oas.NewOptInt(100),
+	})
+	require.NoError(t, err)
+	c, ok := created.(*oas.Coupon)
+	require.True(t, ok, "expected *oas.Coupon, got %T", created)
+	assert.Equal(t, "SPRING18", c.Code)
+	assert.True(t, c.Active)
+	assert.Equal(t, 100, c.Usage.Remaining.Value)
+
+	targeted, err := h.CreateCoupon(ctx, &oas.CouponCreate{
+		Code:              "WAFFLES20",
+		DiscountType:      oas.DiscountTypePercentage,
+		Value:             20,
+		IncludeCategories: []string{"Waffle"},
+		ExcludeProducts:   []string{"3"},
+	})
+	require.NoError(t, err)
+	c, ok = targeted.(*oas.Coupon)
+	require.True(t, ok, "expected *oas.Coupon, got %T", targeted)
+	assert.Equal(t, []string{"Waffle"}, c.IncludeCategories)
+	assert.Equal(t, []string{"3"}, c.ExcludeProducts)
+	assert.Empty(t, c.IncludeProducts)
+
+	blank, err := h.CreateCoupon(ctx, &oas.CouponCreate{
+		Code:            "BLANK",
+		DiscountType:    oas.DiscountTypeFixed,
+		Value:           5,
+		IncludeProducts: []string{""},
+	})
+	require.NoError(t, err)
+	assert.IsType(t, &oas.CreateCouponUnprocessableEntity{}, blank)
+
+	dup, err := h.CreateCoupon(ctx, &oas.CouponCreate{Code: "Spring18", DiscountType: oas.DiscountTypeFixed, Value: 5})
+	require.NoError(t, err)
+	assert.IsType(t, &oas.CreateCouponConflict{}, dup)
+
+	bad, err := h.CreateCoupon(ctx, &oas.CouponCreate{Code: "BIG", DiscountType: oas.DiscountTypePercentage, Value: 150})
+	require.NoError(t, err)
+	badResp, ok := bad.(*oas.CreateCouponUnprocessableEntity)
+	require.True(t, ok, "expected *oas.CreateCouponUnprocessableEntity, got %T", bad)
+	assert.Equal(t, "value must be greater than 0 and at most 100 for percentage discounts", badResp.Message)
+
+	updated, err := h.UpdateCoupon(ctx, &oas.CouponRule{
+		DiscountType: oas.DiscountTypePercentage,
+		Value:        20,
+		ValidFrom:    oas.NewOptDateTime(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)),
+		ValidUntil:   oas.NewOptDateTime(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)),
+	}, oas.UpdateCouponParams{Code: "spring18"})
+	require.NoError(t, err)
+	assert.IsType(t, &oas.UpdateCouponUnprocessableEntity{}, updated)
+
+	missing, err := h.UpdateCoupon(ctx, &oas.CouponRule{DiscountType: oas.DiscountTypeFixed, Value: 5},
+		oas.UpdateCouponParams{Code: "NOPE"})
+	require.NoError(t, err)
+	assert.IsType(t, &oas.UpdateCouponNotFound{}, missing)
+
+	deactivated, err := h.DeactivateCoupon(ctx, oas.DeactivateCouponParams{Code: "spring18"})
+	require.NoError(t, err)
+	c, ok = deactivated.(*oas.Coupon)
+	require.True(t, ok, "expected *oas.Coupon, got %T", deactivated)
+	assert.False(t, c.Active)
+
+	got, err := h.GetCoupon(ctx, oas.GetCouponParams{Code: "NOPE"})
+	require.NoError(t, err)
+	assert.IsType(t, &oas.GetCouponNotFound{}, got)
+
+	list, err := h.ListCoupons(ctx, oas.ListCouponsParams{})
+	require.NoError(t, err)
+	page, ok := list.(*oas.CouponList)
+	require.True(t, ok, "expected *oas.CouponList, got %T", list)
+	assert.Len(t, page.Coupons, 2)
+
+	list, err = h.ListCoupons(ctx, oas.ListCouponsParams{Cursor: oas.NewOptString("!!")})
+	require.NoError(t, err)
+	assert.IsType(t, &oas.ListCouponsBadRequest{}, list)
+}
+
+func TestGetOrder(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
+	createdAt := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	orders := &mockOrderRepo{orders: []order.Order{{
+		ID: "o1",
+		Items: []order.OrderItem{{
+			ProductID: "p1",
+			Quantity:  2,
+			Name:      "Widget (old name)",
+			Category:  "test",
+			UnitPrice: decimal.RequireFromString("9.50"),
+			Subtotal:  decimal.RequireFromString("19.00"),
+			Discount:  decimal.RequireFromString("2.00"),
+		}},
+		Total:      decimal.RequireFromString("18.00"),
+		Discounts:  decimal.RequireFromString("2.00"),
+		CouponCode: "SAVE2",
+		CreatedAt:  createdAt,
+	}}}
+	h := newTestHandler(newProductRepo(p1), &mockCouponValidator{}, orders)
+
+	t.Run("found", func(t *testing.T) {
+		result, err := h.GetOrder(context.Background(), oas.GetOrderParams{OrderId: "o1"})
+		require.NoError(t, err)
+
+		resp, ok := result.(*oas.Order)
+		require.True(t, ok, "expected *oas.Order, got %T", result)
+		assert.Equal(t, "o1", resp.ID.Value)
+		assert.InDelta(t, 18.00, resp.Total.Value, 0.01)
+		assert.Equal(t, "SAVE2", resp.CouponCode.Value)
+		assert.True(t, createdAt.Equal(resp.CreatedAt.Value))
+		require.Len(t, resp.Items, 1)
+		assert.Equal(t, "Widget (old name)", resp.Items[0].Name.Value)
+		assert.Equal(t, "test", resp.Items[0].Category.Value)
+		assert.InDelta(t, 9.50, resp.Items[0].UnitPrice.Value, 0.001)
+		assert.InDelta(t, 19.00, resp.Items[0].Subtotal.Value, 0.001)
+		assert.InDelta(t, 2.00, resp.Items[0].Discount.Value, 0.001)
+		require.Len(t, resp.Products, 1)
+		assert.Equal(t, "Widget", resp.Products[0].Name.Value)
+	})
+
+	t.Run("not found returns 404", func(t *testing.T) {
+		result, err := h.GetOrder(context.Background(), oas.GetOrderParams{OrderId: "missing"})
+		require.NoError(t, err)
+
+		notFound, ok := result.(*oas.GetOrderNotFound)
+		require.True(t, ok, "expected *oas.GetOrderNotFound, got %T", result)
+		assert.Equal(t, int32(404), notFound.Code)
+	})
+}
+
+func TestUpdateOrderStatus(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
+	ctx := auth.WithAPIKey(context.Background(), &auth.APIKeyInfo{ID: "kitchen"})
+
+	tests := []struct {
+		name     string
+		orderID  string
+		status   oas.OrderStatus
+		wantCode int32 // 0 means a successful *oas.Order
+	}{
+		{name: "allowed transition", orderID: "o1", status: oas.OrderStatusAccepted},
+		{name: "illegal transition returns 409", orderID: "o1", status: oas.OrderStatusCompleted, wantCode: 409},
+		{name: "unknown order returns 404", orderID: "missing", status: oas.OrderStatusAccepted, wantCode: 404},
+		{name: "unknown status returns 400", orderID: "o1", status: oas.OrderStatus("lost"), wantCode: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := &mockOrderRepo{orders: []order.Order{{
+				ID:     "o1",
+				Items:  []order.OrderItem{{ProductID: "p1", Quantity: 1}},
+				Status: order.StatusPlaced,
+			}}}
+			h := newTestHandler(newProductRepo(p1), &mockCouponValidator{}, orders)
+
+			result, err := h.UpdateOrderStatus(ctx,
+				&oas.OrderStatusUpdate{Status: tt.status, Reason: oas.NewOptString("on it")},
+				oas.UpdateOrderStatusParams{OrderId: tt.orderID},
+			)
+			require.NoError(t, err)
+
+			switch tt.wantCode {
+			case 0:
+				resp, ok := result.(*oas.Order)
+				require.True(t, ok, "expected *oas.Order, got %T", result)
+				assert.Equal(t, oas.OrderStatusAccepted, resp.Status.Value)
+				assert.True(t, resp.StatusUpdatedAt.IsSet())
+				require.Len(t, resp.StatusHistory, 1)
+				assert.Equal(t, oas.OrderStatusPlaced, resp.StatusHistory[0].From.Value)
+				assert.Equal(t, oas.OrderStatusAccepted, resp.StatusHistory[0].To)
+				assert.Equal(t, "kitchen", resp.StatusHistory[0].Actor.Value)
+				assert.Equal(t, "on it", resp.StatusHistory[0].Reason.Value)
+			case 400:
+				resp, ok := result.(*oas.UpdateOrderStatusBadRequest)
+				require.True(t, ok, "expected *oas.UpdateOrderStatusBadRequest, got %T", result)
+				assert.Equal(t, tt.wantCode, resp.Code)
+			case 404:
+				resp, ok := result.(*oas.UpdateOrderStatusNotFound)
+				require.True(t, ok, "expected *oas.UpdateOrderStatusNotFound, got %T", result)
+				assert.Equal(t, tt.wantCode, resp.Code)
+			case 409:
+				resp, ok := result.(*oas.UpdateOrderStatusConflict)
+				require.True(t, ok, "expected *oas.UpdateOrderStatusConflict, got %T", result)
+				assert.Equal(t, tt.wantCode, resp.Code)
+				assert.Equal(t, "cannot change order status from placed to completed", resp.Message)
+			}
+		})
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	ctx := auth.WithAPIKey(context.Background(), &auth.APIKeyInfo{ID: "customer"})
+	reason := oas.NewOptOrderCancel(oas.OrderCancel{Reason: oas.NewOptString("wrong address")})
+
+	tests := []struct {
+		name     string
+		orderID  string
+		status   order.Status
+		wantCode int32 // 0 means a successful *oas.Order
+	}{
+		{name: "cancels placed order", orderID: "o1", status: order.StatusPlaced},
+		{name: "double cancel returns 409", orderID: "o1", status: order.StatusCancelled, wantCode: 409},
+		{name: "unknown order returns 404", orderID: "missing", status: order.StatusPlaced, wantCode: 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := &mockOrderRepo{orders: []order.Order{{ID: "o1", Status: tt.status, CreatedAt: time.Now()}}}
+			h := newTestHandler(newProductRepo(), &mockCouponValidator{}, orders)
+
+			result, err := h.CancelOrder(ctx, reason, oas.CancelOrderParams{OrderId: tt.orderID})
+			require.NoError(t, err)
+
+			switch tt.wantCode {
+			case 0:
+				resp, ok := result.(*oas.Order)
+				require.True(t, ok, "expected *oas.Order, got %T", result)
+				assert.Equal(t, oas.OrderStatusCancelled, resp.Status.Value)
+				require.Len(t, resp.StatusHistory, 1)
+				assert.Equal(t, "wrong address", resp.StatusHistory[0].Reason.Value)
+				assert.Equal(t, "customer", resp.StatusHistory[0].Actor.Value)
+			case 404:
+				resp, ok := result.(*oas.CancelOrderNotFound)
+				require.True(t, ok, "expected *oas.CancelOrderNotFound, got %T", result)
+				assert.Equal(t, tt.wantCode, resp.Code)
+			case 409:
+				resp, ok := result.(*oas.CancelOrderConflict)
+				require.True(t, ok, "expected *oas.CancelOrderConflict, got %T", result)
+				assert.Equal(t, tt.wantCode, resp.Code)
+			}
+		})
+	}
+}
+
+func TestListOrders(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.NewFromInt(10))
+	base := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	orders := &mockOrderRepo{orders: []order.Order{
+		{ID: "o2", Items: []order.OrderItem{{ProductID: "p1", Quantity: 1}}, CreatedAt: base},
+		{ID: "o1", Items: []order.OrderItem{{ProductID: "p1", Quantity: 1}}, CreatedAt: base.Add(-time.Minute)},
+	}}
+	h := newTestHandler(newProductRepo(p1), &mockCouponValidator{}, orders)
+
+	t.Run("page with next cursor", func(t *testing.T) {
+		result, err := h.ListOrders(context.Background(), oas.ListOrdersParams{Limit: oas.NewOptInt(1)})
+		require.NoError(t, err)
+
+		resp, ok := result.(*oas.OrderList)
+		require.True(t, ok, "expected *oas.OrderList, got %T", result)
+		require.Len(t, resp.Orders, 1)
+		assert.Equal(t, "o2", resp.Orders[0].ID.Value)
+		assert.True(t, resp.NextCursor.IsSet())
+	})
+
+	t.Run("invalid cursor returns 400", func(t *testing.T) {
+		result, err := h.ListOrders(context.Background(), oas.ListOrdersParams{Cursor: oas.NewOptString("%%%")})
+		require.NoError(t, err)
+
+		resp, ok := result.(*oas.ListOrdersBadRequest)
+		require.True(t, ok, "expected *oas.ListOrdersBadRequest, got %T", result)
+		assert.Equal(t, int32(400), resp.Code)
+	})
+
+	t.Run("inverted date range returns 400", func(t *testing.T) {
+		result, err := h.ListOrders(context.Background(), oas.ListOrdersParams{
+			From: oas.NewOptDateTime(base),
+			To:   oas.NewOptDateTime(base.Add(-time.Hour)),
+		})
+		require.NoError(t, err)
+
+		_, ok := result.(*oas.ListOrdersBadRequest)
+		require.True(t, ok, "expected *oas.ListOrdersBadRequest, got %T", result)
+	})
+}
+
+func TestCartFlow(t *testing.T) {
+	ctx := auth.WithAPIKey(context.Background(), &auth.APIKeyInfo{ID: "key-1"})
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
+	cv := &mockCouponValidator{discount: &coupon.Discount{
+		Amount:      decimal.RequireFromString("2.00"),
+		Description: "$2 off",
+	}}
+	orders := &mockOrderRepo{}
+	h := newTestHandler(newProductRepo(p1), cv, orders)
+
+	created, err := h.CreateCart(ctx)
+	require.NoError(t, err)
+	c, ok := created.(*oas.Cart)
+	require.True(t, ok, "expected *oas.Cart, got %T", created)
+	assert.Empty(t, c.Items)
+	assert.Zero(t, c.Total)
+	cartID := c.ID
+
+	t.Run("unknown product returns 422", func(t *testing.T) {
+		result, err := h.SetCartItem(ctx, &oas.CartItemUpdate{Quantity: 1},
+			oas.SetCartItemParams{CartId: cartID, ProductId: "nope"})
+		require.NoError(t, err)
+		resp, ok := result.(*oas.SetCartItemUnprocessableEntity)
+		require.True(t, ok, "expected *oas.SetCartItemUnprocessableEntity, got %T", result)
+		assert.Equal(t, "product nope not found", resp.Message)
+	})
+
+	t.Run("other key gets 404", func(t *testing.T) {
+		otherCtx := auth.WithAPIKey(context.Background(), &auth.APIKeyInfo{ID: "key-2"})
+		result, err := h.GetCart(otherCtx, oas.GetCartParams{CartId: cartID})
+		require.NoError(t, err)
+		_, ok := result.(*oas.GetCartNotFound)
+		require.True(t, ok, "expected *oas.GetCartNotFound, got %T", result)
+	})
+
+	set, err := h.SetCartItem(ctx, &oas.CartItemUpdate{Quantity: 2},
+		oas.SetCartItemParams{CartId: cartID, ProductId: "p1"})
+	require.NoError(t, err)
+	require.IsType(t, &oas.Cart{}, set)
+
+	result, err := h.AttachCartCoupon(ctx, &oas.CartCoupon{Code: "SAVE2"},
+		oas.AttachCartCouponParams{CartId: cartID})
+	require.NoError(t, err)
+	c, ok = result.(*oas.Cart)
+	require.True(t, ok, "expected *oas.Cart, got %T", result)
+	assert.InDelta(t, 20.00, c.Subtotal, 0.001)
+	assert.InDelta(t, 2.00, c.Discount, 0.001)
+	assert.InDelta(t, 18.00, c.Total, 0.001)
+	assert.Equal(t, "SAVE2", c.CouponCode.Value)
+	assert.Equal(t, "$2 off", c.CouponDescription.Value)
+	require.Len(t, c.Items, 1)
+	assert.Equal(t, 2, c.Items[0].Quantity.Value)
+	assert.Nil(t, orders.lastOrder, "pricing a cart must not place an order")
+
+	checkout, err := h.CheckoutCart(ctx, oas.CheckoutCartParams{CartId: cartID})
+	require.NoError(t, err)
+	o, ok := checkout.(*oas.Order)
+	require.True(t, ok, "expected *oas.Order, got %T", checkout)
+	assert.InDelta(t, 18.00, o.Total.Value, 0.001)
+	assert.Equal(t, "SAVE2", o.CouponCode.Value)
+	require.NotNil(t, orders.lastOrder)
+
+	checkout, err = h.CheckoutCart(ctx, oas.CheckoutCartParams{CartId: cartID})
+	require.NoError(t, err)
+	_, ok = checkout.(*oas.CheckoutCartNotFound)
+	require.True(t, ok, "expected *oas.CheckoutCartNotFound, got %T", checkout)
+}
+
+func TestHandleAPIKey(t *testing.T) {
+	pepper := []byte("test-pepper-secret")
+	peppers := auth.Peppers{{Version: 1, Secret: pepper}}
+
+	t.Run("valid key returns context", func(t *testing.T) {
+		apiKey := "my-secret-key"
+		mac := hmac.New(sha256.New, pepper)
+		mac.Write([]byte(apiKey))
+		hexHash := hex.EncodeToString(mac.Sum(nil))
+
+		sh := NewSecurityHandler(
+			&mockAPIKeyRepo{
+				info: &auth.APIKeyInfo{
+					ID:      "key-1",
+					KeyHash: hexHash,
+					Name:    "test-key",
+					Scopes:  []string{"orders:write"},
+				},
+			},
+			peppers,
+			nil,
+			nil,
+		)
+
+		ctx := context.Background()
+		resultCtx, err := sh.HandleAPIKey(ctx, oas.PlaceOrderOperation, oas.APIKey{APIKey: apiKey})
+		require.NoError(t, err)
+		info, ok := auth.FromContext(resultCtx)
+		require.True(t, ok, "api key info should be stored in context")
+		assert.Equal(t, "key-1", info.ID)
+	})
+
+	t.Run("invalid key returns error", func(t *testing.T) {
+		sh := NewSecurityHandler(
+			&mockAPIKeyRepo{
+				err: errors.New("not found"),
+			},
+			peppers,
+			nil,
+			nil,
+		)
+
+		ctx := context.Background()
+		_, err := sh.HandleAPIKey(ctx, oas.PlaceOrderOperation, oas.APIKey{APIKey: "bad-key"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unauthorized")
+	})
+
+	t.Run("missing scope returns scope error", func(t *testing.T) {
+		apiKey := "my-secret-key"
+		mac := hmac.New(sha256.New, pepper)
+		mac.Write([]byte(apiKey))
+		sh := NewSecurityHandler(
+			&mockAPIKeyRepo{
+				info: &auth.APIKeyInfo{
+					ID:      "key-1",
+					KeyHash: hex.EncodeToString(mac.Sum(nil)),
+					Scopes:  []string{auth.ScopeOrdersRead, auth.ScopeOrdersWrite},
+				},
+			},
+			peppers,
+			nil,
+			nil,
+		)
+		ctx := context.Background()
+
+		_, err := sh.HandleAPIKey(ctx, oas.ListOrdersOperation, oas.APIKey{APIKey: apiKey})
+		require.NoError(t, err)
+
+		_, err = sh.HandleAPIKey(ctx, oas.UpdateOrderStatusOperation, oas.APIKey{APIKey: apiKey})
+		var scopeErr *ScopeError
+		require.ErrorAs(t, err, &scopeErr)
+		assert.Equal(t, auth.ScopeOrdersAdmin, scopeErr.Scope)
+
+		_, err = sh.V2().HandleAPIKey(ctx, oasv2.UpdateOrderStatusOperation, oasv2.APIKey{APIKey: apiKey})
+		require.ErrorAs(t, err, &scopeErr)
+
+		_, err = sh.HandleAPIKey(ctx, "SomeNewOperation", oas.APIKey{APIKey: apiKey})
+		require.ErrorAs(t, err, &scopeErr)
+		assert.Empty(t, scopeErr.Scope)
+	})
+
+	t.Run("expired key is refused and use is tracked", func(t *testing.T) {
+		apiKey := "my-secret-key"
+		mac := hmac.New(sha256.New, pepper)
+		mac.Write([]byte(apiKey))
+		now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+		expires := now.Add(time.Hour)
+		repo := &mockAPIKeyRepo{
+			info: &auth.APIKeyInfo{
+				ID:        "key-1",
+				KeyHash:   hex.EncodeToString(mac.Sum(nil)),
+				Prefix:    "my-s",
+				Scopes:    []string{auth.ScopeOrdersWrite},
+				ExpiresAt: &expires,
+			},
+		}
+		usage := &mockUsageStore{}
+		tracker := auth.NewUsageTracker(usage)
+		sh := NewSecurityHandler(repo, peppers, tracker, nil)
+		sh.now = func() time.Time { return now }
+		ctx := context.Background()
+
+		_, err := sh.HandleAPIKey(ctx, oas.PlaceOrderOperation, oas.APIKey{APIKey: apiKey})
+		require.NoError(t, err)
+		_, err = tracker.Flush(ctx)
+		require.NoError(t, err)
+		require.Len(t, usage.batches, 1)
+		assert.Contains(t, usage.batches[0], "key-1")
+
+		now = expires
+		_, err = sh.HandleAPIKey(ctx, oas.PlaceOrderOperation, oas.APIKey{APIKey: apiKey})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unauthorized")
+		n, err := tracker.Flush(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, n, "expired key must not be marked as used")
+	})
+
+	t.Run("key under old pepper is rehashed", func(t *testing.T) {
+		apiKey := "my-secret-key"
+		oldPepper := auth.Pepper{Version: 1, Secret: []byte("old")}
+		newPepper := auth.Pepper{Version: 2, Secret: []byte("new")}
+		repo := &mockAPIKeyRepo{
+			info: &auth.APIKeyInfo{
+				ID:            "key-1",
+				KeyHash:       oldPepper.Hash(apiKey),
+				Scopes:        []string{auth.ScopeOrdersWrite},
+				PepperVersion: 1,
+			},
+		}
+		sh := NewSecurityHandler(repo, auth.Peppers{newPepper, oldPepper}, nil, nil)
+		ctx := context.Background()
+
+		resultCtx, err := sh.HandleAPIKey(ctx, oas.PlaceOrderOperation, oas.APIKey{APIKey: apiKey})
+		require.NoError(t, err)
+		assert.Equal(t, 2, repo.lookups, "current pepper is tried first")
+		assert.Equal(t, 1, repo.rehashs)
+		assert.Equal(t, newPepper.Hash(apiKey), repo.info.KeyHash)
+		assert.Equal(t, 2, repo.info.PepperVersion)
+		info, ok := auth.FromContext(resultCtx)
+		require.True(t, ok)
+		assert.Equal(t, 2, info.PepperVersion)
+
+		repo.lookups = 0
+		_, err = sh.HandleAPIKey(ctx, oas.PlaceOrderOperation, oas.APIKey{APIKey: apiKey})
+		require.NoError(t, err)
+		assert.Equal(t, 1, repo.lookups)
+		assert.Equal(t, 1, repo.rehashs, "migrated key is not rehashed again")
+
+		// The migrated key keeps working once the old pepper is dropped.
+		sh = NewSecurityHandler(repo, auth.Peppers{newPepper}, nil, nil)
+		_, err = sh.HandleAPIKey(ctx, oas.PlaceOrderOperation, oas.APIKey{APIKey: apiKey})
+		require.NoError(t, err)
+	})
+
+	t.Run("unknown key tries every pepper", func(t *testing.T) {
+		repo := &mockAPIKeyRepo{info: &auth.APIKeyInfo{ID: "key-1", KeyHash: "00"}}
+		sh := NewSecurityHandler(repo, auth.Peppers{
+			{Version: 2, Secret: []byte("new")},
+			{Version: 1, Secret: []byte("old")},
+		}, nil, nil)
+
+		_, err := sh.HandleAPIKey(context.Background(), oas.PlaceOrderOperation, oas.APIKey{APIKey: "bad-key"})
+		require.Error(t, err)
+		assert.Equal(t, 2, repo.lookups)
+		assert.Zero(t, repo.rehashs)
+	})
+}
+
+func TestHandleBearerAuth(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	raw, err := key.PublicKey.Bytes()
+	require.NoError(t, err)
+	jwks, err := auth.ParseJWKS(fmt.Appendf(nil,
+		`{"keys":[{"kty":"EC","kid":"k1","crv":"P-256","x":%q,"y":%q}]}`,
+		base64.RawURLEncoding.EncodeToString(raw[1:33]),
+		base64.RawURLEncoding.EncodeToString(raw[33:]),
+	))
+	require.NoError(t, err)
+
+	sign := func(scope string) string {
+		tok := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+			"sub":   "billing",
+			"scope": scope,
+			"exp":   time.Now().Add(time.Minute).Unix(),
+		})
+		tok.Header["kid"] = "k1"
+		s, err := tok.SignedString(key)
+		require.NoError(t, err)
+		return s
+	}
+	sh := NewSecurityHandler(&mockAPIKeyRepo{}, auth.Peppers{{Version: 1}}, nil,
+		auth.NewTokenVerifier(auth.TokenConfig{}, jwks))
+	ctx := context.Background()
+
+	t.Run("valid token returns context", func(t *testing.T) {
+		resultCtx, err := sh.HandleBearerAuth(ctx, oas.ListOrdersOperation, oas.BearerAuth{Token: sign("orders:read")})
+		require.NoError(t, err)
+		info, ok := auth.FromContext(resultCtx)
+		require.True(t, ok)
+		assert.Equal(t, "jwt:billing", info.ID)
+
+		_, err = sh.V2().HandleBearerAuth(ctx, oasv2.ListOrdersOperation, oasv2.BearerAuth{Token: sign("orders:read")})
+		require.NoError(t, err)
+	})
+
+	t.Run("missing scope returns scope error", func(t *testing.T) {
+		_, err := sh.HandleBearerAuth(ctx, oas.UpdateOrderStatusOperation, oas.BearerAuth{Token: sign("orders:read")})
+		var scopeErr *ScopeError
+		require.ErrorAs(t, err, &scopeErr)
+		assert.Equal(t, auth.ScopeOrdersAdmin, scopeErr.Scope)
+	})
+
+	t.Run("invalid token returns error", func(t *testing.T) {
+		_, err := sh.HandleBearerAuth(ctx, oas.ListOrdersOperation, oas.BearerAuth{Token: "bad"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unauthorized")
+	})
+
+	t.Run("tokens are refused without a verifier", func(t *testing.T) {
+		sh := NewSecurityHandler(&mockAPIKeyRepo{}, auth.Peppers{{Version: 1}}, nil, nil)
+		_, err := sh.HandleBearerAuth(ctx, oas.ListOrdersOperation, oas.BearerAuth{Token: sign("orders:read")})
+		require.Error(t, err)
+	})
+}
+
+func TestRateLimitKey(t *testing.T) {
+	pepper := auth.Pepper{Version: 1, Secret: []byte("test-pepper-secret")}
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(time.Hour)
+	repo := &mockAPIKeyRepo{info: &auth.APIKeyInfo{
+		ID:            "key-1",
+		KeyHash:       pepper.Hash("my-secret-key"),
+		RateLimitPlan: "pro",
+		ExpiresAt:     &expires,
+	}}
+	sh := NewSecurityHandler(repo, auth.Peppers{pepper}, nil, nil)
+	sh.now = func() time.Time { return now }
+
+	request := func(header, value string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/product", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		return req
+	}
+
+	key, plan := sh.RateLimitKey(request("api_key", "my-secret-key"))
+	assert.Equal(t, "key:key-1", key)
+	assert.Equal(t, "pro", plan)
+
+	for name, req := range map[string]*http.Request{
+		"anonymous":   request("", ""),
+		"unknown key": request("api_key", "bad-key"),
+		"bad token":   request("Authorization", "Bearer bad"),
+		"signed":      request(HeaderKeyID, "key-1"),
+	} {
+		key, plan := sh.RateLimitKey(req)
+		assert.Empty(t, key, name)
+		assert.Empty(t, plan, name)
+	}
+
+	now = expires
+	key, _ = sh.RateLimitKey(request("api_key", "my-secret-key"))
+	assert.Empty(t, key, "expired keys are limited by IP")
+}
+
+func TestResolveAPIKeys(t *testing.T) {
+	old := auth.Pepper{Version: 1, Secret: []byte("old-pepper-secret")}
+	cur := auth.Pepper{Version: 2, Secret: []byte("new-pepper-secret")}
+	repo := &mockAPIKeyRepo{info: &auth.APIKeyInfo{
+		ID:            "key-1",
+		KeyHash:       cur.Hash("my-secret-key"),
+		PepperVersion: 2,
+		Scopes:        []string{auth.ScopeOrdersRead},
+	}}
+	sh := NewSecurityHandler(repo, auth.Peppers{cur, old}, nil, nil)
+
+	serve := func(apiKey string) error {
+		var authErr error
+		h := sh.ResolveAPIKeys()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			sh.RateLimitKey(r)
+			_, authErr = sh.HandleAPIKey(r.Context(), "ListOrders", oas.APIKey{APIKey: apiKey})
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/api/product", nil)
+		req.Header.Set("api_key", apiKey)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		return authErr
+	}
+
+	require.NoError(t, serve("my-secret-key"))
+	assert.Equal(t, 1, repo.lookups, "the rate limiter and authentication share one lookup")
+
+	repo.lookups = 0
+	require.Error(t, serve("bad-key"))
+	assert.Equal(t, 2, repo.lookups, "an unknown key is looked up once per pepper")
+}
+
+func TestVerifySignedRequests(t *testing.T) {
+	const secret = "kart_test_secret"
+	pepper := auth.Pepper{Version: 1, Secret: []byte("pepper")}
+	stored, err := pepper.Protect("key-1", secret)
+	require.NoError(t, err)
+	repo := &mockAPIKeyRepo{
+		info:   &auth.APIKeyInfo{ID: "key-1", Scopes: []string{auth.ScopeOrdersRead, auth.ScopeOrdersWrite}, PepperVersion: 1},
+		sealed: stored.SigningKey,
+	}
+	sh := NewSecurityHandler(repo, auth.Peppers{pepper}, nil, nil)
+	verifier := auth.NewRequestVerifier(repo, auth.Peppers{pepper}, auth.NewMemoryNonceStore(), 5*time.Minute)
+
+	// The wrapped handler authenticates as ogen would and echoes the body.
+	srv := VerifySignedRequests(verifier)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := sh.HandleSignedRequest(r.Context(), oas.PlaceOrderOperation, oas.SignedRequest{})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		info, _ := auth.FromContext(ctx)
+		body, _ := io.ReadAll(r.Body)
+		_, _ = fmt.Fprintf(w, "%s %s", info.ID, body)
+	}))
+
+	send := func(nonce, signedBody, body string) *httptest.ResponseRecorder {
+		sr := &auth.SignedRequest{
+			KeyID:     "key-1",
+			Method:    http.MethodPost,
+			Path:      "/api/order",
+			Query:     "v=1",
+			Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+			Nonce:     nonce,
+			BodyHash:  sha256.Sum256([]byte(signedBody)),
+		}
+		req := httptest.NewRequest(http.MethodPost, "/api/order?v=1", strings.NewReader(body))
+		req.Header.Set(HeaderKeyID, sr.KeyID)
+		req.Header.Set(HeaderTimestamp, sr.Timestamp)
+		req.Header.Set(HeaderNonce, sr.Nonce)
+		req.Header.Set(HeaderSignature, sr.Sign(auth.SigningKey(secret)))
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("valid signature authenticates and keeps the body", func(t *testing.T) {
+		rec := send("nonce-0000000001", `{"items":[]}`, `{"items":[]}`)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, `key-1 {"items":[]}`, rec.Body.String())
+	})
+
+	t.Run("replayed request is refused", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, send("nonce-0000000002", `{}`, `{}`).Code)
+		rec := send("nonce-0000000002", `{}`, `{}`)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), "unauthorized")
+	})
+
+	t.Run("altered body is refused", func(t *testing.T) {
+		rec := send("nonce-0000000003", `{"items":[]}`, `{"items":[{"productId":"1","quantity":9}]}`)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("unsigned request is not treated as signed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/order", strings.NewReader(`{}`)))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), "not accepted")
+	})
+
+	t.Run("missing scope returns scope error", func(t *testing.T) {
+		repo.info.Scopes = []string{auth.ScopeOrdersRead}
+		t.Cleanup(func() { repo.info.Scopes = []string{auth.ScopeOrdersRead, auth.ScopeOrdersWrite} })
+		rec := send("nonce-0000000004", `{}`, `{}`)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), auth.ScopeOrdersWrite)
+	})
+}
+
+func TestAuditMutations(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.NewFromInt(5))
+	h := newTestHandler(newProductRepo(p1), &mockCouponValidator{}, &mockOrderRepo{})
+	repo := &mockAuditRepo{}
+	h.audit = audit.NewService(repo)
+
+	pepper := auth.Pepper{Version: 1, Secret: []byte("pepper")}
+	keys := &mockAPIKeyRepo{info: &auth.APIKeyInfo{
+		ID:            "key-1",
+		KeyHash:       pepper.Hash("secret"),
+		Scopes:        []string{auth.ScopeOrdersRead, auth.ScopeOrdersWrite, auth.ScopeAuditRead},
+		PepperVersion: 1,
+	}}
+	srv, err := oas.NewServer(h, NewSecurityHandler(keys, auth.Peppers{pepper}, nil, nil),
+		oas.WithPathPrefix("/api"),
+		oas.WithErrorHandler(ErrorHandler),
+	)
+	require.NoError(t, err)
+	api := httpmiddleware.Wrap(srv,
+		httpmiddleware.RequestID(),
+		httpmiddleware.ClientIP(nil),
+		AuditMutations(h.audit, httpmiddleware.MakeRouteFinder(srv)),
+	)
+	do := func(method, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Request-ID", "req-"+path)
+		req.RemoteAddr = "203.0.113.9:4711"
+		if key != "" {
+			req.Header.Set("api_key", key)
+		}
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/api/order", "secret", `{"items":[{"productId":"p1","quantity":1}]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(http.MethodPost, "/api/order/missing/cancel", "secret", `{}`)
+	require.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+	rec = do(http.MethodPatch, "/api/order/o1/status", "secret", `{"status":"accepted"}`)
+	require.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+	// Not recorded: no actor, and not a mutation.
+	require.Equal(t, http.StatusUnauthorized, do(http.MethodPost, "/api/order", "wrong", `{"items":[]}`).Code)
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/api/order", "secret", "").Code)
+
+	require.Len(t, repo.events, 3)
+	placed := repo.events[0]
+	assert.Equal(t, "key-1", placed.Actor)
+	assert.Equal(t, "placeOrder", placed.Operation)
+	assert.Equal(t, "req-/api/order", placed.RequestID)
+	assert.Equal(t, "203.0.113.9", placed.ClientIP)
+	assert.Equal(t, "order", placed.TargetType)
+	assert.NotEmpty(t, placed.TargetID)
+	assert.Equal(t, audit.OutcomeSuccess, placed.Outcome)
+
+	assert.Equal(t, "cancelOrder", repo.events[1].Operation)
+	assert.Equal(t, "missing", repo.events[1].TargetID)
+	assert.Equal(t, audit.OutcomeRejected, repo.events[1].Outcome)
+	assert.Equal(t, http.StatusNotFound, repo.events[1].StatusCode)
+
+	assert.Equal(t, "updateOrderStatus", repo.events[2].Operation)
+	assert.Equal(t, audit.OutcomeDenied, repo.events[2].Outcome)
+
+	t.Run("events can be listed by actor", func(t *testing.T) {
+		rec := do(http.MethodGet, "/api/audit?actor=key-1&limit=2", "secret", "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var list oas.AuditEventList
+		require.NoError(t, list.UnmarshalJSON(rec.Body.Bytes()))
+		require.Len(t, list.Events, 2)
+		assert.Equal(t, "updateOrderStatus", list.Events[0].Operation)
+		assert.Equal(t, oas.AuditEventOutcomeDenied, list.Events[0].Outcome)
+
+		res, err := h.ListAuditEvents(context.Background(), oas.ListAuditEventsParams{Actor: oas.NewOptString("other")})
+		require.NoError(t, err)
+		assert.Empty(t, res.(*oas.AuditEventList).Events)
+
+		res, err = h.ListAuditEvents(context.Background(), oas.ListAuditEventsParams{Cursor: oas.NewOptString("!!")})
+		require.NoError(t, err)
+		_, ok := res.(*oas.ListAuditEventsBadRequest)
+		assert.True(t, ok, "expected *oas.ListAuditEventsBadRequest, got %T", res)
+	})
+}
+
+func TestAuditMutations_OrderTargets(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.NewFromInt(5))
+	orders := &mockOrderRepo{}
+	h := newTestHandler(newProductRepo(p1), &mockCouponValidator{}, orders)
+	repo := &mockAuditRepo{}
+	h.audit = audit.NewService(repo)
+
+	pepper := auth.Pepper{Version: 1, Secret: []byte("pepper")}
+	keys := &mockAPIKeyRepo{info: &auth.APIKeyInfo{
+		ID:            "key-1",
+		KeyHash:       pepper.Hash("secret"),
+		Scopes:        []string{auth.ScopeOrdersWrite},
+		PepperVersion: 1,
+	}}
+	sec := NewSecurityHandler(keys, auth.Peppers{pepper}, nil, nil)
+	v1, err := oas.NewServer(h, sec, oas.WithPathPrefix("/api"), oas.WithErrorHandler(ErrorHandler))
+	require.NoError(t, err)
+	v2, err := oasv2.NewServer(NewV2Handler(h), sec.V2(), oasv2.WithPathPrefix("/api/v2"), oasv2.WithErrorHandler(ErrorHandler))
+	require.NoError(t, err)
+	servers := map[string]http.Handler{
+		"/api/":    httpmiddleware.Wrap(v1, AuditMutations(h.audit, httpmiddleware.MakeRouteFinder(v1))),
+		"/api/v2/": httpmiddleware.Wrap(v2, AuditMutations(h.audit, httpmiddleware.MakeRouteFinder(v2))),
+	}
+	place := func(prefix, idemKey string) {
+		req := httptest.NewRequest(http.MethodPost, prefix+"order",
+			strings.NewReader(`{"items":[{"productId":"p1","quantity":1}]}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("api_key", "secret")
+		if idemKey != "" {
+			req.Header.Set("Idempotency-Key", idemKey)
+		}
+		rec := httptest.NewRecorder()
+		servers[prefix].ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	for _, prefix := range []string{"/api/", "/api/v2/"} {
+		t.Run(prefix, func(t *testing.T) {
+			repo.events = nil
+
+			place(prefix, "")
+			require.Len(t, repo.events, 1)
+			assert.Equal(t, "order", repo.events[0].TargetType)
+			assert.Equal(t, orders.lastOrder.ID, repo.events[0].TargetID)
+
+			// A replay is attributed to the order the original request placed.
+			place(prefix, "idem-"+prefix)
+			placed := orders.lastOrder.ID
+			place(prefix, "idem-"+prefix)
+			assert.Equal(t, placed, orders.lastOrder.ID, "replay must not place another order")
+			require.Len(t, repo.events, 3)
+			assert.Equal(t, placed, repo.events[1].TargetID)
+			assert.Equal(t, placed, repo.events[2].TargetID)
+		})
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	t.Run("scope error is 403", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		err := &ogenerrors.SecurityError{Err: &ScopeError{Operation: "UpdateOrderStatus", Scope: auth.ScopeOrdersAdmin}}
+		ErrorHandler(context.Background(), rec, httptest.NewRequest(http.MethodPatch, "/", nil), err)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.JSONEq(t, `{"code":403,"message":"api key lacks scope orders:admin"}`, rec.Body.String())
+	})
+
+	t.Run("bad key stays 401", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		err := &ogenerrors.SecurityError{Err: errors.New("unauthorized")}
+		ErrorHandler(context.Background(), rec, httptest.NewRequest(http.MethodGet, "/", nil), err)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestV2_Money(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("0.10"))
+	cv := &mockCouponValidator{discount: &coupon.Discount{
+		Amount:      decimal.RequireFromString("0.05"),
+		Description: "5c off",
+	}}
+	h := newTestHandler(newProductRepo(p1), cv, &mockOrderRepo{})
+	h.currency = "AUD"
+	v := NewV2Handler(h)
+	ctx := auth.WithAPIKey(context.Background(), &auth.APIKeyInfo{ID: "key-1"})
+	req := &oasv2.OrderReq{
+		Items:      []oasv2.OrderReqItemsItem{{ProductId: "p1", Quantity: 3}},
+		CouponCode: oasv2.NewOptString("SAVE5"),
+	}
+
+	products, err := v.ListProducts(ctx)
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, oasv2.Money{Amount: "0.10", Currency: "AUD"}, products[0].Price.Value)
+
+	quote, err := v.QuoteOrder(ctx, req)
+	require.NoError(t, err)
+	q, ok := quote.(*oasv2.Quote)
+	require.True(t, ok, "expected *oasv2.Quote, got %T", quote)
+	assert.Equal(t, "0.30", q.Subtotal.Amount)
+	assert.Equal(t, "0.05", q.Discount.Amount)
+	assert.Equal(t, "0.25", q.Total.Amount)
+	assert.Equal(t, "AUD", q.Total.Currency)
+
+	placed, err := v.PlaceOrder(ctx, req, oasv2.PlaceOrderParams{})
+	require.NoError(t, err)
+	o, ok := placed.(*oasv2.Order)
+	require.True(t, ok, "expected *oasv2.Order, got %T", placed)
+	assert.Equal(t, oasv2.Money{Amount: "0.25", Currency: "AUD"}, o.Total.Value)
+	require.Len(t, o.Items, 1)
+	assert.Equal(t, "0.10", o.Items[0].UnitPrice.Value.Amount)
+	assert.Equal(t, "0.30", o.Items[0].Subtotal.Value.Amount)
+}
+
+func TestV2_CartFlow(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("0.10"))
+	cv := &mockCouponValidator{discount: &coupon.Discount{
+		Amount:      decimal.RequireFromString("0.05"),
+		Description: "5c off",
+	}}
+	orders := &mockOrderRepo{}
+	h := newTestHandler(newProductRepo(p1), cv, orders)
+	h.currency = "AUD"
+	v := NewV2Handler(h)
+	ctx := auth.WithAPIKey(context.Background(), &auth.APIKeyInfo{ID: "key-1"})
+
+	created, err := v.CreateCart(ctx)
+	require.NoError(t, err)
+	c, ok := created.(*oasv2.Cart)
+	require.True(t, ok, "expected *oasv2.Cart, got %T", created)
+	assert.Equal(t, oasv2.Money{Amount: "0.00", Currency: "AUD"}, c.Total)
+	cartID := c.ID
+
+	_, err = v.SetCartItem(ctx, &oasv2.CartItemUpdate{Quantity: 3},
+		oasv2.SetCartItemParams{CartId: cartID, ProductId: "p1"})
+	require.NoError(t, err)
+
+	result, err := v.AttachCartCoupon(ctx, &oasv2.CartCoupon{Code: "SAVE5"},
+		oasv2.AttachCartCouponParams{CartId: cartID})
+	require.NoError(t, err)
+	c, ok = result.(*oasv2.Cart)
+	require.True(t, ok, "expected *oasv2.Cart, got %T", result)
+	assert.Equal(t, "0.30", c.Subtotal.Amount)
+	assert.Equal(t, "0.05", c.Discount.Amount)
+	assert.Equal(t, oasv2.Money{Amount: "0.25", Currency: "AUD"}, c.Total)
+	require.Len(t, c.Items, 1)
+	assert.Equal(t, "0.10", c.Items[0].UnitPrice.Value.Amount)
+
+	otherCtx := auth.WithAPIKey(context.Background(), &auth.APIKeyInfo{ID: "key-2"})
+	got, err := v.GetCart(otherCtx, oasv2.GetCartParams{CartId: cartID})
+	require.NoError(t, err)
+	_, ok = got.(*oasv2.GetCartNotFound)
+	require.True(t, ok, "expected *oasv2.GetCartNotFound, got %T", got)
+
+	checkout, err := v.CheckoutCart(ctx, oasv2.CheckoutCartParams{CartId: cartID})
+	require.NoError(t, err)
+	o, ok := checkout.(*oasv2.Order)
+	require.True(t, ok, "expected *oasv2.Order, got %T", checkout)
+	assert.Equal(t, oasv2.Money{Amount: "0.25", Currency: "AUD"}, o.Total.Value)
+	assert.Equal(t, "SAVE5", o.CouponCode.Value)
+	require.NotNil(t, orders.lastOrder)
+
+	checkout, err = v.CheckoutCart(ctx, oasv2.CheckoutCartParams{CartId: cartID})
+	require.NoError(t, err)
+	_, ok = checkout.(*oasv2.CheckoutCartNotFound)
+	require.True(t, ok, "expected *oasv2.CheckoutCartNotFound, got %T", checkout)
+}
+
+func TestV2_Errors(t *testing.T) {
+	h := newTestHandler(newProductRepo(), &mockCouponValidator{}, &mockOrderRepo{})
+	v := NewV2Handler(h)
+	ctx := context.Background()
+
+	result, err := v.PlaceOrder(ctx, &oasv2.OrderReq{
+		Items: []oasv2.OrderReqItemsItem{{ProductId: "nope", Quantity: 1}},
+	}, oasv2.PlaceOrderParams{})
+	require.NoError(t, err)
+	unprocessable, ok := result.(*oasv2.PlaceOrderUnprocessableEntity)
+	require.True(t, ok, "expected *oasv2.PlaceOrderUnprocessableEntity, got %T", result)
+	assert.Equal(t, "product nope not found", unprocessable.Message)
+
+	got, err := v.GetOrder(ctx, oasv2.GetOrderParams{OrderId: "missing"})
+	require.NoError(t, err)
+	_, ok = got.(*oasv2.GetOrderNotFound)
+	require.True(t, ok, "expected *oasv2.GetOrderNotFound, got %T", got)
+}
+
+func TestV2_IdempotencyKeyIsBoundToVersion(t *testing.T) {
+	p1 := newTestProduct("p1", "Widget", decimal.RequireFromString("10.00"))
+	orders := &mockOrderRepo{}
+	h := newTestHandler(newProductRepo(p1), &mockCouponValidator{}, orders)
+	v := NewV2Handler(h)
+	ctx := auth.WithAPIKey(context.Background(), &auth.APIKeyInfo{ID: "key-1"})
+
+	_, err := h.PlaceOrder(ctx,
+		&oas.OrderReq{Items: []oas.OrderReqItemsItem{{ProductId: "p1", Quantity: 1}}},
+		oas.PlaceOrderParams{IdempotencyKey: oas.NewOptString("retry-1")},
+	)
+	require.NoError(t, err)
+
+	orders.lastOrder = nil
+	result, err := v.PlaceOrder(ctx,
+		&oasv2.OrderReq{Items: []oasv2.OrderReqItemsItem{{ProductId: "p1", Quantity: 1}}},
+		oasv2.PlaceOrderParams{IdempotencyKey: oasv2.NewOptString("retry-1")},
+	)
+	require.NoError(t, err)
+	_, ok := result.(*oasv2.PlaceOrderUnprocessableEntity)
+	require.True(t, ok, "expected *oasv2.PlaceOrderUnprocessableEntity, got %T", result)
+	assert.Nil(t, orders.lastOrder)
+
+	replayed, err := v.PlaceOrder(ctx,
+		&oasv2.OrderReq{Items: []oasv2.OrderReqItemsItem{{ProductId: "p1", Quantity: 1}}},
+		oasv2.PlaceOrderParams{IdempotencyKey: oasv2.NewOptString("retry-2")},
+	)
+	require.NoError(t, err)
+	first, ok := replayed.(*oasv2.Order)
+	require.True(t, ok, "expected *oasv2.Order, got %T", replayed)
+
+	replayed, err = v.PlaceOrder(ctx,
+		&oasv2.OrderReq{Items: []oasv2.OrderReqItemsItem{{ProductId: "p1", Quantity: 1}}},
+		oasv2.PlaceOrderParams{IdempotencyKey: oasv2.NewOptString("retry-2")},
+	)
+	require.NoError(t, err)
+	second, ok := replayed.(*oasv2.Order)
+	require.True(t, ok, "expected *oasv2.Order, got %T", replayed)
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, "10.00", second.Total.Value.Amount)
+}
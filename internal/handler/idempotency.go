@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/sdk/zctx"
+	"go.uber.org/zap"
+
+	"github.com/xenking/oolio-kart-challenge/gen/oas"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/idempotency"
+)
+
+// idempotentCodec stores and restores the responses of one operation run
+// under an Idempotency-Key, and builds its error responses for keys that
+// cannot be used.
+type idempotentCodec[R any] struct {
+	encode func(R) (int, []byte, error)
+	decode func(status int, body []byte) (R, error)
+	// reject builds the 409 or 422 response for a key that is in progress
+	// or was reused with a different request.
+	reject func(status int32, msg string) R
+	// target returns the ID of the entity a response created, or "", so a
+	// replay is audited against the same entity as the original request.
+	target func(R) string
+	// transient reports whether a response must not be stored, because a
+	// retry may get a different one (a coupon lockout). Nil stores all.
+	transient func(R) bool
+}
+
+// placeOrderIdempotent runs placeOrder under an Idempotency-Key.
+func (h *Handler) placeOrderIdempotent(ctx context.Context, req *oas.OrderReq, key string) (oas.PlaceOrderRes, error) {
+	body, err := req.MarshalJSON()
+	if err != nil {
+		return nil, errors.Wrap(err, "encode request")
+	}
+
+	return runIdempotent(ctx, h.idempotency, key, idempotency.Fingerprint(body),
+		func(ctx context.Context) (oas.PlaceOrderRes, error) { return h.placeOrder(ctx, req) },
+		idempotentCodec[oas.PlaceOrderRes]{
+			encode: encodePlaceOrderRes,
+			decode: decodePlaceOrderRes,
+			target: func(res oas.PlaceOrderRes) string {
+				if o, ok := res.(*oas.Order); ok {
+					return o.ID.Value
+				}
+				return ""
+			},
+			transient: func(res oas.PlaceOrderRes) bool {
+				_, ok := res.(*oas.CouponAttemptsExceededHeaders)
+				return ok
+			},
+			reject: func(status int32, msg string) oas.PlaceOrderRes {
+				if status == http.StatusConflict {
+					return &oas.PlaceOrderConflict{Code: status, Message: msg}
+				}
+				return &oas.PlaceOrderUnprocessableEntity{Code: status, Message: msg}
+			},
+		},
+	)
+}
+
+// runIdempotent runs fn under an Idempotency-Key. The key is scoped to the
+// authenticated API key and bound to fingerprint, a digest of the request.
+// Any response fn produces is stored and replayed on retries; unexpected
+// errors and transient responses release the key so the retry executes
+// again. If the response
+// cannot be stored, the key is abandoned: retries get 409 for a short while
+// and then execute again, rather than getting 409 for the whole TTL.
+func runIdempotent[R any](
+	ctx context.Context,
+	svc *idempotency.Service,
+	key, fingerprint string,
+	fn func(context.Context) (R, error),
+	codec idempotentCodec[R],
+) (R, error) {
+	var zero R
+	scope := actorFromContext(ctx)
+
+	rec, err := svc.Begin(ctx, scope, key, fingerprint)
+	switch {
+	case errors.Is(err, idempotency.ErrKeyReused):
+		return codec.reject(http.StatusUnprocessableEntity, err.Error()), nil
+	case errors.Is(err, idempotency.ErrInProgress):
+		return codec.reject(http.StatusConflict, err.Error()), nil
+	case err != nil:
+		return zero, errors.Wrap(err, "begin idempotent request")
+	case rec != nil:
+		res, err := codec.decode(rec.StatusCode, rec.Response)
+		if err != nil {
+			return zero, err
+		}
+		if id := codec.target(res); id != "" {
+			setAuditTarget(ctx, id)
+		}
+		return res, nil
+	}
+
+	// The outcome must be recorded even if the client has already hung up,
+	// otherwise the key stays reserved until it expires.
+	storeCtx := context.WithoutCancel(ctx)
+	lg := zctx.From(ctx).With(zap.String("idempotency_key", key))
+
+	res, err := fn(ctx)
+	if err == nil && codec.transient != nil && codec.transient(res) {
+		if relErr := svc.Release(storeCtx, scope, key); relErr != nil {
+			lg.Warn("Failed to release idempotency key", zap.Error(relErr))
+		}
+		return res, nil
+	}
+	if err != nil {
+		if relErr := svc.Release(storeCtx, scope, key); relErr != nil {
+			lg.Warn("Failed to release idempotency key", zap.Error(relErr))
+		}
+		return zero, err
+	}
+
+	// The order is already committed, so the client gets its response even
+	// if it cannot be stored for replay.
+	status, respBody, err := codec.encode(res)
+	if err == nil {
+		err = svc.Complete(storeCtx, scope, key, status, respBody)
+	}
+	if err != nil {
+		lg.Warn("Failed to store idempotent response", zap.Error(err))
+		if err := svc.Abandon(storeCtx, scope, key); err != nil {
+			lg.Warn("Failed to abandon idempotency key", zap.Error(err))
+		}
+	}
+	return res, nil
+}
+
+// encodePlaceOrderRes serializes a placeOrder response for storage.
+func encodePlaceOrderRes(res oas.PlaceOrderRes) (int, []byte, error) {
+	var (
+		status int
+		body   []byte
+		err    error
+	)
+	switch r := res.(type) {
+	case *oas.Order:
+		status = http.StatusOK
+		body, err = r.MarshalJSON()
+	case *oas.PlaceOrderBadRequest:
+		status = http.StatusBadRequest
+		body, err = r.MarshalJSON()
+	case *oas.PlaceOrderUnprocessableEntity:
+		status = http.StatusUnprocessableEntity
+		body, err = r.MarshalJSON()
+	default:
+		return 0, nil, errors.Errorf("unexpected place order response %T", res)
+	}
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "encode response")
+	}
+	return status, body, nil
+}
+
+// decodePlaceOrderRes restores a response stored by encodePlaceOrderRes.
+func decodePlaceOrderRes(status int, body []byte) (oas.PlaceOrderRes, error) {
+	var res interface {
+		oas.PlaceOrderRes
+		UnmarshalJSON([]byte) error
+	}
+	switch status {
+	case http.StatusOK:
+		res = &oas.Order{}
+	case http.StatusBadRequest:
+		res = &oas.PlaceOrderBadRequest{}
+	case http.StatusUnprocessableEntity:
+		res = &oas.PlaceOrderUnprocessableEntity{}
+	default:
+		return nil, errors.Errorf("unexpected stored status %d", status)
+	}
+	if err := res.UnmarshalJSON(body); err != nil {
+		return nil, errors.Wrap(err, "decode stored response")
+	}
+	return res, nil
+}
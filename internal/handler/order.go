@@ -6,56 +6,304 @@ import (
 	"github.com/go-faster/errors"
 
 	"github.com/xenking/oolio-kart-challenge/gen/oas"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/auth"
 	"github.com/xenking/oolio-kart-challenge/internal/domain/coupon"
 	"github.com/xenking/oolio-kart-challenge/internal/domain/order"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/product"
 )
 
-// PlaceOrder converts the OAS request to a domain request, delegates to the
-// order service, and maps the result (or error) back to an OAS response.
-func (h *Handler) PlaceOrder(ctx context.Context, req *oas.OrderReq) (oas.PlaceOrderRes, error) {
-	// Convert OAS types to domain request.
-	items := make([]order.OrderItem, len(req.Items))
-	for i, item := range req.Items {
-		items[i] = order.OrderItem{
-			ProductID: item.ProductId,
-			Quantity:  item.Quantity,
-		}
+// PlaceOrder places an order. When the client sends an Idempotency-Key the
+// call goes through placeOrderIdempotent so retries are replayed instead of
+// placing the order twice.
+func (h *Handler) PlaceOrder(ctx context.Context, req *oas.OrderReq, params oas.PlaceOrderParams) (oas.PlaceOrderRes, error) {
+	if key, ok := params.IdempotencyKey.Get(); ok {
+		return h.placeOrderIdempotent(ctx, req, key)
 	}
+	return h.placeOrder(ctx, req)
+}
 
-	couponCode := ""
-	if code, ok := req.CouponCode.Get(); ok {
-		couponCode = code
+// placeOrder converts the OAS request to a domain request, delegates to the
+// order service, and maps the result (or error) back to an OAS response. The
+// coupon guard is checked here rather than in PlaceOrder, so a retry of an
+// order that already went through is replayed even while locked out.
+func (h *Handler) placeOrder(ctx context.Context, req *oas.OrderReq) (oas.PlaceOrderRes, error) {
+	codes := requestCouponCodes(req.CouponCode.Or(""), req.CouponCodes)
+	if wait := h.couponRetryAfter(ctx, codes); wait > 0 {
+		return couponAttemptsExceeded(wait), nil
 	}
-
 	result, err := h.orderService.PlaceOrder(ctx, order.PlaceOrderRequest{
-		Items:      items,
-		CouponCode: couponCode,
+		Items:       oasToDomainItems(req.Items),
+		CouponCodes: codes,
+		Actor:       actorFromContext(ctx),
 	})
+	h.recordCouponResult(ctx, codes, err)
 	if err != nil {
 		return mapOrderError(err)
 	}
+	setAuditTarget(ctx, result.Order.ID)
 
-	// Build OAS response.
-	respItems := make([]oas.OrderItem, len(req.Items))
-	for i, item := range req.Items {
-		respItems[i] = oas.OrderItem{
-			ProductId: oas.NewOptString(item.ProductId),
-			Quantity:  oas.NewOptInt(item.Quantity),
+	return h.domainToOASOrder(result.Order, result.Products), nil
+}
+
+// QuoteOrder prices a cart the way PlaceOrder would, without placing it.
+func (h *Handler) QuoteOrder(ctx context.Context, req *oas.OrderReq) (oas.QuoteOrderRes, error) {
+	codes := requestCouponCodes(req.CouponCode.Or(""), req.CouponCodes)
+	if wait := h.couponRetryAfter(ctx, codes); wait > 0 {
+		return couponAttemptsExceeded(wait), nil
+	}
+	q, err := h.orderService.Quote(ctx, order.QuoteRequest{
+		Items:       oasToDomainItems(req.Items),
+		CouponCodes: codes,
+		Customer:    actorFromContext(ctx),
+	})
+	if err != nil {
+		return mapQuoteError(err)
+	}
+	h.recordCouponResult(ctx, codes, q.CouponError)
+
+	respProducts := make([]oas.Product, len(q.Products))
+	for i, p := range q.Products {
+		respProducts[i] = h.domainToOASProduct(p)
+	}
+
+	resp := &oas.Quote{
+		Subtotal: q.Subtotal.InexactFloat64(),
+		Discount: q.Discount.InexactFloat64(),
+		Total:    q.Total.InexactFloat64(),
+		Items:    domainToOASItems(q.Items),
+		Products: respProducts,
+	}
+	if len(q.CouponCodes) > 0 {
+		resp.CouponCode = oas.NewOptString(q.CouponCodes[0])
+	}
+	if q.CouponDescription != "" {
+		resp.CouponDescription = oas.NewOptString(q.CouponDescription)
+	}
+	resp.Coupons = domainToOASCoupons(q.Coupons)
+	if q.CouponError != nil {
+		msg, _ := couponErrorMessage(q.CouponError)
+		resp.CouponError = oas.NewOptString(msg)
+	}
+	return resp, nil
+}
+
+// GetOrder returns a single stored order by ID.
+func (h *Handler) GetOrder(ctx context.Context, params oas.GetOrderParams) (oas.GetOrderRes, error) {
+	details, err := h.orderService.GetOrder(ctx, params.OrderId)
+	if err != nil {
+		if errors.Is(err, order.ErrNotFound) {
+			return &oas.GetOrderNotFound{
+				Code:    404,
+				Message: "order not found",
+			}, nil
 		}
+		return nil, errors.Wrap(err, "get order")
+	}
+
+	resp := h.domainToOASOrder(details.Order, details.Products)
+	resp.StatusHistory = domainToOASHistory(details.History)
+	return resp, nil
+}
+
+// UpdateOrderStatus moves an order along its lifecycle on behalf of the
+// authenticated API key.
+func (h *Handler) UpdateOrderStatus(ctx context.Context, req *oas.OrderStatusUpdate, params oas.UpdateOrderStatusParams) (oas.UpdateOrderStatusRes, error) {
+	details, err := h.orderService.UpdateStatus(ctx, order.UpdateStatusRequest{
+		OrderID: params.OrderId,
+		Status:  order.Status(req.Status),
+		Actor:   actorFromContext(ctx),
+		Reason:  req.Reason.Or(""),
+	})
+	if err != nil {
+		return mapOrderStatusError(err)
 	}
 
-	respProducts := make([]oas.Product, len(result.Products))
-	for i, p := range result.Products {
+	resp := h.domainToOASOrder(details.Order, details.Products)
+	resp.StatusHistory = domainToOASHistory(details.History)
+	return resp, nil
+}
+
+// CancelOrder cancels an order on behalf of the authenticated API key and
+// returns it with its updated status history.
+func (h *Handler) CancelOrder(ctx context.Context, req oas.OptOrderCancel, params oas.CancelOrderParams) (oas.CancelOrderRes, error) {
+	var reason string
+	if body, ok := req.Get(); ok {
+		reason = body.Reason.Or("")
+	}
+
+	details, err := h.orderService.Cancel(ctx, order.CancelRequest{
+		OrderID: params.OrderId,
+		Actor:   actorFromContext(ctx),
+		Reason:  reason,
+	})
+	if err != nil {
+		return mapCancelOrderError(err)
+	}
+
+	resp := h.domainToOASOrder(details.Order, details.Products)
+	resp.StatusHistory = domainToOASHistory(details.History)
+	return resp, nil
+}
+
+// ListOrders returns a page of stored orders, newest first.
+func (h *Handler) ListOrders(ctx context.Context, params oas.ListOrdersParams) (oas.ListOrdersRes, error) {
+	req := order.ListOrdersRequest{
+		CouponCode: params.CouponCode.Or(""),
+		Cursor:     params.Cursor.Or(""),
+		Limit:      params.Limit.Or(order.DefaultListLimit),
+	}
+	if from, ok := params.From.Get(); ok {
+		req.From = &from
+	}
+	if to, ok := params.To.Get(); ok {
+		req.To = &to
+	}
+
+	result, err := h.orderService.ListOrders(ctx, req)
+	if err != nil {
+		if errors.Is(err, order.ErrInvalidCursor) || errors.Is(err, order.ErrInvalidDateRange) {
+			return &oas.ListOrdersBadRequest{
+				Code:    400,
+				Message: err.Error(),
+			}, nil
+		}
+		return nil, errors.Wrap(err, "list orders")
+	}
+
+	out := &oas.OrderList{
+		Orders: make([]oas.Order, len(result.Orders)),
+	}
+	for i, d := range result.Orders {
+		out.Orders[i] = *h.domainToOASOrder(d.Order, d.Products)
+	}
+	if result.NextCursor != "" {
+		out.NextCursor = oas.NewOptString(result.NextCursor)
+	}
+	return out, nil
+}
+
+// domainToOASOrder converts a domain order and its products into the ogen
+// response type.
+func (h *Handler) domainToOASOrder(o *order.Order, products []product.Product) *oas.Order {
+	respProducts := make([]oas.Product, len(products))
+	for i, p := range products {
 		respProducts[i] = h.domainToOASProduct(p)
 	}
 
-	return &oas.Order{
-		ID:        oas.NewOptString(result.Order.ID),
-		Total:     oas.NewOptFloat64(result.Order.Total.InexactFloat64()),
-		Discounts: oas.NewOptFloat64(result.Order.Discounts.InexactFloat64()),
-		Items:     respItems,
+	resp := &oas.Order{
+		ID:        oas.NewOptString(o.ID),
+		Total:     oas.NewOptFloat64(o.Total.InexactFloat64()),
+		Discounts: oas.NewOptFloat64(o.Discounts.InexactFloat64()),
+		Items:     domainToOASItems(o.Items),
 		Products:  respProducts,
-	}, nil
+	}
+	if o.CouponCode != "" {
+		resp.CouponCode = oas.NewOptString(o.CouponCode)
+	}
+	resp.Coupons = domainToOASCoupons(o.Coupons)
+	if !o.CreatedAt.IsZero() {
+		resp.CreatedAt = oas.NewOptDateTime(o.CreatedAt)
+	}
+	if o.Status != "" {
+		resp.Status = oas.NewOptOrderStatus(oas.OrderStatus(o.Status))
+	}
+	if !o.StatusUpdatedAt.IsZero() {
+		resp.StatusUpdatedAt = oas.NewOptDateTime(o.StatusUpdatedAt)
+	}
+	return resp
+}
+
+// requestCouponCodes returns the codes of an order request: couponCode,
+// then couponCodes, skipping empty ones.
+func requestCouponCodes(code string, more []string) []string {
+	var codes []string
+	for _, c := range append([]string{code}, more...) {
+		if c != "" {
+			codes = append(codes, c)
+		}
+	}
+	return codes
+}
+
+// domainToOASCoupons converts an order's coupon breakdown to the ogen type.
+func domainToOASCoupons(in []order.AppliedCoupon) []oas.AppliedCoupon {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]oas.AppliedCoupon, len(in))
+	for i, c := range in {
+		out[i] = oas.AppliedCoupon{
+			Code:     c.Code,
+			Discount: c.Discount.InexactFloat64(),
+		}
+		if c.Description != "" {
+			out[i].Description = oas.NewOptString(c.Description)
+		}
+	}
+	return out
+}
+
+// oasToDomainItems converts request line items to domain order items.
+func oasToDomainItems(in []oas.OrderReqItemsItem) []order.OrderItem {
+	items := make([]order.OrderItem, len(in))
+	for i, item := range in {
+		items[i] = order.OrderItem{
+			ProductID: item.ProductId,
+			Quantity:  item.Quantity,
+		}
+	}
+	return items
+}
+
+// domainToOASItems converts priced line items to the ogen type.
+func domainToOASItems(in []order.OrderItem) []oas.OrderItem {
+	items := make([]oas.OrderItem, len(in))
+	for i, item := range in {
+		items[i] = oas.OrderItem{
+			ProductId: oas.NewOptString(item.ProductID),
+			Quantity:  oas.NewOptInt(item.Quantity),
+			UnitPrice: oas.NewOptFloat64(item.UnitPrice.InexactFloat64()),
+			Subtotal:  oas.NewOptFloat64(item.Subtotal.InexactFloat64()),
+			Discount:  oas.NewOptFloat64(item.Discount.InexactFloat64()),
+		}
+		if item.Name != "" {
+			items[i].Name = oas.NewOptString(item.Name)
+		}
+		if item.Category != "" {
+			items[i].Category = oas.NewOptString(item.Category)
+		}
+	}
+	return items
+}
+
+// domainToOASHistory converts an order's status history to the ogen type.
+func domainToOASHistory(history []order.StatusTransition) []oas.OrderStatusTransition {
+	out := make([]oas.OrderStatusTransition, len(history))
+	for i, t := range history {
+		out[i] = oas.OrderStatusTransition{
+			To: oas.OrderStatus(t.To),
+			At: t.At,
+		}
+		if t.From != "" {
+			out[i].From = oas.NewOptOrderStatus(oas.OrderStatus(t.From))
+		}
+		if t.Actor != "" {
+			out[i].Actor = oas.NewOptString(t.Actor)
+		}
+		if t.Reason != "" {
+			out[i].Reason = oas.NewOptString(t.Reason)
+		}
+	}
+	return out
+}
+
+// actorFromContext returns the ID of the authenticated API key, or an empty
+// string for unauthenticated calls.
+func actorFromContext(ctx context.Context) string {
+	if info, ok := auth.FromContext(ctx); ok {
+		return info.ID
+	}
+	return ""
 }
 
 // mapOrderError converts domain errors to OAS error responses.
@@ -67,6 +315,13 @@ func mapOrderError(err error) (oas.PlaceOrderRes, error) {
 		}, nil
 	}
 
+	if errors.Is(err, order.ErrTooManyCoupons) || errors.Is(err, order.ErrDuplicateCoupon) {
+		return &oas.PlaceOrderUnprocessableEntity{
+			Code:    422,
+			Message: err.Error(),
+		}, nil
+	}
+
 	var iqErr *order.InvalidQuantityError
 	if errors.As(err, &iqErr) {
 		return &oas.PlaceOrderUnprocessableEntity{
@@ -83,24 +338,124 @@ func mapOrderError(err error) (oas.PlaceOrderRes, error) {
 		}, nil
 	}
 
-	if errors.Is(err, coupon.ErrInvalidCoupon) {
+	if msg, ok := couponErrorMessage(err); ok {
 		return &oas.PlaceOrderUnprocessableEntity{
 			Code:    422,
-			Message: "invalid coupon code",
+			Message: msg,
 		}, nil
 	}
 
-	if errors.Is(err, coupon.ErrCouponExpired) {
-		return &oas.PlaceOrderUnprocessableEntity{
+	return nil, err
+}
+
+// mapQuoteError converts quote errors to OAS error responses. Coupon
+// rejections never reach here; they are reported inside the quote.
+func mapQuoteError(err error) (oas.QuoteOrderRes, error) {
+	if errors.Is(err, order.ErrEmptyItems) {
+		return &oas.QuoteOrderBadRequest{
+			Code:    400,
+			Message: err.Error(),
+		}, nil
+	}
+
+	if errors.Is(err, order.ErrTooManyCoupons) || errors.Is(err, order.ErrDuplicateCoupon) {
+		return &oas.QuoteOrderUnprocessableEntity{
 			Code:    422,
-			Message: "coupon expired",
+			Message: err.Error(),
 		}, nil
 	}
 
-	if errors.Is(err, coupon.ErrCouponUsageLimitReached) {
-		return &oas.PlaceOrderUnprocessableEntity{
+	var iqErr *order.InvalidQuantityError
+	if errors.As(err, &iqErr) {
+		return &oas.QuoteOrderUnprocessableEntity{
 			Code:    422,
-			Message: "coupon usage limit reached",
+			Message: iqErr.Error(),
+		}, nil
+	}
+
+	var pnfErr *order.ProductNotFoundError
+	if errors.As(err, &pnfErr) {
+		return &oas.QuoteOrderUnprocessableEntity{
+			Code:    422,
+			Message: pnfErr.Error(),
+		}, nil
+	}
+
+	return nil, err
+}
+
+// couponErrorMessage returns the client-facing message for a coupon
+// rejection, and false for any other error. Unknown, expired and used-up
+// coupons share one message so that responses don't reveal which codes
+// exist. A customer's own earlier use of a coupon is no secret to them, and
+// stacking is only checked once every code is known to be valid.
+func couponErrorMessage(err error) (string, bool) {
+	switch {
+	case errors.Is(err, coupon.ErrCouponAlreadyUsed):
+		return "coupon already used", true
+	case errors.Is(err, coupon.ErrCouponNotStackable):
+		return "coupons cannot be combined", true
+	case coupon.IsRejection(err):
+		return "invalid coupon code", true
+	}
+	return "", false
+}
+
+// mapOrderStatusError converts status-change errors to OAS error responses.
+func mapOrderStatusError(err error) (oas.UpdateOrderStatusRes, error) {
+	if errors.Is(err, order.ErrInvalidStatus) {
+		return &oas.UpdateOrderStatusBadRequest{
+			Code:    400,
+			Message: err.Error(),
+		}, nil
+	}
+
+	if errors.Is(err, order.ErrNotFound) {
+		return &oas.UpdateOrderStatusNotFound{
+			Code:    404,
+			Message: "order not found",
+		}, nil
+	}
+
+	var itErr *order.InvalidTransitionError
+	if errors.As(err, &itErr) {
+		return &oas.UpdateOrderStatusConflict{
+			Code:    409,
+			Message: itErr.Error(),
+		}, nil
+	}
+
+	if errors.Is(err, order.ErrStatusConflict) {
+		return &oas.UpdateOrderStatusConflict{
+			Code:    409,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return nil, err
+}
+
+// mapCancelOrderError converts cancellation errors to OAS error responses.
+func mapCancelOrderError(err error) (oas.CancelOrderRes, error) {
+	if errors.Is(err, order.ErrNotFound) {
+		return &oas.CancelOrderNotFound{
+			Code:    404,
+			Message: "order not found",
+		}, nil
+	}
+
+	var itErr *order.InvalidTransitionError
+	if errors.As(err, &itErr) {
+		return &oas.CancelOrderConflict{
+			Code:    409,
+			Message: itErr.Error(),
+		}, nil
+	}
+
+	if errors.Is(err, order.ErrCancelWindowExpired) || errors.Is(err, order.ErrStatusConflict) {
+		return &oas.CancelOrderConflict{
+			Code:    409,
+			Message: err.Error(),
 		}, nil
 	}
 
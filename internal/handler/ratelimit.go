@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RateLimitKey resolves who r is counted against by the rate limiter: the
+// API key it presents, with the key's plan, or the subject of its bearer
+// token under the default plan. Requests that carry neither, or whose
+// credentials do not check out, return an empty key and are limited by
+// client IP. So are signed requests, whose key ID cannot be trusted until
+// the signature has been verified. The key is looked up once with
+// ResolveAPIKeys, which HandleAPIKey reuses.
+func (s *SecurityHandler) RateLimitKey(r *http.Request) (key, plan string) {
+	if apiKey := r.Header.Get("api_key"); apiKey != "" {
+		info, _, ok := s.resolve(r.Context(), apiKey)
+		if !ok || info.Expired(s.now()) {
+			return "", ""
+		}
+		return "key:" + info.ID, info.RateLimitPlan
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && s.tokens != nil {
+		info, err := s.tokens.Verify(token)
+		if err != nil {
+			return "", ""
+		}
+		return "key:" + info.ID, ""
+	}
+	return "", ""
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-faster/errors"
+	"github.com/ogen-go/ogen/ogenerrors"
+
+	"github.com/xenking/oolio-kart-challenge/gen/oas"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/auth"
+)
+
+// operationScopes maps each authenticated operation to the auth scope it
+// requires. Operation names are shared by the v1 and v2 servers. An
+// authenticated operation missing from the map is refused, so a new
+// endpoint must be added here before any key can call it.
+var operationScopes = map[string]string{
+	"ListOrders":        auth.ScopeOrdersRead,
+	"GetOrder":          auth.ScopeOrdersRead,
+	"PlaceOrder":        auth.ScopeOrdersWrite,
+	"QuoteOrder":        auth.ScopeOrdersWrite,
+	"CancelOrder":       auth.ScopeOrdersWrite,
+	"UpdateOrderStatus": auth.ScopeOrdersAdmin,
+	"CreateCart":        auth.ScopeOrdersWrite,
+	"GetCart":           auth.ScopeOrdersWrite,
+	"SetCartItem":       auth.ScopeOrdersWrite,
+	"RemoveCartItem":    auth.ScopeOrdersWrite,
+	"AttachCartCoupon":  auth.ScopeOrdersWrite,
+	"DetachCartCoupon":  auth.ScopeOrdersWrite,
+	"CheckoutCart":      auth.ScopeOrdersWrite,
+	"ListAuditEvents":   auth.ScopeAuditRead,
+	"ListCoupons":       auth.ScopeCouponsAdmin,
+	"GetCoupon":         auth.ScopeCouponsAdmin,
+	"CreateCoupon":      auth.ScopeCouponsAdmin,
+	"UpdateCoupon":      auth.ScopeCouponsAdmin,
+	"DeactivateCoupon":  auth.ScopeCouponsAdmin,
+}
+
+// ScopeError is returned by the security handler when an authenticated key
+// lacks the scope an operation requires. ErrorHandler answers it with 403.
+type ScopeError struct {
+	Operation string
+	// Scope is the missing scope, or empty when the operation has no entry
+	// in the scope map.
+	Scope string
+}
+
+func (e *ScopeError) Error() string {
+	if e.Scope == "" {
+		return "operation " + e.Operation + " is not available to API keys"
+	}
+	return "api key lacks scope " + e.Scope
+}
+
+// ErrorHandler is the ogen error handler for both API servers. Missing
+// scopes are answered with 403 and an Error body; everything else is left
+// to ogen's default handler.
+func ErrorHandler(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) {
+	var scopeErr *ScopeError
+	if !errors.As(err, &scopeErr) {
+		ogenerrors.DefaultErrorHandler(ctx, w, r, err)
+		return
+	}
+
+	body, _ := (&oas.Error{
+		Code:    http.StatusForbidden,
+		Message: scopeErr.Error(),
+	}).MarshalJSON()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	_, _ = w.Write(body)
+}
@@ -2,14 +2,19 @@ package handler
 
 import (
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/hex"
+	"net/http"
+	"time"
 
 	"github.com/go-faster/errors"
+	"github.com/go-faster/sdk/zctx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 
 	"github.com/xenking/oolio-kart-challenge/gen/oas"
+	"github.com/xenking/oolio-kart-challenge/gen/oasv2"
 	"github.com/xenking/oolio-kart-challenge/internal/domain/auth"
 )
 
@@ -17,46 +22,210 @@ import (
 var _ oas.SecurityHandler = (*SecurityHandler)(nil)
 
 // SecurityHandler implements ogen's SecurityHandler interface, authenticating
-// API requests via HMAC-SHA256 hashed API keys.
+// API requests via HMAC-SHA256 hashed API keys, JWT bearer tokens, or
+// requests signed with a key's signing key.
 type SecurityHandler struct {
 	apikeys auth.Repository
-	pepper  []byte
+	peppers auth.Peppers
+	usage   *auth.UsageTracker
+	tokens  *auth.TokenVerifier
+	now     func() time.Time
 }
 
 // NewSecurityHandler creates a SecurityHandler with the given API key
-// repository and HMAC pepper.
-func NewSecurityHandler(apikeys auth.Repository, pepper []byte) *SecurityHandler {
+// repository and HMAC peppers. Successful authentications are reported to
+// usage; a nil tracker disables last-use tracking. Bearer tokens are checked
+// with tokens; a nil verifier refuses them all.
+func NewSecurityHandler(
+	apikeys auth.Repository,
+	peppers auth.Peppers,
+	usage *auth.UsageTracker,
+	tokens *auth.TokenVerifier,
+) *SecurityHandler {
 	return &SecurityHandler{
 		apikeys: apikeys,
-		pepper:  pepper,
+		peppers: peppers,
+		usage:   usage,
+		tokens:  tokens,
+		now:     time.Now,
 	}
 }
 
 // HandleAPIKey authenticates an incoming request by computing the HMAC-SHA256
 // of the provided API key, looking it up in the repository, and performing a
-// constant-time comparison to prevent timing attacks.
-func (s *SecurityHandler) HandleAPIKey(ctx context.Context, _ oas.OperationName, t oas.APIKey) (context.Context, error) {
-	mac := hmac.New(sha256.New, s.pepper)
-	mac.Write([]byte(t.APIKey))
-	hash := mac.Sum(nil)
-	hexHash := hex.EncodeToString(hash)
-
-	info, err := s.apikeys.FindByHash(ctx, hexHash)
-	if err != nil {
+// constant-time comparison to prevent timing attacks. Peppers are tried
+// newest first, and a key found under an older one is rehashed with the
+// current pepper. The key must then hold the scope operationScopes requires
+// for the operation, otherwise a *ScopeError is returned.
+func (s *SecurityHandler) HandleAPIKey(ctx context.Context, op oas.OperationName, t oas.APIKey) (context.Context, error) {
+	return s.authenticate(ctx, op, t.APIKey)
+}
+
+// HandleBearerAuth authenticates an incoming request by its JWT. The token's
+// scopes are checked against operationScopes exactly as an API key's are.
+func (s *SecurityHandler) HandleBearerAuth(ctx context.Context, op oas.OperationName, t oas.BearerAuth) (context.Context, error) {
+	return s.authenticateToken(ctx, op, t.Token)
+}
+
+// V2 returns the same authentication bound to the /api/v2 server's types.
+func (s *SecurityHandler) V2() oasv2.SecurityHandler {
+	return securityHandlerV2{s}
+}
+
+type securityHandlerV2 struct {
+	s *SecurityHandler
+}
+
+func (v securityHandlerV2) HandleAPIKey(ctx context.Context, op oasv2.OperationName, t oasv2.APIKey) (context.Context, error) {
+	return v.s.authenticate(ctx, op, t.APIKey)
+}
+
+func (v securityHandlerV2) HandleBearerAuth(ctx context.Context, op oasv2.OperationName, t oasv2.BearerAuth) (context.Context, error) {
+	return v.s.authenticateToken(ctx, op, t.Token)
+}
+
+func (v securityHandlerV2) HandleSignedRequest(ctx context.Context, op oasv2.OperationName, _ oasv2.SignedRequest) (context.Context, error) {
+	return v.s.authenticateSigned(ctx, op)
+}
+
+func (s *SecurityHandler) authenticate(ctx context.Context, op, apiKey string) (context.Context, error) {
+	info, pepper, ok := s.resolve(ctx, apiKey)
+	if !ok {
 		return ctx, errors.New("unauthorized")
 	}
 
-	// Constant-time comparison guards against timing side-channels even though
-	// the lookup already succeeded — the stored hash could differ from what
-	// we computed if the repository returns a stale/wrong row.
-	storedBytes, err := hex.DecodeString(info.KeyHash)
-	if err != nil {
+	ctx = identify(ctx, info)
+
+	if info.Expired(s.now()) {
+		zctx.From(ctx).Info("Expired API key refused", zap.Timep("expires_at", info.ExpiresAt))
 		return ctx, errors.New("unauthorized")
 	}
+	if s.usage != nil {
+		s.usage.Touch(info.ID)
+	}
+	if cur := s.peppers.Current(); pepper.Version != cur.Version || info.PepperVersion != cur.Version {
+		s.rehash(ctx, info, apiKey)
+	}
+	return authorize(ctx, op, info)
+}
 
-	if subtle.ConstantTimeCompare(hash, storedBytes) != 1 {
+func (s *SecurityHandler) authenticateToken(ctx context.Context, op, token string) (context.Context, error) {
+	if s.tokens == nil {
+		return ctx, errors.New("bearer tokens are not accepted")
+	}
+	info, err := s.tokens.Verify(token)
+	if err != nil {
+		zctx.From(ctx).Info("Bearer token refused", zap.Error(err))
 		return ctx, errors.New("unauthorized")
 	}
+	return authorize(identify(ctx, info), op, info)
+}
+
+// identify records who is calling on the span and logger before the
+// remaining checks, so refusals are attributed too.
+func identify(ctx context.Context, info *auth.APIKeyInfo) context.Context {
+	setAuditActor(ctx, info.ID)
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("kart.api_key.id", info.ID),
+		attribute.String("kart.api_key.prefix", info.Prefix),
+	)
+	return zctx.With(ctx, zap.String("api_key_id", info.ID), zap.String("api_key_prefix", info.Prefix))
+}
+
+// authorize checks that info holds the scope operationScopes requires for
+// op and stores it in ctx for the handlers.
+func authorize(ctx context.Context, op string, info *auth.APIKeyInfo) (context.Context, error) {
+	scope, ok := operationScopes[op]
+	if !ok || !info.HasScope(scope) {
+		err := &ScopeError{Operation: op, Scope: scope}
+		zctx.From(ctx).Info("API key refused", zap.String("operation", op), zap.Error(err))
+		return ctx, err
+	}
+
+	return auth.WithAPIKey(ctx, info), nil
+}
 
-	return ctx, nil
+type apiKeyLookupKey struct{}
+
+// apiKeyLookup is the outcome of looking up a request's api_key header,
+// handed from ResolveAPIKeys to RateLimitKey and HandleAPIKey through the
+// request context.
+type apiKeyLookup struct {
+	apiKey string
+	info   *auth.APIKeyInfo
+	pepper auth.Pepper
+	ok     bool
+}
+
+// ResolveAPIKeys returns middleware that looks up the api_key header of
+// every request that carries one, so that the rate limiter and HandleAPIKey
+// share a single lookup rather than each querying the repository under
+// every pepper. It must run before the rate limiter. Requests without the
+// header pass through untouched.
+func (s *SecurityHandler) ResolveAPIKeys() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKey := r.Header.Get("api_key")
+			if apiKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			res := apiKeyLookup{apiKey: apiKey}
+			res.info, res.pepper, res.ok = s.lookup(r.Context(), apiKey)
+			ctx := context.WithValue(r.Context(), apiKeyLookupKey{}, &res)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// resolve returns the lookup ResolveAPIKeys made for apiKey, or looks it up
+// if there is none.
+func (s *SecurityHandler) resolve(ctx context.Context, apiKey string) (*auth.APIKeyInfo, auth.Pepper, bool) {
+	if res, ok := ctx.Value(apiKeyLookupKey{}).(*apiKeyLookup); ok && res.apiKey == apiKey {
+		return res.info, res.pepper, res.ok
+	}
+	return s.lookup(ctx, apiKey)
+}
+
+// lookup finds the key for apiKey under each pepper in turn, newest first,
+// and returns it with the pepper that matched.
+func (s *SecurityHandler) lookup(ctx context.Context, apiKey string) (*auth.APIKeyInfo, auth.Pepper, bool) {
+	for _, p := range s.peppers {
+		hash := auth.HashKey(p.Secret, apiKey)
+		info, err := s.apikeys.FindByHash(ctx, hex.EncodeToString(hash))
+		if err != nil {
+			continue
+		}
+
+		// Constant-time comparison guards against timing side-channels even
+		// though the lookup already succeeded — the stored hash could differ
+		// from what we computed if the repository returns a stale/wrong row.
+		storedBytes, err := hex.DecodeString(info.KeyHash)
+		if err != nil || subtle.ConstantTimeCompare(hash, storedBytes) != 1 {
+			continue
+		}
+		return info, p, true
+	}
+	return nil, auth.Pepper{}, false
+}
+
+// rehash moves a key to the current pepper. Failure only delays the move to
+// the key's next login, so it is logged rather than failing the request.
+func (s *SecurityHandler) rehash(ctx context.Context, info *auth.APIKeyInfo, apiKey string) {
+	cur := s.peppers.Current()
+	stored, err := cur.Protect(info.ID, apiKey)
+	if err == nil {
+		err = s.apikeys.Rehash(ctx, info.ID, info.KeyHash, stored)
+	}
+	if err != nil {
+		zctx.From(ctx).Warn("Failed to rehash API key", zap.Int("pepper_version", cur.Version), zap.Error(err))
+		return
+	}
+	zctx.From(ctx).Info("Rehashed API key",
+		zap.Int("from_pepper_version", info.PepperVersion),
+		zap.Int("pepper_version", cur.Version),
+	)
+	info.KeyHash = stored.Hash
+	info.PepperVersion = stored.PepperVersion
 }
@@ -0,0 +1,459 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-faster/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/xenking/oolio-kart-challenge/gen/oas"
+	"github.com/xenking/oolio-kart-challenge/gen/oasv2"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/idempotency"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/order"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/product"
+)
+
+// Compile-time check ensuring V2Handler satisfies the ogen Handler interface.
+var _ oasv2.Handler = (*V2Handler)(nil)
+
+// V2Handler implements the /api/v2 operations. It shares the services and
+// error mapping of Handler and differs only in how money is written: v2
+// reports amounts as exact decimal strings with a currency code instead of
+// floats.
+type V2Handler struct {
+	oasv2.UnimplementedHandler
+
+	h *Handler
+}
+
+// NewV2Handler constructs a V2Handler serving the same data as h.
+func NewV2Handler(h *Handler) *V2Handler {
+	return &V2Handler{h: h}
+}
+
+// ListProducts returns every product in the catalog.
+func (v *V2Handler) ListProducts(ctx context.Context) ([]oasv2.Product, error) {
+	products, err := v.h.products.List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "list products")
+	}
+
+	out := make([]oasv2.Product, len(products))
+	for i, p := range products {
+		out[i] = v.product(p)
+	}
+	return out, nil
+}
+
+// GetProduct returns a single product by ID.
+func (v *V2Handler) GetProduct(ctx context.Context, params oasv2.GetProductParams) (oasv2.GetProductRes, error) {
+	p, err := v.h.products.GetByID(ctx, params.ProductId)
+	if err != nil {
+		if errors.Is(err, product.ErrNotFound) {
+			return &oasv2.GetProductNotFound{
+				Code:    404,
+				Message: "product not found",
+			}, nil
+		}
+		return nil, errors.Wrap(err, "get product")
+	}
+
+	result := v.product(*p)
+	return &result, nil
+}
+
+// PlaceOrder places an order. Idempotency-Key handling matches v1; the key
+// is bound to the API version, so reusing a v1 key on v2 is reported as a
+// reused key rather than replaying a response in the other format.
+func (v *V2Handler) PlaceOrder(ctx context.Context, req *oasv2.OrderReq, params oasv2.PlaceOrderParams) (oasv2.PlaceOrderRes, error) {
+	key, ok := params.IdempotencyKey.Get()
+	if !ok {
+		return v.placeOrder(ctx, req)
+	}
+
+	body, err := req.MarshalJSON()
+	if err != nil {
+		return nil, errors.Wrap(err, "encode request")
+	}
+	fingerprint := idempotency.Fingerprint(append([]byte("v2\n"), body...))
+
+	return runIdempotent(ctx, v.h.idempotency, key, fingerprint,
+		func(ctx context.Context) (oasv2.PlaceOrderRes, error) { return v.placeOrder(ctx, req) },
+		idempotentCodec[oasv2.PlaceOrderRes]{
+			encode: encodePlaceOrderResV2,
+			decode: decodePlaceOrderResV2,
+			target: func(res oasv2.PlaceOrderRes) string {
+				if o, ok := res.(*oasv2.Order); ok {
+					return o.ID.Value
+				}
+				return ""
+			},
+			transient: func(res oasv2.PlaceOrderRes) bool {
+				_, ok := res.(*oasv2.CouponAttemptsExceededHeaders)
+				return ok
+			},
+			reject: func(status int32, msg string) oasv2.PlaceOrderRes {
+				if status == http.StatusConflict {
+					return &oasv2.PlaceOrderConflict{Code: status, Message: msg}
+				}
+				return &oasv2.PlaceOrderUnprocessableEntity{Code: status, Message: msg}
+			},
+		},
+	)
+}
+
+func (v *V2Handler) placeOrder(ctx context.Context, req *oasv2.OrderReq) (oasv2.PlaceOrderRes, error) {
+	codes := requestCouponCodes(req.CouponCode.Or(""), req.CouponCodes)
+	if wait := v.h.couponRetryAfter(ctx, codes); wait > 0 {
+		return couponAttemptsExceededV2(wait), nil
+	}
+	result, err := v.h.orderService.PlaceOrder(ctx, order.PlaceOrderRequest{
+		Items:       oasv2ToDomainItems(req.Items),
+		CouponCodes: codes,
+		Actor:       actorFromContext(ctx),
+	})
+	v.h.recordCouponResult(ctx, codes, err)
+	if err != nil {
+		res, err := mapOrderError(err)
+		switch r := res.(type) {
+		case *oas.PlaceOrderBadRequest:
+			return (*oasv2.PlaceOrderBadRequest)(r), nil
+		case *oas.PlaceOrderUnprocessableEntity:
+			return (*oasv2.PlaceOrderUnprocessableEntity)(r), nil
+		}
+		return nil, err
+	}
+	setAuditTarget(ctx, result.Order.ID)
+
+	return v.order(result.Order, result.Products), nil
+}
+
+// QuoteOrder prices a cart the way PlaceOrder would, without placing it.
+func (v *V2Handler) QuoteOrder(ctx context.Context, req *oasv2.OrderReq) (oasv2.QuoteOrderRes, error) {
+	codes := requestCouponCodes(req.CouponCode.Or(""), req.CouponCodes)
+	if wait := v.h.couponRetryAfter(ctx, codes); wait > 0 {
+		return couponAttemptsExceededV2(wait), nil
+	}
+	q, err := v.h.orderService.Quote(ctx, order.QuoteRequest{
+		Items:       oasv2ToDomainItems(req.Items),
+		CouponCodes: codes,
+		Customer:    actorFromContext(ctx),
+	})
+	if err != nil {
+		res, err := mapQuoteError(err)
+		switch r := res.(type) {
+		case *oas.QuoteOrderBadRequest:
+			return (*oasv2.QuoteOrderBadRequest)(r), nil
+		case *oas.QuoteOrderUnprocessableEntity:
+			return (*oasv2.QuoteOrderUnprocessableEntity)(r), nil
+		}
+		return nil, err
+	}
+
+	v.h.recordCouponResult(ctx, codes, q.CouponError)
+
+	respProducts := make([]oasv2.Product, len(q.Products))
+	for i, p := range q.Products {
+		respProducts[i] = v.product(p)
+	}
+
+	resp := &oasv2.Quote{
+		Subtotal: v.money(q.Subtotal),
+		Discount: v.money(q.Discount),
+		Total:    v.money(q.Total),
+		Items:    v.items(q.Items),
+		Products: respProducts,
+	}
+	if len(q.CouponCodes) > 0 {
+		resp.CouponCode = oasv2.NewOptString(q.CouponCodes[0])
+	}
+	if q.CouponDescription != "" {
+		resp.CouponDescription = oasv2.NewOptString(q.CouponDescription)
+	}
+	resp.Coupons = v.coupons(q.Coupons)
+	if q.CouponError != nil {
+		msg, _ := couponErrorMessage(q.CouponError)
+		resp.CouponError = oasv2.NewOptString(msg)
+	}
+	return resp, nil
+}
+
+// GetOrder returns a single stored order by ID.
+func (v *V2Handler) GetOrder(ctx context.Context, params oasv2.GetOrderParams) (oasv2.GetOrderRes, error) {
+	details, err := v.h.orderService.GetOrder(ctx, params.OrderId)
+	if err != nil {
+		if errors.Is(err, order.ErrNotFound) {
+			return &oasv2.GetOrderNotFound{
+				Code:    404,
+				Message: "order not found",
+			}, nil
+		}
+		return nil, errors.Wrap(err, "get order")
+	}
+	return v.orderDetails(details), nil
+}
+
+// UpdateOrderStatus moves an order along its lifecycle on behalf of the
+// authenticated API key.
+func (v *V2Handler) UpdateOrderStatus(ctx context.Context, req *oasv2.OrderStatusUpdate, params oasv2.UpdateOrderStatusParams) (oasv2.UpdateOrderStatusRes, error) {
+	details, err := v.h.orderService.UpdateStatus(ctx, order.UpdateStatusRequest{
+		OrderID: params.OrderId,
+		Status:  order.Status(req.Status),
+		Actor:   actorFromContext(ctx),
+		Reason:  req.Reason.Or(""),
+	})
+	if err != nil {
+		res, err := mapOrderStatusError(err)
+		switch r := res.(type) {
+		case *oas.UpdateOrderStatusBadRequest:
+			return (*oasv2.UpdateOrderStatusBadRequest)(r), nil
+		case *oas.UpdateOrderStatusNotFound:
+			return (*oasv2.UpdateOrderStatusNotFound)(r), nil
+		case *oas.UpdateOrderStatusConflict:
+			return (*oasv2.UpdateOrderStatusConflict)(r), nil
+		}
+		return nil, err
+	}
+	return v.orderDetails(details), nil
+}
+
+// CancelOrder cancels an order on behalf of the authenticated API key and
+// returns it with its updated status history.
+func (v *V2Handler) CancelOrder(ctx context.Context, req oasv2.OptOrderCancel, params oasv2.CancelOrderParams) (oasv2.CancelOrderRes, error) {
+	var reason string
+	if body, ok := req.Get(); ok {
+		reason = body.Reason.Or("")
+	}
+
+	details, err := v.h.orderService.Cancel(ctx, order.CancelRequest{
+		OrderID: params.OrderId,
+		Actor:   actorFromContext(ctx),
+		Reason:  reason,
+	})
+	if err != nil {
+		res, err := mapCancelOrderError(err)
+		switch r := res.(type) {
+		case *oas.CancelOrderNotFound:
+			return (*oasv2.CancelOrderNotFound)(r), nil
+		case *oas.CancelOrderConflict:
+			return (*oasv2.CancelOrderConflict)(r), nil
+		}
+		return nil, err
+	}
+	return v.orderDetails(details), nil
+}
+
+// ListOrders returns a page of stored orders, newest first.
+func (v *V2Handler) ListOrders(ctx context.Context, params oasv2.ListOrdersParams) (oasv2.ListOrdersRes, error) {
+	req := order.ListOrdersRequest{
+		CouponCode: params.CouponCode.Or(""),
+		Cursor:     params.Cursor.Or(""),
+		Limit:      params.Limit.Or(order.DefaultListLimit),
+	}
+	if from, ok := params.From.Get(); ok {
+		req.From = &from
+	}
+	if to, ok := params.To.Get(); ok {
+		req.To = &to
+	}
+
+	result, err := v.h.orderService.ListOrders(ctx, req)
+	if err != nil {
+		if errors.Is(err, order.ErrInvalidCursor) || errors.Is(err, order.ErrInvalidDateRange) {
+			return &oasv2.ListOrdersBadRequest{
+				Code:    400,
+				Message: err.Error(),
+			}, nil
+		}
+		return nil, errors.Wrap(err, "list orders")
+	}
+
+	out := &oasv2.OrderList{
+		Orders: make([]oasv2.Order, len(result.Orders)),
+	}
+	for i, d := range result.Orders {
+		out.Orders[i] = *v.order(d.Order, d.Products)
+	}
+	if result.NextCursor != "" {
+		out.NextCursor = oasv2.NewOptString(result.NextCursor)
+	}
+	return out, nil
+}
+
+// money formats an amount as a v2 Money value. Amounts are rounded to cents
+// so the string always has exactly two decimal places.
+func (v *V2Handler) money(d decimal.Decimal) oasv2.Money {
+	return oasv2.Money{
+		Amount:   d.StringFixed(2),
+		Currency: v.h.currency,
+	}
+}
+
+// product converts a domain product into the v2 response type.
+func (v *V2Handler) product(p product.Product) oasv2.Product {
+	base := v.h.imageBaseURL
+	return oasv2.Product{
+		ID:       oasv2.NewOptString(p.ID),
+		Name:     oasv2.NewOptString(p.Name),
+		Price:    oasv2.NewOptMoney(v.money(p.Price)),
+		Category: oasv2.NewOptString(p.Category),
+		Image: oasv2.NewOptProductImage(oasv2.ProductImage{
+			Thumbnail: oasv2.NewOptString(base + p.Image.Thumbnail),
+			Mobile:    oasv2.NewOptString(base + p.Image.Mobile),
+			Tablet:    oasv2.NewOptString(base + p.Image.Tablet),
+			Desktop:   oasv2.NewOptString(base + p.Image.Desktop),
+		}),
+	}
+}
+
+// order converts a domain order and its products into the v2 response type.
+func (v *V2Handler) order(o *order.Order, products []product.Product) *oasv2.Order {
+	respProducts := make([]oasv2.Product, len(products))
+	for i, p := range products {
+		respProducts[i] = v.product(p)
+	}
+
+	resp := &oasv2.Order{
+		ID:        oasv2.NewOptString(o.ID),
+		Total:     oasv2.NewOptMoney(v.money(o.Total)),
+		Discounts: oasv2.NewOptMoney(v.money(o.Discounts)),
+		Items:     v.items(o.Items),
+		Products:  respProducts,
+	}
+	if o.CouponCode != "" {
+		resp.CouponCode = oasv2.NewOptString(o.CouponCode)
+	}
+	resp.Coupons = v.coupons(o.Coupons)
+	if !o.CreatedAt.IsZero() {
+		resp.CreatedAt = oasv2.NewOptDateTime(o.CreatedAt)
+	}
+	if o.Status != "" {
+		resp.Status = oasv2.NewOptOrderStatus(oasv2.OrderStatus(o.Status))
+	}
+	if !o.StatusUpdatedAt.IsZero() {
+		resp.StatusUpdatedAt = oasv2.NewOptDateTime(o.StatusUpdatedAt)
+	}
+	return resp
+}
+
+// coupons converts an order's coupon breakdown to the v2 type.
+func (v *V2Handler) coupons(in []order.AppliedCoupon) []oasv2.AppliedCoupon {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]oasv2.AppliedCoupon, len(in))
+	for i, c := range in {
+		out[i] = oasv2.AppliedCoupon{
+			Code:     c.Code,
+			Discount: v.money(c.Discount),
+		}
+		if c.Description != "" {
+			out[i].Description = oasv2.NewOptString(c.Description)
+		}
+	}
+	return out
+}
+
+// orderDetails converts an order with its status history.
+func (v *V2Handler) orderDetails(d *order.OrderDetails) *oasv2.Order {
+	resp := v.order(d.Order, d.Products)
+	resp.StatusHistory = make([]oasv2.OrderStatusTransition, len(d.History))
+	for i, t := range d.History {
+		resp.StatusHistory[i] = oasv2.OrderStatusTransition{
+			To: oasv2.OrderStatus(t.To),
+			At: t.At,
+		}
+		if t.From != "" {
+			resp.StatusHistory[i].From = oasv2.NewOptOrderStatus(oasv2.OrderStatus(t.From))
+		}
+		if t.Actor != "" {
+			resp.StatusHistory[i].Actor = oasv2.NewOptString(t.Actor)
+		}
+		if t.Reason != "" {
+			resp.StatusHistory[i].Reason = oasv2.NewOptString(t.Reason)
+		}
+	}
+	return resp
+}
+
+// items converts priced line items to the v2 type.
+func (v *V2Handler) items(in []order.OrderItem) []oasv2.OrderItem {
+	items := make([]oasv2.OrderItem, len(in))
+	for i, item := range in {
+		items[i] = oasv2.OrderItem{
+			ProductId: oasv2.NewOptString(item.ProductID),
+			Quantity:  oasv2.NewOptInt(item.Quantity),
+			UnitPrice: oasv2.NewOptMoney(v.money(item.UnitPrice)),
+			Subtotal:  oasv2.NewOptMoney(v.money(item.Subtotal)),
+			Discount:  oasv2.NewOptMoney(v.money(item.Discount)),
+		}
+		if item.Name != "" {
+			items[i].Name = oasv2.NewOptString(item.Name)
+		}
+		if item.Category != "" {
+			items[i].Category = oasv2.NewOptString(item.Category)
+		}
+	}
+	return items
+}
+
+// oasv2ToDomainItems converts v2 request line items to domain order items.
+func oasv2ToDomainItems(in []oasv2.OrderReqItemsItem) []order.OrderItem {
+	items := make([]order.OrderItem, len(in))
+	for i, item := range in {
+		items[i] = order.OrderItem{
+			ProductID: item.ProductId,
+			Quantity:  item.Quantity,
+		}
+	}
+	return items
+}
+
+// encodePlaceOrderResV2 serializes a v2 placeOrder response for storage.
+func encodePlaceOrderResV2(res oasv2.PlaceOrderRes) (int, []byte, error) {
+	var (
+		status int
+		body   []byte
+		err    error
+	)
+	switch r := res.(type) {
+	case *oasv2.Order:
+		status = http.StatusOK
+		body, err = r.MarshalJSON()
+	case *oasv2.PlaceOrderBadRequest:
+		status = http.StatusBadRequest
+		body, err = r.MarshalJSON()
+	case *oasv2.PlaceOrderUnprocessableEntity:
+		status = http.StatusUnprocessableEntity
+		body, err = r.MarshalJSON()
+	default:
+		return 0, nil, errors.Errorf("unexpected place order response %T", res)
+	}
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "encode response")
+	}
+	return status, body, nil
+}
+
+// decodePlaceOrderResV2 restores a response stored by encodePlaceOrderResV2.
+func decodePlaceOrderResV2(status int, body []byte) (oasv2.PlaceOrderRes, error) {
+	var res interface {
+		oasv2.PlaceOrderRes
+		UnmarshalJSON([]byte) error
+	}
+	switch status {
+	case http.StatusOK:
+		res = &oasv2.Order{}
+	case http.StatusBadRequest:
+		res = &oasv2.PlaceOrderBadRequest{}
+	case http.StatusUnprocessableEntity:
+		res = &oasv2.PlaceOrderUnprocessableEntity{}
+	default:
+		return nil, errors.Errorf("unexpected stored status %d", status)
+	}
+	if err := res.UnmarshalJSON(body); err != nil {
+		return nil, errors.Wrap(err, "decode stored response")
+	}
+	return res, nil
+}
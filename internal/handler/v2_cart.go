@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/go-faster/errors"
+
+	"github.com/xenking/oolio-kart-challenge/gen/oasv2"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/cart"
+	"github.com/xenking/oolio-kart-challenge/internal/domain/order"
+)
+
+// CreateCart starts an empty cart owned by the calling API key.
+func (v *V2Handler) CreateCart(ctx context.Context) (oasv2.CreateCartRes, error) {
+	d, err := v.h.carts.Create(ctx, actorFromContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "create cart")
+	}
+	setAuditTarget(ctx, d.Cart.ID)
+	return v.cart(d), nil
+}
+
+// GetCart returns a cart with its current pricing.
+func (v *V2Handler) GetCart(ctx context.Context, params oasv2.GetCartParams) (oasv2.GetCartRes, error) {
+	d, err := v.h.carts.Get(ctx, actorFromContext(ctx), params.CartId)
+	if err != nil {
+		code, msg, ok := cartErrorStatus(err)
+		switch {
+		case !ok:
+			return nil, errors.Wrap(err, "get cart")
+		case code == 404:
+			return &oasv2.GetCartNotFound{Code: code, Message: msg}, nil
+		default:
+			return &oasv2.GetCartUnprocessableEntity{Code: code, Message: msg}, nil
+		}
+	}
+	return v.cart(d), nil
+}
+
+// SetCartItem adds a product to a cart or changes its quantity.
+func (v *V2Handler) SetCartItem(ctx context.Context, req *oasv2.CartItemUpdate, params oasv2.SetCartItemParams) (oasv2.SetCartItemRes, error) {
+	d, err := v.h.carts.SetItem(ctx, actorFromContext(ctx), params.CartId, params.ProductId, req.Quantity)
+	if err != nil {
+		code, msg, ok := cartErrorStatus(err)
+		switch {
+		case !ok:
+			return nil, errors.Wrap(err, "set cart item")
+		case code == 404:
+			return &oasv2.SetCartItemNotFound{Code: code, Message: msg}, nil
+		default:
+			return &oasv2.SetCartItemUnprocessableEntity{Code: code, Message: msg}, nil
+		}
+	}
+	return v.cart(d), nil
+}
+
+// RemoveCartItem removes a product from a cart.
+func (v *V2Handler) RemoveCartItem(ctx context.Context, params oasv2.RemoveCartItemParams) (oasv2.RemoveCartItemRes, error) {
+	d, err := v.h.carts.RemoveItem(ctx, actorFromContext(ctx), params.CartId, params.ProductId)
+	if err != nil {
+		code, msg, ok := cartErrorStatus(err)
+		switch {
+		case !ok:
+			return nil, errors.Wrap(err, "remove cart item")
+		case code == 404:
+			return &oasv2.RemoveCartItemNotFound{Code: code, Message: msg}, nil
+		default:
+			return &oasv2.RemoveCartItemUnprocessableEntity{Code: code, Message: msg}, nil
+		}
+	}
+	return v.cart(d), nil
+}
+
+// AttachCartCoupon sets the coupon code applied to a cart.
+func (v *V2Handler) AttachCartCoupon(ctx context.Context, req *oasv2.CartCoupon, params oasv2.AttachCartCouponParams) (oasv2.AttachCartCouponRes, error) {
+	if wait := v.h.couponRetryAfter(ctx, []string{req.Code}); wait > 0 {
+		return couponAttemptsExceededV2(wait), nil
+	}
+	d, err := v.h.carts.AttachCoupon(ctx, actorFromContext(ctx), params.CartId, req.Code)
+	if err != nil {
+		code, msg, ok := cartErrorStatus(err)
+		switch {
+		case !ok:
+			return nil, errors.Wrap(err, "attach cart coupon")
+		case code == 404:
+			return &oasv2.AttachCartCouponNotFound{Code: code, Message: msg}, nil
+		default:
+			return &oasv2.AttachCartCouponUnprocessableEntity{Code: code, Message: msg}, nil
+		}
+	}
+	v.h.recordCouponResult(ctx, []string{req.Code}, d.Quote.CouponError)
+	return v.cart(d), nil
+}
+
+// DetachCartCoupon removes the coupon code from a cart.
+func (v *V2Handler) DetachCartCoupon(ctx context.Context, params oasv2.DetachCartCouponParams) (oasv2.DetachCartCouponRes, error) {
+	d, err := v.h.carts.DetachCoupon(ctx, actorFromContext(ctx), params.CartId)
+	if err != nil {
+		code, msg, ok := cartErrorStatus(err)
+		switch {
+		case !ok:
+			return nil, errors.Wrap(err, "detach cart coupon")
+		case code == 404:
+			return &oasv2.DetachCartCouponNotFound{Code: code, Message: msg}, nil
+		default:
+			return &oasv2.DetachCartCouponUnprocessableEntity{Code: code, Message: msg}, nil
+		}
+	}
+	return v.cart(d), nil
+}
+
+// CheckoutCart places an order for a cart's contents and deletes the cart.
+func (v *V2Handler) CheckoutCart(ctx context.Context, params oasv2.CheckoutCartParams) (oasv2.CheckoutCartRes, error) {
+	result, err := v.h.carts.Checkout(ctx, actorFromContext(ctx), params.CartId)
+	if err != nil {
+		if errors.Is(err, order.ErrEmptyItems) {
+			return &oasv2.CheckoutCartBadRequest{
+				Code:    400,
+				Message: err.Error(),
+			}, nil
+		}
+		if msg, ok := couponErrorMessage(err); ok {
+			return &oasv2.CheckoutCartUnprocessableEntity{
+				Code:    422,
+				Message: msg,
+			}, nil
+		}
+		code, msg, ok := cartErrorStatus(err)
+		switch {
+		case !ok:
+			return nil, errors.Wrap(err, "checkout cart")
+		case code == 404:
+			return &oasv2.CheckoutCartNotFound{Code: code, Message: msg}, nil
+		default:
+			return &oasv2.CheckoutCartUnprocessableEntity{Code: code, Message: msg}, nil
+		}
+	}
+	return v.order(result.Order, result.Products), nil
+}
+
+// cart converts a priced cart into the v2 response type.
+func (v *V2Handler) cart(d *cart.Details) *oasv2.Cart {
+	c, q := d.Cart, d.Quote
+
+	respProducts := make([]oasv2.Product, len(q.Products))
+	for i, p := range q.Products {
+		respProducts[i] = v.product(p)
+	}
+
+	resp := &oasv2.Cart{
+		ID:        c.ID,
+		Items:     v.items(q.Items),
+		Products:  respProducts,
+		Subtotal:  v.money(q.Subtotal),
+		Discount:  v.money(q.Discount),
+		Total:     v.money(q.Total),
+		ExpiresAt: c.ExpiresAt,
+	}
+	if c.CouponCode != "" {
+		resp.CouponCode = oasv2.NewOptString(c.CouponCode)
+	}
+	if q.CouponDescription != "" {
+		resp.CouponDescription = oasv2.NewOptString(q.CouponDescription)
+	}
+	if q.CouponError != nil {
+		msg, _ := couponErrorMessage(q.CouponError)
+		resp.CouponError = oasv2.NewOptString(msg)
+	}
+	if !c.CreatedAt.IsZero() {
+		resp.CreatedAt = oasv2.NewOptDateTime(c.CreatedAt)
+	}
+	if !c.UpdatedAt.IsZero() {
+		resp.UpdatedAt = oasv2.NewOptDateTime(c.UpdatedAt)
+	}
+	return resp
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-faster/errors"
 	"github.com/jackc/pgx/v5"
@@ -11,12 +12,58 @@ import (
 	"github.com/xenking/oolio-kart-challenge/internal/domain/auth"
 )
 
-const getAPIKeyByHashSQL = `SELECT id, key_hash, name, scopes
-	FROM api_keys WHERE key_hash = $1 AND active = TRUE`
+const (
+	getAPIKeyByHashSQL = `SELECT id, key_hash, prefix, name, scopes, expires_at, pepper_version, rate_limit_plan
+		FROM api_keys WHERE key_hash = $1 AND active = TRUE`
 
-var _ auth.Repository = (*APIKeyRepository)(nil)
+	getSigningKeySQL = `SELECT id, key_hash, prefix, name, scopes, expires_at, pepper_version, rate_limit_plan, signing_key
+		FROM api_keys WHERE id = $1 AND active = TRUE`
 
-// APIKeyRepository provides API key lookups backed by PostgreSQL.
+	rehashAPIKeySQL = `UPDATE api_keys SET key_hash = $3, signing_key = $4, pepper_version = $5
+		WHERE id = $1 AND key_hash = $2`
+
+	createAPIKeySQL = `INSERT INTO api_keys (id, key_hash, signing_key, prefix, name, scopes, expires_at, pepper_version, rate_limit_plan)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at`
+
+	apiKeyColumns = `id, prefix, name, scopes, active, created_at, expires_at, last_used_at, pepper_version, rate_limit_plan`
+
+	listAPIKeysSQL = `SELECT ` + apiKeyColumns + `
+		FROM api_keys ORDER BY created_at, id`
+
+	getAPIKeySQL = `SELECT ` + apiKeyColumns + `
+		FROM api_keys WHERE id = $1`
+
+	revokeAPIKeySQL = `UPDATE api_keys SET active = FALSE WHERE id = $1`
+
+	setAPIKeySecretSQL = `UPDATE api_keys SET key_hash = $2, signing_key = $3, prefix = $4, pepper_version = $5
+		WHERE id = $1 AND active = TRUE`
+
+	setAPIKeyScopesSQL = `UPDATE api_keys SET scopes = $2 WHERE id = $1`
+
+	setAPIKeyRateLimitPlanSQL = `UPDATE api_keys SET rate_limit_plan = $2 WHERE id = $1`
+
+	// touchAPIKeysSQL applies a batch of last-use times. Batches can land
+	// out of order, so an older time never overwrites a newer one.
+	touchAPIKeysSQL = `UPDATE api_keys k SET last_used_at = u.used_at
+		FROM unnest($1::text[], $2::timestamptz[]) AS u (id, used_at)
+		WHERE k.id = u.id AND (k.last_used_at IS NULL OR k.last_used_at < u.used_at)`
+
+	// APIKeyChangesChannel is the NOTIFY channel that carries the ID of
+	// every API key whose authentication data changes (migration 010).
+	APIKeyChangesChannel = "api_keys_changed"
+)
+
+var (
+	_ auth.Repository = (*APIKeyRepository)(nil)
+	_ auth.KeyStore   = (*APIKeyRepository)(nil)
+	_ auth.UsageStore = (*APIKeyRepository)(nil)
+
+	_ auth.SigningKeyStore = (*APIKeyRepository)(nil)
+)
+
+// APIKeyRepository provides API key lookups and management backed by
+// PostgreSQL.
 type APIKeyRepository struct {
 	pool *pgxpool.Pool
 }
@@ -30,13 +77,179 @@ func NewAPIKeyRepository(pool *pgxpool.Pool) *APIKeyRepository {
 func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*auth.APIKeyInfo, error) {
 	var info auth.APIKeyInfo
 	err := r.pool.QueryRow(ctx, getAPIKeyByHashSQL, hash).Scan(
-		&info.ID, &info.KeyHash, &info.Name, &info.Scopes,
+		&info.ID, &info.KeyHash, &info.Prefix, &info.Name, &info.Scopes, &info.ExpiresAt, &info.PepperVersion,
+		&info.RateLimitPlan,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("api key not found: %w", err)
+			return nil, auth.ErrKeyNotFound
 		}
 		return nil, fmt.Errorf("finding api key by hash: %w", err)
 	}
 	return &info, nil
 }
+
+// FindSigningKey looks up an active API key by ID along with its sealed
+// signing key.
+func (r *APIKeyRepository) FindSigningKey(ctx context.Context, id string) (*auth.APIKeyInfo, []byte, error) {
+	var (
+		info   auth.APIKeyInfo
+		sealed []byte
+	)
+	err := r.pool.QueryRow(ctx, getSigningKeySQL, id).Scan(
+		&info.ID, &info.KeyHash, &info.Prefix, &info.Name, &info.Scopes, &info.ExpiresAt, &info.PepperVersion,
+		&info.RateLimitPlan, &sealed,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, auth.ErrKeyNotFound
+		}
+		return nil, nil, fmt.Errorf("finding api key signing key: %w", err)
+	}
+	return &info, sealed, nil
+}
+
+// Rehash replaces a key's stored secret if its hash is still oldHash.
+func (r *APIKeyRepository) Rehash(ctx context.Context, id, oldHash string, secret auth.StoredSecret) error {
+	_, err := r.pool.Exec(ctx, rehashAPIKeySQL, id, oldHash, secret.Hash, secret.SigningKey, secret.PepperVersion)
+	if err != nil {
+		return fmt.Errorf("rehashing api key: %w", err)
+	}
+	return nil
+}
+
+// CreateKey inserts a new active key. CreatedAt is populated from the
+// database.
+func (r *APIKeyRepository) CreateKey(ctx context.Context, key *auth.Key, secret auth.StoredSecret) error {
+	scopes := key.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	err := r.pool.QueryRow(ctx, createAPIKeySQL,
+		key.ID, secret.Hash, secret.SigningKey, key.Prefix, key.Name, scopes, key.ExpiresAt, secret.PepperVersion,
+		key.RateLimitPlan,
+	).Scan(&key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("inserting api key: %w", err)
+	}
+	return nil
+}
+
+// ListKeys returns every key, revoked ones included, oldest first.
+func (r *APIKeyRepository) ListKeys(ctx context.Context) ([]auth.Key, error) {
+	rows, err := r.pool.Query(ctx, listAPIKeysSQL)
+	if err != nil {
+		return nil, fmt.Errorf("querying api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []auth.Key
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning api key: %w", err)
+		}
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// GetKey returns a key by ID.
+func (r *APIKeyRepository) GetKey(ctx context.Context, id string) (*auth.Key, error) {
+	k, err := scanAPIKey(r.pool.QueryRow(ctx, getAPIKeySQL, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, auth.ErrKeyNotFound
+		}
+		return nil, fmt.Errorf("querying api key: %w", err)
+	}
+	return &k, nil
+}
+
+// RevokeKey marks a key inactive.
+func (r *APIKeyRepository) RevokeKey(ctx context.Context, id string) error {
+	return r.execOne(ctx, "revoking api key", revokeAPIKeySQL, id)
+}
+
+// SetKeySecret replaces the stored secret and prefix of an active key.
+func (r *APIKeyRepository) SetKeySecret(ctx context.Context, id, prefix string, secret auth.StoredSecret) error {
+	return r.execOne(ctx, "updating api key secret", setAPIKeySecretSQL,
+		id, secret.Hash, secret.SigningKey, prefix, secret.PepperVersion)
+}
+
+// SetKeyScopes replaces the scopes of a key.
+func (r *APIKeyRepository) SetKeyScopes(ctx context.Context, id string, scopes []string) error {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return r.execOne(ctx, "updating api key scopes", setAPIKeyScopesSQL, id, scopes)
+}
+
+// SetKeyRateLimitPlan replaces the rate limit plan of a key.
+func (r *APIKeyRepository) SetKeyRateLimitPlan(ctx context.Context, id, plan string) error {
+	return r.execOne(ctx, "updating api key rate limit plan", setAPIKeyRateLimitPlanSQL, id, plan)
+}
+
+// TouchKeys records a batch of last-use times in one statement.
+func (r *APIKeyRepository) TouchKeys(ctx context.Context, usedAt map[string]time.Time) error {
+	ids := make([]string, 0, len(usedAt))
+	times := make([]time.Time, 0, len(usedAt))
+	for id, at := range usedAt {
+		ids = append(ids, id)
+		times = append(times, at)
+	}
+	if _, err := r.pool.Exec(ctx, touchAPIKeysSQL, ids, times); err != nil {
+		return fmt.Errorf("updating api key last use: %w", err)
+	}
+	return nil
+}
+
+// ListenKeyChanges holds a connection listening on APIKeyChangesChannel and
+// calls changed with the ID of each key that changes. listening is called
+// once the listen is in place; changes made before that are not reported.
+// It returns when ctx is cancelled or the connection fails.
+func (r *APIKeyRepository) ListenKeyChanges(ctx context.Context, listening func(), changed func(id string)) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring connection: %w", err)
+	}
+	// The connection is closed rather than returned to the pool, so the
+	// LISTEN does not leak into other queries.
+	pc := conn.Hijack()
+	defer pc.Close(context.WithoutCancel(ctx))
+
+	if _, err := pc.Exec(ctx, "LISTEN "+APIKeyChangesChannel); err != nil {
+		return fmt.Errorf("listening for api key changes: %w", err)
+	}
+	listening()
+
+	for {
+		n, err := pc.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("waiting for api key changes: %w", err)
+		}
+		changed(n.Payload)
+	}
+}
+
+func scanAPIKey(row pgx.Row) (auth.Key, error) {
+	var k auth.Key
+	err := row.Scan(&k.ID, &k.Prefix, &k.Name, &k.Scopes, &k.Active, &k.CreatedAt,
+		&k.ExpiresAt, &k.LastUsedAt, &k.PepperVersion, &k.RateLimitPlan)
+	return k, err
+}
+
+// execOne runs an update that must touch exactly one key.
+func (r *APIKeyRepository) execOne(ctx context.Context, what, sql string, args ...any) error {
+	tag, err := r.pool.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", what, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return auth.ErrKeyNotFound
+	}
+	return nil
+}
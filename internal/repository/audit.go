@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/xenking/oolio-kart-challenge/internal/domain/audit"
+)
+
+const (
+	insertAuditEventSQL = `INSERT INTO audit_events
+		(actor, operation, request_id, client_ip, target_type, target_id, outcome, status_code)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at`
+
+	listAuditEventsSQL = `SELECT id, actor, operation, request_id, client_ip, target_type, target_id, outcome, status_code, created_at
+		FROM audit_events
+		WHERE ($1 = '' OR actor = $1)
+			AND ($2::timestamptz IS NULL OR created_at >= $2)
+			AND ($3::timestamptz IS NULL OR created_at < $3)
+			AND ($4::timestamptz IS NULL OR (created_at, id) < ($4, $5))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $6`
+)
+
+var _ audit.Repository = (*AuditRepository)(nil)
+
+// AuditRepository implements audit.Repository backed by PostgreSQL.
+type AuditRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAuditRepository returns an AuditRepository that uses the given pool.
+func NewAuditRepository(pool *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{pool: pool}
+}
+
+// Insert stores an audit event, setting its ID and CreatedAt.
+func (r *AuditRepository) Insert(ctx context.Context, e *audit.Event) error {
+	err := conn(ctx, r.pool).QueryRow(ctx, insertAuditEventSQL,
+		e.Actor, e.Operation, e.RequestID, e.ClientIP, e.TargetType, e.TargetID, string(e.Outcome), e.StatusCode,
+	).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("inserting audit event: %w", err)
+	}
+	return nil
+}
+
+// List returns audit events matching the filter, newest first.
+func (r *AuditRepository) List(ctx context.Context, f audit.ListFilter) ([]audit.Event, error) {
+	var (
+		afterTime *time.Time
+		afterID   int64
+	)
+	if f.After != nil {
+		afterTime = &f.After.CreatedAt
+		afterID = f.After.ID
+	}
+
+	rows, err := conn(ctx, r.pool).Query(ctx, listAuditEventsSQL,
+		f.Actor, f.From, f.To, afterTime, afterID, f.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing audit events: %w", err)
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (audit.Event, error) {
+		var e audit.Event
+		err := row.Scan(&e.ID, &e.Actor, &e.Operation, &e.RequestID, &e.ClientIP,
+			&e.TargetType, &e.TargetID, &e.Outcome, &e.StatusCode, &e.CreatedAt)
+		return e, err
+	})
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/xenking/oolio-kart-challenge/internal/domain/cart"
+)
+
+const (
+	createCartSQL = `INSERT INTO carts (id, owner, items, coupon_code, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at, updated_at`
+
+	// getCartSQL locks the row so a read-modify-write inside a transaction
+	// cannot race another change to the same cart.
+	getCartSQL = `SELECT id, owner, items, coupon_code, created_at, updated_at, expires_at
+		FROM carts WHERE id = $1 AND expires_at > now()
+		FOR UPDATE`
+
+	updateCartSQL = `UPDATE carts
+		SET items = $2, coupon_code = $3, expires_at = $4, updated_at = now()
+		WHERE id = $1
+		RETURNING updated_at`
+
+	deleteCartSQL = `DELETE FROM carts WHERE id = $1`
+
+	deleteExpiredCartsSQL = `DELETE FROM carts WHERE expires_at <= $1`
+)
+
+var _ cart.Repository = (*CartRepository)(nil)
+
+// CartRepository implements cart.Repository backed by PostgreSQL.
+type CartRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCartRepository returns a CartRepository that uses the given pool.
+func NewCartRepository(pool *pgxpool.Pool) *CartRepository {
+	return &CartRepository{pool: pool}
+}
+
+// Create persists a new cart. CreatedAt and UpdatedAt are populated from the
+// database.
+func (r *CartRepository) Create(ctx context.Context, c *cart.Cart) error {
+	itemsJSON, err := marshalCartItems(c.Items)
+	if err != nil {
+		return err
+	}
+
+	err = conn(ctx, r.pool).QueryRow(ctx, createCartSQL,
+		c.ID, c.Owner, itemsJSON, c.CouponCode, c.ExpiresAt,
+	).Scan(&c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("creating cart %q: %w", c.ID, err)
+	}
+	return nil
+}
+
+// Get returns an unexpired cart, locking its row for the rest of the
+// surrounding transaction. Returns cart.ErrNotFound when there is none.
+func (r *CartRepository) Get(ctx context.Context, id string) (*cart.Cart, error) {
+	var c cart.Cart
+	err := conn(ctx, r.pool).QueryRow(ctx, getCartSQL, id).Scan(
+		&c.ID, &c.Owner, &c.Items, &c.CouponCode, &c.CreatedAt, &c.UpdatedAt, &c.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, cart.ErrNotFound
+		}
+		return nil, fmt.Errorf("getting cart %q: %w", id, err)
+	}
+	return &c, nil
+}
+
+// Update stores the cart's items, coupon and expiry. UpdatedAt is populated
+// from the database.
+func (r *CartRepository) Update(ctx context.Context, c *cart.Cart) error {
+	itemsJSON, err := marshalCartItems(c.Items)
+	if err != nil {
+		return err
+	}
+
+	err = conn(ctx, r.pool).QueryRow(ctx, updateCartSQL,
+		c.ID, itemsJSON, c.CouponCode, c.ExpiresAt,
+	).Scan(&c.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return cart.ErrNotFound
+		}
+		return fmt.Errorf("updating cart %q: %w", c.ID, err)
+	}
+	return nil
+}
+
+// Delete removes a cart.
+func (r *CartRepository) Delete(ctx context.Context, id string) error {
+	if _, err := conn(ctx, r.pool).Exec(ctx, deleteCartSQL, id); err != nil {
+		return fmt.Errorf("deleting cart %q: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteExpired removes carts that expired before now.
+func (r *CartRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, deleteExpiredCartsSQL, now)
+	if err != nil {
+		return 0, fmt.Errorf("deleting expired carts: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// marshalCartItems serializes items for the JSONB column, storing an empty
+// cart as [] rather than null.
+func marshalCartItems(items []cart.Item) ([]byte, error) {
+	if items == nil {
+		items = []cart.Item{}
+	}
+	b, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling cart items: %w", err)
+	}
+	return b, nil
+}
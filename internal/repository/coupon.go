@@ -15,10 +15,70 @@ import (
 
 const (
 	getCouponByCodeSQL = `SELECT code, discount_type, value, min_items, description,
-		valid_from, valid_until, max_uses, uses, max_discount
+		valid_from, valid_until, max_uses, uses, max_discount, max_uses_per_customer, stackable, priority,
+		include_products, include_categories, exclude_products, exclude_categories
 		FROM coupons WHERE UPPER(code) = UPPER($1) AND active = TRUE`
 
-	incrementCouponUsesSQL = `UPDATE coupons SET uses = uses + 1 WHERE code = $1`
+	incrementCouponUsesSQL = `UPDATE coupons SET uses = uses + 1
+		WHERE code = $1 AND active = TRUE AND (max_uses = 0 OR uses < max_uses)`
+
+	// Orders store the code as the customer typed it, so match it the same
+	// way FindByCode does. Inactive coupons are still credited back.
+	decrementCouponUsesSQL = `UPDATE coupons SET uses = uses - 1
+		WHERE UPPER(code) = UPPER($1) AND uses > 0`
+
+	// couponAdminSelectSQL reads coupons with their usage: the orders each
+	// coupon was applied to and its part of their discounts. Cancelled or
+	// rejected orders gave their use back.
+	couponAdminSelectSQL = `SELECT c.code, c.discount_type, c.value, c.min_items, c.description,
+		c.valid_from, c.valid_until, c.max_uses, c.uses, c.max_discount, c.max_uses_per_customer,
+		c.stackable, c.priority,
+		c.include_products, c.include_categories, c.exclude_products, c.exclude_categories,
+		c.active, c.created_at, COALESCE(u.orders, 0), COALESCE(u.discount_total, 0)
+		FROM coupons c
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) AS orders, SUM(oc.discount) AS discount_total
+			FROM order_coupons oc
+			JOIN orders o ON o.id = oc.order_id
+			WHERE UPPER(oc.code) = UPPER(c.code)
+				AND o.status NOT IN ('cancelled', 'rejected')
+		) u ON TRUE`
+
+	getCouponSQL = couponAdminSelectSQL + `
+		WHERE UPPER(c.code) = UPPER($1)`
+
+	listCouponsSQL = couponAdminSelectSQL + `
+		WHERE ($1::boolean IS NULL OR c.active = $1)
+			AND ($2 = '' OR c.discount_type = $2)
+			AND ($3::timestamptz IS NULL OR c.valid_until IS NULL OR c.valid_until >= $3)
+			AND ($4::timestamptz IS NULL OR c.valid_from IS NULL OR c.valid_from < $4)
+			AND ($5 = '' OR c.code > $5)
+		ORDER BY c.code
+		LIMIT $6`
+
+	createCouponSQL = `INSERT INTO coupons
+		(code, discount_type, value, min_items, description, valid_from, valid_until, max_uses, max_discount,
+		max_uses_per_customer, stackable, priority,
+		include_products, include_categories, exclude_products, exclude_categories)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		ON CONFLICT DO NOTHING`
+
+	updateCouponSQL = `UPDATE coupons SET discount_type = $2, value = $3, min_items = $4, description = $5,
+		valid_from = $6, valid_until = $7, max_uses = $8, max_discount = $9, max_uses_per_customer = $10,
+		stackable = $11, priority = $12,
+		include_products = $13, include_categories = $14, exclude_products = $15, exclude_categories = $16
+		WHERE UPPER(code) = UPPER($1)`
+
+	deactivateCouponSQL = `UPDATE coupons SET active = FALSE WHERE UPPER(code) = UPPER($1)`
+
+	countCouponRedemptionsSQL = `SELECT COUNT(*) FROM coupon_redemptions
+		WHERE coupon_code = $1 AND customer = $2`
+
+	insertCouponRedemptionSQL = `INSERT INTO coupon_redemptions (coupon_code, customer, order_id)
+		VALUES ($1, $2, $3)`
+
+	deleteCouponRedemptionSQL = `DELETE FROM coupon_redemptions
+		WHERE UPPER(coupon_code) = UPPER($1) AND order_id = $2`
 )
 
 var _ coupon.Repository = (*CouponRepository)(nil)
@@ -36,7 +96,7 @@ func NewCouponRepository(pool *pgxpool.Pool) *CouponRepository {
 // FindByCode looks up an active coupon by its code (case-insensitive).
 // Returns coupon.ErrInvalidCoupon when no matching active coupon exists.
 func (r *CouponRepository) FindByCode(ctx context.Context, code string) (*coupon.Rule, error) {
-	rows, err := r.pool.Query(ctx, getCouponByCodeSQL, code)
+	rows, err := conn(ctx, r.pool).Query(ctx, getCouponByCodeSQL, code)
 	if err != nil {
 		return nil, fmt.Errorf("finding coupon by code %q: %w", code, err)
 	}
@@ -51,15 +111,166 @@ func (r *CouponRepository) FindByCode(ctx context.Context, code string) (*coupon
 	return &rule, nil
 }
 
-// IncrementUses atomically increments the usage counter for the given coupon code.
+// IncrementUses atomically increments the usage counter for the given coupon
+// code. The update only applies while uses < max_uses, so concurrent
+// redemptions cannot overshoot the limit. Returns
+// coupon.ErrCouponUsageLimitReached when no row was updated.
 func (r *CouponRepository) IncrementUses(ctx context.Context, code string) error {
-	_, err := r.pool.Exec(ctx, incrementCouponUsesSQL, code)
+	tag, err := conn(ctx, r.pool).Exec(ctx, incrementCouponUsesSQL, code)
 	if err != nil {
 		return fmt.Errorf("incrementing uses for coupon %q: %w", code, err)
 	}
+	if tag.RowsAffected() == 0 {
+		return coupon.ErrCouponUsageLimitReached
+	}
+	return nil
+}
+
+// DecrementUses gives back one use of the given coupon code. The counter
+// never goes below zero.
+func (r *CouponRepository) DecrementUses(ctx context.Context, code string) error {
+	if _, err := conn(ctx, r.pool).Exec(ctx, decrementCouponUsesSQL, code); err != nil {
+		return fmt.Errorf("decrementing uses for coupon %q: %w", code, err)
+	}
 	return nil
 }
 
+// Get returns the coupon with the given code, active or not, with its usage.
+// Returns coupon.ErrNotFound when no coupon has the code.
+func (r *CouponRepository) Get(ctx context.Context, code string) (*coupon.Coupon, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, getCouponSQL, code)
+	if err != nil {
+		return nil, fmt.Errorf("getting coupon %q: %w", code, err)
+	}
+
+	c, err := pgx.CollectExactlyOneRow(rows, scanCoupon)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, coupon.ErrNotFound
+		}
+		return nil, fmt.Errorf("getting coupon %q: %w", code, err)
+	}
+	return &c, nil
+}
+
+// List returns coupons matching the filter, ordered by code.
+func (r *CouponRepository) List(ctx context.Context, f coupon.ListFilter) ([]coupon.Coupon, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, listCouponsSQL,
+		f.Active, string(f.DiscountType), f.ValidFrom, f.ValidUntil, f.After, f.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing coupons: %w", err)
+	}
+	return pgx.CollectRows(rows, scanCoupon)
+}
+
+// Create inserts a new active coupon. Returns coupon.ErrExists when the code
+// is taken; the unique index on UPPER(code) makes the check case-insensitive.
+func (r *CouponRepository) Create(ctx context.Context, rule *coupon.Rule) error {
+	tag, err := conn(ctx, r.pool).Exec(ctx, createCouponSQL,
+		rule.Code, string(rule.DiscountType), rule.Value, rule.MinItems, rule.Description,
+		rule.ValidFrom, rule.ValidUntil, rule.MaxUses, rule.MaxDiscount, rule.MaxUsesPerCustomer,
+		rule.Stackable, rule.Priority,
+		textArray(rule.IncludeProducts), textArray(rule.IncludeCategories),
+		textArray(rule.ExcludeProducts), textArray(rule.ExcludeCategories),
+	)
+	if err != nil {
+		return fmt.Errorf("creating coupon %q: %w", rule.Code, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return coupon.ErrExists
+	}
+	return nil
+}
+
+// Update replaces the rule of an existing coupon, leaving uses and active
+// alone. Returns coupon.ErrNotFound when no coupon has the code.
+func (r *CouponRepository) Update(ctx context.Context, rule *coupon.Rule) error {
+	tag, err := conn(ctx, r.pool).Exec(ctx, updateCouponSQL,
+		rule.Code, string(rule.DiscountType), rule.Value, rule.MinItems, rule.Description,
+		rule.ValidFrom, rule.ValidUntil, rule.MaxUses, rule.MaxDiscount, rule.MaxUsesPerCustomer,
+		rule.Stackable, rule.Priority,
+		textArray(rule.IncludeProducts), textArray(rule.IncludeCategories),
+		textArray(rule.ExcludeProducts), textArray(rule.ExcludeCategories),
+	)
+	if err != nil {
+		return fmt.Errorf("updating coupon %q: %w", rule.Code, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return coupon.ErrNotFound
+	}
+	return nil
+}
+
+// Deactivate marks a coupon inactive. Returns coupon.ErrNotFound when no
+// coupon has the code.
+func (r *CouponRepository) Deactivate(ctx context.Context, code string) error {
+	tag, err := conn(ctx, r.pool).Exec(ctx, deactivateCouponSQL, code)
+	if err != nil {
+		return fmt.Errorf("deactivating coupon %q: %w", code, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return coupon.ErrNotFound
+	}
+	return nil
+}
+
+// CountRedemptions returns how many redemptions of the coupon with the exact
+// code are recorded for customer.
+func (r *CouponRepository) CountRedemptions(ctx context.Context, code, customer string) (int, error) {
+	var n int
+	if err := conn(ctx, r.pool).QueryRow(ctx, countCouponRedemptionsSQL, code, customer).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting redemptions of coupon %q: %w", code, err)
+	}
+	return n, nil
+}
+
+// RecordRedemption adds a redemption to the ledger. The order it names may
+// be inserted later in the same transaction.
+func (r *CouponRepository) RecordRedemption(ctx context.Context, rd coupon.Redemption) error {
+	if _, err := conn(ctx, r.pool).Exec(ctx, insertCouponRedemptionSQL, rd.Code, rd.Customer, rd.OrderID); err != nil {
+		return fmt.Errorf("recording redemption of coupon %q: %w", rd.Code, err)
+	}
+	return nil
+}
+
+// DeleteRedemption removes the ledger entry of an order for the given coupon
+// code (case-insensitive). It is a no-op for orders without one.
+func (r *CouponRepository) DeleteRedemption(ctx context.Context, code, orderID string) error {
+	if _, err := conn(ctx, r.pool).Exec(ctx, deleteCouponRedemptionSQL, code, orderID); err != nil {
+		return fmt.Errorf("deleting redemption of coupon %q by order %s: %w", code, orderID, err)
+	}
+	return nil
+}
+
+func scanCoupon(row pgx.CollectableRow) (coupon.Coupon, error) {
+	var (
+		c            coupon.Coupon
+		discountType string
+		minItems     int32
+		maxUses      int32
+		uses         int32
+		perCustomer  int32
+		priority     int32
+		orders       int64
+	)
+	err := row.Scan(
+		&c.Code, &discountType, &c.Value, &minItems, &c.Description,
+		&c.ValidFrom, &c.ValidUntil, &maxUses, &uses, &c.MaxDiscount, &perCustomer,
+		&c.Stackable, &priority,
+		&c.IncludeProducts, &c.IncludeCategories, &c.ExcludeProducts, &c.ExcludeCategories,
+		&c.Active, &c.CreatedAt, &orders, &c.Usage.DiscountTotal,
+	)
+	c.DiscountType = coupon.DiscountType(discountType)
+	c.MinItems = int(minItems)
+	c.MaxUses = int(maxUses)
+	c.Uses = int(uses)
+	c.MaxUsesPerCustomer = int(perCustomer)
+	c.Priority = int(priority)
+	c.Usage.Orders = int(orders)
+	return c, err
+}
+
 func scanCouponRule(row pgx.CollectableRow) (coupon.Rule, error) {
 	var (
 		rule         coupon.Rule
@@ -71,10 +282,14 @@ func scanCouponRule(row pgx.CollectableRow) (coupon.Rule, error) {
 		maxUses      int32
 		uses         int32
 		maxDiscount  decimal.Decimal
+		perCustomer  int32
+		priority     int32
 	)
 	err := row.Scan(
 		&rule.Code, &discountType, &value, &minItems, &rule.Description,
-		&validFrom, &validUntil, &maxUses, &uses, &maxDiscount,
+		&validFrom, &validUntil, &maxUses, &uses, &maxDiscount, &perCustomer,
+		&rule.Stackable, &priority,
+		&rule.IncludeProducts, &rule.IncludeCategories, &rule.ExcludeProducts, &rule.ExcludeCategories,
 	)
 	rule.DiscountType = coupon.DiscountType(discountType)
 	rule.Value = value
@@ -84,5 +299,16 @@ func scanCouponRule(row pgx.CollectableRow) (coupon.Rule, error) {
 	rule.MaxUses = int(maxUses)
 	rule.Uses = int(uses)
 	rule.MaxDiscount = maxDiscount
+	rule.MaxUsesPerCustomer = int(perCustomer)
+	rule.Priority = int(priority)
 	return rule, err
 }
+
+// textArray returns s, or an empty slice when s is nil, for the NOT NULL
+// TEXT[] targeting columns.
+func textArray(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/xenking/oolio-kart-challenge/internal/domain/coupon"
+)
+
+// failCouponAttemptSQL adds $2 to subject $1's codes at $3, starting over
+// if its last code was presented at or before $4. The conflicting update
+// holds the row lock, so concurrent attempts from any server see each
+// other's codes. SET expressions read the row as it was, which is how
+// repeated tells a code already counted.
+const failCouponAttemptSQL = `INSERT INTO coupon_attempts AS a (subject, codes, last_at)
+	VALUES ($1, ARRAY[$2::text], $3)
+	ON CONFLICT (subject) DO UPDATE SET
+		codes = CASE
+			WHEN a.last_at <= $4 THEN ARRAY[$2::text]
+			WHEN $2 = ANY (a.codes) THEN a.codes
+			ELSE a.codes || $2::text
+		END,
+		repeated = a.last_at > $4 AND $2 = ANY (a.codes),
+		blocked_until = CASE WHEN a.last_at <= $4 THEN NULL ELSE a.blocked_until END,
+		last_at = $3
+	RETURNING cardinality(codes), repeated`
+
+// blockCouponSubjectSQL never shortens a block; GREATEST ignores NULL.
+const blockCouponSubjectSQL = `UPDATE coupon_attempts SET
+	blocked_until = GREATEST(blocked_until, $2),
+	codes = CASE WHEN $3 THEN '{}' ELSE codes END
+	WHERE subject = $1`
+
+const couponBlockedUntilSQL = `SELECT max(blocked_until) FROM coupon_attempts WHERE subject = ANY ($1)`
+
+const deleteIdleCouponAttemptsSQL = `DELETE FROM coupon_attempts
+	WHERE last_at <= $2 AND (blocked_until IS NULL OR blocked_until <= $1)`
+
+var _ coupon.AttemptStore = (*CouponAttemptRepository)(nil)
+
+// CouponAttemptRepository keeps the coupon guard's state in PostgreSQL, so
+// every server behind a load balancer counts a caller's invalid codes
+// against the same limits.
+type CouponAttemptRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCouponAttemptRepository returns a CouponAttemptRepository that uses
+// the given pool.
+func NewCouponAttemptRepository(pool *pgxpool.Pool) *CouponAttemptRepository {
+	return &CouponAttemptRepository{pool: pool}
+}
+
+// Fail records that subject presented code at now.
+func (r *CouponAttemptRepository) Fail(ctx context.Context, subject, code string, now, forgetBefore time.Time) (int, error) {
+	var (
+		n        int
+		repeated bool
+	)
+	err := r.pool.QueryRow(ctx, failCouponAttemptSQL, subject, code, now, forgetBefore).Scan(&n, &repeated)
+	if err != nil {
+		return 0, fmt.Errorf("recording coupon attempt: %w", err)
+	}
+	if repeated {
+		return 0, nil
+	}
+	return n, nil
+}
+
+// Block blocks subject until until, unless it already is for longer.
+func (r *CouponAttemptRepository) Block(ctx context.Context, subject string, until time.Time, reset bool) error {
+	if _, err := r.pool.Exec(ctx, blockCouponSubjectSQL, subject, until, reset); err != nil {
+		return fmt.Errorf("blocking coupon subject: %w", err)
+	}
+	return nil
+}
+
+// BlockedUntil returns the latest time any of subjects is blocked until.
+func (r *CouponAttemptRepository) BlockedUntil(ctx context.Context, subjects []string) (time.Time, error) {
+	var until *time.Time
+	if err := r.pool.QueryRow(ctx, couponBlockedUntilSQL, subjects).Scan(&until); err != nil {
+		return time.Time{}, fmt.Errorf("reading coupon blocks: %w", err)
+	}
+	if until == nil {
+		return time.Time{}, nil
+	}
+	return *until, nil
+}
+
+// Prune deletes idle subjects that are no longer blocked and returns how
+// many were removed.
+func (r *CouponAttemptRepository) Prune(ctx context.Context, now, forgetBefore time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, deleteIdleCouponAttemptsSQL, now, forgetBefore)
+	if err != nil {
+		return 0, fmt.Errorf("deleting idle coupon attempts: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/xenking/oolio-kart-challenge/internal/domain/idempotency"
+)
+
+// reserveIdempotencyKeySQL claims a key, taking over rows that have expired
+// but not been pruned yet. A live row makes the upsert a no-op and nothing
+// is returned.
+const reserveIdempotencyKeySQL = `INSERT INTO idempotency_keys (scope, key, fingerprint, expires_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (scope, key) DO UPDATE SET
+		fingerprint = EXCLUDED.fingerprint, status_code = 0, response = NULL,
+		created_at = now(), expires_at = EXCLUDED.expires_at
+	WHERE idempotency_keys.expires_at <= now()
+	RETURNING key`
+
+const getIdempotencyKeySQL = `SELECT scope, key, fingerprint, status_code, response, created_at, expires_at
+	FROM idempotency_keys WHERE scope = $1 AND key = $2 AND expires_at > now()`
+
+const completeIdempotencyKeySQL = `UPDATE idempotency_keys
+	SET status_code = $3, response = $4
+	WHERE scope = $1 AND key = $2 AND status_code = 0`
+
+const releaseIdempotencyKeySQL = `DELETE FROM idempotency_keys
+	WHERE scope = $1 AND key = $2 AND status_code = 0`
+
+const abandonIdempotencyKeySQL = `UPDATE idempotency_keys
+	SET expires_at = LEAST(expires_at, $3)
+	WHERE scope = $1 AND key = $2 AND status_code = 0`
+
+const deleteExpiredIdempotencyKeysSQL = `DELETE FROM idempotency_keys WHERE expires_at <= $1`
+
+// reserveAttempts bounds the reserve/lookup loop. A second attempt is only
+// needed when the holder released the key between our insert and lookup.
+const reserveAttempts = 3
+
+var _ idempotency.Repository = (*IdempotencyRepository)(nil)
+
+// IdempotencyRepository stores Idempotency-Key records in PostgreSQL.
+type IdempotencyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewIdempotencyRepository returns an IdempotencyRepository that uses the given pool.
+func NewIdempotencyRepository(pool *pgxpool.Pool) *IdempotencyRepository {
+	return &IdempotencyRepository{pool: pool}
+}
+
+// Reserve claims the key or returns the live record that holds it.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, rec idempotency.Record) (*idempotency.Record, error) {
+	for range reserveAttempts {
+		var key string
+		err := r.pool.QueryRow(ctx, reserveIdempotencyKeySQL,
+			rec.Scope, rec.Key, rec.Fingerprint, rec.ExpiresAt,
+		).Scan(&key)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("reserving idempotency key: %w", err)
+		}
+
+		existing, err := r.get(ctx, rec.Scope, rec.Key)
+		if err == nil {
+			return existing, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("getting idempotency key: %w", err)
+		}
+		// Released or expired in between; try to claim it again.
+	}
+	return nil, fmt.Errorf("reserving idempotency key: contention after %d attempts", reserveAttempts)
+}
+
+func (r *IdempotencyRepository) get(ctx context.Context, scope, key string) (*idempotency.Record, error) {
+	var rec idempotency.Record
+	err := r.pool.QueryRow(ctx, getIdempotencyKeySQL, scope, key).Scan(
+		&rec.Scope, &rec.Key, &rec.Fingerprint, &rec.StatusCode, &rec.Response,
+		&rec.CreatedAt, &rec.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// Complete stores the response for a reservation.
+func (r *IdempotencyRepository) Complete(ctx context.Context, scope, key string, statusCode int, response []byte) error {
+	if _, err := r.pool.Exec(ctx, completeIdempotencyKeySQL, scope, key, statusCode, response); err != nil {
+		return fmt.Errorf("completing idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release deletes a reservation that has not been completed.
+func (r *IdempotencyRepository) Release(ctx context.Context, scope, key string) error {
+	if _, err := r.pool.Exec(ctx, releaseIdempotencyKeySQL, scope, key); err != nil {
+		return fmt.Errorf("releasing idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Abandon shortens a reservation that has not been completed so it expires
+// at expiresAt at the latest.
+func (r *IdempotencyRepository) Abandon(ctx context.Context, scope, key string, expiresAt time.Time) error {
+	if _, err := r.pool.Exec(ctx, abandonIdempotencyKeySQL, scope, key, expiresAt); err != nil {
+		return fmt.Errorf("abandoning idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes records that expired before now.
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, deleteExpiredIdempotencyKeysSQL, now)
+	if err != nil {
+		return 0, fmt.Errorf("deleting expired idempotency keys: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/xenking/oolio-kart-challenge/internal/domain/auth"
+)
+
+// useNonceSQL records a nonce, taking over a row that has expired but not
+// been pruned yet. A live row makes the upsert a no-op.
+const useNonceSQL = `INSERT INTO request_nonces (key_id, nonce, expires_at)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (key_id, nonce) DO UPDATE SET expires_at = EXCLUDED.expires_at
+	WHERE request_nonces.expires_at <= now()`
+
+const deleteExpiredNoncesSQL = `DELETE FROM request_nonces WHERE expires_at <= now()`
+
+var _ auth.NonceStore = (*NonceRepository)(nil)
+
+// NonceRepository stores signed-request nonces in PostgreSQL, so a nonce
+// is rejected by every server once any of them has seen it.
+type NonceRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewNonceRepository returns a NonceRepository that uses the given pool.
+func NewNonceRepository(pool *pgxpool.Pool) *NonceRepository {
+	return &NonceRepository{pool: pool}
+}
+
+// UseNonce records nonce for key id until expiresAt.
+func (r *NonceRepository) UseNonce(ctx context.Context, id, nonce string, expiresAt time.Time) error {
+	tag, err := r.pool.Exec(ctx, useNonceSQL, id, nonce, expiresAt)
+	if err != nil {
+		return fmt.Errorf("recording request nonce: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return auth.ErrNonceReused
+	}
+	return nil
+}
+
+// Prune deletes expired nonces and returns how many were removed.
+func (r *NonceRepository) Prune(ctx context.Context) (int64, error) {
+	tag, err := r.pool.Exec(ctx, deleteExpiredNoncesSQL)
+	if err != nil {
+		return 0, fmt.Errorf("deleting expired request nonces: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -4,14 +4,74 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/go-faster/errors"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/xenking/oolio-kart-challenge/internal/domain/order"
 )
 
-const createOrderSQL = `INSERT INTO orders (id, items, total, discounts, coupon_code)
-	VALUES ($1, $2, $3, $4, $5)`
+const (
+	// createOrderSQL inserts the order, its applied coupons and its initial
+	// status transition in one statement. $8 is the JSON array of applied
+	// coupons in application order.
+	createOrderSQL = `WITH inserted AS (
+		INSERT INTO orders (id, items, total, discounts, coupon_code, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, status, created_at, status_updated_at
+	), history AS (
+		INSERT INTO order_status_transitions (order_id, to_status, actor, created_at)
+		SELECT id, status, $7, status_updated_at FROM inserted
+	), coupons AS (
+		INSERT INTO order_coupons (order_id, position, code, description, discount)
+		SELECT inserted.id, c.pos, c.coupon->>'code', c.coupon->>'description', (c.coupon->>'discount')::numeric
+		FROM inserted, jsonb_array_elements($8::jsonb) WITH ORDINALITY AS c(coupon, pos)
+	)
+	SELECT created_at, status_updated_at FROM inserted`
+
+	// orderColumnsSQL selects an order for scanOrder, with its applied
+	// coupons aggregated into a JSON array.
+	orderColumnsSQL = `SELECT id, items, total, discounts, coupon_code,
+		COALESCE((
+			SELECT jsonb_agg(jsonb_build_object(
+				'code', oc.code, 'description', oc.description, 'discount', oc.discount
+			) ORDER BY oc.position)
+			FROM order_coupons oc WHERE oc.order_id = orders.id
+		), '[]'),
+		status, status_updated_at, created_at
+		FROM orders`
+
+	getOrderByIDSQL = orderColumnsSQL + ` WHERE id = $1`
+
+	listOrdersSQL = orderColumnsSQL + `
+		WHERE ($1 = '' OR EXISTS (
+			SELECT 1 FROM order_coupons oc
+			WHERE oc.order_id = orders.id AND UPPER(oc.code) = UPPER($1)
+		))
+			AND ($2::timestamptz IS NULL OR created_at >= $2)
+			AND ($3::timestamptz IS NULL OR created_at < $3)
+			AND ($4::timestamptz IS NULL OR (created_at, id) < ($4, $5))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $6`
+
+	// updateOrderStatusSQL only matches while the order is still in the
+	// expected status, so concurrent updates cannot both apply.
+	updateOrderStatusSQL = `WITH updated AS (
+		UPDATE orders SET status = $3, status_updated_at = NOW()
+		WHERE id = $1 AND status = $2
+		RETURNING id, status_updated_at
+	), history AS (
+		INSERT INTO order_status_transitions (order_id, from_status, to_status, actor, reason, created_at)
+		SELECT id, $2, $3, $4, $5, status_updated_at FROM updated
+	)
+	SELECT status_updated_at FROM updated`
+
+	listOrderTransitionsSQL = `SELECT order_id, from_status, to_status, actor, reason, created_at
+		FROM order_status_transitions WHERE order_id = $1
+		ORDER BY id`
+)
 
 var _ order.Repository = (*OrderRepository)(nil)
 
@@ -25,20 +85,106 @@ func NewOrderRepository(pool *pgxpool.Pool) *OrderRepository {
 	return &OrderRepository{pool: pool}
 }
 
-// Create persists a new order. The order items are serialized to JSON for
-// storage in the JSONB column.
-func (r *OrderRepository) Create(ctx context.Context, o *order.Order) error {
+// Create persists a new order along with its applied coupons and initial
+// status transition. The order items are serialized to JSON for storage in
+// the JSONB column. CreatedAt and StatusUpdatedAt are populated from the
+// database.
+func (r *OrderRepository) Create(ctx context.Context, o *order.Order, actor string) error {
 	itemsJSON, err := json.Marshal(o.Items)
 	if err != nil {
 		return fmt.Errorf("marshaling order items: %w", err)
 	}
+	coupons := o.Coupons
+	if coupons == nil {
+		coupons = []order.AppliedCoupon{}
+	}
+	couponsJSON, err := json.Marshal(coupons)
+	if err != nil {
+		return fmt.Errorf("marshaling order coupons: %w", err)
+	}
 
-	_, err = r.pool.Exec(ctx, createOrderSQL,
-		o.ID, itemsJSON, o.Total, o.Discounts, o.CouponCode,
-	)
+	err = conn(ctx, r.pool).QueryRow(ctx, createOrderSQL,
+		o.ID, itemsJSON, o.Total, o.Discounts, o.CouponCode, o.Status, actor, couponsJSON,
+	).Scan(&o.CreatedAt, &o.StatusUpdatedAt)
 	if err != nil {
 		return fmt.Errorf("creating order %q: %w", o.ID, err)
 	}
 
 	return nil
 }
+
+// GetByID returns a single order by its identifier.
+// Returns order.ErrNotFound when no such order exists.
+func (r *OrderRepository) GetByID(ctx context.Context, id string) (*order.Order, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, getOrderByIDSQL, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting order %q: %w", id, err)
+	}
+
+	o, err := pgx.CollectExactlyOneRow(rows, scanOrder)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, order.ErrNotFound
+		}
+		return nil, fmt.Errorf("getting order %q: %w", id, err)
+	}
+	return &o, nil
+}
+
+// List returns orders matching the filter ordered by (created_at, id)
+// descending.
+func (r *OrderRepository) List(ctx context.Context, f order.ListFilter) ([]order.Order, error) {
+	var (
+		afterTime *time.Time
+		afterID   string
+	)
+	if f.After != nil {
+		afterTime = &f.After.CreatedAt
+		afterID = f.After.ID
+	}
+
+	rows, err := conn(ctx, r.pool).Query(ctx, listOrdersSQL,
+		f.CouponCode, f.From, f.To, afterTime, afterID, f.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing orders: %w", err)
+	}
+	return pgx.CollectRows(rows, scanOrder)
+}
+
+// UpdateStatus applies a guarded status change and records it. Returns
+// order.ErrStatusConflict when the order is not in t.From.
+func (r *OrderRepository) UpdateStatus(ctx context.Context, t *order.StatusTransition) error {
+	err := conn(ctx, r.pool).QueryRow(ctx, updateOrderStatusSQL,
+		t.OrderID, t.From, t.To, t.Actor, t.Reason,
+	).Scan(&t.At)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return order.ErrStatusConflict
+		}
+		return fmt.Errorf("updating status of order %q: %w", t.OrderID, err)
+	}
+	return nil
+}
+
+// ListTransitions returns the status history of an order, oldest first.
+func (r *OrderRepository) ListTransitions(ctx context.Context, orderID string) ([]order.StatusTransition, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, listOrderTransitionsSQL, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("listing transitions of order %q: %w", orderID, err)
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (order.StatusTransition, error) {
+		var t order.StatusTransition
+		err := row.Scan(&t.OrderID, &t.From, &t.To, &t.Actor, &t.Reason, &t.At)
+		return t, err
+	})
+}
+
+func scanOrder(row pgx.CollectableRow) (order.Order, error) {
+	var o order.Order
+	err := row.Scan(
+		&o.ID, &o.Items, &o.Total, &o.Discounts, &o.CouponCode, &o.Coupons,
+		&o.Status, &o.StatusUpdatedAt, &o.CreatedAt,
+	)
+	return o, err
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"fmt"
+	"io/fs"
+	"path"
 
 	pgxdecimal "github.com/jackc/pgx-shopspring-decimal"
 	"github.com/jackc/pgx/v5"
@@ -32,11 +34,22 @@ func NewPool(ctx context.Context, databaseURL string) (*pgxpool.Pool, error) {
 	return pool, nil
 }
 
-// RunMigrations executes the embedded DDL schema against the pool.
+// RunMigrations executes the embedded migration files against the pool in
+// lexical order.
 func RunMigrations(ctx context.Context, pool *pgxpool.Pool) error {
-	_, err := pool.Exec(ctx, db.Schema)
+	entries, err := fs.ReadDir(db.Migrations, "migrations")
 	if err != nil {
-		return fmt.Errorf("running migrations: %w", err)
+		return fmt.Errorf("reading migrations: %w", err)
+	}
+
+	for _, e := range entries {
+		sql, err := fs.ReadFile(db.Migrations, path.Join("migrations", e.Name()))
+		if err != nil {
+			return fmt.Errorf("reading migration %s: %w", e.Name(), err)
+		}
+		if _, err := pool.Exec(ctx, string(sql)); err != nil {
+			return fmt.Errorf("running migration %s: %w", e.Name(), err)
+		}
 	}
 	return nil
 }
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/xenking/oolio-kart-challenge/pkg/httpmiddleware"
+)
+
+// countRateLimitHitSQL counts a request in the key's current window unless
+// the sliding count, the previous window's hits weighted by $6 plus the
+// current window's, would exceed $4. Both the insert and the conflicting
+// update carry the check, so concurrent requests from any server can't
+// overshoot the limit. No row is returned when the request is refused.
+const countRateLimitHitSQL = `INSERT INTO rate_limit_windows AS w (key, window_start, hits, expires_at)
+	SELECT $1, $2, 1, $5
+	WHERE $6::float8 * COALESCE((SELECT p.hits FROM rate_limit_windows p
+		WHERE p.key = $1 AND p.window_start = $3), 0) + 1 <= $4::int
+	ON CONFLICT (key, window_start) DO UPDATE SET hits = w.hits + 1
+	WHERE $6::float8 * COALESCE((SELECT p.hits FROM rate_limit_windows p
+		WHERE p.key = $1 AND p.window_start = $3), 0) + w.hits + 1 <= $4::int
+	RETURNING w.hits, COALESCE((SELECT p.hits FROM rate_limit_windows p
+		WHERE p.key = $1 AND p.window_start = $3), 0)`
+
+const deleteExpiredRateLimitWindowsSQL = `DELETE FROM rate_limit_windows WHERE expires_at <= now()`
+
+var _ httpmiddleware.RateLimitStore = (*RateLimitRepository)(nil)
+
+// RateLimitRepository keeps rate limit counters in PostgreSQL, so every
+// server behind a load balancer counts against the same limit.
+type RateLimitRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRateLimitRepository returns a RateLimitRepository that uses the given
+// pool.
+func NewRateLimitRepository(pool *pgxpool.Pool) *RateLimitRepository {
+	return &RateLimitRepository{pool: pool}
+}
+
+// Allow counts a request for key at now if that keeps key within policy.
+// Windows are aligned to multiples of the policy's window, so every server
+// agrees on where they start.
+func (r *RateLimitRepository) Allow(ctx context.Context, key string, policy httpmiddleware.RateLimitPolicy, now time.Time) (httpmiddleware.RateLimitResult, error) {
+	start := now.Truncate(policy.Window)
+	resetAt := start.Add(policy.Window)
+	overlap := 1 - now.Sub(start).Seconds()/policy.Window.Seconds()
+
+	var hits, prevHits int
+	err := r.pool.QueryRow(ctx, countRateLimitHitSQL,
+		key, start, start.Add(-policy.Window), policy.Max, start.Add(2*policy.Window), overlap,
+	).Scan(&hits, &prevHits)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return httpmiddleware.RateLimitResult{ResetAt: resetAt}, nil
+	}
+	if err != nil {
+		return httpmiddleware.RateLimitResult{}, fmt.Errorf("counting rate limit hit: %w", err)
+	}
+
+	return httpmiddleware.RateLimitResult{
+		Allowed:   true,
+		Remaining: max(int(float64(policy.Max)-(float64(prevHits)*overlap+float64(hits))), 0),
+		ResetAt:   resetAt,
+	}, nil
+}
+
+// Prune deletes windows that no longer count towards any limit and returns
+// how many were removed.
+func (r *RateLimitRepository) Prune(ctx context.Context) (int64, error) {
+	tag, err := r.pool.Exec(ctx, deleteExpiredRateLimitWindowsSQL)
+	if err != nil {
+		return 0, fmt.Errorf("deleting expired rate limit windows: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
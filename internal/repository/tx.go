@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/xenking/oolio-kart-challenge/internal/domain/order"
+)
+
+var _ order.Transactor = (*Transactor)(nil)
+
+// txKey is the context key under which the active pgx.Tx is stored.
+type txKey struct{}
+
+// querier is the subset of the pgx API shared by *pgxpool.Pool and pgx.Tx.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// conn returns the transaction bound to ctx by Transactor.WithinTx, or the
+// pool when no transaction is active.
+func conn(ctx context.Context, pool *pgxpool.Pool) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return pool
+}
+
+// Transactor implements order.Transactor using PostgreSQL transactions.
+type Transactor struct {
+	pool *pgxpool.Pool
+}
+
+// NewTransactor returns a Transactor that begins transactions on the given pool.
+func NewTransactor(pool *pgxpool.Pool) *Transactor {
+	return &Transactor{pool: pool}
+}
+
+// WithinTx runs fn inside a transaction. Repositories called with the context
+// passed to fn execute their statements on that transaction. The transaction
+// is committed when fn returns nil and rolled back otherwise. Nested calls
+// join the outer transaction.
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	return pgx.BeginFunc(ctx, t.pool, func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
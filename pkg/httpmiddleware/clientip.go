@@ -0,0 +1,168 @@
+package httpmiddleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// clientIPKey is the context key for the resolved client IP.
+type clientIPKey struct{}
+
+// ClientIPFromContext returns the client IP resolved by ClientIP.
+// It returns an empty string if ClientIP has not run.
+func ClientIPFromContext(ctx context.Context) string {
+	if ip, ok := ctx.Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+	return ""
+}
+
+// ParseTrustedProxies parses a list of CIDRs, such as "10.0.0.0/8". A bare
+// IP address is taken as a single-host prefix. Blank entries are skipped.
+func ParseTrustedProxies(entries []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		if !strings.Contains(e, "/") {
+			addr, err := netip.ParseAddr(e)
+			if err != nil {
+				return nil, fmt.Errorf("trusted proxy %q: %w", e, err)
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(e)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", e, err)
+		}
+		prefixes = append(prefixes, p.Masked())
+	}
+	return prefixes, nil
+}
+
+// ClientIP returns a middleware that resolves the address of the client
+// behind any trusted proxies and stores it in the request context (retrieve
+// with ClientIPFromContext).
+//
+// Forwarding headers are only read when the direct peer is in trusted. The
+// RFC 7239 Forwarded header is preferred over X-Forwarded-For, and
+// X-Real-IP is used only when neither is present. The list of hops is walked
+// from the right, skipping trusted proxies, and the first untrusted address
+// is the client. Entries to the left of it were written by the client
+// itself and are ignored, so they cannot be used to spoof an address. An
+// entry that is not an IP address (such as an obfuscated Forwarded node)
+// stops the walk at the proxy that reported it.
+func ClientIP(trusted []netip.Prefix) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), clientIPKey{}, resolveClientIP(r, trusted))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func resolveClientIP(r *http.Request, trusted []netip.Prefix) string {
+	peer := remoteHost(r)
+	client, err := parseHop(peer)
+	if err != nil || !isTrusted(client, trusted) {
+		return peer
+	}
+
+	hops := forwardedFor(r.Header)
+	if hops == nil {
+		hops = xForwardedFor(r.Header)
+	}
+	if hops == nil {
+		if xri, err := parseHop(r.Header.Get("X-Real-IP")); err == nil {
+			return xri.String()
+		}
+		return client.String()
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := parseHop(hops[i])
+		if err != nil {
+			break
+		}
+		client = hop
+		if !isTrusted(hop, trusted) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// forwardedFor returns the for= node of each element of the Forwarded
+// headers, in order. An element without one yields an empty hop. It returns
+// nil if there is no Forwarded header.
+func forwardedFor(h http.Header) []string {
+	values := h.Values("Forwarded")
+	if len(values) == 0 {
+		return nil
+	}
+	var hops []string
+	for _, v := range values {
+		for _, elem := range strings.Split(v, ",") {
+			var hop string
+			for _, pair := range strings.Split(elem, ";") {
+				k, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if ok && strings.EqualFold(k, "for") {
+					hop = strings.Trim(val, `"`)
+				}
+			}
+			hops = append(hops, hop)
+		}
+	}
+	return hops
+}
+
+// xForwardedFor returns the entries of the X-Forwarded-For headers, in
+// order. It returns nil if there is no such header.
+func xForwardedFor(h http.Header) []string {
+	var hops []string
+	for _, v := range h.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(v, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	return hops
+}
+
+// parseHop parses an address as found in forwarding headers: a bare IP,
+// an IP with a port, or a bracketed IPv6 address with or without a port.
+func parseHop(s string) (netip.Addr, error) {
+	if ap, err := netip.ParseAddrPort(s); err == nil {
+		return ap.Addr().Unmap(), nil
+	}
+	addr, err := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(s, "["), "]"))
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return addr.Unmap(), nil
+}
+
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	for _, p := range trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteHost returns the host part of the request's RemoteAddr.
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
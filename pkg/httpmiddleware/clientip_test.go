@@ -0,0 +1,108 @@
+package httpmiddleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "2001:db8::1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		remote  string
+		headers map[string]string
+		want    string
+	}{
+		{
+			name:   "direct client",
+			remote: "203.0.113.9:1234",
+			want:   "203.0.113.9",
+		},
+		{
+			name:    "untrusted peer's headers are ignored",
+			remote:  "203.0.113.9:1234",
+			headers: map[string]string{"X-Forwarded-For": "198.51.100.1", "Forwarded": "for=198.51.100.1", "X-Real-IP": "198.51.100.1"},
+			want:    "203.0.113.9",
+		},
+		{
+			name:    "rightmost untrusted hop wins",
+			remote:  "10.0.0.2:1234",
+			headers: map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.9, 10.0.0.1"},
+			want:    "203.0.113.9",
+		},
+		{
+			name:    "all hops trusted",
+			remote:  "10.0.0.2:1234",
+			headers: map[string]string{"X-Forwarded-For": "10.0.0.3, 10.0.0.1"},
+			want:    "10.0.0.3",
+		},
+		{
+			name:    "garbage hop stops at the proxy that wrote it",
+			remote:  "10.0.0.2:1234",
+			headers: map[string]string{"X-Forwarded-For": "203.0.113.9, unknown, 10.0.0.1"},
+			want:    "10.0.0.1",
+		},
+		{
+			name:    "forwarded is preferred over x-forwarded-for",
+			remote:  "10.0.0.2:1234",
+			headers: map[string]string{"Forwarded": `for=198.51.100.1, for="[2001:db8:cafe::17]:4711";proto=https, For=10.0.0.1`, "X-Forwarded-For": "203.0.113.9"},
+			want:    "2001:db8:cafe::17",
+		},
+		{
+			name:    "obfuscated forwarded node",
+			remote:  "10.0.0.2:1234",
+			headers: map[string]string{"Forwarded": "for=_hidden, for=10.0.0.1"},
+			want:    "10.0.0.1",
+		},
+		{
+			name:    "x-real-ip from a trusted peer",
+			remote:  "[2001:db8::1]:443",
+			headers: map[string]string{"X-Real-IP": "203.0.113.9"},
+			want:    "203.0.113.9",
+		},
+		{
+			name:    "ipv4-mapped peer",
+			remote:  "[::ffff:10.0.0.2]:1234",
+			headers: map[string]string{"X-Forwarded-For": "203.0.113.9"},
+			want:    "203.0.113.9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := ClientIP(trusted)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got = ClientIPFromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	got, err := ParseTrustedProxies([]string{"10.1.2.3/8", " 127.0.0.1 ", "", "::1"})
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("127.0.0.1/32"),
+		netip.MustParsePrefix("::1/128"),
+	}, got)
+
+	for _, bad := range []string{"10.0.0.0/33", "proxy.internal"} {
+		_, err := ParseTrustedProxies([]string{bad})
+		assert.Error(t, err, bad)
+	}
+}
@@ -63,11 +63,16 @@ func LogRequests(find RouteFinder) Middleware {
 				opName = zap.String("operationName", route.Name())
 				opID = zap.String("operationId", route.OperationID())
 			}
+			clientIP := zap.Skip()
+			if ip := ClientIPFromContext(ctx); ip != "" {
+				clientIP = zap.String("clientIp", ip)
+			}
 			lg.Info("Got request",
 				zap.String("method", r.Method),
 				zap.Stringer("url", r.URL),
 				opID,
 				opName,
+				clientIP,
 			)
 			next.ServeHTTP(w, r)
 		})
@@ -12,6 +12,8 @@ type Route interface {
 	Name() string
 	OperationID() string
 	PathPattern() string
+	// Args returns the path parameter values, in path order.
+	Args() []string
 }
 
 // RouteFinder finds Route by given URL.
@@ -23,3 +25,16 @@ func MakeRouteFinder[R Route, S Server[R]](server S) RouteFinder {
 		return server.FindPath(method, u)
 	}
 }
+
+// ChainRouteFinders combines finders for servers mounted on one mux. The
+// first finder that matches wins.
+func ChainRouteFinders(finders ...RouteFinder) RouteFinder {
+	return func(method string, u *url.URL) (Route, bool) {
+		for _, find := range finders {
+			if r, ok := find(method, u); ok {
+				return r, true
+			}
+		}
+		return nil, false
+	}
+}
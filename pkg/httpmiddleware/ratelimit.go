@@ -3,117 +3,152 @@ package httpmiddleware
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"math"
-	"net"
 	"net/http"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
+
+	"github.com/go-faster/sdk/zctx"
+	"go.uber.org/zap"
 )
 
+// RateLimitPolicy is a request budget: at most Max requests per sliding
+// Window.
+type RateLimitPolicy struct {
+	Max    int
+	Window time.Duration
+}
+
+// ParseRateLimitPolicy parses a policy written as max/window, e.g. "100/1m".
+func ParseRateLimitPolicy(s string) (RateLimitPolicy, error) {
+	rawMax, rawWindow, ok := strings.Cut(s, "/")
+	if !ok {
+		return RateLimitPolicy{}, fmt.Errorf("rate limit policy %q: want max/window", s)
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(rawMax))
+	if err != nil || limit <= 0 {
+		return RateLimitPolicy{}, fmt.Errorf("rate limit policy %q: max must be a positive integer", s)
+	}
+	window, err := time.ParseDuration(strings.TrimSpace(rawWindow))
+	if err != nil || window <= 0 {
+		return RateLimitPolicy{}, fmt.Errorf("rate limit policy %q: window must be a positive duration", s)
+	}
+	return RateLimitPolicy{Max: limit, Window: window}, nil
+}
+
+// ParseRateLimitPolicies parses named policies written as name=max/window,
+// e.g. "pro=1000/1m". Blank entries are skipped.
+func ParseRateLimitPolicies(entries []string) (map[string]RateLimitPolicy, error) {
+	policies := make(map[string]RateLimitPolicy, len(entries))
+	for _, e := range entries {
+		if strings.TrimSpace(e) == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(e, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("rate limit policy %q: want name=max/window", e)
+		}
+		if _, dup := policies[name]; dup {
+			return nil, fmt.Errorf("rate limit policy %q: duplicate name", name)
+		}
+		p, err := ParseRateLimitPolicy(raw)
+		if err != nil {
+			return nil, err
+		}
+		policies[name] = p
+	}
+	return policies, nil
+}
+
 // RateLimitConfig configures the sliding window rate limiter.
 type RateLimitConfig struct {
-	// Max is the maximum number of requests allowed per window.
+	// Max is the maximum number of requests allowed per window under the
+	// default policy.
 	Max int
-	// Window is the duration of each sliding window.
+	// Window is the duration of each sliding window under the default policy.
 	Window time.Duration
-	// KeyFunc extracts the rate limit key from a request.
-	// If nil, the client IP address is used.
-	KeyFunc func(*http.Request) string
-}
-
-// entry tracks request counts across two adjacent windows for the sliding
-// window algorithm.
-type entry struct {
-	prevCount float64
-	prevStart time.Time
-	currCount float64
-	currStart time.Time
+	// KeyFunc returns the key a request is counted against and the name of
+	// its plan in Plans. An empty key falls back to the client IP address,
+	// and an empty or unknown plan to the default policy.
+	// If nil, every request is counted by client IP address. The client IP
+	// is the one resolved by ClientIP, which should run first if the server
+	// is behind a proxy.
+	KeyFunc func(*http.Request) (key, plan string)
+	// Plans are the policies keys can be assigned to, by name.
+	Plans map[string]RateLimitPolicy
+	// Operations overrides the policy for individual operations, by OpenAPI
+	// operation ID, whatever the plan. Requests to an overridden operation
+	// are counted separately from the key's other requests. Routes must be
+	// set for overrides to apply.
+	Operations map[string]RateLimitPolicy
+	// Routes resolves the operation of a request.
+	Routes RouteFinder
+	// Store keeps the request counts. If nil, a new MemoryRateLimitStore
+	// is used.
+	Store RateLimitStore
 }
 
 // rateLimiter holds the shared state for rate limiting.
 type rateLimiter struct {
-	cfg     RateLimitConfig
-	mu      sync.Mutex
-	entries map[string]*entry
+	cfg RateLimitConfig
+	// maxWindow is the longest window of any policy.
+	maxWindow time.Duration
 }
 
 func newRateLimiter(cfg RateLimitConfig) *rateLimiter {
 	if cfg.KeyFunc == nil {
-		cfg.KeyFunc = defaultKeyFunc
+		cfg.KeyFunc = func(*http.Request) (string, string) { return "", "" }
 	}
-	return &rateLimiter{
-		cfg:     cfg,
-		entries: make(map[string]*entry),
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryRateLimitStore()
 	}
-}
-
-// allow checks whether the request identified by key is within the rate limit.
-// It returns the remaining request count, the window reset time, and whether
-// the request is allowed. The caller must NOT hold rl.mu.
-func (rl *rateLimiter) allow(key string, now time.Time) (remaining int, resetAt time.Time, allowed bool) {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-
-	e, ok := rl.entries[key]
-	if !ok {
-		e = &entry{currStart: now}
-		rl.entries[key] = e
-	}
-
-	// Rotate window if the current window has elapsed.
-	if now.Sub(e.currStart) >= rl.cfg.Window {
-		e.prevCount = e.currCount
-		e.prevStart = e.currStart
-		e.currCount = 0
-		e.currStart = now.Truncate(rl.cfg.Window)
-		// If even the previous window is stale, zero it out.
-		if now.Sub(e.prevStart) >= 2*rl.cfg.Window {
-			e.prevCount = 0
+	maxWindow := cfg.Window
+	for _, policies := range []map[string]RateLimitPolicy{cfg.Plans, cfg.Operations} {
+		for _, p := range policies {
+			maxWindow = max(maxWindow, p.Window)
 		}
 	}
-
-	// Sliding window: weight previous window by how much of it overlaps
-	// with the current sliding window.
-	elapsed := now.Sub(e.currStart)
-	overlapRatio := 1.0 - elapsed.Seconds()/rl.cfg.Window.Seconds()
-	if overlapRatio < 0 {
-		overlapRatio = 0
+	return &rateLimiter{
+		cfg:       cfg,
+		maxWindow: maxWindow,
 	}
-	effectiveCount := e.prevCount*overlapRatio + e.currCount
-	resetAt = e.currStart.Add(rl.cfg.Window)
+}
 
-	if effectiveCount >= float64(rl.cfg.Max) {
-		return 0, resetAt, false
+// policy returns the bucket a request is counted in and the policy that
+// applies to it.
+func (rl *rateLimiter) policy(r *http.Request) (string, RateLimitPolicy) {
+	key, plan := rl.cfg.KeyFunc(r)
+	if key == "" {
+		key = clientKey(r)
 	}
-
-	e.currCount++
-	effectiveCount++
-
-	remaining = int(float64(rl.cfg.Max) - effectiveCount)
-	if remaining < 0 {
-		remaining = 0
+	policy, ok := rl.cfg.Plans[plan]
+	if !ok {
+		policy = RateLimitPolicy{Max: rl.cfg.Max, Window: rl.cfg.Window}
 	}
-	return remaining, resetAt, true
-}
 
-// cleanup removes entries whose windows have fully expired.
-func (rl *rateLimiter) cleanup(now time.Time) {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-
-	for key, e := range rl.entries {
-		if now.Sub(e.currStart) >= 2*rl.cfg.Window {
-			delete(rl.entries, key)
+	if len(rl.cfg.Operations) > 0 && rl.cfg.Routes != nil {
+		if route, ok := rl.cfg.Routes(r.Method, r.URL); ok {
+			if override, ok := rl.cfg.Operations[route.OperationID()]; ok {
+				return key + "|" + route.OperationID(), override
+			}
 		}
 	}
+	return key, policy
 }
 
 // startCleanup launches a background goroutine that periodically removes
-// expired entries. It stops when ctx is cancelled.
+// expired entries, if the store supports it. It stops when ctx is cancelled.
 func (rl *rateLimiter) startCleanup(ctx context.Context) {
-	interval := 2 * rl.cfg.Window
+	store, ok := rl.cfg.Store.(interface {
+		Prune(context.Context) (int64, error)
+	})
+	if !ok {
+		return
+	}
+	interval := 2 * rl.maxWindow
 	go func() {
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
@@ -121,8 +156,8 @@ func (rl *rateLimiter) startCleanup(ctx context.Context) {
 			select {
 			case <-ctx.Done():
 				return
-			case now := <-ticker.C:
-				rl.cleanup(now)
+			case <-ticker.C:
+				_, _ = store.Prune(ctx)
 			}
 		}
 	}()
@@ -131,7 +166,12 @@ func (rl *rateLimiter) startCleanup(ctx context.Context) {
 // RateLimit returns a middleware that enforces a per-key sliding window rate
 // limit. When the limit is exceeded, it responds with 429 Too Many Requests
 // and a JSON body. Every response includes X-RateLimit-Limit,
-// X-RateLimit-Remaining, and X-RateLimit-Reset headers.
+// X-RateLimit-Remaining, and X-RateLimit-Reset headers for the policy that
+// applied to the request.
+//
+// If the store fails, the error is logged and the request is let through
+// without rate limit headers, so an outage of a shared store does not take
+// the API down with it.
 //
 // This variant does not start a background cleanup goroutine. Use
 // RateLimitWithCleanup if you need automatic eviction of stale entries.
@@ -141,7 +181,8 @@ func RateLimit(cfg RateLimitConfig) Middleware {
 }
 
 // RateLimitWithCleanup is like RateLimit but additionally starts a background
-// goroutine that evicts expired entries every 2x the window duration. The
+// goroutine that evicts expired entries from the store every 2x the longest
+// window. Stores without a Prune method are left alone. The
 // goroutine stops when ctx is cancelled.
 func RateLimitWithCleanup(ctx context.Context, cfg RateLimitConfig) Middleware {
 	rl := newRateLimiter(cfg)
@@ -152,17 +193,22 @@ func RateLimitWithCleanup(ctx context.Context, cfg RateLimitConfig) Middleware {
 func rateLimitMiddleware(rl *rateLimiter) Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			key := rl.cfg.KeyFunc(r)
+			key, policy := rl.policy(r)
 			now := time.Now()
 
-			remaining, resetAt, allowed := rl.allow(key, now)
+			res, err := rl.cfg.Store.Allow(r.Context(), key, policy, now)
+			if err != nil {
+				zctx.From(r.Context()).Warn("Rate limit check failed, allowing request", zap.Error(err))
+				next.ServeHTTP(w, r)
+				return
+			}
 
-			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rl.cfg.Max))
-			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
-			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(policy.Max))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(res.ResetAt.Unix(), 10))
 
-			if !allowed {
-				retryAfter := time.Until(resetAt)
+			if !res.Allowed {
+				retryAfter := time.Until(res.ResetAt)
 				if retryAfter < 0 {
 					retryAfter = 0
 				}
@@ -181,22 +227,11 @@ func rateLimitMiddleware(rl *rateLimiter) Middleware {
 	}
 }
 
-// defaultKeyFunc extracts the client IP from the request, checking
-// X-Forwarded-For first, then X-Real-IP, then falling back to RemoteAddr.
-func defaultKeyFunc(r *http.Request) string {
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		// X-Forwarded-For may contain a comma-separated list; use the first.
-		if i := strings.IndexByte(xff, ','); i > 0 {
-			return strings.TrimSpace(xff[:i])
-		}
-		return strings.TrimSpace(xff)
-	}
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return xri
-	}
-	host, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		return r.RemoteAddr
+// clientKey returns the client IP resolved by ClientIP, or the request's
+// RemoteAddr host when ClientIP has not run.
+func clientKey(r *http.Request) string {
+	if ip := ClientIPFromContext(r.Context()); ip != "" {
+		return ip
 	}
-	return host
+	return remoteHost(r)
 }
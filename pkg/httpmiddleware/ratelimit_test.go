@@ -1,12 +1,15 @@
 package httpmiddleware
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
+	"github.com/go-faster/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -121,8 +124,8 @@ func TestRateLimit_CustomKeyFunc(t *testing.T) {
 	cfg := RateLimitConfig{
 		Max:    1,
 		Window: time.Minute,
-		KeyFunc: func(r *http.Request) string {
-			return r.Header.Get("X-API-Key")
+		KeyFunc: func(r *http.Request) (string, string) {
+			return r.Header.Get("X-API-Key"), ""
 		},
 	}
 	handler := RateLimit(cfg)(okHandler())
@@ -154,7 +157,9 @@ func TestRateLimit_XForwardedFor(t *testing.T) {
 		Max:    1,
 		Window: time.Minute,
 	}
-	handler := RateLimit(cfg)(okHandler())
+	trusted, err := ParseTrustedProxies([]string{"192.168.0.0/16"})
+	require.NoError(t, err)
+	handler := Wrap(RateLimit(cfg)(okHandler()), ClientIP(trusted))
 
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
 	req.RemoteAddr = "192.168.1.1:4444"
@@ -163,11 +168,209 @@ func TestRateLimit_XForwardedFor(t *testing.T) {
 	handler.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	// Same X-Forwarded-For first IP should be limited.
+	// Same client behind another trusted proxy should be limited.
 	req2 := httptest.NewRequest(http.MethodGet, "/", nil)
 	req2.RemoteAddr = "192.168.1.2:5555" // different RemoteAddr
-	req2.Header.Set("X-Forwarded-For", "203.0.113.50, 70.41.3.18")
+	req2.Header.Set("X-Forwarded-For", "198.51.100.7, 70.41.3.18")
 	w2 := httptest.NewRecorder()
 	handler.ServeHTTP(w2, req2)
 	assert.Equal(t, http.StatusTooManyRequests, w2.Code)
 }
+
+func TestRateLimit_IgnoresUntrustedXForwardedFor(t *testing.T) {
+	handler := Wrap(RateLimit(RateLimitConfig{Max: 1, Window: time.Minute})(okHandler()), ClientIP(nil))
+
+	// A client can't get a fresh budget by making up forwarding headers.
+	for i, spoofed := range []string{"203.0.113.50", "203.0.113.51"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "70.41.3.18:4444"
+		req.Header.Set("X-Forwarded-For", spoofed)
+		req.Header.Set("X-Real-IP", spoofed)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if i == 0 {
+			assert.Equal(t, http.StatusOK, w.Code)
+		} else {
+			assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		}
+	}
+}
+
+func TestRateLimit_Plans(t *testing.T) {
+	cfg := RateLimitConfig{
+		Max:    1,
+		Window: time.Minute,
+		KeyFunc: func(r *http.Request) (string, string) {
+			return r.Header.Get("X-API-Key"), r.Header.Get("X-Plan")
+		},
+		Plans: map[string]RateLimitPolicy{
+			"pro": {Max: 3, Window: time.Minute},
+		},
+	}
+	handler := RateLimit(cfg)(okHandler())
+
+	do := func(key, plan string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-API-Key", key)
+		req.Header.Set("X-Plan", plan)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := range 3 {
+		w := do("key-pro", "pro")
+		assert.Equal(t, http.StatusOK, w.Code, "pro request %d should pass", i+1)
+		assert.Equal(t, "3", w.Header().Get("X-RateLimit-Limit"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, do("key-pro", "pro").Code)
+
+	// Unknown plans get the default policy.
+	w := do("key-other", "enterprise")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, http.StatusTooManyRequests, do("key-other", "enterprise").Code)
+}
+
+func TestRateLimit_KeyFuncFallsBackToIP(t *testing.T) {
+	cfg := RateLimitConfig{
+		Max:     1,
+		Window:  time.Minute,
+		KeyFunc: func(*http.Request) (string, string) { return "", "" },
+	}
+	handler := RateLimit(cfg)(okHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.168.1.1:1111"
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req2 := httptest.NewRequest(http.MethodGet, "/", nil)
+	req2.RemoteAddr = "192.168.1.2:2222"
+	w2 := httptest.NewRecorder()
+	handler.ServeHTTP(w2, req2)
+	assert.Equal(t, http.StatusOK, w2.Code, "a different IP has its own budget")
+}
+
+type fakeRoute struct{ opID string }
+
+func (r fakeRoute) Name() string        { return r.opID }
+func (r fakeRoute) OperationID() string { return r.opID }
+func (r fakeRoute) PathPattern() string { return "/" + r.opID }
+func (r fakeRoute) Args() []string      { return nil }
+
+func TestRateLimit_OperationOverride(t *testing.T) {
+	cfg := RateLimitConfig{
+		Max:    5,
+		Window: time.Minute,
+		Operations: map[string]RateLimitPolicy{
+			"placeOrder": {Max: 1, Window: time.Hour},
+		},
+		Routes: func(_ string, u *url.URL) (Route, bool) {
+			return fakeRoute{opID: u.Path[1:]}, true
+		},
+	}
+	handler := RateLimit(cfg)(okHandler())
+
+	do := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.RemoteAddr = "192.168.1.1:4444"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("/placeOrder")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = do("/placeOrder")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Limit"))
+
+	// Other operations draw on the default budget, untouched by the override.
+	w = do("/listProducts")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "5", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "4", w.Header().Get("X-RateLimit-Remaining"))
+}
+
+func TestParseRateLimitPolicies(t *testing.T) {
+	policies, err := ParseRateLimitPolicies([]string{"free=60/1m", " pro = 1000/1m ", ""})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]RateLimitPolicy{
+		"free": {Max: 60, Window: time.Minute},
+		"pro":  {Max: 1000, Window: time.Minute},
+	}, policies)
+
+	for _, bad := range []string{"pro", "=10/1m", "pro=10", "pro=0/1m", "pro=x/1m", "pro=10/0s", "pro=10/soon"} {
+		_, err := ParseRateLimitPolicies([]string{bad})
+		assert.Error(t, err, bad)
+	}
+
+	_, err = ParseRateLimitPolicies([]string{"pro=1/1m", "pro=2/1m"})
+	assert.Error(t, err, "duplicate names")
+}
+
+type failingStore struct{}
+
+func (failingStore) Allow(context.Context, string, RateLimitPolicy, time.Time) (RateLimitResult, error) {
+	return RateLimitResult{}, errors.New("store unavailable")
+}
+
+func TestRateLimit_StoreErrorAllowsRequest(t *testing.T) {
+	handler := RateLimit(RateLimitConfig{Max: 1, Window: time.Minute, Store: failingStore{}})(okHandler())
+
+	for range 2 {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("X-RateLimit-Limit"))
+	}
+}
+
+func TestRateLimit_SharedStore(t *testing.T) {
+	// Two servers sharing a store enforce one limit between them.
+	store := NewMemoryRateLimitStore()
+	cfg := RateLimitConfig{Max: 2, Window: time.Minute, Store: store}
+	a := RateLimit(cfg)(okHandler())
+	b := RateLimit(cfg)(okHandler())
+
+	codes := make([]int, 0, 3)
+	for _, h := range []http.Handler{a, b, a} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "192.168.1.1:4444"
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+	}
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+}
+
+func TestMemoryRateLimitStore_Prune(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryRateLimitStore()
+	s.now = func() time.Time { return now }
+
+	short := RateLimitPolicy{Max: 5, Window: time.Minute}
+	long := RateLimitPolicy{Max: 5, Window: time.Hour}
+	for key, policy := range map[string]RateLimitPolicy{"short": short, "long": long} {
+		res, err := s.Allow(ctx, key, policy, now)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+		assert.Equal(t, 4, res.Remaining)
+	}
+
+	now = now.Add(2 * time.Minute)
+	n, err := s.Prune(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n, "only the entry with the short window has expired")
+
+	res, err := s.Allow(ctx, "long", long, now)
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.Remaining)
+}
@@ -0,0 +1,126 @@
+package httpmiddleware
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimitResult is the outcome of counting one request.
+type RateLimitResult struct {
+	// Allowed reports whether the request was within the policy and counted.
+	Allowed bool
+	// Remaining is how many more requests the key may make right now.
+	Remaining int
+	// ResetAt is when the current window ends.
+	ResetAt time.Time
+}
+
+// RateLimitStore keeps the sliding window counters behind RateLimit.
+// Implementations must check and count a request in one atomic step, so
+// that servers sharing a store enforce a single limit between them.
+type RateLimitStore interface {
+	// Allow counts a request for key at now if that keeps key within
+	// policy. Requests over the limit are not counted.
+	Allow(ctx context.Context, key string, policy RateLimitPolicy, now time.Time) (RateLimitResult, error)
+}
+
+// entry tracks request counts across two adjacent windows for the sliding
+// window algorithm.
+type entry struct {
+	window    time.Duration
+	prevCount float64
+	prevStart time.Time
+	currCount float64
+	currStart time.Time
+}
+
+// MemoryRateLimitStore is a RateLimitStore for a single server. Each server
+// keeps its own counts, so replicas behind a load balancer each allow the
+// full limit.
+type MemoryRateLimitStore struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// NewMemoryRateLimitStore creates an empty MemoryRateLimitStore.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+}
+
+// Allow checks whether the request identified by key is within policy and
+// counts it if so.
+func (s *MemoryRateLimitStore) Allow(_ context.Context, key string, policy RateLimitPolicy, now time.Time) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || e.window != policy.Window {
+		// A key moved to a plan with a different window starts afresh.
+		e = &entry{window: policy.Window, currStart: now}
+		s.entries[key] = e
+	}
+
+	// Rotate window if the current window has elapsed.
+	if now.Sub(e.currStart) >= policy.Window {
+		e.prevCount = e.currCount
+		e.prevStart = e.currStart
+		e.currCount = 0
+		e.currStart = now.Truncate(policy.Window)
+		// If even the previous window is stale, zero it out.
+		if now.Sub(e.prevStart) >= 2*policy.Window {
+			e.prevCount = 0
+		}
+	}
+
+	effectiveCount := slidingCount(e.prevCount, e.currCount, now.Sub(e.currStart), policy.Window)
+	resetAt := e.currStart.Add(policy.Window)
+
+	if effectiveCount >= float64(policy.Max) {
+		return RateLimitResult{ResetAt: resetAt}, nil
+	}
+
+	e.currCount++
+	return RateLimitResult{
+		Allowed:   true,
+		Remaining: remainingRequests(policy.Max, effectiveCount+1),
+		ResetAt:   resetAt,
+	}, nil
+}
+
+// Prune removes entries whose windows have fully expired and returns how
+// many were removed.
+func (s *MemoryRateLimitStore) Prune(_ context.Context) (int64, error) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	for key, e := range s.entries {
+		if now.Sub(e.currStart) >= 2*e.window {
+			delete(s.entries, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// slidingCount estimates the requests in the sliding window ending elapsed
+// into the current window, weighting the previous window by how much of it
+// the sliding window still overlaps.
+func slidingCount(prev, curr float64, elapsed, window time.Duration) float64 {
+	overlapRatio := 1.0 - elapsed.Seconds()/window.Seconds()
+	if overlapRatio < 0 {
+		overlapRatio = 0
+	}
+	return prev*overlapRatio + curr
+}
+
+func remainingRequests(limit int, effectiveCount float64) int {
+	return max(int(float64(limit)-effectiveCount), 0)
+}
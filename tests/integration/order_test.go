@@ -3,8 +3,15 @@
 package integration
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
 	"net/http"
 	"regexp"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -210,3 +217,502 @@ func TestPlaceOrder_ResponseStructure(t *testing.T) {
 		t.Errorf("product price: got %v, want > 0", product.Price)
 	}
 }
+
+func TestPlaceOrder_CouponMaxUsesUnderConcurrency(t *testing.T) {
+	const (
+		maxUses  = 5
+		requests = 25
+	)
+
+	execSQL(t, `INSERT INTO coupons (code, discount_type, value, description, max_uses, uses)
+		VALUES ('RACE5', 'fixed', 1, 'race test', 5, 0)
+		ON CONFLICT (code) DO UPDATE SET max_uses = EXCLUDED.max_uses, uses = 0, active = TRUE`)
+
+	body, err := json.Marshal(orderRequest{
+		Items:      []orderItemRequest{{ProductID: "1", Quantity: 1}},
+		CouponCode: "RACE5",
+	})
+	if err != nil {
+		t.Fatalf("marshal body: %v", err)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		statuses = make(map[int]int)
+	)
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// t.Fatal must not be called off the test goroutine, so failures
+			// are recorded as status 0 instead of using doPostWithAuth.
+			status := 0
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost,
+				baseURL+"/api/order", bytes.NewReader(body))
+			if err == nil {
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("api_key", testAPIKey)
+				if resp, err := httpClient.Do(req); err == nil {
+					status = resp.StatusCode
+					resp.Body.Close()
+				}
+			}
+
+			mu.Lock()
+			statuses[status]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if got := statuses[http.StatusOK]; got != maxUses {
+		t.Errorf("successful orders: got %d, want %d (statuses: %v)", got, maxUses, statuses)
+	}
+	if got := statuses[http.StatusUnprocessableEntity]; got != requests-maxUses {
+		t.Errorf("rejected orders: got %d, want %d (statuses: %v)", got, requests-maxUses, statuses)
+	}
+
+	if uses := execSQL(t, `SELECT uses FROM coupons WHERE code = 'RACE5'`); uses != "5" {
+		t.Errorf("coupon uses: got %s, want 5", uses)
+	}
+	if n := execSQL(t, `SELECT count(*) FROM orders WHERE coupon_code = 'RACE5'`); n != "5" {
+		t.Errorf("orders with coupon: got %s, want 5", n)
+	}
+}
+
+func TestGetOrder_RoundTrip(t *testing.T) {
+	resp := doPostWithAuth(t, "/api/order", orderRequest{
+		Items:      []orderItemRequest{{ProductID: "2", Quantity: 2}},
+		CouponCode: "HAPPYHOURS",
+	}, testAPIKey)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("place order: expected 200, got %d", resp.StatusCode)
+	}
+	placed := decodeJSON[orderResponse](t, resp)
+
+	getResp := doGetWithAuth(t, "/api/order/"+placed.ID, testAPIKey)
+	defer getResp.Body.Close()
+
+	if getResp.StatusCode != http.StatusOK {
+		t.Fatalf("get order: expected 200, got %d", getResp.StatusCode)
+	}
+
+	got := decodeJSON[orderResponse](t, getResp)
+	if got.ID != placed.ID {
+		t.Errorf("id: got %q, want %q", got.ID, placed.ID)
+	}
+	if got.Total != placed.Total || got.Discounts != placed.Discounts {
+		t.Errorf("totals: got %v/%v, want %v/%v", got.Total, got.Discounts, placed.Total, placed.Discounts)
+	}
+	if got.CouponCode != "HAPPYHOURS" {
+		t.Errorf("coupon code: got %q, want HAPPYHOURS", got.CouponCode)
+	}
+	if got.CreatedAt.IsZero() {
+		t.Error("createdAt is not set")
+	}
+	if len(got.Products) != 1 || got.Products[0].ID != "2" {
+		t.Errorf("products not hydrated: %+v", got.Products)
+	}
+	if len(got.Items) != 1 {
+		t.Fatalf("items: got %d, want 1", len(got.Items))
+	}
+	item := got.Items[0]
+	if item.Name != got.Products[0].Name || item.UnitPrice != got.Products[0].Price {
+		t.Errorf("item snapshot: got %q @ %v, want %q @ %v",
+			item.Name, item.UnitPrice, got.Products[0].Name, got.Products[0].Price)
+	}
+	if math.Abs(item.Discount-got.Discounts) > 0.001 {
+		t.Errorf("item discount: got %v, want %v", item.Discount, got.Discounts)
+	}
+}
+
+func TestGetOrder_SnapshotSurvivesPriceChange(t *testing.T) {
+	// Product 9 is seeded at 4.50 (see db/seed/products.json); restore its
+	// catalog row afterwards so other tests see the seed data.
+	orig := execSQL(t, `SELECT name || '|' || price FROM products WHERE id = '9'`)
+	name, price, _ := strings.Cut(strings.TrimSpace(orig), "|")
+	t.Cleanup(func() {
+		execSQL(t, fmt.Sprintf(`UPDATE products SET name = '%s', price = %s WHERE id = '9'`,
+			strings.ReplaceAll(name, "'", "''"), price))
+	})
+
+	resp := doPostWithAuth(t, "/api/order", orderRequest{
+		Items: []orderItemRequest{{ProductID: "9", Quantity: 3}},
+	}, testAPIKey)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("place order: expected 200, got %d", resp.StatusCode)
+	}
+	placed := decodeJSON[orderResponse](t, resp)
+	if len(placed.Items) != 1 {
+		t.Fatalf("items: got %d, want 1", len(placed.Items))
+	}
+	before := placed.Items[0]
+
+	execSQL(t, `UPDATE products SET price = price + 10, name = 'Renamed' WHERE id = '9'`)
+
+	getResp := doGetWithAuth(t, "/api/order/"+placed.ID, testAPIKey)
+	defer getResp.Body.Close()
+
+	got := decodeJSON[orderResponse](t, getResp)
+	if len(got.Items) != 1 {
+		t.Fatalf("items: got %d, want 1", len(got.Items))
+	}
+	if got.Items[0] != before {
+		t.Errorf("item snapshot changed with catalog: got %+v, want %+v", got.Items[0], before)
+	}
+	if before.Name != name {
+		t.Errorf("item name: got %q, want %q", before.Name, name)
+	}
+	if math.Abs(before.Subtotal-3*before.UnitPrice) > 0.001 {
+		t.Errorf("subtotal: got %v, want 3 x %v", before.Subtotal, before.UnitPrice)
+	}
+	if got.Total != placed.Total {
+		t.Errorf("total: got %v, want %v", got.Total, placed.Total)
+	}
+}
+
+func TestGetOrder_NotFound(t *testing.T) {
+	resp := doGetWithAuth(t, "/api/order/00000000-0000-0000-0000-000000000000", testAPIKey)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestGetOrder_NoAuth(t *testing.T) {
+	resp := doGet(t, "/api/order")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", resp.StatusCode)
+	}
+}
+
+func TestListOrders_CouponFilterAndPagination(t *testing.T) {
+	execSQL(t, `INSERT INTO coupons (code, discount_type, value, description)
+		VALUES ('LISTME', 'fixed', 1, 'listing test')
+		ON CONFLICT (code) DO NOTHING`)
+
+	for range 3 {
+		resp := doPostWithAuth(t, "/api/order", orderRequest{
+			Items:      []orderItemRequest{{ProductID: "1", Quantity: 1}},
+			CouponCode: "LISTME",
+		}, testAPIKey)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("place order: expected 200, got %d", resp.StatusCode)
+		}
+	}
+
+	seen := make(map[string]bool)
+	path := "/api/order?couponCode=listme&limit=2"
+	for page := 0; path != ""; page++ {
+		if page > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+
+		resp := doGetWithAuth(t, path, testAPIKey)
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			t.Fatalf("list orders: expected 200, got %d", resp.StatusCode)
+		}
+		list := decodeJSON[orderListResponse](t, resp)
+		resp.Body.Close()
+
+		for _, o := range list.Orders {
+			if o.CouponCode != "LISTME" {
+				t.Errorf("order %s has coupon %q, want LISTME", o.ID, o.CouponCode)
+			}
+			if seen[o.ID] {
+				t.Errorf("order %s returned twice", o.ID)
+			}
+			seen[o.ID] = true
+		}
+
+		path = ""
+		if list.NextCursor != "" {
+			path = "/api/order?couponCode=listme&limit=2&cursor=" + list.NextCursor
+		}
+	}
+
+	if len(seen) != 3 {
+		t.Errorf("listed %d orders, want 3", len(seen))
+	}
+}
+
+func TestPlaceOrder_IdempotencyKey(t *testing.T) {
+	execSQL(t, `INSERT INTO coupons (code, discount_type, value, min_items, description, active)
+		VALUES ('IDEMP10', 'percentage', 10, 0, 'idempotency test', TRUE)
+		ON CONFLICT (code) DO UPDATE SET uses = 0, max_uses = 0, active = TRUE`)
+
+	headers := map[string]string{
+		"api_key":         testAPIKey,
+		"Idempotency-Key": "integration-retry-1",
+	}
+	body := orderRequest{
+		Items:      []orderItemRequest{{ProductID: "1", Quantity: 2}},
+		CouponCode: "IDEMP10",
+	}
+
+	first := doPostWithHeaders(t, "/api/order", body, headers)
+	defer first.Body.Close()
+	if first.StatusCode != http.StatusOK {
+		t.Fatalf("first request: expected 200, got %d", first.StatusCode)
+	}
+	placed := decodeJSON[orderResponse](t, first)
+
+	retry := doPostWithHeaders(t, "/api/order", body, headers)
+	defer retry.Body.Close()
+	if retry.StatusCode != http.StatusOK {
+		t.Fatalf("retry: expected 200, got %d", retry.StatusCode)
+	}
+	replayed := decodeJSON[orderResponse](t, retry)
+	if replayed.ID != placed.ID || replayed.Total != placed.Total {
+		t.Errorf("retry: got order %s (%v), want %s (%v)", replayed.ID, replayed.Total, placed.ID, placed.Total)
+	}
+
+	if uses := strings.TrimSpace(execSQL(t, `SELECT uses FROM coupons WHERE code = 'IDEMP10'`)); uses != "1" {
+		t.Errorf("coupon uses: got %s, want 1", uses)
+	}
+	if n := strings.TrimSpace(execSQL(t, `SELECT count(*) FROM orders WHERE coupon_code = 'IDEMP10'`)); n != "1" {
+		t.Errorf("orders with coupon: got %s, want 1", n)
+	}
+
+	changed := body
+	changed.Items = []orderItemRequest{{ProductID: "1", Quantity: 3}}
+	mismatch := doPostWithHeaders(t, "/api/order", changed, headers)
+	defer mismatch.Body.Close()
+	if mismatch.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("reused key with different body: expected 422, got %d", mismatch.StatusCode)
+	}
+}
+
+func TestUpdateOrderStatus_Lifecycle(t *testing.T) {
+	resp := doPostWithAuth(t, "/api/order", orderRequest{
+		Items: []orderItemRequest{{ProductID: "3", Quantity: 1}},
+	}, testAPIKey)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("place order: expected 200, got %d", resp.StatusCode)
+	}
+	placed := decodeJSON[orderResponse](t, resp)
+	if placed.Status != "placed" {
+		t.Fatalf("new order status: got %q, want placed", placed.Status)
+	}
+
+	path := "/api/order/" + placed.ID + "/status"
+	accept := doPatchWithAuth(t, path, map[string]string{"status": "accepted", "reason": "on it"}, testAPIKey)
+	defer accept.Body.Close()
+	if accept.StatusCode != http.StatusOK {
+		t.Fatalf("accept: expected 200, got %d", accept.StatusCode)
+	}
+	accepted := decodeJSON[orderResponse](t, accept)
+	if accepted.Status != "accepted" {
+		t.Errorf("status after accept: got %q, want accepted", accepted.Status)
+	}
+
+	skip := doPatchWithAuth(t, path, map[string]string{"status": "completed"}, testAPIKey)
+	defer skip.Body.Close()
+	if skip.StatusCode != http.StatusConflict {
+		t.Errorf("accepted -> completed: expected 409, got %d", skip.StatusCode)
+	}
+
+	bogus := doPatchWithAuth(t, path, map[string]string{"status": "lost"}, testAPIKey)
+	defer bogus.Body.Close()
+	if bogus.StatusCode != http.StatusBadRequest {
+		t.Errorf("unknown status: expected 400, got %d", bogus.StatusCode)
+	}
+
+	getResp := doGetWithAuth(t, "/api/order/"+placed.ID, testAPIKey)
+	defer getResp.Body.Close()
+	got := decodeJSON[orderResponse](t, getResp)
+
+	if len(got.History) != 2 {
+		t.Fatalf("history: got %d entries, want 2: %+v", len(got.History), got.History)
+	}
+	if got.History[0].From != "" || got.History[0].To != "placed" {
+		t.Errorf("first transition: got %q -> %q, want '' -> placed", got.History[0].From, got.History[0].To)
+	}
+	last := got.History[1]
+	if last.From != "placed" || last.To != "accepted" || last.Reason != "on it" || last.Actor == "" {
+		t.Errorf("second transition: got %+v", last)
+	}
+}
+
+func TestUpdateOrderStatus_NotFound(t *testing.T) {
+	resp := doPatchWithAuth(t, "/api/order/00000000-0000-0000-0000-000000000000/status",
+		map[string]string{"status": "accepted"}, testAPIKey)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestCancelOrder_ReleasesCouponUse(t *testing.T) {
+	execSQL(t, `INSERT INTO coupons (code, discount_type, value, min_items, description, active, max_uses)
+		VALUES ('CANCEL1', 'fixed', 1, 0, 'cancel test', TRUE, 1)
+		ON CONFLICT (code) DO UPDATE SET uses = 0, max_uses = 1, active = TRUE`)
+
+	place := func() *http.Response {
+		return doPostWithAuth(t, "/api/order", orderRequest{
+			Items:      []orderItemRequest{{ProductID: "4", Quantity: 1}},
+			CouponCode: "cancel1",
+		}, testAPIKey)
+	}
+
+	resp := place()
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("place order: expected 200, got %d", resp.StatusCode)
+	}
+	placed := decodeJSON[orderResponse](t, resp)
+
+	exhausted := place()
+	defer exhausted.Body.Close()
+	if exhausted.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("second order before cancel: expected 422, got %d", exhausted.StatusCode)
+	}
+
+	cancelPath := "/api/order/" + placed.ID + "/cancel"
+	cancel := doPostWithAuth(t, cancelPath, map[string]string{"reason": "ordered twice"}, testAPIKey)
+	defer cancel.Body.Close()
+	if cancel.StatusCode != http.StatusOK {
+		t.Fatalf("cancel: expected 200, got %d", cancel.StatusCode)
+	}
+	cancelled := decodeJSON[orderResponse](t, cancel)
+	if cancelled.Status != "cancelled" {
+		t.Errorf("status: got %q, want cancelled", cancelled.Status)
+	}
+	if n := len(cancelled.History); n == 0 || cancelled.History[n-1].Reason != "ordered twice" {
+		t.Errorf("cancel reason not recorded: %+v", cancelled.History)
+	}
+
+	if uses := strings.TrimSpace(execSQL(t, `SELECT uses FROM coupons WHERE code = 'CANCEL1'`)); uses != "0" {
+		t.Errorf("coupon uses after cancel: got %s, want 0", uses)
+	}
+
+	again := doPostWithAuth(t, cancelPath, map[string]string{}, testAPIKey)
+	defer again.Body.Close()
+	if again.StatusCode != http.StatusConflict {
+		t.Errorf("double cancel: expected 409, got %d", again.StatusCode)
+	}
+	if uses := strings.TrimSpace(execSQL(t, `SELECT uses FROM coupons WHERE code = 'CANCEL1'`)); uses != "0" {
+		t.Errorf("coupon uses after double cancel: got %s, want 0", uses)
+	}
+
+	retry := place()
+	defer retry.Body.Close()
+	if retry.StatusCode != http.StatusOK {
+		t.Errorf("order after cancel: expected 200, got %d", retry.StatusCode)
+	}
+}
+
+func TestCancelOrder_StackedCouponsAfterMigrationsRerun(t *testing.T) {
+	execSQL(t, `INSERT INTO coupons (code, discount_type, value, min_items, description, active, stackable, max_uses_per_customer)
+		VALUES ('STACK10', 'percentage', 10, 0, 'stack 10%', TRUE, TRUE, 1),
+		       ('STACK1', 'fixed', 1, 0, 'stack $1', TRUE, TRUE, 1)
+		ON CONFLICT (code) DO UPDATE SET uses = 0, max_uses = 0, active = TRUE,
+			stackable = TRUE, max_uses_per_customer = 1`)
+	execSQL(t, `DELETE FROM coupon_redemptions WHERE coupon_code IN ('STACK10', 'STACK1')`)
+
+	// The redemption rows are written before the order row, so the order
+	// only commits if the foreign key on coupon_redemptions is deferred.
+	fkDeferred := func() string {
+		return execSQL(t, `SELECT bool_and(condeferrable AND condeferred) FROM pg_constraint
+			WHERE conrelid = 'coupon_redemptions'::regclass AND contype = 'f'`)
+	}
+	if got := fkDeferred(); got != "t" {
+		t.Fatalf("coupon_redemptions foreign key deferred: got %q, want t", got)
+	}
+
+	place := func() *http.Response {
+		return doPostWithAuth(t, "/api/order", orderRequest{
+			Items:       []orderItemRequest{{ProductID: "4", Quantity: 2}},
+			CouponCode:  "stack10",
+			CouponCodes: []string{"STACK1"},
+		}, testAPIKey)
+	}
+
+	resp := place()
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("place stacked order: expected 200, got %d", resp.StatusCode)
+	}
+	placed := decodeJSON[orderResponse](t, resp)
+	if len(placed.Coupons) != 2 {
+		t.Fatalf("placed coupons: got %+v, want 2", placed.Coupons)
+	}
+
+	redemptions := func() string {
+		return execSQL(t, fmt.Sprintf(`SELECT count(*) FROM coupon_redemptions WHERE order_id = '%s'`, placed.ID))
+	}
+	if n := redemptions(); n != "2" {
+		t.Errorf("redemptions after placing: got %s, want 2", n)
+	}
+
+	limited := place()
+	defer limited.Body.Close()
+	if limited.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("second stacked order: expected 422 from the per-customer limit, got %d", limited.StatusCode)
+	}
+
+	// Migrations run on every start; running them again must leave stacked
+	// orders and the redemption ledger as they were.
+	if err := runSeed(context.Background()); err != nil {
+		t.Fatalf("re-run migrations: %v", err)
+	}
+	if got := fkDeferred(); got != "t" {
+		t.Errorf("coupon_redemptions foreign key deferred after re-run: got %q, want t", got)
+	}
+
+	get := doGetWithAuth(t, "/api/order/"+placed.ID, testAPIKey)
+	defer get.Body.Close()
+	if get.StatusCode != http.StatusOK {
+		t.Fatalf("get order: expected 200, got %d", get.StatusCode)
+	}
+	got := decodeJSON[orderResponse](t, get)
+	if len(got.Coupons) != 2 {
+		t.Fatalf("coupons after re-run: got %+v, want the 2 placed", got.Coupons)
+	}
+	seen := make(map[string]bool)
+	var sum float64
+	for _, c := range got.Coupons {
+		if seen[c.Code] {
+			t.Errorf("coupon %s listed twice: %+v", c.Code, got.Coupons)
+		}
+		seen[c.Code] = true
+		sum += c.Discount
+	}
+	if math.Abs(sum-got.Discounts) > 0.001 {
+		t.Errorf("coupon breakdown sums to %v, want %v", sum, got.Discounts)
+	}
+
+	cancel := doPostWithAuth(t, "/api/order/"+placed.ID+"/cancel", map[string]string{}, testAPIKey)
+	defer cancel.Body.Close()
+	if cancel.StatusCode != http.StatusOK {
+		t.Fatalf("cancel: expected 200, got %d", cancel.StatusCode)
+	}
+
+	if n := redemptions(); n != "0" {
+		t.Errorf("redemptions after cancel: got %s, want 0", n)
+	}
+	if uses := execSQL(t, `SELECT string_agg(uses::text, ',' ORDER BY code) FROM coupons
+		WHERE code IN ('STACK10', 'STACK1')`); uses != "0,0" {
+		t.Errorf("coupon uses after cancel: got %s, want 0,0", uses)
+	}
+
+	retry := place()
+	defer retry.Body.Close()
+	if retry.StatusCode != http.StatusOK {
+		t.Errorf("stacked order after cancel: expected 200, got %d", retry.StatusCode)
+	}
+}
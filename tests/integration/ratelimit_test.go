@@ -0,0 +1,93 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestRateLimit_PostgresStore fires concurrent bursts at quoteOrder, whose
+// limit docker-compose.test.yml sets to quoteLimit per quoteWindow with the
+// postgres store. The first burst lands late in one window and the second
+// just after the next one starts, when the previous window's hits still
+// count almost in full, so exactly quoteLimit requests get through in all.
+func TestRateLimit_PostgresStore(t *testing.T) {
+	const (
+		quoteLimit  = 20
+		quoteWindow = 10 * time.Second
+		burst       = 2 * quoteLimit
+	)
+
+	body, err := json.Marshal(orderRequest{
+		Items: []orderItemRequest{{ProductID: "1", Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("marshal body: %v", err)
+	}
+
+	fire := func() map[int]int {
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			statuses = make(map[int]int)
+		)
+		for range burst {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				// t.Fatal must not be called off the test goroutine, so
+				// failures are recorded as status 0.
+				status := 0
+				req, err := http.NewRequestWithContext(context.Background(), http.MethodPost,
+					baseURL+"/api/order/quote", bytes.NewReader(body))
+				if err == nil {
+					req.Header.Set("Content-Type", "application/json")
+					req.Header.Set("api_key", testAPIKey)
+					if resp, err := httpClient.Do(req); err == nil {
+						status = resp.StatusCode
+						resp.Body.Close()
+					}
+				}
+
+				mu.Lock()
+				statuses[status]++
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		return statuses
+	}
+
+	// Windows are aligned to multiples of their length, on the server as
+	// here. Start halfway through a window, so nothing before it counts.
+	boundary := time.Now().Truncate(quoteWindow).Add(quoteWindow)
+	time.Sleep(time.Until(boundary.Add(quoteWindow / 2)))
+
+	first := fire()
+	if got := first[http.StatusOK]; got != quoteLimit {
+		t.Errorf("first burst allowed: got %d, want %d (statuses: %v)", got, quoteLimit, first)
+	}
+	if got := first[http.StatusTooManyRequests]; got != burst-quoteLimit {
+		t.Errorf("first burst refused: got %d, want %d (statuses: %v)", got, burst-quoteLimit, first)
+	}
+
+	time.Sleep(time.Until(boundary.Add(quoteWindow)))
+
+	second := fire()
+	if got := second[http.StatusOK]; got != 0 {
+		t.Errorf("second burst allowed: got %d, want 0 across the boundary (statuses: %v)", got, second)
+	}
+
+	hits := execSQL(t, `SELECT COALESCE(SUM(hits), 0) FROM rate_limit_windows WHERE key LIKE '%|quoteOrder'`)
+	if hits != strconv.Itoa(quoteLimit) {
+		t.Errorf("stored hits: got %s, want %d", hits, quoteLimit)
+	}
+}